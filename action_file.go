@@ -0,0 +1,175 @@
+package actionlint
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Keys which are allowed in steps of composite action.
+// https://docs.github.com/en/actions/creating-actions/metadata-syntax-for-github-actions#runssteps-for-composite-actions
+var compositeActionStepKeys = []string{
+	"continue-on-error",
+	"env",
+	"id",
+	"if",
+	"name",
+	"run",
+	"shell",
+	"uses",
+	"with",
+	"working-directory",
+}
+
+// isActionMetadataFile returns whether the file at the path is action metadata file (action.yml)
+// rather than workflow file.
+func isActionMetadataFile(path string) bool {
+	b := filepath.Base(path)
+	return b == "action.yml" || b == "action.yaml"
+}
+
+// checkActionMetadata checks the source of action metadata file. Currently keys in steps of
+// composite action and inputs referenced in the steps are checked.
+// https://docs.github.com/en/actions/creating-actions/metadata-syntax-for-github-actions
+func checkActionMetadata(src []byte) []*Error {
+	var n yaml.Node
+	if err := yaml.Unmarshal(src, &n); err != nil {
+		return handleYAMLError(err)
+	}
+	if n.Kind != yaml.DocumentNode || len(n.Content) == 0 {
+		return nil
+	}
+
+	runs := mappingValueOf(n.Content[0], "runs")
+	if runs == nil {
+		return nil
+	}
+	using := mappingValueOf(runs, "using")
+	if using == nil || using.Kind != yaml.ScalarNode || !strings.EqualFold(using.Value, "composite") {
+		return nil
+	}
+	steps := mappingValueOf(runs, "steps")
+	if steps == nil || steps.Kind != yaml.SequenceNode {
+		return nil
+	}
+
+	errs := []*Error{}
+	inputs := declaredActionInputs(n.Content[0])
+	for _, step := range steps.Content {
+		if step.Kind != yaml.MappingNode {
+			continue
+		}
+		errs = append(errs, checkCompositeActionInputRefs(step, inputs)...)
+		for i := 0; i < len(step.Content); i += 2 {
+			k := step.Content[i]
+			if contains(compositeActionStepKeys, k.Value) {
+				continue
+			}
+			errs = append(errs, &Error{
+				Message: fmt.Sprintf(
+					"key %q is not allowed in step of composite action. allowed keys are %s",
+					k.Value,
+					sortedQuotes(compositeActionStepKeys),
+				),
+				Line:   k.Line,
+				Column: k.Column,
+				Kind:   "composite-action",
+			})
+		}
+	}
+	return errs
+}
+
+// declaredActionInputs returns names of inputs declared at "inputs" of the action metadata. Names
+// are lower-cased since properties of contexts are case-insensitive.
+func declaredActionInputs(root *yaml.Node) []string {
+	names := []string{}
+	inputs := mappingValueOf(root, "inputs")
+	if inputs == nil || inputs.Kind != yaml.MappingNode {
+		return names
+	}
+	for i := 0; i < len(inputs.Content); i += 2 {
+		names = append(names, strings.ToLower(inputs.Content[i].Value))
+	}
+	return names
+}
+
+// checkCompositeActionInputRefs checks inputs referenced like ${{ inputs.foo }} in the step of
+// composite action are declared at "inputs" of the action.
+func checkCompositeActionInputRefs(step *yaml.Node, inputs []string) []*Error {
+	errs := []*Error{}
+	check := func(v *yaml.Node, bare bool) {
+		visitExprsInString(newString(v), bare, func(expr ExprNode, line, col int) {
+			VisitExprNode(expr, func(n, _ ExprNode, entering bool) {
+				if !entering {
+					return
+				}
+				name, ok := contextProperty(n, "inputs")
+				if !ok || contains(inputs, strings.ToLower(name)) {
+					return
+				}
+				hint := ""
+				if ss := findSimilarStrings(strings.ToLower(name), inputs); len(ss) > 0 {
+					hint = fmt.Sprintf(" did you mean %s?", sortedQuotes(ss))
+				}
+				available := "no input is declared"
+				if len(inputs) > 0 {
+					available = "declared inputs are " + sortedQuotes(inputs)
+				}
+				t := n.Token()
+				pos := convertExprLineColToPos(t.Line, t.Column, line, col)
+				errs = append(errs, &Error{
+					Message: fmt.Sprintf(
+						"input %q is not declared at \"inputs\" of this action.%s %s",
+						name,
+						hint,
+						available,
+					),
+					Line:   pos.Line,
+					Column: pos.Col,
+					Kind:   "composite-action",
+				})
+			})
+		})
+	}
+
+	var walk func(n *yaml.Node)
+	walk = func(n *yaml.Node) {
+		switch n.Kind {
+		case yaml.ScalarNode:
+			check(n, false)
+		case yaml.MappingNode:
+			for i := 1; i < len(n.Content); i += 2 {
+				walk(n.Content[i])
+			}
+		case yaml.SequenceNode:
+			for _, c := range n.Content {
+				walk(c)
+			}
+		}
+	}
+
+	for i := 0; i+1 < len(step.Content); i += 2 {
+		k, v := step.Content[i], step.Content[i+1]
+		if k.Value == "if" && v.Kind == yaml.ScalarNode {
+			check(v, true) // Expression at "if:" can omit ${{ }}
+			continue
+		}
+		walk(v)
+	}
+	return errs
+}
+
+func mappingValueOf(n *yaml.Node, key string) *yaml.Node {
+	if n.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package actionlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestActionFileCompositeStepKeys(t *testing.T) {
+	src := `name: Test
+description: test
+runs:
+  using: composite
+  steps:
+    - run: echo hello
+      shell: bash
+      timeout-minutes: 10
+    - uses: actions/checkout@v3
+      with:
+        fetch-depth: 0
+      strategy: foo
+`
+	errs := checkActionMetadata([]byte(src))
+	if len(errs) != 2 {
+		t.Fatalf("wanted 2 errors but got %d: %v", len(errs), errs)
+	}
+	want := []struct {
+		key  string
+		line int
+		col  int
+	}{
+		{"timeout-minutes", 8, 7},
+		{"strategy", 12, 7},
+	}
+	for i, w := range want {
+		err := errs[i]
+		if !strings.Contains(err.Message, `key "`+w.key+`" is not allowed in step of composite action`) {
+			t.Errorf("unexpected error message at %d: %q", i, err.Message)
+		}
+		if err.Line != w.line || err.Column != w.col {
+			t.Errorf("wanted position %d:%d but got %d:%d", w.line, w.col, err.Line, err.Column)
+		}
+		if err.Kind != "composite-action" {
+			t.Errorf("unexpected error kind %q", err.Kind)
+		}
+	}
+}
+
+func TestActionFileNotCompositeAction(t *testing.T) {
+	for _, src := range []string{
+		"runs:\n  using: node16\n  main: index.js\n  steps:\n    - timeout-minutes: 10\n",
+		"name: foo\n",
+		"",
+	} {
+		if errs := checkActionMetadata([]byte(src)); len(errs) != 0 {
+			t.Errorf("wanted no error for %q but got %v", src, errs)
+		}
+	}
+}
+
+func TestActionFileLintComposite(t *testing.T) {
+	l, err := NewLinter(&strings.Builder{}, &LinterOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := "runs:\n  using: composite\n  steps:\n    - run: echo\n      shell: bash\n      timeout-minutes: 1\n"
+	errs, err := l.Lint("path/to/action.yml", []byte(src), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("wanted 1 error but got %v", errs)
+	}
+	if errs[0].Kind != "composite-action" || errs[0].Filepath != "path/to/action.yml" {
+		t.Fatalf("unexpected error: %v", errs[0])
+	}
+}
+
+func TestActionFileCompositeInputRefs(t *testing.T) {
+	src := `name: Test
+description: test
+inputs:
+  name:
+    description: name
+  Token:
+    description: token
+runs:
+  using: composite
+  steps:
+    - run: echo "${{ inputs.name }} ${{ inputs.nmae }}"
+      shell: bash
+    - uses: actions/checkout@v3
+      if: inputs.token != '' && inputs.unknown
+      with:
+        token: ${{ inputs['TOKEN'] }}
+        repository: ${{ inputs['repo'] }}
+      env:
+        NAME: ${{ inputs.Name }}
+`
+	errs := checkActionMetadata([]byte(src))
+	want := []struct {
+		msg  string
+		line int
+		col  int
+	}{
+		{`input "nmae" is not declared at "inputs" of this action. did you mean "name"? declared inputs are "name", "token"`, 11, 41},
+		{`input "unknown" is not declared at "inputs" of this action. declared inputs are "name", "token"`, 14, 33},
+		{`input "repo" is not declared at "inputs" of this action.`, 17, 25},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("wanted %d errors but got %d: %v", len(want), len(errs), errs)
+	}
+	for i, w := range want {
+		err := errs[i]
+		if !strings.Contains(err.Message, w.msg) {
+			t.Errorf("error message %q does not contain %q", err.Message, w.msg)
+		}
+		if err.Line != w.line || err.Column != w.col {
+			t.Errorf("wanted position %d:%d but got %d:%d for %q", w.line, w.col, err.Line, err.Column, err.Message)
+		}
+	}
+}
+
+func TestActionFileCompositeInputRefsWithoutInputs(t *testing.T) {
+	src := `name: Test
+runs:
+  using: composite
+  steps:
+    - run: echo ${{ inputs.foo }}
+      shell: bash
+`
+	errs := checkActionMetadata([]byte(src))
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, `input "foo" is not declared at "inputs" of this action. no input is declared`) {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+}
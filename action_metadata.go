@@ -1,11 +1,14 @@
 package actionlint
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -33,6 +36,23 @@ func (required *ActionMetadataInputRequired) UnmarshalYAML(n *yaml.Node) error {
 	return nil
 }
 
+// ActionMetadataRuns is "runs" section of action.yaml. Only "using" field is stored since other
+// fields are not used by actionlint.
+// https://docs.github.com/en/actions/creating-actions/metadata-syntax-for-github-actions#runs
+type ActionMetadataRuns struct {
+	// Using is the runtime of the action like "node16", "docker" or "composite".
+	Using string `yaml:"using" json:"using"`
+}
+
+// ActionMetadataInputType is the type of action input. Inputs in action.yml have no type and
+// accept any string. The type can be declared only in actions metadata file.
+type ActionMetadataInputType struct {
+	// Type is the name of the type. "string", "number", "boolean" or "choice" is available.
+	Type string `json:"type"`
+	// Options is the list of available values when Type is "choice".
+	Options []string `json:"options,omitempty"`
+}
+
 // ActionMetadata represents structure of action.yaml.
 // https://docs.github.com/en/actions/creating-actions/metadata-syntax-for-github-actions
 type ActionMetadata struct {
@@ -43,12 +63,27 @@ type ActionMetadata struct {
 	// Outputs is "outputs" field of action.yaml. Key is name of output. Description is omitted
 	// since actionlint does not use it.
 	Outputs map[string]struct{} `yaml:"outputs" json:"outputs"`
+	// InputTypes is types of inputs declared in actions metadata file. Keys are names of inputs.
+	// Inputs which are not in this map accept any string. This is not a field of action.yaml.
+	InputTypes map[string]*ActionMetadataInputType `yaml:"-" json:"input_types,omitempty"`
 	// SkipInputs is flag to specify behavior of inputs check. When it is true, inputs for this
 	// action will not be checked.
 	SkipInputs bool `json:"skip_inputs"`
 	// SkipOutputs is flag to specify a bit loose typing to outputs object. If it is set to
 	// true, the outputs object accepts any properties along with strictly typed props.
 	SkipOutputs bool `json:"skip_outputs"`
+	// Runs is "runs" field of action.yaml. It is nil when the field is unknown.
+	Runs *ActionMetadataRuns `yaml:"runs" json:"runs,omitempty"`
+	// Permissions is permissions of GITHUB_TOKEN required by this action. Keys are permission
+	// scopes and values are required levels "read" or "write". This is not a field of action.yaml.
+	// It is set to known popular actions.
+	Permissions map[string]string `json:"permissions,omitempty"`
+	// Deprecated is the reason why this action is deprecated or archived. When it is empty, the
+	// action is not deprecated.
+	Deprecated string `json:"deprecated,omitempty"`
+	// Replacement is the spec of the action recommended instead of this deprecated action. It may be
+	// empty when there is no replacement.
+	Replacement string `json:"replacement,omitempty"`
 }
 
 // LocalActionsCache is cache for local actions' metadata. It avoids repeating to find/read/parse
@@ -143,3 +178,181 @@ func readLocalActionMetadataFile(dir string) ([]byte, error) {
 	}
 	return nil, fmt.Errorf("neither action.yaml nor action.yml is found in directory \"%s\"", dir)
 }
+
+// findRepoActionMetadata finds metadata of the action from the additional actions metadata and the
+// popular actions dataset. Keys of the additional metadata can omit the ref like "owner/repo" to
+// match all refs of the action. The additional metadata is prioritized over the dataset.
+func findRepoActionMetadata(spec string, extra map[string]*ActionMetadata) (*ActionMetadata, bool) {
+	if m, ok := extra[spec]; ok {
+		return m, true
+	}
+	if i := strings.IndexRune(spec, '@'); i >= 0 {
+		if m, ok := extra[spec[:i]]; ok {
+			return m, true
+		}
+	}
+	m, ok := PopularActions[spec]
+	return m, ok
+}
+
+// actionsMetadataFileEntry is an entry of actions metadata file. Its format is a subset of
+// action.yml with some extra keys.
+type actionsMetadataFileEntry struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Inputs      map[string]*struct {
+		Description        string   `yaml:"description"`
+		Required           bool     `yaml:"required"`
+		Default            *string  `yaml:"default"`
+		DeprecationMessage string   `yaml:"deprecationMessage"`
+		Type               string   `yaml:"type"`
+		Options            []string `yaml:"options"`
+	} `yaml:"inputs"`
+	Outputs map[string]*struct {
+		Description string `yaml:"description"`
+	} `yaml:"outputs"`
+	Runs *struct {
+		Using string `yaml:"using"`
+		// Other fields like "main" are accepted so that "runs" in action.yml can be copied as-is
+		Rest map[string]interface{} `yaml:",inline"`
+	} `yaml:"runs"`
+	SkipInputs  bool              `yaml:"skip_inputs"`
+	SkipOutputs bool              `yaml:"skip_outputs"`
+	Permissions map[string]string `yaml:"permissions"`
+}
+
+// ReadActionsMetadataFile reads the file which describes metadata of additional actions such as
+// actions in private repositories. The file is a mapping from action specs ("owner/repo@ref" or
+// "owner/repo") to their metadata in JSON or YAML format. The format of metadata is a subset of
+// action.yml. See docs/config.md for more details.
+func ReadActionsMetadataFile(path string) (map[string]*ActionMetadata, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read actions metadata file %q: %w", path, err)
+	}
+	m, err := parseActionsMetadata(b)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse actions metadata file %q: %w", path, err)
+	}
+	return m, nil
+}
+
+func parseActionsMetadata(b []byte) (map[string]*ActionMetadata, error) {
+	var entries map[string]*actionsMetadataFileEntry
+	d := yaml.NewDecoder(bytes.NewReader(b))
+	d.KnownFields(true)
+	if err := d.Decode(&entries); err != nil && err != io.EOF {
+		msg := strings.ReplaceAll(err.Error(), "\n", " ")
+		return nil, errors.New(msg)
+	}
+
+	ret := make(map[string]*ActionMetadata, len(entries))
+	for spec, e := range entries {
+		if strings.HasPrefix(spec, "./") || strings.HasPrefix(spec, "docker://") || !strings.Contains(spec, "/") {
+			return nil, fmt.Errorf("action %q is not in format \"owner/repo@ref\" nor \"owner/repo\". local actions and Docker actions are not supported", spec)
+		}
+		if e == nil {
+			return nil, fmt.Errorf("metadata of action %q is empty", spec)
+		}
+
+		m := &ActionMetadata{
+			Name:        e.Name,
+			Inputs:      make(map[string]ActionMetadataInputRequired, len(e.Inputs)),
+			Outputs:     make(map[string]struct{}, len(e.Outputs)),
+			SkipInputs:  e.SkipInputs,
+			SkipOutputs: e.SkipOutputs,
+		}
+		if m.Name == "" {
+			m.Name = spec
+		}
+		for n, i := range e.Inputs {
+			m.Inputs[n] = ActionMetadataInputRequired(i != nil && i.Required && i.Default == nil)
+			if i == nil {
+				continue
+			}
+			t, err := parseActionInputType(i.Type, i.Options)
+			if err != nil {
+				return nil, fmt.Errorf("input %q in metadata of action %q is invalid: %w", n, spec, err)
+			}
+			if t == nil {
+				continue
+			}
+			if i.Default != nil {
+				if msg := t.check(*i.Default); msg != "" {
+					return nil, fmt.Errorf("default value of input %q in metadata of action %q is invalid: %s", n, spec, msg)
+				}
+			}
+			if m.InputTypes == nil {
+				m.InputTypes = map[string]*ActionMetadataInputType{}
+			}
+			m.InputTypes[n] = t
+		}
+		for n := range e.Outputs {
+			m.Outputs[n] = struct{}{}
+		}
+		for scope, p := range e.Permissions {
+			if _, ok := allPermissionScopes[scope]; !ok {
+				return nil, fmt.Errorf("unknown permission scope %q in metadata of action %q", scope, spec)
+			}
+			if p != "read" && p != "write" {
+				return nil, fmt.Errorf("permission %q of scope %q in metadata of action %q is invalid. available values are \"read\" and \"write\"", p, scope, spec)
+			}
+		}
+		if e.Runs != nil && e.Runs.Using != "" {
+			m.Runs = &ActionMetadataRuns{Using: e.Runs.Using}
+		}
+		if len(e.Permissions) > 0 {
+			m.Permissions = e.Permissions
+		}
+		ret[spec] = m
+	}
+
+	return ret, nil
+}
+
+// parseActionInputType parses "type" and "options" of input in actions metadata file. It returns nil
+// when no type is declared.
+func parseActionInputType(ty string, opts []string) (*ActionMetadataInputType, error) {
+	switch ty {
+	case "":
+		if len(opts) > 0 {
+			return nil, errors.New("\"options\" is available only when \"type\" is \"choice\"")
+		}
+		return nil, nil
+	case "string", "number", "boolean":
+		if len(opts) > 0 {
+			return nil, errors.New("\"options\" is available only when \"type\" is \"choice\"")
+		}
+		return &ActionMetadataInputType{Type: ty}, nil
+	case "choice":
+		if len(opts) == 0 {
+			return nil, errors.New("\"options\" must not be empty when \"type\" is \"choice\"")
+		}
+		return &ActionMetadataInputType{Type: ty, Options: opts}, nil
+	default:
+		return nil, fmt.Errorf("unknown type %q. available types are \"string\", \"number\", \"boolean\" and \"choice\"", ty)
+	}
+}
+
+// check checks the value of input matches to the type. It returns a message describing the
+// mismatch, or an empty string when the value is valid.
+func (t *ActionMetadataInputType) check(v string) string {
+	switch t.Type {
+	case "number":
+		if _, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err != nil {
+			return fmt.Sprintf("%q is not a number", v)
+		}
+	case "boolean":
+		if v != "true" && v != "false" {
+			return fmt.Sprintf("%q is not a boolean. available values are \"true\" and \"false\"", v)
+		}
+	case "choice":
+		for _, o := range t.Options {
+			if v == o {
+				return ""
+			}
+		}
+		return fmt.Sprintf("%q is not one of the options %s", v, quotesAll(t.Options))
+	}
+	return ""
+}
@@ -21,6 +21,7 @@ func testGetWantedActionMetadata() *ActionMetadata {
 		Outputs: map[string]struct{}{
 			"user_id": {},
 		},
+		Runs: &ActionMetadataRuns{Using: "node14"},
 	}
 	return want
 }
@@ -28,7 +29,7 @@ func testGetWantedActionMetadata() *ActionMetadata {
 // Normal cases
 
 func TestLocalActionsFindMetadata(t *testing.T) {
-	proj := &Project{filepath.Join("testdata", "action_metadata"), nil}
+	proj := &Project{root: filepath.Join("testdata", "action_metadata")}
 	c := NewLocalActionsCache(proj, nil)
 
 	want := testGetWantedActionMetadata()
@@ -79,7 +80,7 @@ func TestLocalActionsFindMetadata(t *testing.T) {
 
 func TestLocalActionsFindConcurrently(t *testing.T) {
 	n := 10
-	proj := &Project{filepath.Join("testdata", "action_metadata"), nil}
+	proj := &Project{root: filepath.Join("testdata", "action_metadata")}
 	c := NewLocalActionsCache(proj, nil)
 	ret := make(chan *ActionMetadata)
 	err := make(chan error)
@@ -140,7 +141,7 @@ func TestLocalActionsProjectIsNil(t *testing.T) {
 }
 
 func TestLocalActionsIgnoreRemoteActions(t *testing.T) {
-	proj := &Project{filepath.Join("testdata", "action_metadata"), nil}
+	proj := &Project{root: filepath.Join("testdata", "action_metadata")}
 	c := NewLocalActionsCache(proj, nil)
 	for _, spec := range []string{"actions/checkout@v2", "docker://example.com/foo/bar"} {
 		m, err := c.FindMetadata(spec)
@@ -155,7 +156,7 @@ func TestLocalActionsIgnoreRemoteActions(t *testing.T) {
 
 func TestLocalActionsLogCacheHit(t *testing.T) {
 	dbg := &bytes.Buffer{}
-	proj := &Project{filepath.Join("testdata", "action_metadata"), nil}
+	proj := &Project{root: filepath.Join("testdata", "action_metadata")}
 	c := NewLocalActionsCache(proj, dbg)
 
 	want := testGetWantedActionMetadata()
@@ -184,7 +185,7 @@ func TestLocalActionsLogCacheHit(t *testing.T) {
 // Error cases
 
 func TestLocalActionsFailures(t *testing.T) {
-	proj := &Project{filepath.Join("testdata", "action_metadata"), nil}
+	proj := &Project{root: filepath.Join("testdata", "action_metadata")}
 
 	testCases := []struct {
 		what string
@@ -237,7 +238,7 @@ func TestLocalActionsFailures(t *testing.T) {
 
 func TestLocalActionsConcurrentFailures(t *testing.T) {
 	n := 10
-	proj := &Project{filepath.Join("testdata", "action_metadata"), nil}
+	proj := &Project{root: filepath.Join("testdata", "action_metadata")}
 	c := NewLocalActionsCache(proj, nil)
 	errC := make(chan error)
 
@@ -271,7 +272,7 @@ func TestLocalActionsConcurrentFailures(t *testing.T) {
 }
 
 func TestLocalActionsConcurrentMultipleMetadataAndFailures(t *testing.T) {
-	proj := &Project{filepath.Join("testdata", "action_metadata"), nil}
+	proj := &Project{root: filepath.Join("testdata", "action_metadata")}
 	c := NewLocalActionsCache(proj, nil)
 
 	inputs := []string{
@@ -431,6 +432,20 @@ outputs:
 				},
 			},
 		},
+		{
+			what: "runs",
+			input: `name: Test
+runs:
+  using: composite
+  steps:
+    - run: echo hello
+      shell: bash
+`,
+			want: ActionMetadata{
+				Name: "Test",
+				Runs: &ActionMetadataRuns{Using: "composite"},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -487,3 +502,180 @@ outputs: "foo"`,
 		})
 	}
 }
+
+func TestActionsMetadataFileReadOK(t *testing.T) {
+	have, err := ReadActionsMetadataFile(filepath.Join("testdata", "actions_metadata_file", "ok.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*ActionMetadata{
+		"my-org/my-action@v1": {
+			Name: "My action",
+			Inputs: map[string]ActionMetadataInputRequired{
+				"token": true,
+				"level": false,
+			},
+			Outputs: map[string]struct{}{
+				"result": {},
+			},
+		},
+		"my-org/other-action": {
+			Name: "my-org/other-action",
+			Inputs: map[string]ActionMetadataInputRequired{
+				"path": false,
+			},
+			Outputs:     map[string]struct{}{},
+			SkipOutputs: true,
+		},
+		"my-org/typed-action@v1": {
+			Name: "my-org/typed-action@v1",
+			Inputs: map[string]ActionMetadataInputRequired{
+				"count":   false,
+				"dry-run": false,
+				"mode":    false,
+			},
+			Outputs: map[string]struct{}{},
+			InputTypes: map[string]*ActionMetadataInputType{
+				"count":   {Type: "number"},
+				"dry-run": {Type: "boolean"},
+				"mode":    {Type: "choice", Options: []string{"fast", "slow"}},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestActionsMetadataFileParseJSON(t *testing.T) {
+	src := `{"my-org/my-action@v1": {"inputs": {"token": {"required": true}}, "outputs": {"result": {}}}}`
+	have, err := parseActionsMetadata([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, ok := have["my-org/my-action@v1"]
+	if !ok {
+		t.Fatal("action was not found", have)
+	}
+	if !m.Inputs["token"] {
+		t.Fatal("input is not required", m.Inputs)
+	}
+	if _, ok := m.Outputs["result"]; !ok {
+		t.Fatal("output was not found", m.Outputs)
+	}
+}
+
+func TestActionsMetadataFileParsePermissions(t *testing.T) {
+	src := "my-org/release-action:\n  permissions:\n    contents: write\n    pull-requests: read\n"
+	have, err := parseActionsMetadata([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"contents": "write", "pull-requests": "read"}
+	if diff := cmp.Diff(want, have["my-org/release-action"].Permissions); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestActionsMetadataFileParseRuns(t *testing.T) {
+	src := "my-org/js-action@v1:\n  runs:\n    using: node16\n    main: dist/index.js\n"
+	have, err := parseActionsMetadata([]byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &ActionMetadataRuns{Using: "node16"}
+	if diff := cmp.Diff(want, have["my-org/js-action@v1"].Runs); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestActionsMetadataFileParseError(t *testing.T) {
+	testCases := []struct {
+		what string
+		src  string
+		want string
+	}{
+		{
+			what: "broken YAML",
+			src:  "foo: [",
+			want: "yaml:",
+		},
+		{
+			what: "unknown key",
+			src:  "my-org/my-action@v1:\n  input:\n    foo: {}\n",
+			want: "field input not found",
+		},
+		{
+			what: "invalid type",
+			src:  "my-org/my-action@v1:\n  inputs:\n    foo:\n      required: 42\n",
+			want: "cannot unmarshal",
+		},
+		{
+			what: "local action",
+			src:  "./path/to/action:\n  name: foo\n",
+			want: `action "./path/to/action" is not in format "owner/repo@ref" nor "owner/repo"`,
+		},
+		{
+			what: "no owner",
+			src:  "my-action@v1:\n  name: foo\n",
+			want: `action "my-action@v1" is not in format`,
+		},
+		{
+			what: "unknown permission scope",
+			src:  "my-org/my-action@v1:\n  permissions:\n    content: write\n",
+			want: `unknown permission scope "content" in metadata of action "my-org/my-action@v1"`,
+		},
+		{
+			what: "invalid permission",
+			src:  "my-org/my-action@v1:\n  permissions:\n    contents: none\n",
+			want: `permission "none" of scope "contents" in metadata of action "my-org/my-action@v1" is invalid`,
+		},
+		{
+			what: "unknown input type",
+			src:  "my-org/my-action@v1:\n  inputs:\n    foo:\n      type: integer\n",
+			want: `input "foo" in metadata of action "my-org/my-action@v1" is invalid: unknown type "integer"`,
+		},
+		{
+			what: "choice without options",
+			src:  "my-org/my-action@v1:\n  inputs:\n    foo:\n      type: choice\n",
+			want: `"options" must not be empty when "type" is "choice"`,
+		},
+		{
+			what: "options without choice",
+			src:  "my-org/my-action@v1:\n  inputs:\n    foo:\n      type: string\n      options: [a, b]\n",
+			want: `"options" is available only when "type" is "choice"`,
+		},
+		{
+			what: "default value mismatching type",
+			src:  "my-org/my-action@v1:\n  inputs:\n    foo:\n      type: number\n      default: many\n",
+			want: `default value of input "foo" in metadata of action "my-org/my-action@v1" is invalid: "many" is not a number`,
+		},
+		{
+			what: "empty metadata",
+			src:  "my-org/my-action@v1:\n",
+			want: `metadata of action "my-org/my-action@v1" is empty`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			_, err := parseActionsMetadata([]byte(tc.src))
+			if err == nil {
+				t.Fatal("error did not occur")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("%q is not contained in error message %q", tc.want, err.Error())
+			}
+		})
+	}
+}
+
+func TestActionsMetadataFileNotFound(t *testing.T) {
+	_, err := ReadActionsMetadataFile(filepath.Join("testdata", "actions_metadata_file", "does-not-exist.yaml"))
+	if err == nil {
+		t.Fatal("error did not occur")
+	}
+	if !strings.Contains(err.Error(), "could not read actions metadata file") {
+		t.Fatalf("unexpected error: %q", err.Error())
+	}
+}
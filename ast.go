@@ -26,6 +26,12 @@ type String struct {
 	Value string
 	// Quoted represents the string is quoted with ' or " in the YAML source.
 	Quoted bool
+	// Literal represents the string is in literal block scalar like "|" in the YAML source. In the
+	// case, the first line of the string is at the next line of Pos.
+	Literal bool
+	// Indent is the number of spaces to indent lines of the literal block scalar. It is 0 when
+	// Literal is false or the indentation is unknown.
+	Indent int
 	// Pos is a position of the string in source.
 	Pos *Pos
 }
@@ -208,6 +214,9 @@ type WorkflowCallEventInput struct {
 // WorkflowCallEventSecret is a secret configuration of workflow_call event.
 // https://docs.github.com/en/actions/learn-github-actions/workflow-syntax-for-github-actions#onworkflow_callsecrets
 type WorkflowCallEventSecret struct {
+	// Name is a name of the secret in its original case. Keys of WorkflowCallEvent.Secrets are
+	// lower-cased since secret names are case insensitive.
+	Name *String
 	// Description is a description of the secret.
 	Description *String
 	// Required represents if the secret is required or optional. When this value is nil, it means optional.
@@ -719,6 +728,10 @@ type Runner struct {
 	// Labels is list label names to select a runner to run a job. There are preset labels and user
 	// defined labels. Runner matching to the labels is selected.
 	Labels []*String
+	// Group is a name of runner group to select a runner from. This value is nil when "runs-on" is
+	// not a mapping or "group" is omitted.
+	// https://docs.github.com/en/actions/using-jobs/choosing-the-runner-for-a-job#choosing-runners-in-a-group
+	Group *String
 }
 
 // WorkflowCallInput is a normal input for workflow call.
@@ -732,7 +745,8 @@ type WorkflowCallInput struct {
 // WorkflowCallSecret is a secret input for workflow call.
 // https://docs.github.com/en/actions/learn-github-actions/workflow-syntax-for-github-actions#jobsjob_idwith
 type WorkflowCallSecret struct {
-	// Name is a name of the secret
+	// Name is a name of the secret in its original case. Keys of WorkflowCall.Secrets are
+	// lower-cased since secret names are case insensitive.
 	Name *String
 	// Value is a value of the secret
 	Value *String
@@ -747,6 +761,10 @@ type WorkflowCall struct {
 	Inputs map[string]*WorkflowCallInput
 	// Secrets is a map from secret name to secret value at 'secrets:'.
 	Secrets map[string]*WorkflowCallSecret
+	// InheritSecrets is true when 'secrets: inherit' is specified. In the case, all secrets of the
+	// caller workflow are implicitly passed to the called workflow.
+	// https://docs.github.com/en/actions/using-workflows/reusing-workflows#passing-secrets-to-nested-workflows
+	InheritSecrets bool
 }
 
 // Job is configuration of how to run a job.
@@ -0,0 +1,178 @@
+package actionlint
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// baselineLineTolerance is the max distance of lines between an error and a baseline entry to be
+// matched. It allows small line shifts by editing other parts of the workflow file.
+const baselineLineTolerance = 10
+
+// Positions in error messages like "at line 3, col 5" are normalized since they are shifted by
+// editing other parts of the workflow file as well as positions of errors.
+var reBaselinePosInMessage = regexp.MustCompile(`\bline \d+(?:, col \d+)?`)
+
+func normalizeBaselineMessage(msg string) string {
+	return reBaselinePosInMessage.ReplaceAllString(msg, "line _")
+}
+
+// BaselineEntry is an error recorded in a baseline file.
+type BaselineEntry struct {
+	// Filepath is a file path where the error occurred. Path separators are always slashes.
+	Filepath string `json:"filepath"`
+	// Line is a line number where the error occurred. This value is 1-based.
+	Line int `json:"line"`
+	// Kind is a kind of the error. Usually rule name which found the error.
+	Kind string `json:"kind"`
+	// Message is an error message.
+	Message string `json:"message"`
+}
+
+type baselineFile struct {
+	Findings []*BaselineEntry `json:"findings"`
+}
+
+// Baseline is a set of known errors recorded in a baseline file. Errors matching the entries are
+// suppressed so that only new errors are reported. An error matches an entry when they have the
+// same file path, kind and message, and their lines are close. Numbers of matched entries and stale
+// entries are recorded while filtering errors so one instance should be used for one linting run.
+// Filtering errors is thread-safe.
+type Baseline struct {
+	entries map[string][]*BaselineEntry // Entries by file path
+	total   int
+	mu      sync.Mutex
+	matched int
+	stale   int
+}
+
+// NewBaseline creates a new Baseline instance from the entries.
+func NewBaseline(entries []*BaselineEntry) *Baseline {
+	m := map[string][]*BaselineEntry{}
+	for _, e := range entries {
+		m[e.Filepath] = append(m[e.Filepath], e)
+	}
+	return &Baseline{entries: m, total: len(entries)}
+}
+
+// ReadBaselineFile reads the baseline file written by WriteBaselineFile.
+func ReadBaselineFile(path string) (*Baseline, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read baseline file %q: %w", path, err)
+	}
+	var f baselineFile
+	if err := json.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("could not parse baseline file %q: %w", path, err)
+	}
+	return NewBaseline(f.Findings), nil
+}
+
+// WriteBaselineFile records the errors in a baseline file. The file can be read by ReadBaselineFile
+// to suppress the errors in later runs.
+func WriteBaselineFile(path string, errs []*Error) error {
+	f := baselineFile{Findings: make([]*BaselineEntry, 0, len(errs))}
+	for _, err := range errs {
+		f.Findings = append(f.Findings, &BaselineEntry{
+			Filepath: filepath.ToSlash(err.Filepath),
+			Line:     err.Line,
+			Kind:     err.Kind,
+			Message:  err.Message,
+		})
+	}
+	sort.SliceStable(f.Findings, func(i, j int) bool {
+		a, b := f.Findings[i], f.Findings[j]
+		if a.Filepath != b.Filepath {
+			return a.Filepath < b.Filepath
+		}
+		return a.Line < b.Line
+	})
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false) // Keep characters like '&' in messages readable
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(&f); err != nil {
+		return fmt.Errorf("could not encode baseline: %w", err)
+	}
+	if err := ioutil.WriteFile(path, b.Bytes(), 0644); err != nil {
+		return fmt.Errorf("could not write baseline file %q: %w", path, err)
+	}
+	return nil
+}
+
+// Filter removes errors matching the baseline entries from the errors in the file. Each entry
+// matches at most one error. When multiple entries can match an error, the closest one is used.
+// Entries of the file which match no error are counted as stale.
+func (b *Baseline) Filter(path string, errs []*Error) []*Error {
+	entries := b.entries[filepath.ToSlash(path)]
+	if len(entries) == 0 {
+		return errs
+	}
+
+	used := make([]bool, len(entries))
+	kept := make([]*Error, 0, len(errs))
+	matched := 0
+	for _, err := range errs {
+		msg := normalizeBaselineMessage(err.Message)
+		found := -1
+		for i, e := range entries {
+			if used[i] || e.Kind != err.Kind || normalizeBaselineMessage(e.Message) != msg {
+				continue
+			}
+			d := absInt(e.Line - err.Line)
+			if d > baselineLineTolerance {
+				continue
+			}
+			if found < 0 || d < absInt(entries[found].Line-err.Line) {
+				found = i
+			}
+		}
+		if found < 0 {
+			kept = append(kept, err)
+			continue
+		}
+		used[found] = true
+		matched++
+	}
+
+	b.mu.Lock()
+	b.matched += matched
+	b.stale += len(entries) - matched
+	b.mu.Unlock()
+
+	return kept
+}
+
+// Matched returns the number of errors suppressed by the baseline.
+func (b *Baseline) Matched() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.matched
+}
+
+// Stale returns the number of baseline entries which matched no error in the filtered files. They
+// were fixed or changed so the baseline should be updated.
+func (b *Baseline) Stale() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stale
+}
+
+// Len returns the number of entries in the baseline.
+func (b *Baseline) Len() int {
+	return b.total
+}
+
+func absInt(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
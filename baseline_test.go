@@ -0,0 +1,104 @@
+package actionlint
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBaselineWriteAndRead(t *testing.T) {
+	errs := []*Error{
+		{Filepath: filepath.Join(".github", "workflows", "b.yaml"), Line: 3, Column: 1, Kind: "expression", Message: "foo & bar"},
+		{Filepath: filepath.Join(".github", "workflows", "a.yaml"), Line: 9, Column: 5, Kind: "env-var", Message: "error 2"},
+		{Filepath: filepath.Join(".github", "workflows", "a.yaml"), Line: 2, Column: 5, Kind: "env-var", Message: "error 1"},
+	}
+	p := filepath.Join(t.TempDir(), "baseline.json")
+	if err := WriteBaselineFile(p, errs); err != nil {
+		t.Fatal(err)
+	}
+	b, err := ReadBaselineFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() != 3 {
+		t.Fatalf("wanted 3 entries but got %d", b.Len())
+	}
+
+	es := b.entries[".github/workflows/a.yaml"]
+	if len(es) != 2 || es[0].Line != 2 || es[1].Line != 9 {
+		t.Fatalf("entries are not sorted by line: %v", es)
+	}
+	es = b.entries[".github/workflows/b.yaml"]
+	if len(es) != 1 || es[0].Message != "foo & bar" || es[0].Kind != "expression" {
+		t.Fatalf("unexpected entries: %v", es)
+	}
+}
+
+func TestBaselineReadError(t *testing.T) {
+	testCases := []struct {
+		file string
+		want string
+	}{
+		{"does-not-exist.json", "could not read baseline file"},
+		{"broken.json", "could not parse baseline file"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.file, func(t *testing.T) {
+			_, err := ReadBaselineFile(filepath.Join("testdata", "baseline", tc.file))
+			if err == nil {
+				t.Fatal("error did not occur")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("%q is not contained in error message %q", tc.want, err.Error())
+			}
+		})
+	}
+}
+
+func TestBaselineFilter(t *testing.T) {
+	b := NewBaseline([]*BaselineEntry{
+		{Filepath: "a.yaml", Line: 10, Kind: "expression", Message: "type mismatch"},
+		{Filepath: "a.yaml", Line: 20, Kind: "expression", Message: "type mismatch"},
+		{Filepath: "a.yaml", Line: 30, Kind: "job-needs", Message: "job \"x\" is defined at line 3, col 5"},
+		{Filepath: "a.yaml", Line: 40, Kind: "env-var", Message: "fixed error"},
+		{Filepath: "b.yaml", Line: 1, Kind: "env-var", Message: "error in other file"},
+	})
+
+	errs := []*Error{
+		// Matches the entry at line 10 after shifted by 2 lines
+		{Line: 12, Kind: "expression", Message: "type mismatch"},
+		// Matches the entry at line 20 which is closer than line 10
+		{Line: 19, Kind: "expression", Message: "type mismatch"},
+		// All entries with the same message were already used
+		{Line: 21, Kind: "expression", Message: "type mismatch"},
+		// Positions in message are normalized
+		{Line: 33, Kind: "job-needs", Message: "job \"x\" is defined at line 6, col 5"},
+		// Too far from the entry
+		{Line: 100, Kind: "expression", Message: "type mismatch"},
+		// Different kind
+		{Line: 30, Kind: "expression", Message: "job \"x\" is defined at line 3, col 5"},
+	}
+
+	have := b.Filter("a.yaml", errs)
+	lines := []int{}
+	for _, e := range have {
+		lines = append(lines, e.Line)
+	}
+	if len(lines) != 3 || lines[0] != 21 || lines[1] != 100 || lines[2] != 30 {
+		t.Fatalf("unexpected errors remain: %v", lines)
+	}
+	if b.Matched() != 3 {
+		t.Fatalf("wanted 3 matched errors but got %d", b.Matched())
+	}
+	if b.Stale() != 1 {
+		t.Fatalf("wanted 1 stale entry but got %d", b.Stale())
+	}
+
+	if have := b.Filter("c.yaml", errs); len(have) != len(errs) {
+		t.Fatalf("errors in file not in baseline were filtered: %v", have)
+	}
+	if have := b.Filter("b.yaml", []*Error{}); len(have) != 0 || b.Stale() != 2 {
+		t.Fatalf("entry in file without errors should be stale: %v, %d", have, b.Stale())
+	}
+}
@@ -1,12 +1,17 @@
 package actionlint
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"runtime"
 	"runtime/debug"
+	"strings"
+	"sync/atomic"
 )
 
 // These variables might be modified by ldflags on building release binaries by GoReleaser. Do not modify manually
@@ -20,10 +25,14 @@ const (
 	ExitStatusSuccessNoProblem = 0
 	// ExitStatusSuccessProblemFound is the exit status when the command ran successfully with some problem found.
 	ExitStatusSuccessProblemFound = 1
+	// ExitStatusFailure is the exit status when some workflow could not be parsed as YAML or the
+	// command stopped due to some fatal error like I/O error while checking workflows.
+	ExitStatusFailure = 2
 	// ExitStatusInvalidCommandOption is the exit status when parsing command line options failed.
-	ExitStatusInvalidCommandOption = 2
-	// ExitStatusFailure is the exit status when the command stopped due to some fatal error while checking workflows.
-	ExitStatusFailure = 3
+	ExitStatusInvalidCommandOption = 3
+	// ExitStatusTimeout is the exit status when linting did not finish within the duration given by
+	// -timeout flag. Errors found until then are printed.
+	ExitStatusTimeout = 4
 )
 
 const commandUsageHeader = `Usage: actionlint [FLAGS] [FILES...] [-]
@@ -44,6 +53,11 @@ const commandUsageHeader = `Usage: actionlint [FLAGS] [FILES...] [-]
 
     $ actionlint -
 
+  Multiple workflows in stdin separated with '---' are checked separately.
+  Their file names can be given with -stdin-filenames option:
+
+    $ cat a.yaml <(echo ---) b.yaml | actionlint -stdin-filenames a.yaml,b.yaml -
+
   To serialize errors into JSON, use -format option. It allows to format error
   messages flexibly with Go template syntax.
 
@@ -78,7 +92,11 @@ type Command struct {
 	Stderr io.Writer
 }
 
-func (cmd *Command) runLinter(args []string, opts *LinterOptions, initConfig bool) ([]*Error, error) {
+func (cmd *Command) runLinter(args []string, opts *LinterOptions, initConfig, fix bool, stdinNames []string, repo string) ([]*Error, error) {
+	if repo != "" {
+		return cmd.runLinterOnRemoteRepository(repo, opts)
+	}
+
 	l, err := NewLinter(cmd.Stdout, opts)
 	if err != nil {
 		return nil, err
@@ -88,19 +106,196 @@ func (cmd *Command) runLinter(args []string, opts *LinterOptions, initConfig boo
 		return nil, l.GenerateDefaultConfig(".")
 	}
 
+	var errs []*Error
 	if len(args) == 0 {
-		return l.LintRepository(".")
+		errs, err = l.LintRepository(".")
+	} else if len(args) == 1 && args[0] == "-" {
+		var b []byte
+		b, err = ioutil.ReadAll(cmd.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("could not read stdin: %w", err)
+		}
+		errs, err = l.LintDocuments("<stdin>", b, stdinNames, nil)
+	} else {
+		errs, err = l.LintFiles(args, nil)
+	}
+
+	if err == nil && fix && atomic.LoadInt32(&l.fixerEnabled) == 0 {
+		fmt.Fprintln(cmd.Stderr, "note: -fix did nothing since no enabled rule can fix errors. enable a rule which can fix errors such as \"deprecated-commands\" with -enable-rule")
+	}
+
+	return errs, err
+}
+
+// runLinterOnRemoteRepository fetches the repository given as git URL or path to tarball into a
+// temporary directory and lints all workflow files in it. The temporary directory is removed after
+// linting. File paths in errors are relative to the root of the repository.
+func (cmd *Command) runLinterOnRemoteRepository(repo string, opts *LinterOptions) ([]*Error, error) {
+	root, cleanup, err := fetchRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	o := *opts
+	o.RelativeTo = root
+	l, err := NewLinter(cmd.Stdout, &o)
+	if err != nil {
+		return nil, err
+	}
+	l.log("Fetched repository", repo, "into", root)
+	return l.lintProject(&Project{root: root})
+}
+
+// writeBaseline lints the files and records all errors found in the baseline file instead of
+// printing them.
+func (cmd *Command) writeBaseline(path string, args []string, opts *LinterOptions, stdinNames []string, repo string) int {
+	// Record each occurrence of errors since baseline filters errors before deduplicating them
+	o := *opts
+	o.Dedup = false
+	out := cmd.Stdout
+	cmd.Stdout = ioutil.Discard
+	errs, err := cmd.runLinter(args, &o, false, false, stdinNames, repo)
+	cmd.Stdout = out
+	if err != nil {
+		fmt.Fprintln(cmd.Stderr, err.Error())
+		if errors.Is(err, ErrTimeout) {
+			return ExitStatusTimeout
+		}
+		if errors.Is(err, ErrInvalidOption) {
+			return ExitStatusInvalidCommandOption
+		}
+		return ExitStatusFailure
+	}
+	if err := WriteBaselineFile(path, errs); err != nil {
+		fmt.Fprintln(cmd.Stderr, err.Error())
+		return ExitStatusFailure
 	}
+	fmt.Fprintf(cmd.Stderr, "Recorded %d errors in baseline %q\n", len(errs), path)
+	return ExitStatusSuccessNoProblem
+}
+
+// applyFixes applies fixes of the errors to the files and returns errors which were not fixed. The
+// base parameter is the base directory of relative file paths in the errors. When dryRun is true,
+// the files are not modified. Instead, the differences by the fixes are printed to stdout in unified
+// diff format and all errors are returned since they are not fixed yet. The diff is colorized when
+// colorful is true.
+func (cmd *Command) applyFixes(errs []*Error, base string, dryRun, colorful bool) ([]*Error, error) {
+	files := []string{}
+	byFile := map[string][]*Error{}
+	for _, e := range errs {
+		if len(e.Fixes) == 0 {
+			continue
+		}
+		if _, ok := byFile[e.Filepath]; !ok {
+			files = append(files, e.Filepath)
+		}
+		byFile[e.Filepath] = append(byFile[e.Filepath], e)
+	}
+
+	fixed := map[*Error]struct{}{}
+	for _, p := range files {
+		fixable := byFile[p]
+		path := p
+		edits := []*TextEdit{}
+		for _, e := range fixable {
+			edits = append(edits, e.Fixes...)
+		}
 
-	if len(args) == 1 && args[0] == "-" {
-		b, err := ioutil.ReadAll(cmd.Stdin)
+		if base != "" && !filepath.IsAbs(path) {
+			path = filepath.Join(base, path)
+		}
+		src, err := ioutil.ReadFile(path)
 		if err != nil {
-			return nil, fmt.Errorf("could not read stdin: %w", err)
+			return nil, fmt.Errorf("could not read %q to apply fixes: %w", path, err)
 		}
-		return l.Lint("<stdin>", b, nil)
+		out, applied := ApplyTextEdits(src, edits)
+		if len(applied) == 0 {
+			continue
+		}
+
+		if dryRun {
+			writeUnifiedDiff(cmd.Stdout, filepath.ToSlash(p), src, out, colorful)
+			continue
+		}
+
+		st, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("could not stat %q to apply fixes: %w", path, err)
+		}
+		if err := ioutil.WriteFile(path, out, st.Mode()); err != nil {
+			return nil, fmt.Errorf("could not write fixes to %q: %w", path, err)
+		}
+
+		done := make(map[*TextEdit]struct{}, len(applied))
+		for _, e := range applied {
+			done[e] = struct{}{}
+		}
+	Loop:
+		for _, e := range fixable {
+			for _, f := range e.Fixes {
+				if _, ok := done[f]; !ok {
+					continue Loop
+				}
+			}
+			fixed[e] = struct{}{}
+			fmt.Fprintf(cmd.Stderr, "fixed: %s\n", e.Error())
+		}
+	}
+
+	remaining := make([]*Error, 0, len(errs)-len(fixed))
+	for _, e := range errs {
+		if _, ok := fixed[e]; !ok {
+			remaining = append(remaining, e)
+		}
+	}
+	return remaining, nil
+}
+
+// runDiff compares two workflow files structurally and prints their differences. It returns true
+// when some difference is found.
+func (cmd *Command) runDiff(before, after string) (bool, error) {
+	a, err := parseWorkflowFile(before)
+	if err != nil {
+		return false, err
+	}
+	b, err := parseWorkflowFile(after)
+	if err != nil {
+		return false, err
+	}
+
+	diffs := DiffWorkflows(a, b)
+	if len(diffs) == 0 {
+		return false, nil
+	}
+
+	fmt.Fprintf(cmd.Stdout, "--- %s\n+++ %s\n", before, after)
+	for _, d := range diffs {
+		fmt.Fprintln(cmd.Stdout, d)
+	}
+	return true, nil
+}
+
+func parseWorkflowFile(path string) (*Workflow, error) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %q: %w", path, err)
+	}
+	w, errs := Parse(src)
+	if w == nil {
+		// Parsing fails only when the file is not a valid YAML
+		return nil, fmt.Errorf("could not parse %q: %s", path, errs[0].Message)
 	}
+	return w, nil
+}
 
-	return l.LintFiles(args, nil)
+func (cmd *Command) runLanguageServer(opts *LinterOptions) error {
+	// Outputs from linter are not used. Diagnostics are sent to client via stdout instead.
+	l, err := NewLinter(ioutil.Discard, opts)
+	if err != nil {
+		return err
+	}
+	return NewLanguageServer(l, cmd.Stdin, cmd.Stdout).Serve()
 }
 
 type ignorePatternFlags []string
@@ -113,6 +308,61 @@ func (i *ignorePatternFlags) Set(v string) error {
 	return nil
 }
 
+type enableRuleFlags []string
+
+func (e *enableRuleFlags) String() string {
+	return "option for enabled rules"
+}
+func (e *enableRuleFlags) Set(v string) error {
+	*e = append(*e, v)
+	return nil
+}
+
+type presetFlags []string
+
+func (p *presetFlags) String() string {
+	return "option for presets"
+}
+func (p *presetFlags) Set(v string) error {
+	*p = append(*p, v)
+	return nil
+}
+
+type errorOnFlags []string
+
+func (e *errorOnFlags) String() string {
+	return "option for rules treated as errors"
+}
+func (e *errorOnFlags) Set(v string) error {
+	*e = append(*e, v)
+	return nil
+}
+
+type expectRuleFlags []string
+
+func (e *expectRuleFlags) String() string {
+	return "option for expected rules"
+}
+func (e *expectRuleFlags) Set(v string) error {
+	*e = append(*e, v)
+	return nil
+}
+
+// checkExpectedRules returns names of the expected rules which reported no error.
+func checkExpectedRules(errs []*Error, expected []string) []string {
+	fired := map[string]struct{}{}
+	for _, err := range errs {
+		fired[err.Kind] = struct{}{}
+	}
+	missing := []string{}
+	for _, r := range expected {
+		if _, ok := fired[r]; !ok {
+			missing = append(missing, r)
+		}
+	}
+	return missing
+}
+
 // Main is main function of actionlint. It takes command line arguments as string slice and returns
 // exit status. The args should be entire arguments including the program name, usually given via
 // os.Args.
@@ -120,22 +370,62 @@ func (cmd *Command) Main(args []string) int {
 	var ver bool
 	var opts LinterOptions
 	var ignorePats ignorePatternFlags
+	var enableRules enableRuleFlags
+	var presets presetFlags
+	var errorOn errorOnFlags
+	var expectRules expectRuleFlags
 	var initConfig bool
 	var noColor bool
 	var color bool
+	var lsp bool
+	var fix bool
+	var dryRun bool
+	var diff bool
+	var stdinNames string
+	var repo string
+	var baselineFile string
+	var writeBaseline string
+	var sortBy string
+	var cpuProfile string
+	var memProfile string
 
 	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
 	flags.SetOutput(cmd.Stderr)
 	flags.Var(&ignorePats, "ignore", "Regular expression matching to error messages you want to ignore. This flag is repeatable")
+	flags.Var(&enableRules, "enable-rule", "Name of rule which is disabled by default to enable. This flag is repeatable. See https://github.com/rhysd/actionlint/tree/main/docs/usage.md#optional-rules")
+	flags.Var(&presets, "preset", "Name of preset which applies a bundle of rule settings like \"security\" or \"strict\". Custom presets defined in config file can also be specified. This flag is repeatable. See https://github.com/rhysd/actionlint/tree/main/docs/usage.md#presets")
+	flags.Var(&errorOn, "error-on", "Name of rule whose errors are treated as errors rather than warnings. This takes precedence over \"rules\" in config file. This flag is repeatable. See https://github.com/rhysd/actionlint/tree/main/docs/usage.md#severity")
+	flags.Var(&expectRules, "expect", "Name of rule which is expected to report some error. The exit status is non-zero only when some expected rule reported no error. This flag is repeatable and intended for testing that known-bad workflows are still caught")
 	flags.StringVar(&opts.Shellcheck, "shellcheck", "shellcheck", "Command name or file path of \"shellcheck\" external command")
 	flags.StringVar(&opts.Pyflakes, "pyflakes", "pyflakes", "Command name or file path of \"pyflakes\" external command")
+	flags.BoolVar(&opts.DisableExternal, "no-external", false, "Disable all rules which run external commands like shellcheck and pyflakes. Other rules still run. Disabled rules are printed with -verbose")
 	flags.BoolVar(&opts.Oneline, "oneline", false, "Use one line per one error. Useful for reading error messages from programs")
-	flags.StringVar(&opts.Format, "format", "", "Custom template to format error messages in Go template syntax. See https://github.com/rhysd/actionlint/tree/main/docs/usage.md#format")
+	flags.StringVar(&opts.Format, "format", "", "Custom template to format error messages in Go template syntax. Template accessing fields of one error like \"{{.Filepath}}:{{.Line}}\" formats each error in one line. \"ghactions\" prints errors as annotations of GitHub Actions. \"jsonl\" prints errors as newline-delimited JSON. \"sarif\" prints errors as SARIF log. \"grouped-sarif\" prints SARIF log grouping the same errors in each file into one result. See https://github.com/rhysd/actionlint/tree/main/docs/usage.md#format")
+	flags.IntVar(&opts.MaxFindings, "max-findings", 0, "Maximum number of errors to print. Omitted errors are counted and still make the exit status non-zero. 0 means no limit")
+	flags.BoolVar(&opts.MaxFindingsPerFile, "max-findings-per-file", false, "Apply the limit of -max-findings to each file instead of all files")
+	flags.StringVar(&sortBy, "sort", "", "Order of errors across all files. \"severity\" prints errors before warnings reported by optional rules, then sorts them by file paths and positions. \"position\" sorts errors by file paths and positions. By default, errors are printed in the order of files given as arguments")
+	flags.IntVar(&opts.MaxShellcheckScriptBytes, "max-shellcheck-script-bytes", 0, "Skip shellcheck for scripts at \"run:\" larger than this size in bytes. 0 means the default size (256KiB). Negative value means no limit")
+	flags.DurationVar(&opts.Timeout, "timeout", 0, "Timeout of the entire linting like \"30s\" or \"5m\". When exceeded, running shellcheck and pyflakes processes are killed, errors found until then are printed and the exit status is 4. 0 means no timeout")
+	flags.BoolVar(&opts.Dedup, "dedup", false, "Collapse duplicate errors which have the same message in each file into one")
+	flags.BoolVar(&opts.Dependabot, "dependabot", false, "Also lint Dependabot configuration file .github/dependabot.yml. \"version\", \"package-ecosystem\" and \"schedule.interval\" are checked. Workflow rules are not applied to the file")
+	flags.StringVar(&opts.ActionsMetadataFile, "actions-metadata", "", "File path to JSON or YAML file which describes metadata of additional actions like actions in private repositories. See https://github.com/rhysd/actionlint/tree/main/docs/config.md#actions-metadata")
 	flags.StringVar(&opts.ConfigFile, "config-file", "", "File path to config file")
+	flags.StringVar(&opts.DefaultBranch, "default-branch", "", "Name of the default branch of the repository like \"main\". It is used by optional \"default-branch\" rule and takes precedence over \"default-branch\" in config file")
+	flags.StringVar(&stdinNames, "stdin-filenames", "", "Comma-separated file names of workflows in stdin separated with \"---\". Findings in each workflow are reported with the file name")
+	flags.StringVar(&repo, "repo", "", "Git URL or path to tarball of repository to lint instead of the current repository. The repository is cloned or extracted into a temporary directory and all workflow files in it are linted. File paths in errors are relative to the repository root")
+	flags.StringVar(&baselineFile, "baseline", "", "File path to baseline file written by -write-baseline. Errors recorded in the file are suppressed and only new errors are reported")
+	flags.StringVar(&writeBaseline, "write-baseline", "", "Record all errors found in this run to the baseline file at the given path instead of printing them. See https://github.com/rhysd/actionlint/tree/main/docs/usage.md#baseline")
+	flags.StringVar(&opts.RelativeTo, "relative-to", "", "Base directory of file paths in errors. Paths outside the directory are printed as absolute paths. When not given, paths are relative to current directory")
 	flags.BoolVar(&initConfig, "init-config", false, "Generate default config file at .github/actionlint.yaml in current project")
-	flags.BoolVar(&noColor, "no-color", false, "Disable colorful output")
-	flags.BoolVar(&color, "color", false, "Always enable colorful output. This is useful to force colorful outputs")
+	flags.BoolVar(&noColor, "no-color", false, "Disable colorful output. This takes precedence over -color and $NO_COLOR environment variable")
+	flags.BoolVar(&color, "color", false, "Always enable colorful output even if $NO_COLOR environment variable is set. This is useful to force colorful outputs")
+	flags.BoolVar(&fix, "fix", false, "Fix errors by modifying workflow files in place when rules can fix them mechanically. Applied fixes are printed to stderr")
+	flags.BoolVar(&dryRun, "dry-run", false, "With -fix, print fixes as unified diff instead of modifying files. Exit status is non-zero when some fix of an error (not a warning) is pending")
+	flags.BoolVar(&diff, "diff", false, "Compare two workflow files given as arguments structurally and print differences in triggers, jobs and steps. Exit status is non-zero when some difference is found")
+	flags.BoolVar(&lsp, "lsp", false, "Run as language server communicating via stdin and stdout. Only diagnostics are supported")
 	flags.BoolVar(&opts.Verbose, "verbose", false, "Enable verbose output")
+	flags.StringVar(&cpuProfile, "cpuprofile", "", "Write CPU profile of this run to the file in pprof format. It can be analyzed with \"go tool pprof\"")
+	flags.StringVar(&memProfile, "memprofile", "", "Write memory profile at the end of this run to the file in pprof format. It can be analyzed with \"go tool pprof\"")
 	flags.BoolVar(&opts.Debug, "debug", false, "Enable debug output (for development)")
 	flags.BoolVar(&ver, "version", false, "Show version and how this binary was installed")
 	flags.Usage = func() {
@@ -163,7 +453,24 @@ func (cmd *Command) Main(args []string) int {
 		return ExitStatusSuccessNoProblem
 	}
 
+	if cpuProfile != "" || memProfile != "" {
+		p, err := startProfiler(cpuProfile, memProfile)
+		if err != nil {
+			fmt.Fprintln(cmd.Stderr, err.Error())
+			return ExitStatusInvalidCommandOption
+		}
+		defer func() {
+			// Failing to write profiles does not change the exit status since linting was done
+			if err := p.stop(); err != nil {
+				fmt.Fprintln(cmd.Stderr, err.Error())
+			}
+		}()
+	}
+
 	opts.IgnorePatterns = ignorePats
+	opts.EnableRules = enableRules
+	opts.Presets = presets
+	opts.ErrorOn = errorOn
 	opts.LogWriter = cmd.Stderr
 
 	if color {
@@ -173,13 +480,149 @@ func (cmd *Command) Main(args []string) int {
 		opts.Color = ColorOptionKindNever
 	}
 
-	errs, err := cmd.runLinter(flags.Args(), &opts, initConfig)
+	if lsp {
+		if err := cmd.runLanguageServer(&opts); err != nil {
+			fmt.Fprintln(cmd.Stderr, err.Error())
+			return ExitStatusFailure
+		}
+		return ExitStatusSuccessNoProblem
+	}
+
+	if diff {
+		if len(flags.Args()) != 2 {
+			fmt.Fprintf(cmd.Stderr, "-diff requires exactly two workflow files but got %d arguments\n", len(flags.Args()))
+			return ExitStatusInvalidCommandOption
+		}
+		found, err := cmd.runDiff(flags.Arg(0), flags.Arg(1))
+		if err != nil {
+			fmt.Fprintln(cmd.Stderr, err.Error())
+			return ExitStatusFailure
+		}
+		if found {
+			return ExitStatusSuccessProblemFound
+		}
+		return ExitStatusSuccessNoProblem
+	}
+
+	if opts.MaxFindings < 0 {
+		fmt.Fprintf(cmd.Stderr, "value of -max-findings must not be negative but got %d\n", opts.MaxFindings)
+		return ExitStatusInvalidCommandOption
+	}
+
+	switch sortBy {
+	case "":
+	case "position":
+		opts.Sort = ErrorLessByPosition
+	case "severity":
+		opts.Sort = ErrorLessBySeverity
+	default:
+		fmt.Fprintf(cmd.Stderr, "value of -sort must be \"severity\" or \"position\" but got %q\n", sortBy)
+		return ExitStatusInvalidCommandOption
+	}
+
+	if opts.Timeout < 0 {
+		fmt.Fprintf(cmd.Stderr, "value of -timeout must not be negative but got %s\n", opts.Timeout)
+		return ExitStatusInvalidCommandOption
+	}
+
+	if dryRun && !fix {
+		fmt.Fprintln(cmd.Stderr, "-dry-run can be used only with -fix")
+		return ExitStatusInvalidCommandOption
+	}
+
+	if fix && len(expectRules) > 0 {
+		fmt.Fprintln(cmd.Stderr, "-expect cannot be used with -fix")
+		return ExitStatusInvalidCommandOption
+	}
+
+	for _, r := range expectRules {
+		if err := checkRuleName(r); err != nil {
+			fmt.Fprintf(cmd.Stderr, "invalid value of -expect: %s\n", err)
+			return ExitStatusInvalidCommandOption
+		}
+	}
+
+	if fix && len(flags.Args()) == 1 && flags.Arg(0) == "-" {
+		fmt.Fprintln(cmd.Stderr, "-fix cannot be used with input from stdin")
+		return ExitStatusInvalidCommandOption
+	}
+
+	var names []string
+	if stdinNames != "" {
+		if len(flags.Args()) != 1 || flags.Arg(0) != "-" {
+			fmt.Fprintln(cmd.Stderr, "-stdin-filenames can be used only with input from stdin")
+			return ExitStatusInvalidCommandOption
+		}
+		names = strings.Split(stdinNames, ",")
+	}
+
+	if repo != "" {
+		if len(flags.Args()) > 0 || fix || initConfig || opts.RelativeTo != "" {
+			fmt.Fprintln(cmd.Stderr, "-repo cannot be used with file arguments, -fix, -init-config or -relative-to")
+			return ExitStatusInvalidCommandOption
+		}
+	}
+
+	if writeBaseline != "" && (baselineFile != "" || fix || len(expectRules) > 0) {
+		fmt.Fprintln(cmd.Stderr, "-write-baseline cannot be used with -baseline, -fix or -expect")
+		return ExitStatusInvalidCommandOption
+	}
+
+	if baselineFile != "" {
+		b, err := ReadBaselineFile(baselineFile)
+		if err != nil {
+			fmt.Fprintln(cmd.Stderr, err.Error())
+			return ExitStatusInvalidCommandOption
+		}
+		opts.Baseline = b
+	}
+
+	if writeBaseline != "" {
+		return cmd.writeBaseline(writeBaseline, flags.Args(), &opts, names, repo)
+	}
+
+	errs, err := cmd.runLinter(flags.Args(), &opts, initConfig, fix, names, repo)
+	if opts.Baseline != nil && err == nil {
+		b := opts.Baseline
+		fmt.Fprintf(cmd.Stderr, "%d errors were suppressed by %d entries in baseline %q\n", b.Matched(), b.Len(), baselineFile)
+		if s := b.Stale(); s > 0 {
+			fmt.Fprintf(cmd.Stderr, "%d baseline entries are stale since their errors were not found. update the baseline with -write-baseline\n", s)
+		}
+	}
 	if err != nil {
 		fmt.Fprintln(cmd.Stderr, err.Error())
+		if errors.Is(err, ErrTimeout) {
+			return ExitStatusTimeout
+		}
+		if errors.Is(err, ErrInvalidOption) {
+			return ExitStatusInvalidCommandOption
+		}
 		return ExitStatusFailure
 	}
-	if len(errs) > 0 {
-		return ExitStatusSuccessProblemFound // Linter found some issues, yay!
+	if fix {
+		colorful := isColorEnabled(opts.Color, cmd.Stdout, os.Getenv)
+		errs, err = cmd.applyFixes(errs, opts.RelativeTo, dryRun, colorful)
+		if err != nil {
+			fmt.Fprintln(cmd.Stderr, err.Error())
+			return ExitStatusFailure
+		}
+	}
+	for _, err := range errs {
+		if err.Kind == ErrorKindYAMLSyntax {
+			return ExitStatusFailure // Some file could not be parsed
+		}
+	}
+	if len(expectRules) > 0 {
+		if missing := checkExpectedRules(errs, expectRules); len(missing) > 0 {
+			fmt.Fprintf(cmd.Stderr, "expected rules reported no error: %s\n", strings.Join(missing, ", "))
+			return ExitStatusSuccessProblemFound
+		}
+		return ExitStatusSuccessNoProblem
+	}
+	for _, err := range errs {
+		if err.Severity() == SeverityError {
+			return ExitStatusSuccessProblemFound // Linter found some issues, yay!
+		}
 	}
 
 	return ExitStatusSuccessNoProblem
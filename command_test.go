@@ -2,9 +2,15 @@ package actionlint
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func ExampleCommand() {
@@ -28,3 +34,769 @@ func ExampleCommand() {
 		panic("actionlint command failed: " + output.String())
 	}
 }
+
+func TestCommandFixErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "actionlint-fix-")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: |
+          echo '::set-output name=foo::bar'
+          echo "::set-output name=piyo::\"x\""
+        id: foo
+`
+	path := filepath.Join(dir, "test.yaml")
+	if err := ioutil.WriteFile(path, []byte(src), 0644); err != nil {
+		panic(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := Command{
+		Stdin:  os.Stdin,
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	status := cmd.Main([]string{"actionlint", "-shellcheck=", "-pyflakes=", "-oneline", "-enable-rule", "deprecated-commands", "-error-on", "deprecated-commands", "-fix", path})
+	if status != ExitStatusSuccessProblemFound {
+		t.Fatalf("unexpected exit status %d. stdout=%q stderr=%q", status, stdout.String(), stderr.String())
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	want := strings.Replace(src, `echo '::set-output name=foo::bar'`, `echo 'foo=bar' >> "$GITHUB_OUTPUT"`, 1)
+	if have := string(b); have != want {
+		t.Fatalf("wanted %q but got %q", want, have)
+	}
+
+	if out := stderr.String(); strings.Count(out, "fixed: ") != 1 || !strings.Contains(out, "at line 1 in this script") {
+		t.Fatalf("applied fix should be reported: %q", out)
+	}
+}
+
+func TestCommandFixErrorsAtPosition(t *testing.T) {
+	dir, err := ioutil.TempDir("", "actionlint-fix-")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// The same text as the fixed command appears before the 'run:' value in the line
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - { name: "echo ::add-path::/opt/bin", run: echo ::add-path::/opt/bin }
+`
+	path := filepath.Join(dir, "test.yaml")
+	if err := ioutil.WriteFile(path, []byte(src), 0644); err != nil {
+		panic(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := Command{
+		Stdin:  os.Stdin,
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	status := cmd.Main([]string{"actionlint", "-shellcheck=", "-pyflakes=", "-oneline", "-enable-rule", "deprecated-commands", "-fix", path})
+	if status != ExitStatusSuccessNoProblem {
+		t.Fatalf("unexpected exit status %d. stdout=%q stderr=%q", status, stdout.String(), stderr.String())
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	want := strings.Replace(src, `run: echo ::add-path::/opt/bin`, `run: echo /opt/bin >> "$GITHUB_PATH"`, 1)
+	if have := string(b); have != want {
+		t.Fatalf("wanted %q but got %q", want, have)
+	}
+}
+
+func TestCommandFixWithoutFixerRule(t *testing.T) {
+	dir, err := ioutil.TempDir("", "actionlint-fix-")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo '::set-output name=foo::bar'
+`
+	path := filepath.Join(dir, "test.yaml")
+	if err := ioutil.WriteFile(path, []byte(src), 0644); err != nil {
+		panic(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := Command{
+		Stdin:  os.Stdin,
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	status := cmd.Main([]string{"actionlint", "-shellcheck=", "-pyflakes=", "-fix", path})
+	if status != ExitStatusSuccessNoProblem {
+		t.Fatalf("unexpected exit status %d. stdout=%q stderr=%q", status, stdout.String(), stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "-fix did nothing since no enabled rule can fix errors") {
+		t.Fatalf("note should be printed: %q", stderr.String())
+	}
+}
+
+func TestCommandFixErrorsDryRun(t *testing.T) {
+	dir, err := ioutil.TempDir("", "actionlint-fix-")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: |
+          echo '::set-output name=foo::bar'
+        id: foo
+`
+	path := filepath.Join(dir, "test.yaml")
+	if err := ioutil.WriteFile(path, []byte(src), 0644); err != nil {
+		panic(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := Command{
+		Stdin:  os.Stdin,
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	status := cmd.Main([]string{"actionlint", "-shellcheck=", "-pyflakes=", "-oneline", "-no-color", "-relative-to", dir, "-enable-rule", "deprecated-commands", "-error-on", "deprecated-commands", "-fix", "-dry-run", path})
+	if status != ExitStatusSuccessProblemFound {
+		t.Fatalf("unexpected exit status %d. stdout=%q stderr=%q", status, stdout.String(), stderr.String())
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		panic(err)
+	}
+	if have := string(b); have != src {
+		t.Fatalf("file should not be modified with -dry-run: %q", have)
+	}
+
+	want := `--- a/test.yaml
++++ b/test.yaml
+@@ -4,5 +4,5 @@
+     runs-on: ubuntu-latest
+     steps:
+       - run: |
+-          echo '::set-output name=foo::bar'
++          echo 'foo=bar' >> "$GITHUB_OUTPUT"
+         id: foo
+`
+	if out := stdout.String(); !strings.HasSuffix(out, want) {
+		t.Fatalf("wanted diff %q at the end of output but got %q", want, out)
+	}
+	if out := stderr.String(); strings.Contains(out, "fixed: ") {
+		t.Fatalf("fix should not be reported as applied: %q", out)
+	}
+}
+
+func TestCommandDryRunWithoutFix(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cmd := Command{
+		Stdin:  strings.NewReader("on: push"),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	status := cmd.Main([]string{"actionlint", "-dry-run", "-"})
+	if status != ExitStatusInvalidCommandOption {
+		t.Fatalf("unexpected exit status %d", status)
+	}
+	if !strings.Contains(stderr.String(), "-dry-run can be used only with -fix") {
+		t.Fatalf("unexpected stderr: %q", stderr.String())
+	}
+}
+
+func TestCommandFixErrorsFromStdin(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cmd := Command{
+		Stdin:  strings.NewReader("on: push"),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	status := cmd.Main([]string{"actionlint", "-fix", "-"})
+	if status != ExitStatusInvalidCommandOption {
+		t.Fatalf("unexpected exit status %d", status)
+	}
+	if !strings.Contains(stderr.String(), "-fix cannot be used with input from stdin") {
+		t.Fatalf("unexpected stderr: %q", stderr.String())
+	}
+}
+
+func TestCommandFixesInJSONOutput(t *testing.T) {
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo '::set-output name=foo::bar'
+        id: foo
+`
+	var stdout, stderr bytes.Buffer
+	cmd := Command{
+		Stdin:  strings.NewReader(src),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	status := cmd.Main([]string{"actionlint", "-shellcheck=", "-pyflakes=", "-enable-rule", "deprecated-commands", "-format", "{{json .}}", "-"})
+	if status != ExitStatusSuccessNoProblem {
+		t.Fatalf("unexpected exit status %d. stdout=%q stderr=%q", status, stdout.String(), stderr.String())
+	}
+
+	errs := []*ErrorTemplateFields{}
+	if err := json.Unmarshal(stdout.Bytes(), &errs); err != nil {
+		t.Fatalf("output is not a valid JSON: %s: %q", err, stdout.String())
+	}
+	if len(errs) != 1 {
+		t.Fatalf("one error should be reported but got %d errors: %q", len(errs), stdout.String())
+	}
+
+	want := []*TextEdit{
+		{Line: 6, Column: 14, EndLine: 6, EndColumn: 47, NewText: `echo 'foo=bar' >> "$GITHUB_OUTPUT"`},
+	}
+	if !cmp.Equal(want, errs[0].Fixes) {
+		t.Fatal(cmp.Diff(want, errs[0].Fixes))
+	}
+
+	// The same edit is applied by -fix
+	have, _ := ApplyTextEdits([]byte(src), errs[0].Fixes)
+	if !strings.Contains(string(have), `echo 'foo=bar' >> "$GITHUB_OUTPUT"`) {
+		t.Fatalf("fix was not applied correctly: %q", have)
+	}
+}
+
+func TestCommandExitStatus(t *testing.T) {
+	testCases := []struct {
+		what  string
+		args  []string
+		stdin string
+		want  int
+	}{
+		{
+			what:  "no problem",
+			args:  []string{"-"},
+			stdin: "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n",
+			want:  ExitStatusSuccessNoProblem,
+		},
+		{
+			what:  "problem found",
+			args:  []string{"-"},
+			stdin: "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n",
+			want:  ExitStatusSuccessProblemFound,
+		},
+		{
+			what:  "broken YAML",
+			args:  []string{"-"},
+			stdin: "on: push\njobs: [\n",
+			want:  ExitStatusFailure,
+		},
+		{
+			what:  "broken YAML with custom format",
+			args:  []string{"-format", "{{json .}}", "-"},
+			stdin: "on: push\njobs: [\n",
+			want:  ExitStatusFailure,
+		},
+		{
+			what:  "broken YAML with max findings",
+			args:  []string{"-oneline", "-max-findings", "1", "-"},
+			stdin: "on: push\njobs: [\n",
+			want:  ExitStatusFailure,
+		},
+		{
+			what: "file not found",
+			args: []string{filepath.Join("testdata", "does-not-exist.yaml")},
+			want: ExitStatusFailure,
+		},
+		{
+			what: "unknown flag",
+			args: []string{"-this-flag-does-not-exist"},
+			want: ExitStatusInvalidCommandOption,
+		},
+		{
+			what:  "sorted by severity",
+			args:  []string{"-sort", "severity", "-"},
+			stdin: "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n",
+			want:  ExitStatusSuccessProblemFound,
+		},
+		{
+			what: "invalid sort order",
+			args: []string{"-sort", "kind", "-"},
+			want: ExitStatusInvalidCommandOption,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			var out bytes.Buffer
+			cmd := Command{
+				Stdin:  strings.NewReader(tc.stdin),
+				Stdout: &out,
+				Stderr: &out,
+			}
+			args := append([]string{"actionlint", "-shellcheck=", "-pyflakes="}, tc.args...)
+			if status := cmd.Main(args); status != tc.want {
+				t.Fatalf("wanted exit status %d but got %d. output=%q", tc.want, status, out.String())
+			}
+		})
+	}
+}
+
+func TestCommandDiff(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.yaml")
+	if err := ioutil.WriteFile(a, []byte("on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n"), 0644); err != nil {
+		panic(err)
+	}
+	b := filepath.Join(dir, "b.yaml")
+	if err := ioutil.WriteFile(b, []byte("on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n      - run: echo\n"), 0644); err != nil {
+		panic(err)
+	}
+
+	testCases := []struct {
+		what   string
+		args   []string
+		status int
+		stdout string
+	}{
+		{
+			what:   "difference found",
+			args:   []string{a, b},
+			status: ExitStatusSuccessProblemFound,
+			stdout: fmt.Sprintf("--- %s\n+++ %s\n+ jobs.test.steps.#2\n", a, b),
+		},
+		{
+			what:   "same workflows",
+			args:   []string{a, a},
+			status: ExitStatusSuccessNoProblem,
+		},
+		{
+			what:   "wrong number of arguments",
+			args:   []string{a},
+			status: ExitStatusInvalidCommandOption,
+		},
+		{
+			what:   "file not found",
+			args:   []string{a, filepath.Join(dir, "does-not-exist.yaml")},
+			status: ExitStatusFailure,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			cmd := Command{
+				Stdin:  strings.NewReader(""),
+				Stdout: &stdout,
+				Stderr: &stderr,
+			}
+			args := append([]string{"actionlint", "-diff"}, tc.args...)
+			if status := cmd.Main(args); status != tc.status {
+				t.Fatalf("wanted exit status %d but got %d. stderr=%q", tc.status, status, stderr.String())
+			}
+			if have := stdout.String(); have != tc.stdout {
+				t.Fatalf("wanted stdout %q but got %q", tc.stdout, have)
+			}
+		})
+	}
+}
+
+func TestCommandExpectRules(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "test.yaml")
+	src := "on: push\njobs:\n  test:\n    runs-on: foo\n    steps:\n      - run: echo '${{ unknown }}'\n      - run: echo '::set-output name=foo::bar'\n"
+	if err := ioutil.WriteFile(f, []byte(src), 0644); err != nil {
+		panic(err)
+	}
+
+	testCases := []struct {
+		what   string
+		expect []string
+		status int
+		stderr string
+	}{
+		{
+			what:   "expected rule fired",
+			expect: []string{"expression"},
+			status: ExitStatusSuccessNoProblem,
+		},
+		{
+			what:   "all expected rules fired",
+			expect: []string{"expression", "runner-label"},
+			status: ExitStatusSuccessNoProblem,
+		},
+		{
+			what:   "expected rule did not fire",
+			expect: []string{"expression", "job-needs", "events"},
+			status: ExitStatusSuccessProblemFound,
+			stderr: "expected rules reported no error: job-needs, events\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			cmd := Command{
+				Stdin:  strings.NewReader(""),
+				Stdout: &stdout,
+				Stderr: &stderr,
+			}
+			args := []string{"actionlint", "-shellcheck=", "-pyflakes="}
+			for _, e := range tc.expect {
+				args = append(args, "-expect", e)
+			}
+			args = append(args, f)
+			if status := cmd.Main(args); status != tc.status {
+				t.Fatalf("wanted exit status %d but got %d. stderr=%q", tc.status, status, stderr.String())
+			}
+			if have := stderr.String(); have != tc.stderr {
+				t.Fatalf("wanted stderr %q but got %q", tc.stderr, have)
+			}
+			if stdout.Len() == 0 {
+				t.Fatal("errors were not printed")
+			}
+		})
+	}
+}
+
+func TestCommandExpectUnknownRule(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cmd := Command{
+		Stdin:  strings.NewReader(""),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	status := cmd.Main([]string{"actionlint", "-expect", "expresion", "-"})
+	if status != ExitStatusInvalidCommandOption {
+		t.Fatalf("wanted exit status %d but got %d", ExitStatusInvalidCommandOption, status)
+	}
+	want := `invalid value of -expect: unknown rule "expresion". did you mean "expression"?`
+	if have := stderr.String(); !strings.Contains(have, want) {
+		t.Fatalf("wanted %q in stderr but got %q", want, have)
+	}
+}
+
+func TestCommandInvalidLinterOptions(t *testing.T) {
+	testCases := []struct {
+		what string
+		args []string
+		want string
+	}{
+		{
+			what: "invalid template",
+			args: []string{"-format", "{{.Foo}}"},
+			want: `accesses unknown field "Foo"`,
+		},
+		{
+			what: "unknown optional rule",
+			args: []string{"-enable-rule", "nosuch"},
+			want: `"nosuch"`,
+		},
+		{
+			what: "unknown rule for -error-on",
+			args: []string{"-error-on", "nosuch"},
+			want: `"nosuch"`,
+		},
+		{
+			what: "unknown preset",
+			args: []string{"-preset", "nosuch"},
+			want: `unknown preset "nosuch"`,
+		},
+		{
+			what: "broken regular expression",
+			args: []string{"-ignore", "("},
+			want: `invalid regular expression for ignore pattern "("`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			cmd := Command{
+				Stdin:  strings.NewReader("on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n"),
+				Stdout: &stdout,
+				Stderr: &stderr,
+			}
+			args := append([]string{"actionlint", "-shellcheck=", "-pyflakes="}, tc.args...)
+			status := cmd.Main(append(args, "-"))
+			if status != ExitStatusInvalidCommandOption {
+				t.Fatalf("wanted exit status %d but got %d. stderr=%q", ExitStatusInvalidCommandOption, status, stderr.String())
+			}
+			if have := stderr.String(); !strings.Contains(have, tc.want) {
+				t.Fatalf("wanted %q in stderr but got %q", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestCommandExpectRulesYAMLSyntaxError(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	cmd := Command{
+		Stdin:  strings.NewReader("on: push\njobs:\n  test: [\n"),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	status := cmd.Main([]string{"actionlint", "-expect", "syntax-check", "-"})
+	if status != ExitStatusFailure {
+		t.Fatalf("wanted exit status %d but got %d. stderr=%q", ExitStatusFailure, status, stderr.String())
+	}
+}
+
+func TestCommandSeverityOverrides(t *testing.T) {
+	dir := t.TempDir()
+	f := filepath.Join(dir, "test.yaml")
+	src := "on: push\njobs:\n  test:\n    runs-on: foo\n    steps:\n      - run: echo '${{ unknown }}'\n      - run: echo '::set-output name=foo::bar'\n"
+	if err := ioutil.WriteFile(f, []byte(src), 0644); err != nil {
+		panic(err)
+	}
+	cfg := filepath.Join(dir, "actionlint.yaml")
+	if err := ioutil.WriteFile(cfg, []byte("rules:\n  expression: warning\n  runner-label: warning\n"), 0644); err != nil {
+		panic(err)
+	}
+
+	testCases := []struct {
+		what   string
+		args   []string
+		status int
+	}{
+		{
+			what:   "no override",
+			args:   []string{},
+			status: ExitStatusSuccessProblemFound,
+		},
+		{
+			what:   "all errors are warnings",
+			args:   []string{"-config-file", cfg},
+			status: ExitStatusSuccessNoProblem,
+		},
+		{
+			what:   "warning in config is overridden by -error-on",
+			args:   []string{"-config-file", cfg, "-error-on", "expression"},
+			status: ExitStatusSuccessProblemFound,
+		},
+		{
+			what:   "errors of optional rule are warnings",
+			args:   []string{"-config-file", cfg, "-enable-rule", "deprecated-commands"},
+			status: ExitStatusSuccessNoProblem,
+		},
+		{
+			what:   "optional rule is overridden by -error-on",
+			args:   []string{"-config-file", cfg, "-enable-rule", "deprecated-commands", "-error-on", "deprecated-commands"},
+			status: ExitStatusSuccessProblemFound,
+		},
+		{
+			what:   "unknown rule",
+			args:   []string{"-error-on", "expresion"},
+			status: ExitStatusInvalidCommandOption,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			var out bytes.Buffer
+			cmd := Command{
+				Stdin:  strings.NewReader(""),
+				Stdout: &out,
+				Stderr: &out,
+			}
+			args := append([]string{"actionlint", "-shellcheck=", "-pyflakes="}, tc.args...)
+			args = append(args, f)
+			if status := cmd.Main(args); status != tc.status {
+				t.Fatalf("wanted exit status %d but got %d. output=%q", tc.status, status, out.String())
+			}
+		})
+	}
+}
+
+func TestCommandRemoteRepository(t *testing.T) {
+	p := testWriteTarball(t, map[string]string{"owner-repo-1234567/.github/workflows/ci.yaml": testRemoteWorkflow}, true)
+
+	var stdout, stderr bytes.Buffer
+	cmd := Command{
+		Stdin:  strings.NewReader(""),
+		Stdout: &stdout,
+		Stderr: &stderr,
+	}
+	args := []string{"actionlint", "-oneline", "-shellcheck=", "-pyflakes=", "-repo", p}
+	if status := cmd.Main(args); status != ExitStatusSuccessProblemFound {
+		t.Fatalf("wanted exit status %d but got %d. stderr=%q", ExitStatusSuccessProblemFound, status, stderr.String())
+	}
+	want := filepath.Join(".github", "workflows", "ci.yaml") + ":6:"
+	if out := stdout.String(); !strings.HasPrefix(out, want) {
+		t.Fatalf("file path in error is not relative to the repository root: %q", out)
+	}
+
+	stderr.Reset()
+	args = []string{"actionlint", "-repo", p, "test.yaml"}
+	if status := cmd.Main(args); status != ExitStatusInvalidCommandOption {
+		t.Fatalf("wanted exit status %d but got %d", ExitStatusInvalidCommandOption, status)
+	}
+}
+
+func TestCommandBaseline(t *testing.T) {
+	dir := t.TempDir()
+	wf := filepath.Join(dir, "test.yaml")
+	src := "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo ${{ unknown }}\n"
+	if err := ioutil.WriteFile(wf, []byte(src), 0644); err != nil {
+		panic(err)
+	}
+	baseline := filepath.Join(dir, "baseline.json")
+
+	run := func(args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		cmd := Command{
+			Stdin:  strings.NewReader(""),
+			Stdout: &stdout,
+			Stderr: &stderr,
+		}
+		args = append([]string{"actionlint", "-oneline", "-shellcheck=", "-pyflakes="}, args...)
+		return cmd.Main(append(args, wf)), stdout.String(), stderr.String()
+	}
+
+	status, stdout, stderr := run("-write-baseline", baseline)
+	if status != ExitStatusSuccessNoProblem {
+		t.Fatalf("exit status of -write-baseline is %d: %s", status, stderr)
+	}
+	if stdout != "" {
+		t.Fatalf("errors should not be printed with -write-baseline: %q", stdout)
+	}
+	if !strings.Contains(stderr, "Recorded 1 errors in baseline") {
+		t.Fatalf("unexpected stderr: %q", stderr)
+	}
+
+	// Shift the error by 2 lines and add a new error
+	src = "# comment 1\n# comment 2\n" + src + "      - run: echo ${{ unknown2 }}\n"
+	if err := ioutil.WriteFile(wf, []byte(src), 0644); err != nil {
+		panic(err)
+	}
+
+	status, stdout, stderr = run("-baseline", baseline)
+	if status != ExitStatusSuccessProblemFound {
+		t.Fatalf("exit status should be %d but got %d: %s", ExitStatusSuccessProblemFound, status, stderr)
+	}
+	if strings.Count(stdout, "\n") != 1 || !strings.Contains(stdout, "unknown2") {
+		t.Fatalf("only the new error should be reported: %q", stdout)
+	}
+	if !strings.Contains(stderr, "1 errors were suppressed by 1 entries in baseline") || strings.Contains(stderr, "stale") {
+		t.Fatalf("unexpected stderr: %q", stderr)
+	}
+
+	status, _, _ = run("-baseline", baseline, "-write-baseline", baseline)
+	if status != ExitStatusInvalidCommandOption {
+		t.Fatalf("-baseline and -write-baseline should not be used together but exit status was %d", status)
+	}
+
+	status, _, stderr = run("-baseline", filepath.Join(dir, "does-not-exist.json"))
+	if status != ExitStatusInvalidCommandOption || !strings.Contains(stderr, "could not read baseline file") {
+		t.Fatalf("unexpected result for missing baseline file: %d %q", status, stderr)
+	}
+}
+
+func TestCommandBaselineWithDedup(t *testing.T) {
+	dir := t.TempDir()
+	wf := filepath.Join(dir, "test.yaml")
+	src := "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo ${{ unknown }}\n      - run: echo ${{ unknown }}\n      - run: echo ${{ unknown }}\n"
+	if err := ioutil.WriteFile(wf, []byte(src), 0644); err != nil {
+		panic(err)
+	}
+	baseline := filepath.Join(dir, "baseline.json")
+
+	run := func(args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		cmd := Command{
+			Stdin:  strings.NewReader(""),
+			Stdout: &stdout,
+			Stderr: &stderr,
+		}
+		args = append([]string{"actionlint", "-oneline", "-shellcheck=", "-pyflakes=", "-dedup"}, args...)
+		return cmd.Main(append(args, wf)), stdout.String(), stderr.String()
+	}
+
+	status, _, stderr := run("-write-baseline", baseline)
+	if status != ExitStatusSuccessNoProblem {
+		t.Fatalf("exit status of -write-baseline is %d: %s", status, stderr)
+	}
+	if !strings.Contains(stderr, "Recorded 3 errors in baseline") {
+		t.Fatalf("each occurrence should be recorded even with -dedup: %q", stderr)
+	}
+
+	status, stdout, stderr := run("-baseline", baseline)
+	if status != ExitStatusSuccessNoProblem || stdout != "" {
+		t.Fatalf("all errors should be suppressed but got status %d: %q", status, stdout)
+	}
+	if !strings.Contains(stderr, "3 errors were suppressed by 3 entries in baseline") {
+		t.Fatalf("unexpected stderr: %q", stderr)
+	}
+
+	// Add new occurrences of the same error
+	src += "      - run: echo ${{ unknown }}\n      - run: echo ${{ unknown }}\n"
+	if err := ioutil.WriteFile(wf, []byte(src), 0644); err != nil {
+		panic(err)
+	}
+
+	status, stdout, _ = run("-baseline", baseline)
+	if status != ExitStatusSuccessProblemFound {
+		t.Fatalf("exit status should be %d but got %d", ExitStatusSuccessProblemFound, status)
+	}
+	if strings.Count(stdout, "\n") != 1 || !strings.Contains(stdout, "(occurred 2 times)") {
+		t.Fatalf("only the new errors should be reported as one error: %q", stdout)
+	}
+}
+
+func TestCommandProfile(t *testing.T) {
+	dir := t.TempDir()
+	cpu := filepath.Join(dir, "cpu.prof")
+	mem := filepath.Join(dir, "mem.prof")
+
+	var out bytes.Buffer
+	cmd := Command{
+		Stdin:  strings.NewReader("on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n"),
+		Stdout: &out,
+		Stderr: &out,
+	}
+	args := []string{"actionlint", "-oneline", "-shellcheck=", "-pyflakes=", "-cpuprofile", cpu, "-memprofile", mem, "-"}
+	if status := cmd.Main(args); status != ExitStatusSuccessProblemFound {
+		t.Fatalf("profiling should not change exit status but got %d: %q", status, out.String())
+	}
+	if !strings.Contains(out.String(), `"steps" section is missing in job "test"`) {
+		t.Fatalf("profiling should not change errors: %q", out.String())
+	}
+
+	for _, f := range []string{cpu, mem} {
+		s, err := os.Stat(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s.Size() == 0 {
+			t.Errorf("profile %q is empty", f)
+		}
+	}
+
+	out.Reset()
+	args = []string{"actionlint", "-cpuprofile", filepath.Join(dir, "does-not-exist", "cpu.prof"), "-"}
+	if status := cmd.Main(args); status != ExitStatusInvalidCommandOption {
+		t.Fatalf("wanted exit status %d but got %d: %q", ExitStatusInvalidCommandOption, status, out.String())
+	}
+}
@@ -16,6 +16,31 @@ type Config struct {
 		// Labels is label names for self-hosted runner.
 		Labels []string `yaml:"labels"`
 	} `yaml:"self-hosted-runner"`
+	// Schedule is configuration for schedule event.
+	Schedule struct {
+		// SuspiciousHours is hours (0-23) in UTC which are suspicious for cron schedules. Schedules
+		// running at these hours are reported since they are likely written in local time.
+		SuspiciousHours []int `yaml:"suspicious-hours"`
+	} `yaml:"schedule"`
+	// Environments is names of environments in the repository. 'environment' of jobs are checked
+	// with this list.
+	Environments []string `yaml:"environments"`
+	// DefaultBranch is a name of the default branch of the repository. It is used by optional
+	// "default-branch" rule.
+	DefaultBranch string `yaml:"default-branch"`
+	// EnableRules is names of rules to enable. Only rules which are disabled by default can be
+	// specified.
+	EnableRules []string `yaml:"enable-rules"`
+	// Extends is names of presets to apply. Rules enabled by the presets are enabled in addition to
+	// EnableRules. Severities set by the presets are overridden by Rules.
+	Extends []string `yaml:"extends"`
+	// Presets is a mapping from names of custom presets to their definitions. Custom presets can be
+	// selected with Extends or -preset flag. They take precedence over built-in presets with the same
+	// names.
+	Presets map[string]*Preset `yaml:"presets"`
+	// Rules is a mapping from names of rules to severities of their errors. The severity is
+	// "error" or "warning". It overrides the default severities of the rules.
+	Rules map[string]string `yaml:"rules"`
 }
 
 func parseConfig(b []byte, path string) (*Config, error) {
@@ -24,6 +49,47 @@ func parseConfig(b []byte, path string) (*Config, error) {
 		msg := strings.ReplaceAll(err.Error(), "\n", " ")
 		return nil, fmt.Errorf("could not parse config file %q: %s", path, msg)
 	}
+	for _, r := range c.EnableRules {
+		if err := checkOptionalRuleName(r, "enable"); err != nil {
+			return nil, fmt.Errorf("invalid \"enable-rules\" in config file %q: %w", path, err)
+		}
+	}
+	for n, p := range c.Presets {
+		if p == nil {
+			return nil, fmt.Errorf("invalid preset %q at \"presets\" in config file %q: preset must be a mapping", n, path)
+		}
+		for _, r := range p.EnableRules {
+			if err := checkOptionalRuleName(r, "enable"); err != nil {
+				return nil, fmt.Errorf("invalid \"enable-rules\" of preset %q in config file %q: %w", n, path, err)
+			}
+		}
+		for _, r := range p.DisableRules {
+			if err := checkOptionalRuleName(r, "disable"); err != nil {
+				return nil, fmt.Errorf("invalid \"disable-rules\" of preset %q in config file %q: %w", n, path, err)
+			}
+		}
+		for r, s := range p.Rules {
+			if err := checkRuleName(r); err != nil {
+				return nil, fmt.Errorf("invalid \"rules\" of preset %q in config file %q: %w", n, path, err)
+			}
+			if err := checkSeverity(s); err != nil {
+				return nil, fmt.Errorf("invalid severity of rule %q at \"rules\" of preset %q in config file %q: %w", r, n, path, err)
+			}
+		}
+	}
+	for _, n := range c.Extends {
+		if _, err := lookupPreset(n, c.Presets); err != nil {
+			return nil, fmt.Errorf("invalid \"extends\" in config file %q: %w", path, err)
+		}
+	}
+	for n, s := range c.Rules {
+		if err := checkRuleName(n); err != nil {
+			return nil, fmt.Errorf("invalid \"rules\" in config file %q: %w", path, err)
+		}
+		if err := checkSeverity(s); err != nil {
+			return nil, fmt.Errorf("invalid severity of rule %q at \"rules\" in config file %q: %w", n, path, err)
+		}
+	}
 	return &c, nil
 }
 
@@ -39,6 +105,18 @@ func writeDefaultConfigFile(path string) error {
 	b := []byte(`self-hosted-runner:
   # Labels of self-hosted runner in array of string
   labels: []
+schedule:
+  # Hours (0-23) in UTC which are suspicious as scheduled time. For example, business hours in
+  # your local time. Schedules running at these hours are reported
+  suspicious-hours: []
+# Names of environments in your repository. Environments used by jobs are checked with them
+environments: []
+# Names of rules which are disabled by default to enable
+enable-rules: []
+# Names of presets to apply like "security" or "strict"
+extends: []
+# Severities of errors of rules. Severity is "error" or "warning"
+rules: {}
 `)
 	if err := ioutil.WriteFile(path, b, 0644); err != nil {
 		return fmt.Errorf("could not write default configuration file at %q: %w", path, err)
@@ -69,6 +69,138 @@ func TestConfigParseError(t *testing.T) {
 	}
 }
 
+func TestConfigParseUnknownRuleToEnable(t *testing.T) {
+	input := "enable-rules: [hash-files, unknown-rule]\n"
+	_, err := parseConfig([]byte(input), "/path/to/file.yml")
+	if err == nil {
+		t.Fatal("error did not occur")
+	}
+	msg := err.Error()
+	if !strings.Contains(msg, "unknown rule \"unknown-rule\" to enable") {
+		t.Fatalf("unexpected error message: %q", msg)
+	}
+}
+
+func TestConfigParsePresets(t *testing.T) {
+	input := `extends: [security, mine]
+presets:
+  mine:
+    enable-rules: [pipefail]
+    disable-rules: [secrets-in-args]
+    rules:
+      pipefail: error
+`
+	c, err := parseConfig([]byte(input), "/path/to/file.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(c.Extends, []string{"security", "mine"}) {
+		t.Fatal("unexpected extends:", c.Extends)
+	}
+	want := &Preset{
+		EnableRules:  []string{"pipefail"},
+		DisableRules: []string{"secrets-in-args"},
+		Rules:        map[string]string{"pipefail": SeverityError},
+	}
+	if p, ok := c.Presets["mine"]; !ok || !cmp.Equal(p, want) {
+		t.Fatal("unexpected presets:", c.Presets)
+	}
+}
+
+func TestConfigParsePresetsError(t *testing.T) {
+	testCases := []struct {
+		what  string
+		input string
+		want  string
+	}{
+		{
+			what:  "unknown preset at extends",
+			input: "extends: [secure]\n",
+			want:  `invalid "extends" in config file "/path/to/file.yml": unknown preset "secure"`,
+		},
+		{
+			what:  "unknown rule in custom preset",
+			input: "presets:\n  mine:\n    enable-rules: [unknown-rule]\n",
+			want:  `invalid "enable-rules" of preset "mine" in config file "/path/to/file.yml": unknown rule "unknown-rule" to enable`,
+		},
+		{
+			what:  "unknown rule to disable in custom preset",
+			input: "presets:\n  mine:\n    disable-rules: [expression]\n",
+			want:  `invalid "disable-rules" of preset "mine" in config file "/path/to/file.yml": unknown rule "expression" to disable`,
+		},
+		{
+			what:  "unknown rule of severity in custom preset",
+			input: "presets:\n  mine:\n    rules:\n      unknown-rule: error\n",
+			want:  `invalid "rules" of preset "mine" in config file "/path/to/file.yml": unknown rule "unknown-rule"`,
+		},
+		{
+			what:  "invalid severity in custom preset",
+			input: "presets:\n  mine:\n    rules:\n      pipefail: fatal\n",
+			want:  `invalid severity of rule "pipefail" at "rules" of preset "mine" in config file "/path/to/file.yml"`,
+		},
+		{
+			what:  "null preset",
+			input: "presets:\n  mine:\n",
+			want:  `invalid preset "mine" at "presets"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			_, err := parseConfig([]byte(tc.input), "/path/to/file.yml")
+			if err == nil {
+				t.Fatal("error did not occur")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("%q is not contained in error message %q", tc.want, err.Error())
+			}
+		})
+	}
+}
+
+func TestConfigParseRules(t *testing.T) {
+	input := "rules:\n  expression: error\n  pipefail: warning\n"
+	c, err := parseConfig([]byte(input), "/path/to/file.yml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"expression": SeverityError, "pipefail": SeverityWarning}
+	if !cmp.Equal(want, c.Rules) {
+		t.Fatal(cmp.Diff(want, c.Rules))
+	}
+}
+
+func TestConfigParseRulesError(t *testing.T) {
+	testCases := []struct {
+		what  string
+		input string
+		want  string
+	}{
+		{
+			what:  "unknown rule",
+			input: "rules:\n  expresion: error\n",
+			want:  `invalid "rules" in config file "/path/to/file.yml": unknown rule "expresion". did you mean "expression"?`,
+		},
+		{
+			what:  "unknown severity",
+			input: "rules:\n  expression: fatal\n",
+			want:  `invalid severity of rule "expression" at "rules" in config file "/path/to/file.yml": unknown severity "fatal"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			_, err := parseConfig([]byte(tc.input), "/path/to/file.yml")
+			if err == nil {
+				t.Fatal("error did not occur")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("%q is not contained in error message %q", tc.want, err.Error())
+			}
+		})
+	}
+}
+
 func TestConfigReadFileOK(t *testing.T) {
 	p := filepath.Join("testdata", "config", "ok.yml")
 	c, err := readConfigFile(p)
@@ -79,6 +211,18 @@ func TestConfigReadFileOK(t *testing.T) {
 	if !cmp.Equal(c.SelfHostedRunner.Labels, labels) {
 		t.Fatal(cmp.Diff(c.SelfHostedRunner.Labels, labels))
 	}
+	hours := []int{9, 10, 11}
+	if !cmp.Equal(c.Schedule.SuspiciousHours, hours) {
+		t.Fatal(cmp.Diff(c.Schedule.SuspiciousHours, hours))
+	}
+	rules := []string{"hash-files"}
+	if !cmp.Equal(c.EnableRules, rules) {
+		t.Fatal(cmp.Diff(c.EnableRules, rules))
+	}
+	envs := []string{"production", "staging"}
+	if !cmp.Equal(c.Environments, envs) {
+		t.Fatal(cmp.Diff(c.Environments, envs))
+	}
 }
 
 func TestConfigReadFileReadError(t *testing.T) {
@@ -0,0 +1,161 @@
+package actionlint
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Values of "package-ecosystem" in dependabot.yml.
+// https://docs.github.com/en/code-security/dependabot/dependabot-version-updates/configuration-options-for-the-dependabot.yml-file#package-ecosystem
+var dependabotPackageEcosystems = []string{
+	"bun",
+	"bundler",
+	"cargo",
+	"composer",
+	"devcontainers",
+	"docker",
+	"docker-compose",
+	"dotnet-sdk",
+	"elm",
+	"gitsubmodule",
+	"github-actions",
+	"gomod",
+	"gradle",
+	"helm",
+	"maven",
+	"mix",
+	"npm",
+	"nuget",
+	"pip",
+	"pub",
+	"swift",
+	"terraform",
+	"uv",
+}
+
+// Package managers which are not package ecosystems of Dependabot. They are covered by other
+// ecosystems.
+var dependabotEcosystemAliases = map[string]string{
+	"yarn":        "npm",
+	"pnpm":        "npm",
+	"pipenv":      "pip",
+	"poetry":      "pip",
+	"pip-compile": "pip",
+	"go":          "gomod",
+	"golang":      "gomod",
+	"actions":     "github-actions",
+	"git":         "gitsubmodule",
+}
+
+// Values of "schedule.interval" in dependabot.yml.
+// https://docs.github.com/en/code-security/dependabot/dependabot-version-updates/configuration-options-for-the-dependabot.yml-file#scheduleinterval
+var dependabotScheduleIntervals = []string{
+	"cron",
+	"daily",
+	"monthly",
+	"quarterly",
+	"semiannually",
+	"weekly",
+	"yearly",
+}
+
+// isDependabotConfigFile returns whether the file at the path is Dependabot configuration file
+// (.github/dependabot.yml) rather than workflow file.
+func isDependabotConfigFile(path string) bool {
+	b := filepath.Base(path)
+	if b != "dependabot.yml" && b != "dependabot.yaml" {
+		return false
+	}
+	return filepath.Base(filepath.Dir(path)) == ".github"
+}
+
+// checkDependabotConfig checks the source of Dependabot configuration file. Currently "version",
+// "package-ecosystem" and "schedule.interval" of each update are checked. Other keys are not
+// checked.
+// https://docs.github.com/en/code-security/dependabot/dependabot-version-updates/configuration-options-for-the-dependabot.yml-file
+func checkDependabotConfig(src []byte) []*Error {
+	var n yaml.Node
+	if err := yaml.Unmarshal(src, &n); err != nil {
+		return handleYAMLError(err)
+	}
+	if n.Kind != yaml.DocumentNode || len(n.Content) == 0 {
+		return []*Error{dependabotError(&n, "dependabot.yml is empty")}
+	}
+
+	root := n.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return []*Error{dependabotError(root, "top level of dependabot.yml must be mapping node but got %s node", nodeKindName(root.Kind))}
+	}
+
+	errs := []*Error{}
+	if v := mappingValueOf(root, "version"); v == nil {
+		errs = append(errs, dependabotError(root, "\"version\" is missing in dependabot.yml. it must be 2"))
+	} else if v.Kind != yaml.ScalarNode || v.Value != "2" {
+		errs = append(errs, dependabotError(v, "\"version\" of dependabot.yml must be 2 but got %q", v.Value))
+	}
+
+	updates := mappingValueOf(root, "updates")
+	if updates == nil {
+		errs = append(errs, dependabotError(root, "\"updates\" is missing in dependabot.yml"))
+		return errs
+	}
+	if updates.Kind != yaml.SequenceNode {
+		errs = append(errs, dependabotError(updates, "\"updates\" of dependabot.yml must be sequence node but got %s node", nodeKindName(updates.Kind)))
+		return errs
+	}
+
+	for _, u := range updates.Content {
+		if u.Kind != yaml.MappingNode {
+			errs = append(errs, dependabotError(u, "element of \"updates\" must be mapping node but got %s node", nodeKindName(u.Kind)))
+			continue
+		}
+		errs = append(errs, checkDependabotUpdate(u)...)
+	}
+
+	return errs
+}
+
+func checkDependabotUpdate(u *yaml.Node) []*Error {
+	errs := []*Error{}
+
+	if e := mappingValueOf(u, "package-ecosystem"); e == nil {
+		errs = append(errs, dependabotError(u, "\"package-ecosystem\" is missing in element of \"updates\""))
+	} else if !contains(dependabotPackageEcosystems, e.Value) {
+		hint := ""
+		if a, ok := dependabotEcosystemAliases[strings.ToLower(e.Value)]; ok {
+			hint = fmt.Sprintf(" use %q for %s.", a, e.Value)
+		} else if ss := findSimilarStrings(e.Value, dependabotPackageEcosystems); len(ss) > 0 {
+			hint = fmt.Sprintf(" did you mean %s?", sortedQuotes(ss))
+		}
+		errs = append(errs, dependabotError(e, "package ecosystem %q is unknown.%s available ecosystems are %s", e.Value, hint, sortedQuotes(dependabotPackageEcosystems)))
+	}
+
+	s := mappingValueOf(u, "schedule")
+	if s == nil {
+		errs = append(errs, dependabotError(u, "\"schedule\" is missing in element of \"updates\""))
+		return errs
+	}
+	if s.Kind != yaml.MappingNode {
+		errs = append(errs, dependabotError(s, "\"schedule\" must be mapping node but got %s node", nodeKindName(s.Kind)))
+		return errs
+	}
+	if i := mappingValueOf(s, "interval"); i == nil {
+		errs = append(errs, dependabotError(s, "\"interval\" is missing in \"schedule\""))
+	} else if !contains(dependabotScheduleIntervals, i.Value) {
+		errs = append(errs, dependabotError(i, "schedule interval %q is invalid. available intervals are %s", i.Value, sortedQuotes(dependabotScheduleIntervals)))
+	}
+
+	return errs
+}
+
+func dependabotError(n *yaml.Node, format string, args ...interface{}) *Error {
+	return &Error{
+		Message: fmt.Sprintf(format, args...),
+		Line:    n.Line,
+		Column:  n.Column,
+		Kind:    "dependabot",
+	}
+}
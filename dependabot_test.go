@@ -0,0 +1,114 @@
+package actionlint
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDependabotIsConfigFile(t *testing.T) {
+	testCases := []struct {
+		path string
+		want bool
+	}{
+		{filepath.Join(".github", "dependabot.yml"), true},
+		{filepath.Join("repo", ".github", "dependabot.yaml"), true},
+		{filepath.Join(".github", "workflows", "dependabot.yml"), false},
+		{"dependabot.yml", false},
+		{filepath.Join(".github", "actionlint.yml"), false},
+	}
+	for _, tc := range testCases {
+		if have := isDependabotConfigFile(tc.path); have != tc.want {
+			t.Errorf("wanted %v for %q but got %v", tc.want, tc.path, have)
+		}
+	}
+}
+
+func TestDependabotCheckConfigOK(t *testing.T) {
+	src := `version: 2
+updates:
+  - package-ecosystem: github-actions
+    directory: /
+    schedule:
+      interval: weekly
+      day: monday
+  - package-ecosystem: npm
+    directory: /web
+    schedule:
+      interval: cron
+      cronjob: "0 9 * * *"
+`
+	if errs := checkDependabotConfig([]byte(src)); len(errs) != 0 {
+		t.Fatalf("wanted no error but got %v", errs)
+	}
+}
+
+func TestDependabotCheckConfigError(t *testing.T) {
+	testCases := []struct {
+		what string
+		src  string
+		want []string
+	}{
+		{
+			what: "invalid version",
+			src:  "version: 1\nupdates: []\n",
+			want: []string{`1:10: "version" of dependabot.yml must be 2 but got "1"`},
+		},
+		{
+			what: "missing version and updates",
+			src:  "registries: {}\n",
+			want: []string{
+				`1:1: "version" is missing in dependabot.yml`,
+				`1:1: "updates" is missing in dependabot.yml`,
+			},
+		},
+		{
+			what: "updates is not sequence",
+			src:  "version: 2\nupdates:\n  package-ecosystem: npm\n",
+			want: []string{`3:3: "updates" of dependabot.yml must be sequence node but got mapping node`},
+		},
+		{
+			what: "typo in package ecosystem",
+			src:  "version: 2\nupdates:\n  - package-ecosystem: github-action\n    directory: /\n    schedule:\n      interval: daily\n",
+			want: []string{`3:24: package ecosystem "github-action" is unknown. did you mean "github-actions"?`},
+		},
+		{
+			what: "package manager instead of package ecosystem",
+			src:  "version: 2\nupdates:\n  - package-ecosystem: yarn\n    directory: /\n    schedule:\n      interval: daily\n",
+			want: []string{`3:24: package ecosystem "yarn" is unknown. use "npm" for yarn.`},
+		},
+		{
+			what: "invalid schedule interval",
+			src:  "version: 2\nupdates:\n  - package-ecosystem: npm\n    directory: /\n    schedule:\n      interval: hourly\n",
+			want: []string{`6:17: schedule interval "hourly" is invalid. available intervals are "cron", "daily"`},
+		},
+		{
+			what: "missing keys in update",
+			src:  "version: 2\nupdates:\n  - directory: /\n  - package-ecosystem: npm\n    schedule:\n      day: monday\n",
+			want: []string{
+				`3:5: "package-ecosystem" is missing in element of "updates"`,
+				`3:5: "schedule" is missing in element of "updates"`,
+				`6:7: "interval" is missing in "schedule"`,
+			},
+		},
+		{
+			what: "broken YAML",
+			src:  "version: 2\nupdates: [\n",
+			want: []string{`could not parse as YAML`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			errs := checkDependabotConfig([]byte(tc.src))
+			if len(errs) != len(tc.want) {
+				t.Fatalf("wanted %d errors but got %v", len(tc.want), errs)
+			}
+			for i, err := range errs {
+				if !strings.Contains(err.Error(), tc.want[i]) {
+					t.Errorf("error %q does not contain %q", err.Error(), tc.want[i])
+				}
+			}
+		})
+	}
+}
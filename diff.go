@@ -0,0 +1,210 @@
+package actionlint
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Number of unchanged lines shown around changes in unified diff.
+const diffContextLines = 3
+
+type diffOp int
+
+const (
+	diffOpEqual diffOp = iota
+	diffOpDelete
+	diffOpInsert
+)
+
+type diffLine struct {
+	op   diffOp
+	text string
+}
+
+// splitDiffLines splits the source into lines. The second return value is true when the last line
+// ends with a newline.
+func splitDiffLines(src []byte) ([]string, bool) {
+	s := string(src)
+	if s == "" {
+		return nil, true
+	}
+	eol := strings.HasSuffix(s, "\n")
+	if eol {
+		s = s[:len(s)-1]
+	}
+	return strings.Split(s, "\n"), eol
+}
+
+// diffLines computes the shortest edit script from the lines a to the lines b using the longest
+// common subsequence. Common prefix and suffix are skipped to keep the table small since edits by
+// fixes are usually small.
+func diffLines(a, b []string) []diffLine {
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+
+	ret := make([]diffLine, 0, len(a)+len(b))
+	for _, l := range a[:pre] {
+		ret = append(ret, diffLine{diffOpEqual, l})
+	}
+
+	ma, mb := a[pre:len(a)-suf], b[pre:len(b)-suf]
+	// lcs[i][j] is the length of the longest common subsequence of ma[i:] and mb[j:]
+	lcs := make([][]int, len(ma)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(mb)+1)
+	}
+	for i := len(ma) - 1; i >= 0; i-- {
+		for j := len(mb) - 1; j >= 0; j-- {
+			if ma[i] == mb[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(ma) || j < len(mb) {
+		switch {
+		case i < len(ma) && j < len(mb) && ma[i] == mb[j]:
+			ret = append(ret, diffLine{diffOpEqual, ma[i]})
+			i++
+			j++
+		case j == len(mb) || (i < len(ma) && lcs[i+1][j] >= lcs[i][j+1]):
+			ret = append(ret, diffLine{diffOpDelete, ma[i]})
+			i++
+		default:
+			ret = append(ret, diffLine{diffOpInsert, mb[j]})
+			j++
+		}
+	}
+
+	for _, l := range a[len(a)-suf:] {
+		ret = append(ret, diffLine{diffOpEqual, l})
+	}
+	return ret
+}
+
+// writeUnifiedDiff writes the difference between the sources of the file in unified diff format.
+// Nothing is written when the sources are the same. The output is colorized when colorful is true.
+func writeUnifiedDiff(out io.Writer, path string, before, after []byte, colorful bool) {
+	a, aEOL := splitDiffLines(before)
+	b, bEOL := splitDiffLines(after)
+	lines := diffLines(a, b)
+
+	// When newline at end of file is added or removed, the last line is changed
+	if aEOL != bEOL && len(lines) > 0 && lines[len(lines)-1].op == diffOpEqual {
+		last := lines[len(lines)-1].text
+		lines[len(lines)-1].op = diffOpDelete
+		lines = append(lines, diffLine{diffOpInsert, last})
+	}
+
+	changed := false
+	for _, l := range lines {
+		if l.op != diffOpEqual {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return
+	}
+
+	diffHeader := newErrorColor(color.Bold, colorful)
+	diffHunk := newErrorColor(color.FgCyan, colorful)
+	diffDelete := newErrorColor(color.FgRed, colorful)
+	diffInsert := newErrorColor(color.FgGreen, colorful)
+
+	diffHeader.Fprintf(out, "--- a/%s\n", path)
+	diffHeader.Fprintf(out, "+++ b/%s\n", path)
+
+	// Find ranges of hunks. Changes close to each other are merged into one hunk
+	type hunk struct{ start, end int }
+	hunks := []hunk{}
+	for idx, l := range lines {
+		if l.op == diffOpEqual {
+			continue
+		}
+		start, end := idx-diffContextLines, idx+diffContextLines+1
+		if start < 0 {
+			start = 0
+		}
+		if end > len(lines) {
+			end = len(lines)
+		}
+		if len(hunks) > 0 && start <= hunks[len(hunks)-1].end {
+			hunks[len(hunks)-1].end = end
+			continue
+		}
+		hunks = append(hunks, hunk{start, end})
+	}
+
+	// Line numbers in old and new sources at the head of each line
+	oldLine, newLine := make([]int, len(lines)), make([]int, len(lines))
+	o, n := 1, 1
+	lastOld, lastNew := -1, -1
+	for idx, l := range lines {
+		oldLine[idx], newLine[idx] = o, n
+		if l.op != diffOpInsert {
+			lastOld = idx
+			o++
+		}
+		if l.op != diffOpDelete {
+			lastNew = idx
+			n++
+		}
+	}
+
+	for _, h := range hunks {
+		oldCount, newCount := 0, 0
+		for _, l := range lines[h.start:h.end] {
+			if l.op != diffOpInsert {
+				oldCount++
+			}
+			if l.op != diffOpDelete {
+				newCount++
+			}
+		}
+		oldStart, newStart := oldLine[h.start], newLine[h.start]
+		// In unified diff format, start line of empty range is the line before the range
+		if oldCount == 0 {
+			oldStart--
+		}
+		if newCount == 0 {
+			newStart--
+		}
+		diffHunk.Fprintf(out, "@@ -%s +%s @@\n", diffRange(oldStart, oldCount), diffRange(newStart, newCount))
+
+		for idx := h.start; idx < h.end; idx++ {
+			l := lines[idx]
+			switch l.op {
+			case diffOpEqual:
+				fmt.Fprintf(out, " %s\n", l.text)
+			case diffOpDelete:
+				diffDelete.Fprintf(out, "-%s\n", l.text)
+			case diffOpInsert:
+				diffInsert.Fprintf(out, "+%s\n", l.text)
+			}
+			if (idx == lastOld && !aEOL && l.op != diffOpInsert) || (idx == lastNew && !bEOL && l.op != diffOpDelete) {
+				fmt.Fprintln(out, `\ No newline at end of file`)
+			}
+		}
+	}
+}
+
+func diffRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,74 @@
+package actionlint
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestDiffWriteUnifiedDiff(t *testing.T) {
+	testCases := []struct {
+		what   string
+		before string
+		after  string
+		want   string
+	}{
+		{
+			what:   "no change",
+			before: "a\nb\nc\n",
+			after:  "a\nb\nc\n",
+			want:   "",
+		},
+		{
+			what:   "replace line",
+			before: "a\nb\nc\n",
+			after:  "a\nB\nc\n",
+			want:   "--- a/test.yaml\n+++ b/test.yaml\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			what:   "insert line at top",
+			before: "a\nb\n",
+			after:  "x\na\nb\n",
+			want:   "--- a/test.yaml\n+++ b/test.yaml\n@@ -1,2 +1,3 @@\n+x\n a\n b\n",
+		},
+		{
+			what:   "insert line to empty file",
+			before: "",
+			after:  "x\n",
+			want:   "--- a/test.yaml\n+++ b/test.yaml\n@@ -0,0 +1 @@\n+x\n",
+		},
+		{
+			what:   "delete last line",
+			before: "a\nb\nc\n",
+			after:  "a\nb\n",
+			want:   "--- a/test.yaml\n+++ b/test.yaml\n@@ -1,3 +1,2 @@\n a\n b\n-c\n",
+		},
+		{
+			what:   "separate hunks",
+			before: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			after:  "one\n2\n3\n4\n5\n6\n7\n8\n9\nten\n",
+			want:   "--- a/test.yaml\n+++ b/test.yaml\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+ten\n",
+		},
+		{
+			what:   "no newline at end of file",
+			before: "a\nb",
+			after:  "a\nB",
+			want:   "--- a/test.yaml\n+++ b/test.yaml\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+B\n\\ No newline at end of file\n",
+		},
+		{
+			what:   "newline added at end of file",
+			before: "a\nb",
+			after:  "a\nb\n",
+			want:   "--- a/test.yaml\n+++ b/test.yaml\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			var b bytes.Buffer
+			writeUnifiedDiff(&b, "test.yaml", []byte(tc.before), []byte(tc.after), false)
+			if have := b.String(); have != tc.want {
+				t.Fatalf("wanted:\n%s\nbut got:\n%s", tc.want, have)
+			}
+		})
+	}
+}
@@ -6,18 +6,23 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"reflect"
+	"sort"
 	"strings"
 	"text/template"
+	"text/template/parse"
 
 	"github.com/fatih/color"
 	"github.com/mattn/go-runewidth"
 )
 
-var (
-	bold   = color.New(color.Bold)
-	green  = color.New(color.FgGreen)
-	yellow = color.New(color.FgYellow)
-	gray   = color.New(color.FgHiBlack)
+const (
+	// ErrorKindYAMLSyntax is a kind of errors caused by broken YAML syntax. Errors of this kind are
+	// reported when the input could not be parsed as YAML.
+	ErrorKindYAMLSyntax = "yaml-syntax"
+	// ErrorKindSyntaxCheck is a kind of errors caused by invalid workflow syntax. Errors of this
+	// kind are reported by parser, for example, on unexpected keys or missing required keys.
+	ErrorKindSyntaxCheck = "syntax-check"
 )
 
 // Error represents an error detected by actionlint rules
@@ -31,7 +36,20 @@ type Error struct {
 	// Column is a column number where the error occurred. This value is 1-based.
 	Column int
 	// Kind is a string to represent kind of the error. Usually rule name which found the error.
+	// Errors reported by parser have ErrorKindYAMLSyntax or ErrorKindSyntaxCheck kind. This value
+	// is stable so it can be used for filtering errors programmatically.
 	Kind string
+	// Fixes is a list of text edits to fix the error. This is empty when the error cannot be fixed
+	// mechanically.
+	Fixes []*TextEdit
+	// SeverityOverride is a severity of the error overridden by "rules" in config file or -error-on
+	// flag. This is empty when the severity is not overridden. Use Severity method to get the
+	// severity of the error.
+	SeverityOverride string
+	// DefaultSeverity is a severity of the error decided by the rule which reported it. Rules set
+	// SeverityWarning to errors which may be false positives. This is empty when the rule does not
+	// decide it. Use Severity method to get the severity of the error.
+	DefaultSeverity string
 }
 
 // Error returns summary of the error as string.
@@ -75,14 +93,36 @@ func (e *Error) GetTemplateFields(source []byte) *ErrorTemplateFields {
 		Line:     e.Line,
 		Column:   e.Column,
 		Kind:     e.Kind,
+		Severity: e.Severity(),
 		Snippet:  snippet,
+		Fixes:    e.Fixes,
 	}
 }
 
+// newErrorColor creates a color to print errors and diffs. The color is enabled or disabled by the
+// enabled parameter regardless of fatih/color.NoColor.
+func newErrorColor(attr color.Attribute, enabled bool) *color.Color {
+	c := color.New(attr)
+	if enabled {
+		c.EnableColor()
+	} else {
+		c.DisableColor()
+	}
+	return c
+}
+
 // PrettyPrint prints the error with user-friendly way. It prints file name, source position, error
-// message with colorful output and source snippet with indicator. When nil is set to source, no
-// source snippet is not printed. To disable colorful output, set true to fatih/color.NoColor.
-func (e *Error) PrettyPrint(w io.Writer, source []byte) {
+// message and source snippet with indicator in the same format as actionlint command. The source
+// parameter is the content of the file where the error occurred. When nil is set to source, no
+// source snippet is printed. When colorful is true, the output is colorized with ANSI escape
+// sequences. Tabs in the source line are kept in the indicator line so that the indicator is put
+// under the error position.
+func (e *Error) PrettyPrint(w io.Writer, source []byte, colorful bool) {
+	bold := newErrorColor(color.Bold, colorful)
+	green := newErrorColor(color.FgGreen, colorful)
+	yellow := newErrorColor(color.FgYellow, colorful)
+	gray := newErrorColor(color.FgHiBlack, colorful)
+
 	yellow.Fprint(w, e.Filepath)
 	gray.Fprint(w, ":")
 	fmt.Fprint(w, e.Line)
@@ -146,9 +186,19 @@ func (e *Error) getIndicator(line string) string {
 		uw-- // Decrement for place for '^'
 	}
 
-	// Count width of spaces before '^'
-	sw := runewidth.StringWidth(line[:start])
-	return fmt.Sprintf("%s^%s", strings.Repeat(" ", sw), strings.Repeat("~", uw))
+	// Put spaces before '^'. Tabs are kept as-is since the width of tab depends on where it is
+	// displayed. The indicator line has the prefix with the same width as the source line.
+	var b strings.Builder
+	for _, c := range line[:start] {
+		if c == '\t' {
+			b.WriteRune(c)
+		} else {
+			b.WriteString(strings.Repeat(" ", runewidth.RuneWidth(c)))
+		}
+	}
+	b.WriteRune('^')
+	b.WriteString(strings.Repeat("~", uw))
+	return b.String()
 }
 
 // ByErrorPosition is type for sort.Interface. It sorts errors slice in line and column order.
@@ -169,6 +219,114 @@ func (by ByErrorPosition) Swap(i, j int) {
 	by[i], by[j] = by[j], by[i]
 }
 
+const (
+	// SeverityError is a severity of errors which are definitely mistakes in workflows.
+	SeverityError = "error"
+	// SeverityWarning is a severity of errors reported by optional rules. They are heuristic or
+	// opinionated so they may be false positives.
+	SeverityWarning = "warning"
+)
+
+// Severity returns severity of the error. When the severity is overridden by SeverityOverride, it
+// is returned. Next, DefaultSeverity is returned when the rule decided it. Otherwise errors reported
+// by optional rules, which are disabled by default, are SeverityWarning and other errors are
+// SeverityError.
+func (e *Error) Severity() string {
+	if e.SeverityOverride != "" {
+		return e.SeverityOverride
+	}
+	if e.DefaultSeverity != "" {
+		return e.DefaultSeverity
+	}
+	if _, ok := optionalRules[e.Kind]; ok {
+		return SeverityWarning
+	}
+	return SeverityError
+}
+
+func checkSeverity(s string) error {
+	if s == SeverityError || s == SeverityWarning {
+		return nil
+	}
+	return fmt.Errorf("unknown severity %q. severity must be %q or %q", s, SeverityError, SeverityWarning)
+}
+
+// ErrorLess is a comparator of errors to sort them. It returns true when the error a should be
+// placed before the error b.
+type ErrorLess func(a, b *Error) bool
+
+// ErrorLessByPosition compares errors by file paths, lines and columns.
+func ErrorLessByPosition(a, b *Error) bool {
+	if a.Filepath != b.Filepath {
+		return a.Filepath < b.Filepath
+	}
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	return a.Column < b.Column
+}
+
+// ErrorLessBySeverity compares errors by severities, file paths, lines and columns. Errors of
+// SeverityError are placed before errors of SeverityWarning.
+func ErrorLessBySeverity(a, b *Error) bool {
+	if sa, sb := a.Severity(), b.Severity(); sa != sb {
+		return sa == SeverityError
+	}
+	return ErrorLessByPosition(a, b)
+}
+
+// SortErrors sorts the errors with the comparator. The sort is stable so errors which are equal in
+// the comparator keep their original order.
+func SortErrors(errs []*Error, less ErrorLess) {
+	sort.SliceStable(errs, func(i, j int) bool {
+		return less(errs[i], errs[j])
+	})
+}
+
+// DedupErrors collapses duplicate errors into one. Errors which have the same kind, message and
+// position are collapsed into the first one. Errors which have the same kind and message at
+// different positions are collapsed into the first occurrence and the number of the occurrences is
+// added to its message. Fixes of the collapsed errors are moved to the first occurrence. The errors
+// should be sorted by their positions in advance so that the first occurrence is kept.
+func DedupErrors(errs []*Error) []*Error {
+	type key struct {
+		kind string
+		msg  string
+	}
+	type pos struct {
+		line int
+		col  int
+	}
+
+	firsts := map[key]*Error{}
+	poses := map[key]map[pos]struct{}{}
+	deduped := make([]*Error, 0, len(errs))
+	for _, err := range errs {
+		k := key{err.Kind, err.Message}
+		p := pos{err.Line, err.Column}
+		first, ok := firsts[k]
+		if !ok {
+			firsts[k] = err
+			poses[k] = map[pos]struct{}{p: {}}
+			deduped = append(deduped, err)
+			continue
+		}
+		if _, ok := poses[k][p]; ok {
+			continue // Identical error at the same position. Its fixes are the same as the first one
+		}
+		poses[k][p] = struct{}{}
+		first.Fixes = append(first.Fixes, err.Fixes...)
+	}
+
+	for k, first := range firsts {
+		if n := len(poses[k]); n > 1 {
+			first.Message = fmt.Sprintf("%s (occurred %d times)", first.Message, n)
+		}
+	}
+
+	return deduped
+}
+
 // ErrorTemplateFields holds all fields to format one error message.
 type ErrorTemplateFields struct {
 	// Message is error message body.
@@ -182,9 +340,14 @@ type ErrorTemplateFields struct {
 	Column int `json:"column"`
 	// Kind is a rule name the error belongs to.
 	Kind string `json:"kind"`
+	// Severity is a severity of the error. It is SeverityError or SeverityWarning.
+	Severity string `json:"severity"`
 	// Snippet is a code snippet and indicator to indicate where the error occurred.
 	// When encoding into JSON, this field may be omitted when the snippet is empty.
 	Snippet string `json:"snippet,omitempty"`
+	// Fixes is a list of text edits to fix the error. They are the same edits as ones applied by
+	// -fix flag. When encoding into JSON, this field may be omitted when the error cannot be fixed.
+	Fixes []*TextEdit `json:"fixes,omitempty"`
 }
 
 func unescapeBackslash(s string) string {
@@ -206,11 +369,73 @@ func unescapeBackslash(s string) string {
 // formatting error messages with -format option.
 type ErrorFormatter struct {
 	temp *template.Template
+	// streamable is true when errors of each file can be printed separately. It is false for custom
+	// templates since they may produce one document from all errors like JSON array.
+	streamable bool
+	// perError is true when the template formats one error like {{.Filepath}}:{{.Line}} instead of
+	// the sequence of errors.
+	perError bool
+}
+
+// ErrorFormatGitHubActions is a special format name to print errors as workflow commands of GitHub
+// Actions. Errors are shown as annotations on GitHub UI when actionlint runs on GitHub Actions.
+// Errors of SeverityWarning are printed as warning messages and other errors are printed as error
+// messages.
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#setting-an-error-message
+const ErrorFormatGitHubActions = "ghactions"
+
+const errorFormatGitHubActionsTemplate = `{{range $err := .}}::{{if eq $err.Severity "warning"}}warning{{else}}error{{end}} {{if $err.Filepath}}file={{ghactions_property $err.Filepath}},{{end}}line={{$err.Line}},col={{$err.Column}},title={{ghactions_property $err.Kind}}::{{ghactions_data $err.Message}}
+{{end}}`
+
+// ErrorFormatJSONL is a special format name to print errors as newline-delimited JSON (NDJSON). Each
+// error is printed as one JSON object in one line. The object has the same fields as elements of
+// the JSON array printed by {{json .}}. Nothing is printed when no error is found.
+// https://github.com/ndjson/ndjson-spec
+const ErrorFormatJSONL = "jsonl"
+
+const errorFormatJSONLTemplate = `{{range $err := .}}{{json $err}}{{end}}`
+
+// ErrorFormatSARIF is a special format name to print errors as SARIF 2.1.0 log. Each error is
+// printed as one result. The log can be uploaded to GitHub code scanning.
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+const ErrorFormatSARIF = "sarif"
+
+// ErrorFormatGroupedSARIF is a special format name to print errors as SARIF 2.1.0 log like
+// ErrorFormatSARIF. Errors which have the same rule ID, message and file are grouped into one result
+// with multiple locations.
+const ErrorFormatGroupedSARIF = "grouped-sarif"
+
+// https://github.com/actions/toolkit/blob/main/packages/core/src/command.ts
+func escapeGitHubActionsData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeGitHubActionsProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
 }
 
 // NewErrorFormatter creates new ErrorFormatter instance. Given format must contain at least one
-// {{ }} placeholder. Escaped characters like \n in the format string are unescaped.
+// {{ }} placeholder. Escaped characters like \n in the format string are unescaped. When the format
+// is ErrorFormatGitHubActions, the errors are formatted as workflow commands of GitHub Actions. When
+// the format is ErrorFormatJSONL, the errors are formatted as newline-delimited JSON. When the
+// format is ErrorFormatSARIF or ErrorFormatGroupedSARIF, the errors are formatted as SARIF log. When
+// the template accesses fields of dot at top level like {{.Filepath}}:{{.Line}}, it formats each error
+// separately. Otherwise it formats the sequence of errors. Error is returned when the template
+// accesses some field which ErrorTemplateFields does not have.
 func NewErrorFormatter(format string) (*ErrorFormatter, error) {
+	streamable := false
+	switch format {
+	case ErrorFormatGitHubActions:
+		format = errorFormatGitHubActionsTemplate
+		streamable = true // Each workflow command is printed in one line
+	case ErrorFormatJSONL:
+		format = errorFormatJSONLTemplate
+		streamable = true // Each JSON object is printed in one line
+	case ErrorFormatSARIF:
+		format = "{{sarif .}}" // SARIF log is one JSON document of all errors
+	case ErrorFormatGroupedSARIF:
+		format = "{{grouped_sarif .}}"
+	}
 	if !strings.Contains(format, "{{") {
 		return nil, fmt.Errorf("template to format error messages must contain at least one {{ }} placeholder: %s", format)
 	}
@@ -226,18 +451,145 @@ func NewErrorFormatter(format string) (*ErrorFormatter, error) {
 		"replace": func(s string, oldnew ...string) string {
 			return strings.NewReplacer(oldnew...).Replace(s)
 		},
+		"ghactions_data":     escapeGitHubActionsData,
+		"ghactions_property": escapeGitHubActionsProperty,
+		"sarif": func(errs []*ErrorTemplateFields) (string, error) {
+			return encodeSARIF(errs, false)
+		},
+		"grouped_sarif": func(errs []*ErrorTemplateFields) (string, error) {
+			return encodeSARIF(errs, true)
+		},
 	}
 	t, err := template.New("error formatter").Funcs(funcs).Parse(unescapeBackslash(format))
 	if err != nil {
 		return nil, fmt.Errorf("template %q to format error messages could not be parsed: %w", format, err)
 	}
-	return &ErrorFormatter{t}, nil
+
+	c := &errorTemplateChecker{}
+	c.checkList(t.Tree.Root, true)
+	if c.unknown != "" {
+		return nil, fmt.Errorf(
+			"template %q to format error messages accesses unknown field %q. available fields are %s",
+			format,
+			c.unknown,
+			sortedQuotes(errorTemplateFieldNames()),
+		)
+	}
+	if c.perError {
+		streamable = true // Each error is formatted separately
+	}
+
+	return &ErrorFormatter{t, streamable, c.perError}, nil
+}
+
+func errorTemplateFieldNames() []string {
+	ty := reflect.TypeOf(ErrorTemplateFields{})
+	names := make([]string, 0, ty.NumField())
+	for i := 0; i < ty.NumField(); i++ {
+		names = append(names, ty.Field(i).Name)
+	}
+	return names
+}
+
+// errorTemplateChecker checks fields accessed in the parsed template to format error messages.
+// Since all field accesses in the template are for ErrorTemplateFields or TextEdit in its Fixes,
+// unknown fields can be detected statically. When a field of dot is accessed at top level like {{.Filepath}}, the
+// template formats one error rather than the sequence of errors.
+type errorTemplateChecker struct {
+	perError bool
+	unknown  string
 }
 
-// Print formats the slice of template fields and prints it with given writer.
+func (c *errorTemplateChecker) checkFields(idents []string) {
+	if c.unknown != "" {
+		return
+	}
+	for _, i := range idents {
+		if _, ok := reflect.TypeOf(ErrorTemplateFields{}).FieldByName(i); ok {
+			continue
+		}
+		// Fields of elements of Fixes like {{range $f := $err.Fixes}}{{$f.NewText}}{{end}}
+		if _, ok := reflect.TypeOf(TextEdit{}).FieldByName(i); ok {
+			continue
+		}
+		c.unknown = i
+		return
+	}
+}
+
+// checkList checks nodes in the list. top is true when dot is the value passed to the template.
+func (c *errorTemplateChecker) checkList(l *parse.ListNode, top bool) {
+	if l == nil {
+		return
+	}
+	for _, n := range l.Nodes {
+		c.checkNode(n, top)
+	}
+}
+
+func (c *errorTemplateChecker) checkNode(n parse.Node, top bool) {
+	switch n := n.(type) {
+	case *parse.ActionNode:
+		c.checkNode(n.Pipe, top)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			for _, a := range cmd.Args {
+				c.checkNode(a, top)
+			}
+		}
+	case *parse.FieldNode:
+		if top {
+			c.perError = true
+		}
+		c.checkFields(n.Ident)
+	case *parse.VariableNode:
+		c.checkFields(n.Ident[1:]) // First element is variable name like $err
+	case *parse.ChainNode:
+		c.checkNode(n.Node, top)
+		c.checkFields(n.Field)
+	case *parse.IfNode:
+		c.checkNode(n.Pipe, top)
+		c.checkList(n.List, top)
+		c.checkList(n.ElseList, top)
+	case *parse.RangeNode:
+		c.checkNode(n.Pipe, top)
+		c.checkList(n.List, false)
+		c.checkList(n.ElseList, top)
+	case *parse.WithNode:
+		c.checkNode(n.Pipe, top)
+		c.checkList(n.List, false)
+		c.checkList(n.ElseList, top)
+	case *parse.ListNode:
+		c.checkList(n, top)
+	}
+}
+
+// Print formats the slice of template fields and prints it with given writer. When the template
+// formats one error like {{.Filepath}}:{{.Line}}, each error is formatted separately and printed
+// in one line. Newline is appended to the formatted text unless it ends with newline.
 func (f *ErrorFormatter) Print(out io.Writer, t []*ErrorTemplateFields) error {
-	if err := f.temp.Execute(out, t); err != nil {
-		return fmt.Errorf("could not format error messages: %w", err)
+	if !f.perError {
+		if err := f.temp.Execute(out, t); err != nil {
+			return fmt.Errorf("could not format error messages: %w", err)
+		}
+		return nil
+	}
+
+	var b strings.Builder
+	for _, e := range t {
+		b.Reset()
+		if err := f.temp.Execute(&b, e); err != nil {
+			return fmt.Errorf("could not format error message: %w", err)
+		}
+		if !strings.HasSuffix(b.String(), "\n") {
+			b.WriteByte('\n')
+		}
+		if _, err := io.WriteString(out, b.String()); err != nil {
+			return fmt.Errorf("could not format error message: %w", err)
+		}
 	}
 	return nil
 }
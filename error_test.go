@@ -200,6 +200,36 @@ func TestErrorPrettyPrint(t *testing.T) {
 1 | this is source
   | `,
 		},
+		{
+			message: "error in tab-indented line",
+			line:    1,
+			column:  3,
+			source:  "\t\tfoo: bar",
+			expected: "filename.txt:1:3: error in tab-indented line [kind]\n" +
+				"  |\n" +
+				"1 | \t\tfoo: bar\n" +
+				"  | \t\t^~~~",
+		},
+		{
+			message: "error after tabs and spaces",
+			line:    2,
+			column:  9,
+			source:  "jobs:\n\t  run:\techo",
+			expected: "filename.txt:2:9: error after tabs and spaces [kind]\n" +
+				"  |\n" +
+				"2 | \t  run:\techo\n" +
+				"  | \t      \t^~~~",
+		},
+		{
+			message: "error after wide characters and tab",
+			line:    1,
+			column:  8,
+			source:  "\u3042\u3044\t- foo",
+			expected: "filename.txt:1:8: error after wide characters and tab [kind]\n" +
+				"  |\n" +
+				"1 | \u3042\u3044\t- foo\n" +
+				"  |     \t^",
+		},
 		{
 			message:  "error at zero line and zero column",
 			line:     0,
@@ -215,7 +245,7 @@ func TestErrorPrettyPrint(t *testing.T) {
 			err.Filepath = "filename.txt"
 
 			var buf bytes.Buffer
-			err.PrettyPrint(&buf, []byte(tc.source))
+			err.PrettyPrint(&buf, []byte(tc.source), false)
 
 			out := buf.String()
 			want := tc.expected + "\n"
@@ -226,6 +256,35 @@ func TestErrorPrettyPrint(t *testing.T) {
 	}
 }
 
+func TestErrorPrettyPrintColorful(t *testing.T) {
+	saved := color.NoColor
+	color.NoColor = true // Colorful output should not depend on the global flag
+	defer func() { color.NoColor = saved }()
+
+	err := errorAt(&Pos{1, 6}, "kind", "message")
+	err.Filepath = "filename.txt"
+	src := []byte("this is source")
+
+	var buf bytes.Buffer
+	err.PrettyPrint(&buf, src, true)
+	out := buf.String()
+	if !strings.Contains(out, "\x1b[") {
+		t.Fatalf("output is not colorized: %q", out)
+	}
+	for _, s := range []string{"filename.txt", "message", "this is source", "^~"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("output %q does not contain %q", out, s)
+		}
+	}
+
+	buf.Reset()
+	color.NoColor = false
+	err.PrettyPrint(&buf, src, false)
+	if out := buf.String(); strings.Contains(out, "\x1b[") {
+		t.Fatalf("output should not be colorized: %q", out)
+	}
+}
+
 func TestErrorSortByErrorPosition(t *testing.T) {
 	testCases := [][]struct {
 		line int
@@ -271,12 +330,84 @@ func TestErrorSortByErrorPosition(t *testing.T) {
 	}
 }
 
+func TestErrorSeverity(t *testing.T) {
+	for kind, want := range map[string]string{
+		"expression":          SeverityError,
+		ErrorKindSyntaxCheck:  SeverityError,
+		"post-parse":          SeverityError,
+		"pipefail":            SeverityWarning,
+		"action-permissions":  SeverityWarning,
+		"unknown-custom-kind": SeverityError,
+	} {
+		e := &Error{Kind: kind}
+		if have := e.Severity(); have != want {
+			t.Errorf("wanted severity %q for kind %q but got %q", want, kind, have)
+		}
+	}
+}
+
+func TestErrorSeverityOverride(t *testing.T) {
+	e := &Error{Kind: "pipefail", SeverityOverride: SeverityError}
+	if have := e.Severity(); have != SeverityError {
+		t.Errorf("wanted severity %q but got %q", SeverityError, have)
+	}
+	e = &Error{Kind: "expression", SeverityOverride: SeverityWarning}
+	if have := e.Severity(); have != SeverityWarning {
+		t.Errorf("wanted severity %q but got %q", SeverityWarning, have)
+	}
+	e = &Error{Kind: "expression", DefaultSeverity: SeverityWarning}
+	if have := e.Severity(); have != SeverityWarning {
+		t.Errorf("wanted severity %q but got %q", SeverityWarning, have)
+	}
+	e = &Error{Kind: "expression", DefaultSeverity: SeverityWarning, SeverityOverride: SeverityError}
+	if have := e.Severity(); have != SeverityError {
+		t.Errorf("wanted severity %q but got %q", SeverityError, have)
+	}
+}
+
+func TestErrorSortErrors(t *testing.T) {
+	errs := []*Error{
+		{Filepath: "b.yaml", Line: 1, Column: 1, Kind: "pipefail", Message: "1"},
+		{Filepath: "b.yaml", Line: 3, Column: 1, Kind: "expression", Message: "2"},
+		{Filepath: "a.yaml", Line: 5, Column: 2, Kind: "job-name", Message: "3"},
+		{Filepath: "b.yaml", Line: 2, Column: 4, Kind: "expression", Message: "4"},
+		{Filepath: "a.yaml", Line: 5, Column: 2, Kind: "expression", Message: "5"},
+		{Filepath: "a.yaml", Line: 5, Column: 1, Kind: "expression", Message: "6"},
+		{Filepath: "a.yaml", Line: 5, Column: 2, Kind: "syntax-check", Message: "7"},
+	}
+
+	testCases := []struct {
+		what string
+		less ErrorLess
+		want []string
+	}{
+		{"position", ErrorLessByPosition, []string{"6", "3", "5", "7", "1", "4", "2"}},
+		{"severity", ErrorLessBySeverity, []string{"6", "5", "7", "4", "2", "3", "1"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			sorted := append([]*Error{}, errs...)
+			SortErrors(sorted, tc.less)
+			have := make([]string, 0, len(sorted))
+			for _, e := range sorted {
+				have = append(have, e.Message)
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
 func TestErrorGetTemplateFields(t *testing.T) {
 	testCases := []struct {
-		message string
-		column  int
-		source  string
-		snippet string
+		message  string
+		kind     string
+		column   int
+		source   string
+		snippet  string
+		severity string
 	}{
 		{
 			message: "simple message with source",
@@ -295,11 +426,25 @@ func TestErrorGetTemplateFields(t *testing.T) {
 			source:  "this is source",
 			snippet: "this is source",
 		},
+		{
+			message:  "error from optional rule",
+			kind:     "deprecated-commands",
+			column:   1,
+			severity: SeverityWarning,
+		},
 	}
 
 	for i, tc := range testCases {
 		t.Run(strconv.Itoa(i), func(t *testing.T) {
-			err := errorAt(&Pos{1, tc.column}, "kind", tc.message)
+			kind := tc.kind
+			if kind == "" {
+				kind = "kind"
+			}
+			sev := tc.severity
+			if sev == "" {
+				sev = SeverityError
+			}
+			err := errorAt(&Pos{1, tc.column}, kind, tc.message)
 			err.Filepath = "filename.txt"
 			f := err.GetTemplateFields([]byte(tc.source))
 			if f.Message != tc.message {
@@ -320,6 +465,9 @@ func TestErrorGetTemplateFields(t *testing.T) {
 			if f.Snippet != tc.snippet {
 				t.Fatalf("wanted %q but have %q", tc.snippet, f.Snippet)
 			}
+			if f.Severity != sev {
+				t.Fatalf("wanted %q but have %q", sev, f.Severity)
+			}
 		})
 	}
 }
@@ -332,6 +480,7 @@ var testErrorTemplateFields = []*ErrorTemplateFields{
 		Column:   2,
 		Snippet:  "snippet 1",
 		Kind:     "kind1",
+		Severity: SeverityError,
 	},
 	{
 		Message:  "message 2",
@@ -340,6 +489,10 @@ var testErrorTemplateFields = []*ErrorTemplateFields{
 		Column:   4,
 		Snippet:  "snippet 2",
 		Kind:     "kind2",
+		Severity: SeverityWarning,
+		Fixes: []*TextEdit{
+			{Line: 3, Column: 4, EndLine: 3, EndColumn: 8, NewText: "fixed"},
+		},
 	},
 }
 
@@ -364,6 +517,10 @@ func TestErrorPrintFormattedWithTemplateFields(t *testing.T) {
 			temp: "{{range $ = .}}{{replace $.Kind \"kind\" \"king\"}}{{end}}",
 			want: "king1king2",
 		},
+		{
+			temp: "{{range $ = .}}{{range $f := $.Fixes}}{{$f.Line}}:{{$f.Column}}-{{$f.EndLine}}:{{$f.EndColumn}} {{$f.NewText}}{{end}}{{end}}",
+			want: "3:4-3:8 fixed",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -384,6 +541,66 @@ func TestErrorPrintFormattedWithTemplateFields(t *testing.T) {
 	}
 }
 
+func TestErrorPrintFormattedEachError(t *testing.T) {
+	testCases := []struct {
+		temp string
+		want string
+	}{
+		{
+			temp: "{{.Filepath}}:{{.Line}}:{{.Column}}: {{.Message}} [{{.Kind}}]",
+			want: "file1:1:2: message 1 [kind1]\nfile2:3:4: message 2 [kind2]\n",
+		},
+		{
+			temp: "{{.Line}}\\n",
+			want: "1\n3\n",
+		},
+		{
+			temp: "{{if eq .Kind \"kind2\"}}{{json .Snippet}}{{else}}{{.Kind}}{{end}}",
+			want: "kind1\n\"snippet 2\"\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.temp, func(t *testing.T) {
+			f, err := NewErrorFormatter(tc.temp)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var b strings.Builder
+			if err := f.Print(&b, testErrorTemplateFields); err != nil {
+				t.Fatal(err)
+			}
+			if !f.streamable {
+				t.Fatal("errors should be streamable when each error is formatted separately")
+			}
+			have := b.String()
+			if tc.want != have {
+				t.Fatalf("wanted %q but have %q", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestErrorFormatterSequenceOfErrors(t *testing.T) {
+	// Templates which don't access fields of dot at top level format the sequence of errors
+	for _, temp := range []string{
+		"{{json .}}",
+		"{{range .}}{{.Line}}{{end}}",
+		"{{with index . 0}}{{.Line}}{{end}}",
+		"{{(index . 0).Line}}",
+		ErrorFormatGitHubActions,
+		ErrorFormatJSONL,
+	} {
+		f, err := NewErrorFormatter(temp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if f.perError {
+			t.Errorf("template %q should format the sequence of errors", temp)
+		}
+	}
+}
+
 func TestErrorPrintFormattedErrors(t *testing.T) {
 	errs := []*Error{
 		errorAt(&Pos{1, 1}, "kind1", "error1"),
@@ -424,6 +641,94 @@ func TestErrorPrintSerializedIntoJSON(t *testing.T) {
 	}
 }
 
+func TestErrorFormatterGitHubActions(t *testing.T) {
+	f, err := NewErrorFormatter(ErrorFormatGitHubActions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := []*ErrorTemplateFields{
+		{
+			Message:  "message 1",
+			Filepath: ".github/workflows/ci.yaml",
+			Line:     1,
+			Column:   2,
+			Kind:     "kind1",
+			Severity: SeverityError,
+		},
+		{
+			Message:  "100% multi\nline\r\nmessage",
+			Filepath: "dir,with:special%chars/ci.yaml",
+			Line:     3,
+			Column:   4,
+			Kind:     "kind2",
+			Severity: SeverityError,
+		},
+		{
+			Message:  "from optional rule",
+			Filepath: ".github/workflows/ci.yaml",
+			Line:     5,
+			Column:   6,
+			Kind:     "kind3",
+			Severity: SeverityWarning,
+		},
+		{
+			Message: "from stdin",
+			Line:    7,
+			Column:  8,
+			Kind:    "kind4",
+		},
+	}
+
+	var b strings.Builder
+	if err := f.Print(&b, fields); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `::error file=.github/workflows/ci.yaml,line=1,col=2,title=kind1::message 1
+::error file=dir%2Cwith%3Aspecial%25chars/ci.yaml,line=3,col=4,title=kind2::100%25 multi%0Aline%0D%0Amessage
+::warning file=.github/workflows/ci.yaml,line=5,col=6,title=kind3::from optional rule
+::error line=7,col=8,title=kind4::from stdin
+`
+	if have := b.String(); have != want {
+		t.Fatalf("wanted %q but have %q", want, have)
+	}
+}
+
+func TestErrorFormatterJSONL(t *testing.T) {
+	f, err := NewErrorFormatter(ErrorFormatJSONL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	if err := f.Print(&b, testErrorTemplateFields); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != len(testErrorTemplateFields) {
+		t.Fatalf("wanted %d lines but got %d lines: %q", len(testErrorTemplateFields), len(lines), b.String())
+	}
+	for i, l := range lines {
+		var decoded ErrorTemplateFields
+		if err := json.Unmarshal([]byte(l), &decoded); err != nil {
+			t.Fatalf("line %d is not a valid JSON object: %s: %q", i+1, err, l)
+		}
+		if !cmp.Equal(testErrorTemplateFields[i], &decoded) {
+			t.Fatal(cmp.Diff(testErrorTemplateFields[i], &decoded))
+		}
+	}
+
+	b.Reset()
+	if err := f.Print(&b, []*ErrorTemplateFields{}); err != nil {
+		t.Fatal(err)
+	}
+	if b.Len() != 0 {
+		t.Fatalf("nothing should be printed when no error was found but got %q", b.String())
+	}
+}
+
 func TestErrorNewErrorFormatterError(t *testing.T) {
 	testCases := []struct {
 		temp string
@@ -431,6 +736,11 @@ func TestErrorNewErrorFormatterError(t *testing.T) {
 	}{
 		{"hello", "template to format error messages must contain at least one {{ }} placeholder"},
 		{"{{xxx", "template \"{{xxx\" to format error messages could not be parsed"},
+		{"{{.Foo}}", "template \"{{.Foo}}\" to format error messages accesses unknown field \"Foo\". available fields are \"Column\", \"Filepath\""},
+		{"{{range $err := .}}{{$err.Lines}}{{end}}", "accesses unknown field \"Lines\""},
+		{"{{range .}}{{if .Line}}{{.Msg}}{{end}}{{end}}", "accesses unknown field \"Msg\""},
+		{"{{(index . 0).Kinds}}", "accesses unknown field \"Kinds\""},
+		{"{{with index . 0}}{{.Message.Length}}{{end}}", "accesses unknown field \"Length\""},
 	}
 
 	for _, tc := range testCases {
@@ -452,7 +762,8 @@ func TestErrorFormatterPrintError(t *testing.T) {
 		temp string
 		want string
 	}{
-		{ioutil.Discard, "{{.Foo}}", "can't evaluate field Foo in type"},
+		{ioutil.Discard, "{{(index . 2).Message}}", "index out of range"},
+		{testErrorWriter{}, "{{.Message}}", "dummy write error"},
 		{testErrorWriter{}, "{{(index . 0).Message}}", "dummy write error"},
 	}
 
@@ -488,3 +799,45 @@ func TestErrorFormatterPrintJSONEncodeError(t *testing.T) {
 		t.Fatalf("%q is not contained in error message %q", want, err.Error())
 	}
 }
+
+func TestErrorDedupErrors(t *testing.T) {
+	edit := func(line int) *TextEdit {
+		return &TextEdit{Line: line, Column: 1, EndLine: line, EndColumn: 2, NewText: "x"}
+	}
+	errs := []*Error{
+		{Message: "type mismatch", Line: 1, Column: 3, Kind: "expression"},
+		{Message: "type mismatch", Line: 1, Column: 3, Kind: "expression"},
+		{Message: "other error", Line: 2, Column: 1, Kind: "expression", Fixes: []*TextEdit{edit(2)}},
+		{Message: "type mismatch", Line: 3, Column: 3, Kind: "expression"},
+		{Message: "type mismatch", Line: 4, Column: 3, Kind: "syntax-check"},
+		{Message: "other error", Line: 5, Column: 1, Kind: "expression", Fixes: []*TextEdit{edit(5)}},
+		{Message: "type mismatch", Line: 6, Column: 3, Kind: "expression"},
+	}
+
+	want := []*Error{
+		{Message: "type mismatch (occurred 3 times)", Line: 1, Column: 3, Kind: "expression"},
+		{Message: "other error (occurred 2 times)", Line: 2, Column: 1, Kind: "expression", Fixes: []*TextEdit{edit(2), edit(5)}},
+		{Message: "type mismatch", Line: 4, Column: 3, Kind: "syntax-check"},
+	}
+
+	have := DedupErrors(errs)
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestErrorDedupErrorsNoDuplicate(t *testing.T) {
+	errs := []*Error{
+		{Message: "error 1", Line: 1, Column: 3, Kind: "expression"},
+		{Message: "error 2", Line: 1, Column: 3, Kind: "expression"},
+		{Message: "error 1", Line: 1, Column: 3, Kind: "other"},
+	}
+	want := []*Error{
+		{Message: "error 1", Line: 1, Column: 3, Kind: "expression"},
+		{Message: "error 2", Line: 1, Column: 3, Kind: "expression"},
+		{Message: "error 1", Line: 1, Column: 3, Kind: "other"},
+	}
+	if diff := cmp.Diff(want, DedupErrors(errs)); diff != "" {
+		t.Fatal(diff)
+	}
+}
@@ -0,0 +1,133 @@
+package actionlint
+
+// Properties included in all webhook event payloads.
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads
+func newEventPayloadType(props map[string]ExprType) *ObjectType {
+	ty := NewStrictObjectType(map[string]ExprType{
+		"enterprise":   NewEmptyObjectType(),
+		"installation": NewEmptyObjectType(),
+		"organization": NewEmptyObjectType(),
+		"repository":   NewEmptyObjectType(),
+		"sender":       NewEmptyObjectType(),
+	})
+	for n, t := range props {
+		ty.Props[n] = t
+	}
+	return ty
+}
+
+func newPullRequestEventPayloadType() *ObjectType {
+	return newEventPayloadType(map[string]ExprType{
+		"action":             StringType{},
+		"after":              StringType{},
+		"assignee":           NewEmptyObjectType(),
+		"before":             StringType{},
+		"changes":            NewEmptyObjectType(),
+		"label":              NewEmptyObjectType(),
+		"number":             NumberType{},
+		"pull_request":       NewEmptyObjectType(),
+		"reason":             StringType{},
+		"requested_reviewer": NewEmptyObjectType(),
+		"requested_team":     NewEmptyObjectType(),
+	})
+}
+
+// BuiltinEventPayloadTypes defines types of 'github.event' payloads of well-known events. Keys are
+// event names. Only top-level properties of the payloads are strictly typed since their nested
+// objects are large and differ by activity types. Events which are not in this map have untyped
+// payloads.
+// https://docs.github.com/en/webhooks/webhook-events-and-payloads
+var BuiltinEventPayloadTypes = map[string]*ObjectType{
+	"create": newEventPayloadType(map[string]ExprType{
+		"description":   StringType{},
+		"master_branch": StringType{},
+		"pusher_type":   StringType{},
+		"ref":           StringType{},
+		"ref_type":      StringType{},
+	}),
+	"delete": newEventPayloadType(map[string]ExprType{
+		"pusher_type": StringType{},
+		"ref":         StringType{},
+		"ref_type":    StringType{},
+	}),
+	"issue_comment": newEventPayloadType(map[string]ExprType{
+		"action":  StringType{},
+		"changes": NewEmptyObjectType(),
+		"comment": NewEmptyObjectType(),
+		"issue":   NewEmptyObjectType(),
+	}),
+	"issues": newEventPayloadType(map[string]ExprType{
+		"action":    StringType{},
+		"assignee":  NewEmptyObjectType(),
+		"changes":   NewEmptyObjectType(),
+		"issue":     NewEmptyObjectType(),
+		"label":     NewEmptyObjectType(),
+		"milestone": NewEmptyObjectType(),
+	}),
+	"merge_group": newEventPayloadType(map[string]ExprType{
+		"action":      StringType{},
+		"merge_group": NewEmptyObjectType(),
+	}),
+	"pull_request": newPullRequestEventPayloadType(),
+	"pull_request_review": newEventPayloadType(map[string]ExprType{
+		"action":       StringType{},
+		"changes":      NewEmptyObjectType(),
+		"pull_request": NewEmptyObjectType(),
+		"review":       NewEmptyObjectType(),
+	}),
+	"pull_request_review_comment": newEventPayloadType(map[string]ExprType{
+		"action":       StringType{},
+		"changes":      NewEmptyObjectType(),
+		"comment":      NewEmptyObjectType(),
+		"pull_request": NewEmptyObjectType(),
+	}),
+	"pull_request_target": newPullRequestEventPayloadType(),
+	"push": newEventPayloadType(map[string]ExprType{
+		"after":       StringType{},
+		"base_ref":    StringType{},
+		"before":      StringType{},
+		"commits":     &ArrayType{Elem: NewEmptyObjectType()},
+		"compare":     StringType{},
+		"created":     BoolType{},
+		"deleted":     BoolType{},
+		"forced":      BoolType{},
+		"head_commit": NewEmptyObjectType(),
+		"pusher":      NewEmptyObjectType(),
+		"ref":         StringType{},
+	}),
+	"release": newEventPayloadType(map[string]ExprType{
+		"action":  StringType{},
+		"changes": NewEmptyObjectType(),
+		"release": NewEmptyObjectType(),
+	}),
+	"repository_dispatch": newEventPayloadType(map[string]ExprType{
+		"action":         StringType{},
+		"branch":         StringType{},
+		"client_payload": NewEmptyObjectType(),
+	}),
+	"workflow_dispatch": newEventPayloadType(map[string]ExprType{
+		"inputs":   NewEmptyObjectType(),
+		"ref":      StringType{},
+		"workflow": StringType{},
+	}),
+	"workflow_run": newEventPayloadType(map[string]ExprType{
+		"action":       StringType{},
+		"workflow":     NewEmptyObjectType(),
+		"workflow_run": NewEmptyObjectType(),
+	}),
+}
+
+// eventPayloadType returns the name of the event and the type of 'github.event' payload of the
+// workflow triggered by the events. The payload is typed only when the workflow is triggered by
+// exactly one well-known event. nil is returned when the events are mixed or unknown.
+func eventPayloadType(events []Event) (string, *ObjectType) {
+	if len(events) != 1 {
+		return "", nil
+	}
+	n := events[0].EventName()
+	ty, ok := BuiltinEventPayloadTypes[n]
+	if !ok {
+		return "", nil
+	}
+	return n, ty
+}
@@ -1,5 +1,7 @@
 package actionlint
 
+import "strings"
+
 // ExprNode is a node of expression syntax tree. To know the syntax, see
 // https://docs.github.com/en/actions/learn-github-actions/expressions
 type ExprNode interface {
@@ -88,8 +90,10 @@ func (n *StringNode) Token() *Token {
 type ObjectDerefNode struct {
 	// Receiver is an expression at receiver of property dereference.
 	Receiver ExprNode
-	// Property is a name of property to access.
+	// Property is a name of property to access. It is lower-cased since property names are case
+	// insensitive.
 	Property string
+	orig     string // Property name in its original case
 }
 
 // Token returns the first token of the node. This method is useful to get position of this node.
@@ -262,3 +266,21 @@ func visitExprNode(n, p ExprNode, f VisitExprNodeFunc) {
 func VisitExprNode(n ExprNode, f VisitExprNodeFunc) {
 	visitExprNode(n, nil, f)
 }
+
+// contextProperty returns the name of the property when the node accesses a property of the
+// context like inputs.foo or inputs['foo'].
+func contextProperty(n ExprNode, ctx string) (string, bool) {
+	switch n := n.(type) {
+	case *ObjectDerefNode:
+		if v, ok := n.Receiver.(*VariableNode); ok && strings.EqualFold(v.Name, ctx) {
+			return n.Property, true
+		}
+	case *IndexAccessNode:
+		if v, ok := n.Operand.(*VariableNode); ok && strings.EqualFold(v.Name, ctx) {
+			if s, ok := n.Index.(*StringNode); ok {
+				return s.Value, true
+			}
+		}
+	}
+	return "", false
+}
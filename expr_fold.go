@@ -0,0 +1,128 @@
+package actionlint
+
+import (
+	"strconv"
+	"strings"
+)
+
+// literalToString converts the literal node to string in the same manner as GitHub Actions coerces
+// values to strings. The second return value is false when the node is not a literal.
+// https://docs.github.com/en/actions/learn-github-actions/expressions#literals
+func literalToString(n ExprNode) (string, bool) {
+	switch n := n.(type) {
+	case *StringNode:
+		return n.Value, true
+	case *IntNode:
+		return strconv.Itoa(n.Value), true
+	case *FloatNode:
+		return strconv.FormatFloat(n.Value, 'f', -1, 64), true
+	case *BoolNode:
+		if n.Value {
+			return "true", true
+		}
+		return "false", true
+	case *NullNode:
+		return "", true
+	default:
+		return "", false
+	}
+}
+
+// foldConstantFuncCall evaluates the call of string built-in function contains(), startsWith(),
+// endsWith() or format() when all its arguments are constants. Nested calls of the functions are
+// also folded. It returns *BoolNode or *StringNode as the result. The second return value is false
+// when the call cannot be folded.
+func foldConstantFuncCall(n *FuncCallNode) (ExprNode, bool) {
+	args := make([]string, 0, len(n.Args))
+	var first ExprNode
+	for i, a := range n.Args {
+		if c, ok := a.(*FuncCallNode); ok {
+			f, ok := foldConstantFuncCall(c)
+			if !ok {
+				return nil, false
+			}
+			a = f
+		}
+		if i == 0 {
+			first = a
+		}
+		s, ok := literalToString(a)
+		if !ok {
+			return nil, false
+		}
+		args = append(args, s)
+	}
+
+	// Note: Comparisons in these functions are case-insensitive
+	switch strings.ToLower(n.Callee) {
+	case "contains":
+		if len(args) != 2 {
+			return nil, false
+		}
+		if _, ok := first.(*StringNode); !ok {
+			return nil, false // Searching array is not available with literals
+		}
+		b := strings.Contains(strings.ToLower(args[0]), strings.ToLower(args[1]))
+		return &BoolNode{Value: b, tok: n.tok}, true
+	case "startswith":
+		if len(args) != 2 {
+			return nil, false
+		}
+		b := strings.HasPrefix(strings.ToLower(args[0]), strings.ToLower(args[1]))
+		return &BoolNode{Value: b, tok: n.tok}, true
+	case "endswith":
+		if len(args) != 2 {
+			return nil, false
+		}
+		b := strings.HasSuffix(strings.ToLower(args[0]), strings.ToLower(args[1]))
+		return &BoolNode{Value: b, tok: n.tok}, true
+	case "format":
+		if len(args) == 0 {
+			return nil, false
+		}
+		s, ok := foldFormat(args[0], args[1:])
+		if !ok {
+			return nil, false
+		}
+		return &StringNode{Value: s, tok: n.tok}, true
+	default:
+		return nil, false
+	}
+}
+
+// foldFormat replaces placeholders like {0} in the format string with the arguments. '{{' and '}}'
+// are escapes of '{' and '}'. The second return value is false when the format string is invalid.
+func foldFormat(format string, args []string) (string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(format); i++ {
+		c := format[i]
+		switch c {
+		case '{':
+			if i+1 < len(format) && format[i+1] == '{' {
+				b.WriteByte('{')
+				i++
+				continue
+			}
+			end := strings.IndexByte(format[i:], '}')
+			if end < 0 {
+				return "", false
+			}
+			idx, err := strconv.Atoi(format[i+1 : i+end])
+			if err != nil || idx < 0 || idx >= len(args) {
+				return "", false
+			}
+			b.WriteString(args[idx])
+			i += end
+		case '}':
+			if i+1 < len(format) && format[i+1] == '}' {
+				b.WriteByte('}')
+				i++
+				continue
+			}
+			return "", false
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), true
+}
@@ -0,0 +1,84 @@
+package actionlint
+
+import (
+	"testing"
+)
+
+func TestFoldConstantFuncCall(t *testing.T) {
+	tests := []struct {
+		input string
+		want  interface{} // bool or string
+	}{
+		{"contains('abc', 'a')", true},
+		{"contains('abc', 'z')", false},
+		{"contains('ABC', 'b')", true},
+		{"contains('v1.2', 1.2)", true},
+		{"contains('abc', null)", true},
+		{"startsWith('refs/heads/main', 'refs/heads/')", true},
+		{"startsWith('refs/tags/v1', 'refs/heads/')", false},
+		{"endsWith('foo.yaml', '.YAML')", true},
+		{"endsWith('foo.yml', '.yaml')", false},
+		{"format('{0}-{1}', 'a', 1)", "a-1"},
+		{"format('{{{0}}}', true)", "{true}"},
+		{"format('no placeholder')", "no placeholder"},
+		{"contains(format('{0}/{1}', 'a', 'b'), 'a/b')", true},
+		{"startsWith(format('{0}', 'abc'), 'b')", false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.input, func(t *testing.T) {
+			e, err := NewExprParser().Parse(NewExprLexer(tc.input + "}}"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, ok := e.(*FuncCallNode)
+			if !ok {
+				t.Fatalf("not a function call: %#v", e)
+			}
+			v, ok := foldConstantFuncCall(f)
+			if !ok {
+				t.Fatal("function call was not folded")
+			}
+			switch want := tc.want.(type) {
+			case bool:
+				b, ok := v.(*BoolNode)
+				if !ok || b.Value != want {
+					t.Fatalf("wanted %v but have %#v", want, v)
+				}
+			case string:
+				s, ok := v.(*StringNode)
+				if !ok || s.Value != want {
+					t.Fatalf("wanted %q but have %#v", want, v)
+				}
+			}
+		})
+	}
+}
+
+func TestFoldConstantFuncCallNotFolded(t *testing.T) {
+	for _, input := range []string{
+		"contains(github.ref, 'main')",
+		"contains('abc', github.ref)",
+		"contains(fromJSON('[\"a\"]'), 'a')",
+		"startsWith(format('{0}', env.FOO), 'a')",
+		"format('{0}')",
+		"format('{0', 'a')",
+		"format('}', 'a')",
+		"toJSON('a')",
+		"contains('abc')",
+	} {
+		t.Run(input, func(t *testing.T) {
+			e, err := NewExprParser().Parse(NewExprLexer(input + "}}"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			f, ok := e.(*FuncCallNode)
+			if !ok {
+				t.Fatalf("not a function call: %#v", e)
+			}
+			if v, ok := foldConstantFuncCall(f); ok {
+				t.Fatalf("function call was unexpectedly folded to %#v", v)
+			}
+		})
+	}
+}
@@ -148,6 +148,7 @@ type UntrustedInputChecker struct {
 	cur             []*UntrustedInputMap
 	start           ExprNode
 	errs            []*ExprError
+	hint            string
 }
 
 // NewUntrustedInputChecker creates a new UntrustedInputChecker instance. The roots argument is a
@@ -274,8 +275,9 @@ func (u *UntrustedInputChecker) end() {
 	if len(inputs) == 1 {
 		err := errorfAtExpr(
 			u.start,
-			"%q is potentially untrusted. avoid using it directly in inline scripts. instead, pass it through an environment variable. see https://docs.github.com/en/actions/security-guides/security-hardening-for-github-actions for more details",
+			"%q is potentially untrusted. avoid using it directly in inline scripts.%s instead, pass it through an environment variable. see https://docs.github.com/en/actions/security-guides/security-hardening-for-github-actions for more details",
 			inputs[0],
+			u.hint,
 		)
 		u.errs = append(u.errs, err)
 	} else if len(inputs) > 1 {
@@ -283,8 +285,9 @@ func (u *UntrustedInputChecker) end() {
 		// filter syntax. Show all properties in error message.
 		err := errorfAtExpr(
 			u.start,
-			"object filter extracts potentially untrusted properties %s. avoid using the value directly in inline scripts. instead, pass the value through an environment variable. see https://docs.github.com/en/actions/security-guides/security-hardening-for-github-actions for more details",
+			"object filter extracts potentially untrusted properties %s. avoid using the value directly in inline scripts.%s instead, pass the value through an environment variable. see https://docs.github.com/en/actions/security-guides/security-hardening-for-github-actions for more details",
 			sortedQuotes(inputs),
+			u.hint,
 		)
 		u.errs = append(u.errs, err)
 	}
@@ -326,6 +329,12 @@ func (u *UntrustedInputChecker) Errs() []*ExprError {
 	return u.errs
 }
 
+// setHint sets an additional explanation of the risk which is put in error messages. For example,
+// it explains where the untrusted input is put in the script.
+func (u *UntrustedInputChecker) setHint(h string) {
+	u.hint = h
+}
+
 // Init initializes a state of checker.
 func (u *UntrustedInputChecker) Init() {
 	u.errs = u.errs[:0]
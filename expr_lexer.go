@@ -180,8 +180,11 @@ func NewExprLexer(src string) *ExprLexer {
 }
 
 func (lex *ExprLexer) error(msg string) {
+	lex.errorAt(lex.scan.Pos(), msg)
+}
+
+func (lex *ExprLexer) errorAt(p scanner.Position, msg string) {
 	if lex.lexErr == nil {
-		p := lex.scan.Pos()
 		lex.lexErr = &ExprError{
 			Message: msg,
 			Offset:  p.Offset,
@@ -379,18 +382,35 @@ func (lex *ExprLexer) lexHexInt() *Token {
 
 func (lex *ExprLexer) lexString() *Token {
 	// precond: current char is '
+	var bs *scanner.Position // Position of backslash just before the current char
 	for {
 		switch lex.eat() {
 		case '\'':
 			if lex.eat() != '\'' { // when not escaped single quote ''
+				// Backslash escape like 'it\'s' ends the string literal at \' and the rest follows
+				// it. Note that a backslash at the end of string literal like 'C:\' is valid.
+				if r := lex.scan.Peek(); bs != nil && (isAlnum(r) || r == '_') {
+					return lex.invalidEscape(*bs)
+				}
 				return lex.token(TokenKindString)
 			}
+			bs = nil
+		case '\\':
+			p := lex.scan.Pos()
+			bs = &p
 		case scanner.EOF:
 			return lex.unexpected(scanner.EOF, "end of string literal", "'''")
+		default:
+			bs = nil
 		}
 	}
 }
 
+func (lex *ExprLexer) invalidEscape(p scanner.Position) *Token {
+	lex.errorAt(p, `invalid escape sequence "\'" in string literal. backslash escape is not available in string literals. escape a single quote by doubling it like 'it''s'`)
+	return lex.eof()
+}
+
 func (lex *ExprLexer) lexEnd() *Token {
 	r := lex.eat() // eat the first '}'
 	if r != '}' {
@@ -68,6 +68,21 @@ func TestLexOneToken(t *testing.T) {
 			input: "'''hello''world'''",
 			kind:  TokenKindString,
 		},
+		{
+			what:  "string with doubled single quote",
+			input: "'a''b'",
+			kind:  TokenKindString,
+		},
+		{
+			what:  "string with backslash at end",
+			input: `'C:\'`,
+			kind:  TokenKindString,
+		},
+		{
+			what:  "string with backslash followed by doubled single quote",
+			input: `'it\''s'`,
+			kind:  TokenKindString,
+		},
 		{
 			what:  "string with braces",
 			input: "'braces {in} string {{is}} ok!'",
@@ -627,6 +642,18 @@ func TestLexExprError(t *testing.T) {
 			want:  "unexpected EOF while lexing end of string literal",
 			col:   11,
 		},
+		{
+			what:  "backslash escape in string literal",
+			input: `'a\'b'`,
+			want:  `invalid escape sequence "\'" in string literal. backslash escape is not available in string literals. escape a single quote by doubling it like 'it''s'`,
+			col:   3,
+		},
+		{
+			what:  "backslash escape in the middle of string",
+			input: `'it\'s ok' == x`,
+			want:  `invalid escape sequence "\'" in string literal`,
+			col:   4,
+		},
 		{
 			what:  "invalid char after -",
 			input: "-a",
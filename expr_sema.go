@@ -2,6 +2,7 @@ package actionlint
 
 import (
 	"fmt"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
@@ -248,11 +249,13 @@ var BuiltinGlobalVariableTypes = map[string]ExprType{
 	"steps": NewEmptyStrictObjectType(), // This value will be updated contextually
 	// https://docs.github.com/en/actions/learn-github-actions/contexts#runner-context
 	"runner": NewStrictObjectType(map[string]ExprType{
-		"name":       StringType{},
-		"os":         StringType{},
-		"arch":       StringType{},
-		"temp":       StringType{},
-		"tool_cache": StringType{},
+		"name":        StringType{},
+		"os":          NewStringEnumType("Linux", "Windows", "macOS"),
+		"arch":        NewStringEnumType("X86", "X64", "ARM", "ARM64"),
+		"environment": NewStringEnumType("github-hosted", "self-hosted"),
+		"debug":       StringType{},
+		"temp":        StringType{},
+		"tool_cache":  StringType{},
 		// These are not documented but actually exist
 		"workspace": StringType{},
 	}),
@@ -288,6 +291,9 @@ type ExprSemanticsChecker struct {
 	varsCopied      bool
 	githubVarCopied bool
 	untrusted       *UntrustedInputChecker
+	availableCtxs   []string
+	unavailableCtxs map[string]string
+	eventName       string
 }
 
 // NewExprSemanticsChecker creates new ExprSemanticsChecker instance. When checkUntrustedInput is
@@ -385,6 +391,14 @@ func (sema *ExprSemanticsChecker) UpdateInputs(ty *ObjectType) {
 	sema.vars["inputs"] = ty
 }
 
+// UpdateEvent updates 'github.event' object to given object type of the payload of the event. The
+// type of event payload depends on the event which triggers the workflow.
+func (sema *ExprSemanticsChecker) UpdateEvent(name string, ty *ObjectType) {
+	sema.ensureGithubVarCopied()
+	sema.eventName = name
+	sema.vars["github"].(*ObjectType).Props["event"] = ty.DeepCopy()
+}
+
 // UpdateDispatchInputs updates 'github.event.inputs' object to given object type.
 func (sema *ExprSemanticsChecker) UpdateDispatchInputs(ty *ObjectType) {
 	sema.ensureGithubVarCopied()
@@ -398,6 +412,26 @@ func (sema *ExprSemanticsChecker) UpdateJobs(ty *ObjectType) {
 	sema.vars["jobs"] = ty
 }
 
+// SetContextAvailability sets names of contexts available in the expression. Available contexts
+// differ by where the expression is placed. For example, 'steps' context is not available in
+// 'jobs.<job_id>.if'. Using other contexts is reported as error. When this method is not called,
+// all contexts are available.
+// https://docs.github.com/en/actions/learn-github-actions/contexts#context-availability
+func (sema *ExprSemanticsChecker) SetContextAvailability(avail []string) {
+	sema.availableCtxs = avail
+}
+
+// SetUnavailableContext marks the context as unavailable regardless of where the expression is
+// placed. For example, 'inputs' context is not available in workflows which are not triggered by
+// 'workflow_call' nor 'workflow_dispatch' events. Using the context is reported as error with the
+// reason.
+func (sema *ExprSemanticsChecker) SetUnavailableContext(name, reason string) {
+	if sema.unavailableCtxs == nil {
+		sema.unavailableCtxs = map[string]string{}
+	}
+	sema.unavailableCtxs[name] = reason
+}
+
 func (sema *ExprSemanticsChecker) visitUntrustedCheckerOnLeaveNode(n ExprNode) {
 	if sema.untrusted != nil {
 		sema.untrusted.OnVisitNodeLeave(n)
@@ -415,9 +449,33 @@ func (sema *ExprSemanticsChecker) checkVariable(n *VariableNode) ExprType {
 		return AnyType{}
 	}
 
+	if sema.availableCtxs != nil && !contains(sema.availableCtxs, n.Name) {
+		sema.errorf(
+			n,
+			"context %q is not available here. available contexts are %s. see https://docs.github.com/en/actions/learn-github-actions/contexts#context-availability for more details",
+			n.Name,
+			sortedQuotes(sema.availableCtxs),
+		)
+		return AnyType{}
+	}
+
+	if r, ok := sema.unavailableCtxs[n.Name]; ok {
+		sema.errorf(n, "context %q is not available since %s", n.Name, r)
+		return AnyType{}
+	}
+
 	return v
 }
 
+func isGithubEventNode(n ExprNode) bool {
+	d, ok := n.(*ObjectDerefNode)
+	if !ok || d.Property != "event" {
+		return false
+	}
+	v, ok := d.Receiver.(*VariableNode)
+	return ok && v.Name == "github"
+}
+
 func (sema *ExprSemanticsChecker) checkObjectDeref(n *ObjectDerefNode) ExprType {
 	switch ty := sema.check(n.Receiver).(type) {
 	case AnyType:
@@ -430,6 +488,14 @@ func (sema *ExprSemanticsChecker) checkObjectDeref(n *ObjectDerefNode) ExprType
 			return ty.Mapped
 		}
 		if ty.IsStrict() {
+			if sema.eventName != "" && isGithubEventNode(n.Receiver) {
+				ps := make([]string, 0, len(ty.Props))
+				for p := range ty.Props {
+					ps = append(ps, p)
+				}
+				sema.errorf(n, "property %q is not defined in payload of %q event. available properties are %s", n.Property, sema.eventName, sortedQuotes(ps))
+				return AnyType{}
+			}
 			sema.errorf(n, "property %q is not defined in object type %s", n.Property, ty.String())
 		}
 		return AnyType{}
@@ -524,6 +590,7 @@ func (sema *ExprSemanticsChecker) checkIndexAccess(n *IndexAccessNode) ExprType
 	case *ArrayType:
 		switch idx.(type) {
 		case AnyType, NumberType:
+			sema.checkArrayIndexLiteral(n.Index)
 			return ty.Elem
 		default:
 			sema.errorf(n.Index, "index access of array must be type of number but got %q", idx.String())
@@ -543,7 +610,18 @@ func (sema *ExprSemanticsChecker) checkIndexAccess(n *IndexAccessNode) ExprType
 					return ty.Mapped
 				}
 				if ty.IsStrict() {
-					sema.errorf(n, "property %q is not defined in object type %s", lit.Value, ty.String())
+					sema.errorf(n.Index, "property %q is not defined in object type %s", lit.Value, ty.String())
+				}
+			} else if e := idx.(StringType).Enum; e != nil && ty.Mapped == nil && ty.IsStrict() {
+				// Index access with string whose possible values are known like foo[steps.x.outcome]
+				missing := []string{}
+				for _, v := range e.Values {
+					if _, ok := ty.Props[v]; !ok {
+						missing = append(missing, v)
+					}
+				}
+				if len(missing) > 0 {
+					sema.errorf(n.Index, "properties %s are not defined in object type %s. the index is one of %s", quotesAll(missing), ty.String(), quotesAll(e.Values))
 				}
 			}
 			if ty.Mapped != nil {
@@ -560,6 +638,21 @@ func (sema *ExprSemanticsChecker) checkIndexAccess(n *IndexAccessNode) ExprType
 	}
 }
 
+// checkArrayIndexLiteral checks the number literal at index of array. Index must be a non-negative
+// integer. Otherwise, the index access is always evaluated to null.
+func (sema *ExprSemanticsChecker) checkArrayIndexLiteral(idx ExprNode) {
+	switch lit := idx.(type) {
+	case *IntNode:
+		if lit.Value < 0 {
+			sema.errorf(idx, "index access of array must be non-negative integer but got %d. the index access is always evaluated to null", lit.Value)
+		}
+	case *FloatNode:
+		if lit.Value < 0 || lit.Value != math.Trunc(lit.Value) {
+			sema.errorf(idx, "index access of array must be non-negative integer but got %v. the index access is always evaluated to null", lit.Value)
+		}
+	}
+}
+
 func checkFuncSignature(n *FuncCallNode, sig *FuncSignature, args []ExprType) *ExprError {
 	lp, la := len(sig.Params), len(args)
 	if sig.VariableLengthParams && (lp > la) || !sig.VariableLengthParams && lp != la {
@@ -689,18 +782,84 @@ func (sema *ExprSemanticsChecker) checkNotOp(n *NotOpNode) ExprType {
 }
 
 func (sema *ExprSemanticsChecker) checkCompareOp(n *CompareOpNode) ExprType {
-	sema.check(n.Left)
-	sema.check(n.Right)
+	lty := sema.check(n.Left)
+	rty := sema.check(n.Right)
 	// Note: Comparing values is very loose. Any value can be compared with any value without an
 	// error.
 	// https://docs.github.com/en/actions/learn-github-actions/expressions#operators
+	if n.Kind == CompareOpNodeKindEq || n.Kind == CompareOpNodeKindNotEq {
+		sema.checkStringEnumComparison(n.Left, rty)
+		sema.checkStringEnumComparison(n.Right, lty)
+	}
 	return BoolType{}
 }
 
+// checkStringEnumComparison checks the string literal compared with a value of string enum type is
+// one of the possible values. Note that strings are compared case-insensitively.
+func (sema *ExprSemanticsChecker) checkStringEnumComparison(lit ExprNode, ty ExprType) {
+	l, ok := lit.(*StringNode)
+	if !ok {
+		return
+	}
+	s, ok := ty.(StringType)
+	if !ok || s.Enum == nil {
+		return
+	}
+	for _, v := range s.Enum.Values {
+		if strings.EqualFold(v, l.Value) {
+			return
+		}
+	}
+	sema.errorf(
+		l,
+		"string literal %q can never be equal to the compared value since the value is one of %s",
+		l.Value,
+		sortedQuotes(append([]string{}, s.Enum.Values...)),
+	)
+}
+
 func (sema *ExprSemanticsChecker) checkLogicalOp(n *LogicalOpNode) ExprType {
-	lty := sema.check(n.Left)
-	rty := sema.check(n.Right)
-	return lty.Merge(rty)
+	ty, _ := sema.checkLogicalOpWithTruthyType(n)
+	return ty
+}
+
+// checkLogicalOpWithTruthyType checks the logical operator and returns its type and the type of its
+// value when the value is truthy. The second return value is nil when the value is always falsy.
+// The truthy type makes types of ternary-like chains such as `c && x || y` precise. Since the chain
+// is evaluated to `y` when `c` is falsy, the type of `c` does not affect the result type.
+func (sema *ExprSemanticsChecker) checkLogicalOpWithTruthyType(n *LogicalOpNode) (ExprType, ExprType) {
+	lty, ltruthy := sema.checkOperandWithTruthyType(n.Left)
+	rty, rtruthy := sema.checkOperandWithTruthyType(n.Right)
+
+	if n.Kind == LogicalOpNodeKindAnd {
+		// `a && b` is evaluated to `a` when `a` is falsy, otherwise `b`
+		if ltruthy == nil {
+			return lty, nil
+		}
+		return lty.Merge(rty), rtruthy
+	}
+
+	// `a || b` is evaluated to `a` when `a` is truthy, otherwise `b`
+	if ltruthy == nil {
+		return rty, rtruthy
+	}
+	ty := ltruthy.Merge(rty)
+	if rtruthy == nil {
+		return ty, ltruthy
+	}
+	return ty, ltruthy.Merge(rtruthy)
+}
+
+func (sema *ExprSemanticsChecker) checkOperandWithTruthyType(n ExprNode) (ExprType, ExprType) {
+	if l, ok := n.(*LogicalOpNode); ok {
+		defer sema.visitUntrustedCheckerOnLeaveNode(l) // Visit nodes in the same order as check()
+		return sema.checkLogicalOpWithTruthyType(l)
+	}
+	ty := sema.check(n)
+	if _, ok := ty.(NullType); ok {
+		return ty, nil // null is always falsy
+	}
+	return ty, ty
 }
 
 func (sema *ExprSemanticsChecker) check(expr ExprNode) ExprType {
@@ -313,6 +313,59 @@ func TestExprSemanticsCheckOK(t *testing.T) {
 			input:    "'foo' || 42",
 			expected: StringType{},
 		},
+		{
+			what:     "default value with || operator",
+			input:    "steps.foo.outputs.bar || 'default'",
+			expected: StringType{},
+			steps: NewStrictObjectType(map[string]ExprType{
+				"foo": NewStrictObjectType(map[string]ExprType{
+					"outputs": NewStrictObjectType(map[string]ExprType{
+						"bar": StringType{},
+					}),
+					"conclusion": StringType{},
+					"outcome":    StringType{},
+				}),
+			}),
+		},
+		{
+			what:     "|| operator with null on left hand side",
+			input:    "null || 42",
+			expected: NumberType{},
+		},
+		{
+			what:     "ternary-like chain of && and ||",
+			input:    "github.event_name == 'push' && 10 || 20",
+			expected: NumberType{},
+		},
+		{
+			what:     "ternary-like chain of && and || with default value",
+			input:    "github.ref == 'refs/heads/main' && steps.foo.outputs.bar || 'default'",
+			expected: StringType{},
+			steps: NewStrictObjectType(map[string]ExprType{
+				"foo": NewStrictObjectType(map[string]ExprType{
+					"outputs": NewStrictObjectType(map[string]ExprType{
+						"bar": StringType{},
+					}),
+					"conclusion": StringType{},
+					"outcome":    StringType{},
+				}),
+			}),
+		},
+		{
+			what:     "nested ternary-like chain of && and ||",
+			input:    "github.event_name == 'push' && 1 || github.event_name == 'pull_request' && 2 || 3",
+			expected: NumberType{},
+		},
+		{
+			what:     "ternary-like chain of && and || with null",
+			input:    "github.event_name == 'push' && null || 42",
+			expected: NumberType{},
+		},
+		{
+			what:     "ternary-like chain of && and || with different types",
+			input:    "github.event_name == 'push' && 42 || true",
+			expected: AnyType{},
+		},
 		{
 			what:  "coercing two objects on && operator",
 			input: "foo() && bar()",
@@ -407,6 +460,23 @@ func TestExprSemanticsCheckOK(t *testing.T) {
 				}),
 			}),
 		},
+		{
+			what:     "compare step conclusion with valid value case-insensitively",
+			input:    "steps.foo.conclusion == 'Success' && 'skipped' != steps.foo.outcome",
+			expected: BoolType{},
+			steps: NewStrictObjectType(map[string]ExprType{
+				"foo": NewStrictObjectType(map[string]ExprType{
+					"outputs":    NewEmptyObjectType(),
+					"conclusion": NewStringEnumType("success", "failure", "cancelled", "skipped"),
+					"outcome":    NewStringEnumType("success", "failure", "cancelled", "skipped"),
+				}),
+			}),
+		},
+		{
+			what:     "compare runner OS and arch with valid values case-insensitively",
+			input:    "runner.os == 'linux' && runner.arch != 'ARM64' && runner.environment == 'self-hosted'",
+			expected: BoolType{},
+		},
 		{
 			what:     "needs context object",
 			input:    "needs",
@@ -819,6 +889,55 @@ func TestExprSemanticsCheckError(t *testing.T) {
 				"property \"fooooo\" is not defined in object type",
 			},
 		},
+		{
+			what:  "negative integer index access to array",
+			input: "test()[-1]",
+			expected: []string{
+				"index access of array must be non-negative integer but got -1",
+			},
+			funcs: map[string][]*FuncSignature{
+				"test": {
+					{
+						Name: "test",
+						Ret: &ArrayType{
+							Elem: StringType{},
+						},
+					},
+				},
+			},
+		},
+		{
+			what:  "float number index access to array",
+			input: "test()[1.5]",
+			expected: []string{
+				"index access of array must be non-negative integer but got 1.5",
+			},
+			funcs: map[string][]*FuncSignature{
+				"test": {
+					{
+						Name: "test",
+						Ret: &ArrayType{
+							Elem: StringType{},
+						},
+					},
+				},
+			},
+		},
+		{
+			what:  "enum string index access to strict object",
+			input: "github[test()]",
+			expected: []string{
+				"properties \"foo\", \"bar\" are not defined in object type",
+			},
+			funcs: map[string][]*FuncSignature{
+				"test": {
+					{
+						Name: "test",
+						Ret:  NewStringEnumType("foo", "bar", "sha"),
+					},
+				},
+			},
+		},
 		{
 			what:  "undefined function",
 			input: "foooo()",
@@ -961,6 +1080,47 @@ func TestExprSemanticsCheckError(t *testing.T) {
 				}),
 			}),
 		},
+		{
+			what:  "compare step outcome with invalid value",
+			input: "steps.foo.outcome == 'succeeded'",
+			expected: []string{
+				"string literal \"succeeded\" can never be equal to the compared value since the value is one of \"cancelled\", \"failure\", \"skipped\", \"success\"",
+			},
+			steps: NewStrictObjectType(map[string]ExprType{
+				"foo": NewStrictObjectType(map[string]ExprType{
+					"outputs":    NewEmptyObjectType(),
+					"conclusion": NewStringEnumType("success", "failure", "cancelled", "skipped"),
+					"outcome":    NewStringEnumType("success", "failure", "cancelled", "skipped"),
+				}),
+			}),
+		},
+		{
+			what:  "compare invalid value with needs result",
+			input: "'skip' != needs.foo.result",
+			expected: []string{
+				"string literal \"skip\" can never be equal to the compared value since the value is one of",
+			},
+			needs: NewStrictObjectType(map[string]ExprType{
+				"foo": NewStrictObjectType(map[string]ExprType{
+					"outputs": NewEmptyObjectType(),
+					"result":  NewStringEnumType("success", "failure", "cancelled", "skipped"),
+				}),
+			}),
+		},
+		{
+			what:  "compare runner OS with invalid value",
+			input: "runner.os == 'ubuntu'",
+			expected: []string{
+				"string literal \"ubuntu\" can never be equal to the compared value since the value is one of \"Linux\", \"Windows\", \"macOS\"",
+			},
+		},
+		{
+			what:  "compare invalid value with runner arch",
+			input: "'amd64' != runner.arch",
+			expected: []string{
+				"string literal \"amd64\" can never be equal to the compared value since the value is one of \"ARM\", \"ARM64\", \"X64\", \"X86\"",
+			},
+		},
 		{
 			what:  "step output value without typed steps outputs",
 			input: "steps.foo.outputs",
@@ -1131,6 +1291,148 @@ func TestExprSematincsCheckerUpdateDispatchInputsVarType(t *testing.T) {
 	}
 }
 
+func TestExprSemanticsCheckerUpdateEvent(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  string
+	}{
+		{"github.event.head_commit.message", ""},
+		{"github.event.inputs.foo", ""},
+		{"github.event.pull_request.number", `property "pull_request" is not defined in payload of "push" event`},
+		{"github.event.inputs.bar", `property "bar" is not defined in object type {foo: string}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			l := NewExprLexer(tc.input + "}}")
+			e, err := NewExprParser().Parse(l)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := NewExprSemanticsChecker(false)
+			c.UpdateEvent("push", BuiltinEventPayloadTypes["push"])
+			c.UpdateDispatchInputs(NewStrictObjectType(map[string]ExprType{"foo": StringType{}}))
+			_, errs := c.Check(e)
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("wanted 1 error but got %v", errs)
+			}
+			if !strings.Contains(errs[0].Message, tc.want) {
+				t.Fatalf("error message %q does not contain %q", errs[0].Message, tc.want)
+			}
+		})
+	}
+
+	if _, ok := BuiltinEventPayloadTypes["push"].Props["inputs"]; ok {
+		t.Error("global payload type of push event was modified")
+	}
+}
+
+func TestExprSemanticsCheckerEventPayloadType(t *testing.T) {
+	testCases := []struct {
+		what   string
+		events []Event
+		want   string
+	}{
+		{"single event", []Event{&WebhookEvent{Hook: &String{Value: "push"}}}, "push"},
+		{"mixed events", []Event{&WebhookEvent{Hook: &String{Value: "push"}}, &WebhookEvent{Hook: &String{Value: "pull_request"}}}, ""},
+		{"unknown event", []Event{&ScheduledEvent{}}, ""},
+		{"no event", []Event{}, ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			n, ty := eventPayloadType(tc.events)
+			if n != tc.want {
+				t.Fatalf("wanted event %q but got %q", tc.want, n)
+			}
+			if (ty == nil) != (tc.want == "") {
+				t.Fatalf("unexpected payload type %v", ty)
+			}
+		})
+	}
+}
+
+func TestExprSemanticsCheckerSetContextAvailability(t *testing.T) {
+	testCases := []struct {
+		input string
+		avail []string
+		want  string
+	}{
+		{"github.sha", []string{"github", "needs"}, ""},
+		{"github.sha", nil, ""},
+		{"steps.foo.outputs.bar", nil, ""},
+		{"steps.foo", []string{"github", "needs"}, `context "steps" is not available here. available contexts are "github", "needs"`},
+		{"github.sha == env.FOO", []string{"github"}, `context "env" is not available here`},
+		{"undefined_var", []string{"github"}, `undefined variable "undefined_var"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			e, err := NewExprParser().Parse(NewExprLexer(tc.input + "}}"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := NewExprSemanticsChecker(false)
+			c.UpdateSteps(NewEmptyObjectType())
+			c.SetContextAvailability(tc.avail)
+			_, errs := c.Check(e)
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("wanted 1 error but got %v", errs)
+			}
+			if !strings.Contains(errs[0].Message, tc.want) {
+				t.Fatalf("error message %q does not contain %q", errs[0].Message, tc.want)
+			}
+		})
+	}
+}
+
+func TestExprSemanticsCheckerSetUnavailableContext(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  string
+	}{
+		{"github.sha", ""},
+		{"inputs.foo", `context "inputs" is not available since no input is defined`},
+		{"toJSON(inputs)", `context "inputs" is not available since no input is defined`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			e, err := NewExprParser().Parse(NewExprLexer(tc.input + "}}"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := NewExprSemanticsChecker(false)
+			c.SetUnavailableContext("inputs", "no input is defined")
+			_, errs := c.Check(e)
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("wanted 1 error but got %v", errs)
+			}
+			if !strings.Contains(errs[0].Message, tc.want) {
+				t.Fatalf("error message %q does not contain %q", errs[0].Message, tc.want)
+			}
+		})
+	}
+}
+
 func testObjectPropertiesAreInLowerCase(t *testing.T, ty ExprType) {
 	switch ty := ty.(type) {
 	case *ObjectType:
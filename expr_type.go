@@ -2,6 +2,7 @@ package actionlint
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -11,6 +12,10 @@ import (
 type ExprType interface {
 	// String returns string representation of the type.
 	String() string
+	// PrettyString returns human-readable string representation of the type. Unlike String, nested
+	// types are rendered in multiple lines with indentation when the one-line representation is too
+	// long. The indent parameter is the indentation of the line where the type is put.
+	PrettyString(indent int) string
 	// Assignable returns if other type can be assignable to the type.
 	Assignable(other ExprType) bool
 	// Merge merges other type into this type. When other type conflicts with this type, the merged
@@ -20,6 +25,13 @@ type ExprType interface {
 	DeepCopy() ExprType
 }
 
+// prettyTypeMaxWidth is the maximum width of one-line representation of types in PrettyString.
+const prettyTypeMaxWidth = 80
+
+// prettyTypeMaxProps is the maximum number of properties of object types rendered in PrettyString.
+// Rest of properties are elided.
+const prettyTypeMaxProps = 20
+
 // AnyType represents type which can be any type. It also indicates that a value of the type cannot
 // be type-checked since it's type cannot be known statically.
 type AnyType struct{}
@@ -28,6 +40,12 @@ func (ty AnyType) String() string {
 	return "any"
 }
 
+// PrettyString returns human-readable string representation of the type. For scalar types, it is
+// the same as String.
+func (ty AnyType) PrettyString(_ int) string {
+	return ty.String()
+}
+
 // Assignable returns if other type can be assignable to the type.
 func (ty AnyType) Assignable(_ ExprType) bool {
 	return true
@@ -51,6 +69,12 @@ func (ty NullType) String() string {
 	return "null"
 }
 
+// PrettyString returns human-readable string representation of the type. For scalar types, it is
+// the same as String.
+func (ty NullType) PrettyString(_ int) string {
+	return ty.String()
+}
+
 // Assignable returns if other type can be assignable to the type.
 func (ty NullType) Assignable(other ExprType) bool {
 	switch other.(type) {
@@ -82,6 +106,12 @@ func (ty NumberType) String() string {
 	return "number"
 }
 
+// PrettyString returns human-readable string representation of the type. For scalar types, it is
+// the same as String.
+func (ty NumberType) PrettyString(_ int) string {
+	return ty.String()
+}
+
 // Assignable returns if other type can be assignable to the type.
 func (ty NumberType) Assignable(other ExprType) bool {
 	// TODO: Is string of numbers corced into number?
@@ -100,7 +130,7 @@ func (ty NumberType) Merge(other ExprType) ExprType {
 	case NumberType:
 		return ty
 	case StringType:
-		return other
+		return StringType{}
 	default:
 		return AnyType{}
 	}
@@ -118,6 +148,12 @@ func (ty BoolType) String() string {
 	return "bool"
 }
 
+// PrettyString returns human-readable string representation of the type. For scalar types, it is
+// the same as String.
+func (ty BoolType) PrettyString(_ int) string {
+	return ty.String()
+}
+
 // Assignable returns if other type can be assignable to the type.
 func (ty BoolType) Assignable(other ExprType) bool {
 	// Any type can be converted into bool..
@@ -133,7 +169,7 @@ func (ty BoolType) Merge(other ExprType) ExprType {
 	case BoolType:
 		return ty
 	case StringType:
-		return other
+		return StringType{}
 	default:
 		return AnyType{}
 	}
@@ -145,12 +181,34 @@ func (ty BoolType) DeepCopy() ExprType {
 }
 
 // StringType is type for string values.
-type StringType struct{}
+type StringType struct {
+	// Enum is a set of all possible values of the string. nil means the string can be any value.
+	// Comparisons with string literals which are not in the set are reported since they never
+	// match. This field is a pointer to keep StringType comparable with == operator.
+	Enum *StringEnum
+}
+
+// StringEnum is a set of all possible values of string type.
+type StringEnum struct {
+	// Values is a list of the possible values.
+	Values []string
+}
+
+// NewStringEnumType creates new StringType instance whose value is one of the given values.
+func NewStringEnumType(values ...string) StringType {
+	return StringType{&StringEnum{values}}
+}
 
 func (ty StringType) String() string {
 	return "string"
 }
 
+// PrettyString returns human-readable string representation of the type. For scalar types, it is
+// the same as String.
+func (ty StringType) PrettyString(_ int) string {
+	return ty.String()
+}
+
 // Assignable returns if other type can be assignable to the type.
 func (ty StringType) Assignable(other ExprType) bool {
 	// Bool and null types also can be coerced into string. But in almost all case, those coercing
@@ -166,9 +224,24 @@ func (ty StringType) Assignable(other ExprType) bool {
 // Merge merges other type into this type. When other type conflicts with this type, the merged
 // result is any type as fallback.
 func (ty StringType) Merge(other ExprType) ExprType {
-	switch other.(type) {
-	case StringType, NumberType, BoolType:
-		return ty
+	switch other := other.(type) {
+	case StringType:
+		if ty.Enum == nil || other.Enum == nil {
+			return StringType{}
+		}
+		vs := append([]string{}, ty.Enum.Values...)
+	Loop:
+		for _, r := range other.Enum.Values {
+			for _, l := range vs {
+				if l == r {
+					continue Loop
+				}
+			}
+			vs = append(vs, r)
+		}
+		return NewStringEnumType(vs...)
+	case NumberType, BoolType:
+		return StringType{}
 	default:
 		return AnyType{}
 	}
@@ -176,7 +249,10 @@ func (ty StringType) Merge(other ExprType) ExprType {
 
 // DeepCopy duplicates itself. All its child types are copied recursively.
 func (ty StringType) DeepCopy() ExprType {
-	return ty
+	if ty.Enum == nil {
+		return ty
+	}
+	return NewStringEnumType(append([]string{}, ty.Enum.Values...)...)
 }
 
 // ObjectType is type for objects, which can hold key-values.
@@ -252,9 +328,54 @@ func (ty *ObjectType) String() string {
 	for n, t := range ty.Props {
 		ps = append(ps, fmt.Sprintf("%s: %s", n, t.String()))
 	}
+	sort.Strings(ps) // Make output deterministic
 	return fmt.Sprintf("{%s}", strings.Join(ps, "; "))
 }
 
+// PrettyString returns human-readable string representation of the type. When the one-line
+// representation is too long, properties are rendered line by line with indentation. When the
+// object has too many properties, rest of them are elided.
+func (ty *ObjectType) PrettyString(indent int) string {
+	if !ty.IsStrict() {
+		if ty.IsLoose() {
+			return "object"
+		}
+		return fmt.Sprintf("{string => %s}", ty.Mapped.PrettyString(indent))
+	}
+
+	if s := ty.String(); indent+len(s) <= prettyTypeMaxWidth || len(ty.Props) == 0 {
+		return s
+	}
+
+	names := make([]string, 0, len(ty.Props))
+	for n := range ty.Props {
+		names = append(names, n)
+	}
+	sort.Strings(names) // Make output deterministic
+
+	elided := 0
+	if len(names) > prettyTypeMaxProps {
+		elided = len(names) - prettyTypeMaxProps
+		names = names[:prettyTypeMaxProps]
+	}
+
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, n := range names {
+		b.WriteString(strings.Repeat(" ", indent+2))
+		b.WriteString(n)
+		b.WriteString(": ")
+		b.WriteString(ty.Props[n].PrettyString(indent + 2))
+		b.WriteByte('\n')
+	}
+	if elided > 0 {
+		fmt.Fprintf(&b, "%s... %d more props\n", strings.Repeat(" ", indent+2), elided)
+	}
+	b.WriteString(strings.Repeat(" ", indent))
+	b.WriteByte('}')
+	return b.String()
+}
+
 // Assignable returns if other type can be assignable to the type.
 // In other words, rhs type is more strict than lhs (receiver) type.
 func (ty *ObjectType) Assignable(other ExprType) bool {
@@ -367,6 +488,15 @@ func (ty *ArrayType) String() string {
 	return fmt.Sprintf("array<%s>", ty.Elem.String())
 }
 
+// PrettyString returns human-readable string representation of the type. When the one-line
+// representation is too long, the element type is rendered in multiple lines.
+func (ty *ArrayType) PrettyString(indent int) string {
+	if s := ty.String(); indent+len(s) <= prettyTypeMaxWidth {
+		return s
+	}
+	return fmt.Sprintf("array<%s>", ty.Elem.PrettyString(indent))
+}
+
 // Assignable returns if other type can be assignable to the type.
 func (ty *ArrayType) Assignable(other ExprType) bool {
 	switch other := other.(type) {
@@ -1,6 +1,7 @@
 package actionlint
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -425,6 +426,16 @@ func TestExprTypeStringize(t *testing.T) {
 			ty:   NewMapObjectType(NumberType{}),
 			want: "{string => number}",
 		},
+		{
+			what: "props are sorted",
+			ty: NewStrictObjectType(map[string]ExprType{
+				"foo":  StringType{},
+				"bar":  NumberType{},
+				"piyo": BoolType{},
+				"aaa":  NullType{},
+			}),
+			want: "{aaa: null; bar: number; foo: string; piyo: bool}",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -437,6 +448,139 @@ func TestExprTypeStringize(t *testing.T) {
 	}
 }
 
+func TestExprTypePrettyString(t *testing.T) {
+	nested := NewStrictObjectType(map[string]ExprType{
+		"name": StringType{},
+		"repository": NewStrictObjectType(map[string]ExprType{
+			"full_name":      StringType{},
+			"default_branch": StringType{},
+			"private":        BoolType{},
+			"topics":         &ArrayType{Elem: StringType{}},
+		}),
+		"commits": &ArrayType{
+			Elem: NewStrictObjectType(map[string]ExprType{
+				"id":      StringType{},
+				"message": StringType{},
+				"author": NewStrictObjectType(map[string]ExprType{
+					"name":  StringType{},
+					"email": StringType{},
+					"date":  StringType{},
+				}),
+			}),
+		},
+		"labels": NewMapObjectType(NumberType{}),
+		"extra":  NewEmptyObjectType(),
+	})
+
+	manyProps := map[string]ExprType{}
+	for i := 0; i < prettyTypeMaxProps+3; i++ {
+		manyProps[fmt.Sprintf("property_%02d", i)] = StringType{}
+	}
+
+	testCases := []struct {
+		what   string
+		ty     ExprType
+		indent int
+		want   string
+	}{
+		{
+			what: "scalar",
+			ty:   StringType{},
+			want: "string",
+		},
+		{
+			what: "short object",
+			ty: NewStrictObjectType(map[string]ExprType{
+				"foo": StringType{},
+				"bar": &ArrayType{Elem: NumberType{}},
+			}),
+			want: "{bar: array<number>; foo: string}",
+		},
+		{
+			what: "loose object",
+			ty:   NewObjectType(manyProps),
+			want: "object",
+		},
+		{
+			what: "nested object",
+			ty:   nested,
+			want: `{
+  commits: array<{
+    author: {date: string; email: string; name: string}
+    id: string
+    message: string
+  }>
+  extra: object
+  labels: {string => number}
+  name: string
+  repository: {
+    default_branch: string
+    full_name: string
+    private: bool
+    topics: array<string>
+  }
+}`,
+		},
+		{
+			what:   "nested object with indent",
+			ty:     nested,
+			indent: 4,
+			want: `{
+      commits: array<{
+        author: {date: string; email: string; name: string}
+        id: string
+        message: string
+      }>
+      extra: object
+      labels: {string => number}
+      name: string
+      repository: {
+        default_branch: string
+        full_name: string
+        private: bool
+        topics: array<string>
+      }
+    }`,
+		},
+		{
+			what: "array of long object",
+			ty:   &ArrayType{Elem: NewStrictObjectType(manyProps)},
+			want: `array<{
+  property_00: string
+  property_01: string
+  property_02: string
+  property_03: string
+  property_04: string
+  property_05: string
+  property_06: string
+  property_07: string
+  property_08: string
+  property_09: string
+  property_10: string
+  property_11: string
+  property_12: string
+  property_13: string
+  property_14: string
+  property_15: string
+  property_16: string
+  property_17: string
+  property_18: string
+  property_19: string
+  ... 3 more props
+}>`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			have := tc.ty.PrettyString(tc.indent)
+			if have != tc.want {
+				t.Fatalf("wanted:\n%s\nbut got:\n%s", tc.want, have)
+			}
+		})
+	}
+}
+
 func TestExprTypeMergeSimple(t *testing.T) {
 	testCases := []ExprType{
 		AnyType{},
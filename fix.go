@@ -0,0 +1,140 @@
+package actionlint
+
+import (
+	"bytes"
+	"sort"
+)
+
+// TextEdit is a textual edit to fix an error. Text in the range from the start position to the end
+// position is replaced with NewText. Line and column numbers are 1-based and columns are counted in
+// bytes. The end position is exclusive.
+type TextEdit struct {
+	// Line is a line number of the start position of the range.
+	Line int `json:"line"`
+	// Column is a column number of the start position of the range.
+	Column int `json:"column"`
+	// EndLine is a line number of the end position of the range.
+	EndLine int `json:"end_line"`
+	// EndColumn is a column number of the end position of the range.
+	EndColumn int `json:"end_column"`
+	// NewText is a text to replace the range with.
+	NewText string `json:"new_text"`
+}
+
+// Fix is a fix of an error suggested by a rule. Rules cannot know exact positions of texts in source
+// since some information like quotes of strings is lost while parsing. So the fix is described as a
+// replacement of text at the position. Old must be at the position in the source. The fix is
+// resolved into TextEdit by linter and added to Fixes field of the error.
+type Fix struct {
+	// Error is an error fixed by this fix.
+	Error *Error
+	// Line is a line number (1-based) of the text to be replaced.
+	Line int
+	// Column is a column number (1-based) in bytes where the text to be replaced starts.
+	Column int
+	// Old is a text to be replaced.
+	Old string
+	// New is a text to replace the old text with.
+	New string
+}
+
+// Fixer is an optional interface implemented by rules. Rules implementing this interface can suggest
+// fixes of errors they found as textual edits.
+type Fixer interface {
+	// Fixes returns fixes of errors found by the rule.
+	Fixes() []*Fix
+}
+
+// resolveFixes resolves the fixes into text edits and adds them to the errors. Fixes which cannot
+// be resolved since the old text does not exist at the position in the source are ignored.
+func resolveFixes(fixes []*Fix, src []byte) {
+	if len(fixes) == 0 {
+		return
+	}
+
+	lines := bytes.Split(src, []byte{'\n'})
+	for _, f := range fixes {
+		if f.Line <= 0 || len(lines) < f.Line || f.Column <= 0 || f.Old == "" {
+			continue
+		}
+		l := lines[f.Line-1]
+		if len(l) < f.Column-1 || !bytes.HasPrefix(l[f.Column-1:], []byte(f.Old)) {
+			continue
+		}
+		f.Error.Fixes = append(f.Error.Fixes, &TextEdit{
+			Line:      f.Line,
+			Column:    f.Column,
+			EndLine:   f.Line,
+			EndColumn: f.Column + len(f.Old),
+			NewText:   f.New,
+		})
+	}
+}
+
+// textEditOffsets converts the positions of the edit into byte offsets in the source. The third
+// return value is false when the positions are out of the source.
+func textEditOffsets(e *TextEdit, lineStarts []int, src []byte) (int, int, bool) {
+	offset := func(line, col int) (int, bool) {
+		if line <= 0 || len(lineStarts) < line || col <= 0 {
+			return 0, false
+		}
+		o := lineStarts[line-1] + col - 1
+		if o > len(src) {
+			return 0, false
+		}
+		return o, true
+	}
+	s, ok := offset(e.Line, e.Column)
+	if !ok {
+		return 0, 0, false
+	}
+	t, ok := offset(e.EndLine, e.EndColumn)
+	if !ok || t < s {
+		return 0, 0, false
+	}
+	return s, t, true
+}
+
+// ApplyTextEdits applies the text edits to the source and returns the result. When some edits
+// overlap, only the first one in source order is applied to avoid clobbering the source. The
+// second return value is the edits which were actually applied.
+func ApplyTextEdits(src []byte, edits []*TextEdit) ([]byte, []*TextEdit) {
+	lineStarts := []int{0}
+	for i, b := range src {
+		if b == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+
+	type offsetEdit struct {
+		start int
+		end   int
+		edit  *TextEdit
+	}
+
+	es := make([]offsetEdit, 0, len(edits))
+	for _, e := range edits {
+		if s, t, ok := textEditOffsets(e, lineStarts, src); ok {
+			es = append(es, offsetEdit{s, t, e})
+		}
+	}
+	sort.SliceStable(es, func(i, j int) bool {
+		return es[i].start < es[j].start
+	})
+
+	var b bytes.Buffer
+	applied := make([]*TextEdit, 0, len(es))
+	prev := 0
+	for _, e := range es {
+		if e.start < prev {
+			continue // Overlapping with the previous edit
+		}
+		b.Write(src[prev:e.start])
+		b.WriteString(e.edit.NewText)
+		prev = e.end
+		applied = append(applied, e.edit)
+	}
+	b.Write(src[prev:])
+
+	return b.Bytes(), applied
+}
@@ -0,0 +1,109 @@
+package actionlint
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFixApplyTextEdits(t *testing.T) {
+	src := "foo: bar\nbaz: qux\n"
+	testCases := []struct {
+		what    string
+		edits   []*TextEdit
+		want    string
+		applied int
+	}{
+		{
+			what:    "no edit",
+			edits:   []*TextEdit{},
+			want:    src,
+			applied: 0,
+		},
+		{
+			what: "single edit",
+			edits: []*TextEdit{
+				{Line: 1, Column: 6, EndLine: 1, EndColumn: 9, NewText: "BAR"},
+			},
+			want:    "foo: BAR\nbaz: qux\n",
+			applied: 1,
+		},
+		{
+			what: "multiple edits in reverse order",
+			edits: []*TextEdit{
+				{Line: 2, Column: 1, EndLine: 2, EndColumn: 4, NewText: "hello"},
+				{Line: 1, Column: 1, EndLine: 1, EndColumn: 4, NewText: "x"},
+			},
+			want:    "x: bar\nhello: qux\n",
+			applied: 2,
+		},
+		{
+			what: "edit across lines",
+			edits: []*TextEdit{
+				{Line: 1, Column: 4, EndLine: 2, EndColumn: 4, NewText: ""},
+			},
+			want:    "foo: qux\n",
+			applied: 1,
+		},
+		{
+			what: "insertion",
+			edits: []*TextEdit{
+				{Line: 2, Column: 1, EndLine: 2, EndColumn: 1, NewText: "# comment\n"},
+			},
+			want:    "foo: bar\n# comment\nbaz: qux\n",
+			applied: 1,
+		},
+		{
+			what: "overlapping edit is not applied",
+			edits: []*TextEdit{
+				{Line: 1, Column: 1, EndLine: 1, EndColumn: 5, NewText: "aaa:"},
+				{Line: 1, Column: 3, EndLine: 1, EndColumn: 9, NewText: "bbb"},
+			},
+			want:    "aaa: bar\nbaz: qux\n",
+			applied: 1,
+		},
+		{
+			what: "out of range",
+			edits: []*TextEdit{
+				{Line: 10, Column: 1, EndLine: 10, EndColumn: 2, NewText: "x"},
+				{Line: 1, Column: 5, EndLine: 1, EndColumn: 1, NewText: "x"},
+			},
+			want:    src,
+			applied: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			out, applied := ApplyTextEdits([]byte(src), tc.edits)
+			if have := string(out); have != tc.want {
+				t.Fatalf("wanted %q but got %q", tc.want, have)
+			}
+			if len(applied) != tc.applied {
+				t.Fatalf("wanted %d edits applied but got %d: %v", tc.applied, len(applied), applied)
+			}
+		})
+	}
+}
+
+func TestFixResolveFixes(t *testing.T) {
+	src := "steps:\n  - run: |\n      echo hello\n      echo world; echo world\n"
+	err := &Error{}
+	fixes := []*Fix{
+		{Error: err, Line: 4, Column: 24, Old: "world", New: "WORLD"},
+		{Error: err, Line: 4, Column: 12, Old: "world", New: "WORLD"},
+		{Error: err, Line: 4, Column: 1, Old: "world", New: "WORLD"},  // Not found at the position
+		{Error: err, Line: 3, Column: 12, Old: "world", New: "WORLD"}, // Not found in the line
+		{Error: err, Line: 4, Column: 99, Old: "world", New: "WORLD"},
+		{Error: err, Line: 10, Column: 1, Old: "world", New: "WORLD"},
+	}
+	resolveFixes(fixes, []byte(src))
+
+	want := []*TextEdit{
+		{Line: 4, Column: 24, EndLine: 4, EndColumn: 29, NewText: "WORLD"},
+		{Line: 4, Column: 12, EndLine: 4, EndColumn: 17, NewText: "WORLD"},
+	}
+	if diff := cmp.Diff(want, err.Fixes); diff != "" {
+		t.Fatal(diff)
+	}
+}
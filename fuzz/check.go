@@ -28,8 +28,11 @@ func FuzzCheck(data []byte) int {
 		actionlint.NewRuleEvents(),
 		actionlint.NewRuleGlob(),
 		actionlint.NewRuleJobNeeds(),
+		actionlint.NewRuleEnvironment([]string{}),
 		actionlint.NewRuleAction(c),
 		actionlint.NewRuleEnvVar(),
+		actionlint.NewRuleCacheKey(),
+		actionlint.NewRuleSecretsInOutputs(),
 		actionlint.NewRuleStepID(),
 		actionlint.NewRuleExpression(c),
 	}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"text/scanner"
 	"unicode"
+	"unicode/utf8"
 )
 
 // Note:
@@ -239,3 +240,53 @@ func ValidateRefGlob(pat string) []InvalidGlobPattern {
 func ValidatePathGlob(pat string) []InvalidGlobPattern {
 	return validateGlob(pat, false)
 }
+
+// globLiteralPrefix returns the longest prefix of the glob pattern which does not contain special
+// characters. Escaped special characters in the prefix are unescaped. The leading ! of negate
+// pattern is removed. The second return value is true when the whole pattern is literal.
+func globLiteralPrefix(pat string) (string, bool) {
+	pat = strings.TrimPrefix(pat, "!")
+	var b strings.Builder
+	for i := 0; i < len(pat); i++ {
+		c := pat[i]
+		switch c {
+		case '\\':
+			if i+1 < len(pat) && strings.IndexByte("[?*+\\!", pat[i+1]) >= 0 {
+				i++ // Eat the escaped character
+				c = pat[i]
+			}
+		case '*', '[':
+			return b.String(), false
+		case '+':
+			// The preceding character appears at least once so it is part of the prefix
+			return b.String(), false
+		case '?':
+			// The preceding character may not appear so it is not part of the prefix
+			s := b.String()
+			_, n := utf8.DecodeLastRuneInString(s)
+			return s[:len(s)-n], false
+		}
+		b.WriteByte(c)
+	}
+	return b.String(), true
+}
+
+// globSubsumes returns whether all paths matched by the glob pattern pat are also matched by the
+// glob pattern sup. Since checking it precisely is hard, only obvious cases are detected. It returns
+// true when the patterns are identical or when sup is a literal prefix followed by "**" and the
+// literal prefix of pat starts with it. For example, "docs/**" subsumes "docs/*.md" and
+// "docs/api/**". False negatives are possible but false positives are not.
+func globSubsumes(sup, pat string) bool {
+	if sup == pat {
+		return true
+	}
+	if !strings.HasSuffix(sup, "**") || strings.HasSuffix(sup, `\**`) {
+		return false
+	}
+	x, literal := globLiteralPrefix(sup[:len(sup)-2])
+	if !literal {
+		return false
+	}
+	p, _ := globLiteralPrefix(pat)
+	return strings.HasPrefix(p, x)
+}
@@ -411,3 +411,66 @@ func TestValidateGlobQuoteCharacterInErrorMessage(t *testing.T) {
 		})
 	}
 }
+
+func TestGlobLiteralPrefix(t *testing.T) {
+	testCases := []struct {
+		input   string
+		prefix  string
+		literal bool
+	}{
+		{"src/main.go", "src/main.go", true},
+		{"src/**", "src/", false},
+		{"src/**/*.ts", "src/", false},
+		{"docs/*.md", "docs/", false},
+		{"src/foo*.go", "src/foo", false},
+		{"src/fooo?.go", "src/foo", false},
+		{"src/fo+.go", "src/fo", false},
+		{"src/[abc].go", "src/", false},
+		{"**.md", "", false},
+		{"!docs/**", "docs/", false},
+		{`src/\*\*/main.go`, "src/**/main.go", true},
+		{`foo\bar/*`, `foo\bar/`, false},
+		{"", "", true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			prefix, literal := globLiteralPrefix(tc.input)
+			if prefix != tc.prefix || literal != tc.literal {
+				t.Fatalf("wanted (%q, %v) but got (%q, %v)", tc.prefix, tc.literal, prefix, literal)
+			}
+		})
+	}
+}
+
+func TestGlobSubsumes(t *testing.T) {
+	testCases := []struct {
+		sup  string
+		pat  string
+		want bool
+	}{
+		{"docs/**", "docs/**", true},
+		{"README.md", "README.md", true},
+		{"docs/**", "docs/*.md", true},
+		{"docs/**", "docs/api/**", true},
+		{"docs/**", "docs/index.md", true},
+		{"**", "src/**/*.ts", true},
+		{"**", "**.md", true},
+		{"docs**", "docs-old/**", true},
+		{"docs/**", "doc/**", false},
+		{"docs/**", "**/docs/**", false},
+		{"docs/**", "docs", false},
+		{"docs/*", "docs/api/**", false},
+		{"**.md", "docs/*.md", false},
+		{"d?cs/**", "docs/**", false},
+		{`docs\**`, "docs*/foo", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.sup+" "+tc.pat, func(t *testing.T) {
+			if have := globSubsumes(tc.sup, tc.pat); have != tc.want {
+				t.Fatalf("wanted %v but got %v", tc.want, have)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ require (
 	github.com/google/go-cmp v0.5.6
 	github.com/kr/pretty v0.2.1
 	github.com/mattn/go-colorable v0.1.12
+	github.com/mattn/go-isatty v0.0.14
 	github.com/mattn/go-runewidth v0.0.13
 	github.com/robfig/cron v1.2.0
 	github.com/yuin/goldmark v1.4.0
@@ -17,7 +18,6 @@ require (
 
 require (
 	github.com/kr/text v0.1.0 // indirect
-	github.com/mattn/go-isatty v0.0.14 // indirect
 	github.com/rivo/uniseg v0.2.0 // indirect
 	golang.org/x/xerrors v0.0.0-20191204190536-9bdfabe68543 // indirect
 )
@@ -0,0 +1,76 @@
+package actionlint
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Matches the start of heredoc like `<<EOF`, `<<-EOF`, `<< 'EOF'` or `<<\EOF`. Here string `<<<` is
+// not matched. The first submatch is "-" of `<<-`, the second one is a quote of the delimiter and
+// the third one is the delimiter.
+var reHeredocStart = regexp.MustCompile(`(?:^|[^<])<<(-?)\s*(['"\\]?)([A-Za-z_][A-Za-z0-9_]*)`)
+
+// heredoc is a here document in a shell script.
+// https://www.gnu.org/software/bash/manual/html_node/Redirections.html#Here-Documents
+type heredoc struct {
+	// delim is a delimiter of the heredoc like "EOF".
+	delim string
+	// quoted is true when the delimiter is quoted like 'EOF'. Lines in the heredoc are not expanded
+	// by shell in the case.
+	quoted bool
+	// stripTabs is true when the heredoc starts with `<<-`. Leading tabs of the lines including the
+	// closing delimiter are removed in the case.
+	stripTabs bool
+	// start is a 0-based index of the first line of the heredoc body.
+	start int
+	// end is a 0-based index of the line of the closing delimiter. It is the number of lines when the
+	// heredoc is not closed.
+	end int
+}
+
+// isDelim returns if the line closes the heredoc. The line must be exactly the delimiter. Only
+// leading tabs are allowed when the heredoc starts with `<<-`.
+func (h *heredoc) isDelim(line string) bool {
+	if h.stripTabs {
+		line = strings.TrimLeft(line, "\t")
+	}
+	return line == h.delim
+}
+
+// contains returns if the 0-based line index is in the body of the heredoc.
+func (h *heredoc) contains(line int) bool {
+	return h.start <= line && line < h.end
+}
+
+// heredocAt returns the heredoc whose body contains the 0-based line index. It returns nil when the
+// line is not in any heredoc.
+func heredocAt(hs []*heredoc, line int) *heredoc {
+	for _, h := range hs {
+		if h.contains(line) {
+			return h
+		}
+	}
+	return nil
+}
+
+// findHeredocs finds heredocs in the lines of the shell script. Only the first heredoc in each line
+// is detected.
+func findHeredocs(lines []string) []*heredoc {
+	ret := []*heredoc{}
+	for i := 0; i < len(lines); i++ {
+		m := reHeredocStart.FindStringSubmatch(lines[i])
+		if m == nil {
+			continue
+		}
+		h := &heredoc{delim: m[3], quoted: m[2] != "", stripTabs: m[1] != "", start: i + 1, end: len(lines)}
+		for j := i + 1; j < len(lines); j++ {
+			if h.isDelim(lines[j]) {
+				h.end = j
+				break
+			}
+		}
+		ret = append(ret, h)
+		i = h.end
+	}
+	return ret
+}
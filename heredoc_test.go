@@ -0,0 +1,84 @@
+package actionlint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFindHeredocs(t *testing.T) {
+	testCases := []struct {
+		what  string
+		input string
+		want  []*heredoc
+	}{
+		{
+			what:  "no heredoc",
+			input: "echo hello\necho world",
+			want:  []*heredoc{},
+		},
+		{
+			what:  "unquoted delimiter",
+			input: "cat <<EOF > out.txt\nhello\nEOF\necho done",
+			want:  []*heredoc{{delim: "EOF", quoted: false, start: 1, end: 2}},
+		},
+		{
+			what:  "single-quoted delimiter",
+			input: "cat << 'END'\nhello\nEND",
+			want:  []*heredoc{{delim: "END", quoted: true, start: 1, end: 2}},
+		},
+		{
+			what:  "double-quoted delimiter",
+			input: "cat <<\"EOF\"\nhello\nEOF",
+			want:  []*heredoc{{delim: "EOF", quoted: true, start: 1, end: 2}},
+		},
+		{
+			what:  "escaped delimiter",
+			input: "cat <<\\EOF\nhello\nEOF",
+			want:  []*heredoc{{delim: "EOF", quoted: true, start: 1, end: 2}},
+		},
+		{
+			what:  "indented heredoc",
+			input: "if true; then\n\tcat <<-EOF\n\thello\n\tEOF\nfi",
+			want:  []*heredoc{{delim: "EOF", quoted: false, stripTabs: true, start: 2, end: 3}},
+		},
+		{
+			what:  "indented delimiter does not close heredoc",
+			input: "cat <<EOF\nhello\n  EOF\n\tEOF\nEOF ",
+			want:  []*heredoc{{delim: "EOF", quoted: false, start: 1, end: 5}},
+		},
+		{
+			what:  "delimiter indented with spaces does not close heredoc with <<-",
+			input: "cat <<-EOF\nhello\n  EOF\n\t\tEOF",
+			want:  []*heredoc{{delim: "EOF", quoted: false, stripTabs: true, start: 1, end: 3}},
+		},
+		{
+			what:  "multiple heredocs",
+			input: "cat <<A\na\nA\ncat <<'B'\nb\nb\nB",
+			want: []*heredoc{
+				{delim: "A", quoted: false, start: 1, end: 2},
+				{delim: "B", quoted: true, start: 4, end: 6},
+			},
+		},
+		{
+			what:  "not closed",
+			input: "cat <<EOF\nhello\nworld",
+			want:  []*heredoc{{delim: "EOF", quoted: false, start: 1, end: 3}},
+		},
+		{
+			what:  "here string",
+			input: "cat <<<EOF\nhello\nEOF",
+			want:  []*heredoc{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			have := findHeredocs(strings.Split(tc.input, "\n"))
+			if !cmp.Equal(tc.want, have, cmp.AllowUnexported(heredoc{})) {
+				t.Fatal(cmp.Diff(tc.want, have, cmp.AllowUnexported(heredoc{})))
+			}
+		})
+	}
+}
@@ -12,10 +12,12 @@ import (
 	"runtime"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/fatih/color"
 	"github.com/mattn/go-colorable"
+	"github.com/mattn/go-isatty"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
 )
@@ -38,8 +40,8 @@ type ColorOptionKind int
 
 const (
 	// ColorOptionKindAuto is kind to determine to colorize errors output automatically. It is
-	// determined based on pty and $NO_COLOR environment variable. See document of fatih/color
-	// for more details.
+	// determined based on whether the output is a terminal, $NO_COLOR and $TERM environment
+	// variables. See https://no-color.org/ for more details of $NO_COLOR.
 	ColorOptionKindAuto ColorOptionKind = iota
 	// ColorOptionKindAlways is kind to always colorize errors output.
 	ColorOptionKindAlways
@@ -78,22 +80,163 @@ type LinterOptions struct {
 	ConfigFile string
 	// Format is a custom template to format error messages. It must follow Go Template format and
 	// contain at least one {{ }} placeholder. https://pkg.go.dev/text/template
+	// When ErrorFormatGitHubActions is set, errors are formatted as workflow commands of GitHub Actions.
+	// When ErrorFormatJSONL is set, errors are formatted as newline-delimited JSON. When the template
+	// accesses fields of one error like {{.Filepath}}:{{.Line}}, each error is formatted in one line.
 	Format string
+	// MaxFindings is the maximum number of errors to be printed. When more errors are found, they are
+	// omitted from the output and the number of omitted errors is printed instead. Note that the
+	// number is not printed when Format is set to keep the output machine-readable. Errors returned
+	// from Linter methods are not affected by this option. Zero means no limit.
+	MaxFindings int
+	// MaxFindingsPerFile is a flag to apply MaxFindings to each file instead of all files.
+	MaxFindingsPerFile bool
+	// RelativeTo is a base directory of file paths in errors. File paths are converted into paths
+	// relative to the directory. When a file is outside the directory, its absolute path is used.
+	// When it is empty, file paths are relative to the current working directory if possible.
+	RelativeTo string
+	// EnableRules is names of rules which are disabled by default to enable. Rules enabled in config
+	// file are also enabled.
+	EnableRules []string
+	// Presets is names of presets to apply. Built-in presets are listed in BuiltinPresets. Custom
+	// presets defined in config file can also be specified. Rules enabled by the presets are enabled
+	// in addition to EnableRules. Severities set by the presets are overridden by ErrorOn.
+	Presets []string
+	// ErrorOn is names of rules whose errors are treated as SeverityError. They take precedence
+	// over "rules" in config file.
+	ErrorOn []string
+	// ActionsMetadataFile is a path to the file which describes metadata of additional actions which
+	// are not included in the popular actions dataset. See ReadActionsMetadataFile for the format.
+	// Empty string means no file is given.
+	ActionsMetadataFile string
+	// Dedup is a flag to collapse duplicate errors in each file. See DedupErrors for details.
+	Dedup bool
+	// Dependabot is a flag to also lint Dependabot configuration file .github/dependabot.yml. The
+	// file is linted with LintRepository and when it is given to LintFiles or LintFile. Only basic
+	// checks for the file are applied and workflow rules are not applied to it.
+	Dependabot bool
+	// MaxShellcheckScriptBytes is the max size of script in bytes to be checked by shellcheck. Scripts
+	// at 'run:' larger than the size are not checked since shellcheck may take too long time. Zero
+	// means DefaultMaxShellcheckScriptBytes. Negative value means no limit.
+	MaxShellcheckScriptBytes int
+	// ActionOutputsTypes is types of outputs of actions. Keys are action specs like "owner/repo@ref"
+	// or "owner/repo", or local action paths like "./path/to/action". Values are types of
+	// `steps.<step_id>.outputs` of steps which run the actions. For example, `outputs.result` of
+	// actions/github-script can be typed as string. Nil means no type is registered.
+	ActionOutputsTypes map[string]ExprType
+	// Timeout is the max duration of one linting run by methods like LintFiles or LintRepository.
+	// When the duration is exceeded, running external processes like shellcheck are killed and the
+	// method returns errors of the files which were completely linted with an error wrapping
+	// ErrTimeout. Zero means no timeout.
+	Timeout time.Duration
+	// DisableExternal is a flag to disable all rules which run external commands like shellcheck and
+	// pyflakes. Other rules still run. It takes precedence over Shellcheck and Pyflakes options.
+	DisableExternal bool
+	// DefaultBranch is a name of the default branch of the repository used by optional
+	// "default-branch" rule. It takes precedence over "default-branch" in config file. Empty string
+	// means the default branch is not given.
+	DefaultBranch string
+	// PostParse is a hook function to run custom checks on each parsed workflow. It is called with
+	// the syntax tree of the workflow and its file path after all built-in rules checked the
+	// workflow. It is not called when the workflow could not be parsed or the file is an action
+	// metadata file. Returned errors are merged with errors of the built-in rules, so they are also
+	// filtered by IgnorePatterns and Baseline, and sorted by their positions. Line and Column of the
+	// errors must be set from positions of the syntax tree nodes (see Pos). Filepath is always
+	// overwritten and empty Kind is set to "post-parse". Since files are checked in parallel, the
+	// function may be called concurrently. The syntax tree must not be modified. Nil means no hook.
+	PostParse func(w *Workflow, path string) []*Error
+	// Baseline is a set of known errors to be suppressed. Only errors which don't match the baseline
+	// are reported. Nil means no baseline. See ReadBaselineFile for reading a baseline file.
+	Baseline *Baseline
+	// Sort is a comparator to sort errors of all linted files before printing them. Errors are
+	// sorted across files, then MaxFindings is applied to the sorted errors. Errors returned from
+	// Linter methods are also sorted. For example, ErrorLessBySeverity prints errors before
+	// warnings. Nil means the default order, which is the order of files then positions in each file.
+	Sort ErrorLess
 	// More options will come here
 }
 
-// Linter is struct to lint workflow files.
+// Linter is struct to lint workflow files. Once a Linter instance is created, it can be reused for
+// linting many files. Options, config file and compiled patterns are prepared only once on creating
+// the instance. Its methods are safe to be called concurrently.
 type Linter struct {
 	projects      *Projects
 	out           io.Writer
+	outMu         sync.Mutex
 	logOut        io.Writer
 	logLevel      LogLevel
 	oneline       bool
 	shellcheck    string
 	pyflakes      string
+	noExternal    bool
+	baseline      *Baseline
+	postParse     func(*Workflow, string) []*Error
 	ignorePats    []*regexp.Regexp
 	defaultConfig *Config
 	errFmt        *ErrorFormatter
+	maxFindings   int
+	maxPerFile    bool
+	sortLess      ErrorLess
+	enableRules   []string
+	presets       []string
+	errorOn       []string
+	defaultBranch string
+	relBase       string
+	colorful      bool
+	dedup         bool
+	dependabot    bool
+	actionsMeta   map[string]*ActionMetadata
+	outputsTys    map[string]ExprType
+	maxShBytes    int
+	timeout       time.Duration
+	fixerEnabled  int32 // Set to 1 atomically when some enabled rule implements Fixer
+}
+
+// ErrTimeout is an error returned when linting exceeds the timeout set to Timeout of LinterOptions.
+// It is wrapped by the error returned from Linter methods. Check it with errors.Is.
+var ErrTimeout = errors.New("linting timed out")
+
+// ErrInvalidOption is an error returned when some value of LinterOptions is invalid such as unknown
+// rule name or broken regular expression. It is wrapped by the error returned from NewLinter and
+// Linter methods. Check it with errors.Is.
+var ErrInvalidOption = errors.New("invalid linter option")
+
+// invalidOptionError wraps an error caused by invalid LinterOptions value. Its message is the same
+// as the wrapped error.
+type invalidOptionError struct {
+	err error
+}
+
+func (e *invalidOptionError) Error() string {
+	return e.err.Error()
+}
+
+func (e *invalidOptionError) Unwrap() error {
+	return e.err
+}
+
+func (e *invalidOptionError) Is(target error) bool {
+	return target == ErrInvalidOption
+}
+
+// isColorEnabled returns whether errors output to the writer should be colorized. Explicit option
+// is prioritized the most. Otherwise $NO_COLOR environment variable is respected (see
+// https://no-color.org/) and then colorful output is enabled only when the writer is a terminal.
+func isColorEnabled(kind ColorOptionKind, out io.Writer, getenv func(string) string) bool {
+	switch kind {
+	case ColorOptionKindAlways:
+		return true
+	case ColorOptionKindNever:
+		return false
+	}
+	if getenv("NO_COLOR") != "" || getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := out.(*os.File)
+	if !ok {
+		return false
+	}
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
 }
 
 // NewLinter creates a new Linter instance.
@@ -108,12 +251,8 @@ func NewLinter(out io.Writer, opts *LinterOptions) (*Linter, error) {
 		level = LogLevelDebug
 	}
 
-	if opts.Color == ColorOptionKindNever {
-		color.NoColor = true
-	} else {
-		if opts.Color == ColorOptionKindAlways {
-			color.NoColor = false
-		}
+	colorful := isColorEnabled(opts.Color, out, os.Getenv)
+	if colorful {
 		// Allow colorful output on Windows
 		if f, ok := out.(*os.File); ok {
 			out = colorable.NewColorable(f)
@@ -138,31 +277,95 @@ func NewLinter(out io.Writer, opts *LinterOptions) (*Linter, error) {
 	for _, s := range opts.IgnorePatterns {
 		r, err := regexp.Compile(s)
 		if err != nil {
-			return nil, fmt.Errorf("invalid regular expression for ignore pattern %q: %s", s, err.Error())
+			return nil, &invalidOptionError{fmt.Errorf("invalid regular expression for ignore pattern %q: %s", s, err.Error())}
 		}
 		ignore = append(ignore, r)
 	}
 
+	for _, r := range opts.EnableRules {
+		if err := checkOptionalRuleName(r, "enable"); err != nil {
+			return nil, &invalidOptionError{err}
+		}
+	}
+
+	for _, r := range opts.ErrorOn {
+		if err := checkRuleName(r); err != nil {
+			return nil, &invalidOptionError{err}
+		}
+	}
+
+	// Custom presets can be validated only when config file is given. Otherwise the presets are
+	// validated after reading config file of each project.
+	if cfg != nil {
+		if _, err := resolvePresets(opts.Presets, cfg.Presets); err != nil {
+			return nil, &invalidOptionError{err}
+		}
+	}
+
+	base := ""
+	if opts.RelativeTo != "" {
+		d, err := filepath.Abs(opts.RelativeTo)
+		if err != nil {
+			return nil, &invalidOptionError{fmt.Errorf("could not resolve base directory %q of file paths: %w", opts.RelativeTo, err)}
+		}
+		if s, err := os.Stat(d); err != nil || !s.IsDir() {
+			return nil, &invalidOptionError{fmt.Errorf("base directory %q of file paths is not a directory", opts.RelativeTo)}
+		}
+		base = d
+	}
+
+	var actionsMeta map[string]*ActionMetadata
+	if opts.ActionsMetadataFile != "" {
+		m, err := ReadActionsMetadataFile(opts.ActionsMetadataFile)
+		if err != nil {
+			return nil, err
+		}
+		actionsMeta = m
+	}
+
+	maxShBytes := opts.MaxShellcheckScriptBytes
+	if maxShBytes == 0 {
+		maxShBytes = DefaultMaxShellcheckScriptBytes
+	}
+
 	var formatter *ErrorFormatter
 	if opts.Format != "" {
 		f, err := NewErrorFormatter(opts.Format)
 		if err != nil {
-			return nil, err
+			return nil, &invalidOptionError{err}
 		}
 		formatter = f
 	}
 
 	return &Linter{
-		NewProjects(),
-		out,
-		lout,
-		level,
-		opts.Oneline,
-		opts.Shellcheck,
-		opts.Pyflakes,
-		ignore,
-		cfg,
-		formatter,
+		projects:      NewProjects(),
+		out:           out,
+		logOut:        lout,
+		logLevel:      level,
+		oneline:       opts.Oneline,
+		shellcheck:    opts.Shellcheck,
+		pyflakes:      opts.Pyflakes,
+		noExternal:    opts.DisableExternal,
+		baseline:      opts.Baseline,
+		postParse:     opts.PostParse,
+		ignorePats:    ignore,
+		defaultConfig: cfg,
+		errFmt:        formatter,
+		maxFindings:   opts.MaxFindings,
+		maxPerFile:    opts.MaxFindingsPerFile,
+		sortLess:      opts.Sort,
+		enableRules:   opts.EnableRules,
+		presets:       opts.Presets,
+		errorOn:       opts.ErrorOn,
+		defaultBranch: opts.DefaultBranch,
+		relBase:       base,
+		colorful:      colorful,
+		dedup:         opts.Dedup,
+		dependabot:    opts.Dependabot,
+		actionsMeta:   actionsMeta,
+		outputsTys:    opts.ActionOutputsTypes,
+		maxShBytes:    maxShBytes,
+		timeout:       opts.Timeout,
 	}, nil
 }
 
@@ -182,6 +385,21 @@ func (l *Linter) debug(format string, args ...interface{}) {
 	fmt.Fprintf(l.logOut, format, args...)
 }
 
+// newContext creates a context for one linting run. Its deadline is set when the timeout is
+// configured.
+func (l *Linter) newContext() (context.Context, context.CancelFunc) {
+	if l.timeout > 0 {
+		return context.WithTimeout(context.Background(), l.timeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// timeoutError creates an error wrapping ErrTimeout. The n parameter is the number of files which
+// were not linted completely.
+func (l *Linter) timeoutError(n, total int) error {
+	return fmt.Errorf("%w after %s. %d of %d files were not linted completely", ErrTimeout, l.timeout, n, total)
+}
+
 func (l *Linter) debugWriter() io.Writer {
 	if l.logLevel < LogLevelDebug {
 		return nil
@@ -224,6 +442,11 @@ func (l *Linter) LintRepository(dir string) ([]*Error, error) {
 	}
 
 	l.log("Detected project:", proj.RootDir())
+	return l.lintProject(proj)
+}
+
+// lintProject lints all YAML workflow files in the workflows directory of the project.
+func (l *Linter) lintProject(proj *Project) ([]*Error, error) {
 	wd := proj.WorkflowsDir()
 
 	files := []string{}
@@ -250,6 +473,13 @@ func (l *Linter) LintRepository(dir string) ([]*Error, error) {
 	// To make output deterministic, sort order of file paths
 	sort.Strings(files)
 
+	if l.dependabot {
+		if p := proj.DependabotConfigFile(); p != "" {
+			l.log("Found Dependabot config file:", p)
+			files = append(files, p)
+		}
+	}
+
 	return l.LintFiles(files, proj)
 }
 
@@ -267,30 +497,29 @@ func (l *Linter) LintFiles(filepaths []string, project *Project) ([]*Error, erro
 
 	l.log("Linting", n, "files")
 
-	cwd := ""
-	if wd, err := os.Getwd(); err == nil {
-		cwd = wd
-	}
+	base := l.baseDir()
+
+	ctx, cancel := l.newContext()
+	defer cancel()
 
-	proc := newConcurrentProcess(runtime.NumCPU())
+	proc := newConcurrentProcessWithContext(ctx, runtime.NumCPU())
 	localActions := NewLocalActionsCache(project, l.debugWriter())
+	localWorkflows := NewLocalReusableWorkflowCache(project, l.debugWriter())
 	sema := semaphore.NewWeighted(int64(runtime.NumCPU()))
-	ctx := context.Background()
+	var cancelled int32 // Number of files which were not linted due to timeout
 
-	type workspace struct {
-		path string
-		errs []*Error
-		src  []byte
-	}
-
-	ws := make([]workspace, 0, len(filepaths))
+	ws := make([]lintedFile, 0, len(filepaths))
 	for _, p := range filepaths {
-		ws = append(ws, workspace{path: p})
+		ws = append(ws, lintedFile{path: p})
 	}
 
+	// Errors are printed as soon as files are linted in path order
+	sink := l.newErrorSink(len(ws))
+
 	eg := errgroup.Group{}
 	for i := range ws {
 		// Each element of ws is accessed by single goroutine so mutex is unnecessary
+		idx := i
 		w := &ws[i]
 		p := project
 		if p == nil {
@@ -301,25 +530,31 @@ func (l *Linter) LintFiles(filepaths []string, project *Project) ([]*Error, erro
 
 		eg.Go(func() error {
 			// Bound concurrency on reading files to avoid "too many files to open" error (issue #3)
-			sema.Acquire(ctx, 1)
+			if err := sema.Acquire(ctx, 1); err != nil {
+				l.log("Linting", w.path, "was cancelled:", err)
+				atomic.AddInt32(&cancelled, 1)
+				return sink.add(idx, w) // Add the file with no error so that the following files are printed
+			}
 			src, err := ioutil.ReadFile(w.path)
 			sema.Release(1)
 			if err != nil {
 				return fmt.Errorf("could not read %q: %w", w.path, err)
 			}
 
-			if cwd != "" {
-				if r, err := filepath.Rel(cwd, w.path); err == nil {
-					w.path = r // Use relative path if possible
-				}
-			}
-			errs, err := l.check(w.path, src, p, proc, localActions)
+			w.path = l.relPath(base, w.path)
+			errs, err := l.check(w.path, src, p, proc, localActions, localWorkflows)
 			if err != nil {
+				if ctx.Err() != nil {
+					l.log("Linting", w.path, "was cancelled:", err)
+					atomic.AddInt32(&cancelled, 1)
+					w.src = src
+					return sink.add(idx, w)
+				}
 				return fmt.Errorf("fatal error while checking %s: %w", w.path, err)
 			}
 			w.src = src
 			w.errs = errs
-			return nil
+			return sink.add(idx, w)
 		})
 	}
 
@@ -328,35 +563,36 @@ func (l *Linter) LintFiles(filepaths []string, project *Project) ([]*Error, erro
 		return nil, err
 	}
 
-	total := 0
-	for i := range ws {
-		total += len(ws[i].errs)
+	all, err := sink.flush()
+	if err != nil {
+		return nil, err
 	}
 
-	all := make([]*Error, 0, total)
-	if l.errFmt != nil {
-		temp := make([]*ErrorTemplateFields, 0, total)
-		for i := range ws {
-			w := &ws[i]
-			for _, err := range w.errs {
-				temp = append(temp, err.GetTemplateFields(w.src))
-			}
-			all = append(all, w.errs...)
-		}
-		if err := l.errFmt.Print(l.out, temp); err != nil {
-			return nil, err
-		}
-	} else {
-		for i := range ws {
-			w := &ws[i]
-			l.printErrors(w.errs, w.src)
-			all = append(all, w.errs...)
-		}
+	l.log("Found", len(all), "errors in", n, "files")
+
+	if cancelled > 0 {
+		return all, l.timeoutError(int(cancelled), n)
 	}
+	return all, nil
+}
 
-	l.log("Found", total, "errors in", n, "files")
+// lintedFile is a result of linting one file.
+type lintedFile struct {
+	path string
+	errs []*Error
+	src  []byte
+}
 
-	return all, nil
+// printLintedFiles prints errors of the linted files considering the max number of findings and
+// returns all errors of them.
+func (l *Linter) printLintedFiles(ws []lintedFile) ([]*Error, error) {
+	sink := l.newErrorSink(len(ws))
+	for i := range ws {
+		if err := sink.add(i, &ws[i]); err != nil {
+			return nil, err
+		}
+	}
+	return sink.flush()
 }
 
 // LintFile lints one YAML workflow file and outputs the errors to given writer. The project
@@ -371,27 +607,27 @@ func (l *Linter) LintFile(path string, project *Project) ([]*Error, error) {
 		return nil, fmt.Errorf("could not read %q: %w", path, err)
 	}
 
-	cwd := ""
-	if wd, err := os.Getwd(); err == nil {
-		cwd = wd
-		if r, err := filepath.Rel(cwd, path); err == nil {
-			path = r
-		}
-	}
+	path = l.relPath(l.baseDir(), path)
+
+	ctx, cancel := l.newContext()
+	defer cancel()
 
-	proc := newConcurrentProcess(runtime.NumCPU())
+	proc := newConcurrentProcessWithContext(ctx, runtime.NumCPU())
 	localActions := NewLocalActionsCache(project, l.debugWriter())
-	errs, err := l.check(path, src, project, proc, localActions)
+	localWorkflows := NewLocalReusableWorkflowCache(project, l.debugWriter())
+	errs, err := l.check(path, src, project, proc, localActions, localWorkflows)
 	proc.wait()
 	if err != nil {
+		if ctx.Err() != nil {
+			l.log("Linting", path, "was cancelled:", err)
+			return []*Error{}, l.timeoutError(1, 1)
+		}
 		return nil, err
 	}
 
-	if l.errFmt != nil {
-		l.errFmt.PrintErrors(l.out, errs, src)
-	} else {
-		l.printErrors(errs, src)
-	}
+	l.outMu.Lock()
+	l.printFileErrors(path, errs, src)
+	l.outMu.Unlock()
 	return errs, err
 }
 
@@ -401,22 +637,152 @@ func (l *Linter) LintFile(path string, project *Project) ([]*Error, error) {
 // Note that only given Project instance is used for configuration. No config is automatically loaded
 // based on path parameter.
 func (l *Linter) Lint(path string, content []byte, project *Project) ([]*Error, error) {
-	proc := newConcurrentProcess(runtime.NumCPU())
+	ctx, cancel := l.newContext()
+	defer cancel()
+
+	proc := newConcurrentProcessWithContext(ctx, runtime.NumCPU())
 	localActions := NewLocalActionsCache(project, l.debugWriter())
-	errs, err := l.check(path, content, project, proc, localActions)
+	localWorkflows := NewLocalReusableWorkflowCache(project, l.debugWriter())
+	errs, err := l.check(path, content, project, proc, localActions, localWorkflows)
 	proc.wait()
 	if err != nil {
+		if ctx.Err() != nil {
+			l.log("Linting", path, "was cancelled:", err)
+			return []*Error{}, l.timeoutError(1, 1)
+		}
 		return nil, err
 	}
-	if l.errFmt != nil {
-		l.errFmt.PrintErrors(l.out, errs, content)
-	} else {
-		l.printErrors(errs, content)
-	}
+	l.outMu.Lock()
+	l.printFileErrors(path, errs, content)
+	l.outMu.Unlock()
 	return errs, nil
 }
 
-func (l *Linter) check(path string, content []byte, project *Project, proc *concurrentProcess, localActions *LocalActionsCache) ([]*Error, error) {
+// LintDocuments lints YAML content which contains multiple workflows separated with document
+// separator "---" like a stream piped from multiple files. Each document is linted as a separate
+// workflow. The names parameter is a list of file paths of the documents in order. When it is
+// empty, paths of the documents are generated from the path parameter with their 1-based indices
+// like "<stdin>#2". Line numbers in errors are relative to the entire content so that they point
+// the lines in the stream accurately.
+// Note that only given Project instance is used for configuration as well as Lint method.
+func (l *Linter) LintDocuments(path string, content []byte, names []string, project *Project) ([]*Error, error) {
+	docs := splitYAMLDocuments(content)
+	if len(names) > 0 && len(names) != len(docs) {
+		return nil, fmt.Errorf("%d file names are given but %d documents are found in %s", len(names), len(docs), path)
+	}
+	if len(names) == 0 && len(docs) == 1 {
+		return l.Lint(path, content, project)
+	}
+
+	l.log("Linting", len(docs), "documents in", path)
+
+	ctx, cancel := l.newContext()
+	defer cancel()
+
+	proc := newConcurrentProcessWithContext(ctx, runtime.NumCPU())
+	localActions := NewLocalActionsCache(project, l.debugWriter())
+	localWorkflows := NewLocalReusableWorkflowCache(project, l.debugWriter())
+	ws := make([]lintedFile, 0, len(docs))
+	for i, d := range docs {
+		p := fmt.Sprintf("%s#%d", path, i+1)
+		if len(names) > 0 {
+			p = names[i]
+		}
+		if d.err != nil {
+			d.err.Filepath = p
+			ws = append(ws, lintedFile{p, []*Error{d.err}, d.src})
+			continue
+		}
+		errs, err := l.check(p, d.src, project, proc, localActions, localWorkflows)
+		if err != nil {
+			proc.wait()
+			if ctx.Err() != nil {
+				// Print errors of the documents linted until timeout
+				l.log("Linting", p, "was cancelled:", err)
+				all, err := l.printLintedFiles(ws)
+				if err != nil {
+					return nil, err
+				}
+				return all, l.timeoutError(len(docs)-i, len(docs))
+			}
+			return nil, err
+		}
+		ws = append(ws, lintedFile{p, errs, d.src})
+	}
+	proc.wait()
+
+	return l.printLintedFiles(ws)
+}
+
+// yamlDocument is one document in YAML stream split by splitYAMLDocuments.
+type yamlDocument struct {
+	// src is the source of the document. Lines of other documents are replaced with empty lines so
+	// that line numbers in the document are the same as ones in the entire stream.
+	src []byte
+	// err is an error when the document is broken by its separator.
+	err *Error
+}
+
+// Line of YAML document separator. Separators followed by content like "--- !tag" are not supported.
+var reYAMLDocumentSeparator = regexp.MustCompile(`^---\s*(#.*)?$`)
+
+// splitYAMLDocuments splits YAML stream into documents by document separator "---". Documents which
+// contain no content such as a leading separator are omitted.
+func splitYAMLDocuments(src []byte) []yamlDocument {
+	lines := strings.Split(string(src), "\n")
+	docs := []yamlDocument{}
+
+	add := func(start, end int) {
+		first := -1
+		for i := start; i < end; i++ {
+			l := strings.TrimSpace(lines[i])
+			if l != "" && !strings.HasPrefix(l, "#") {
+				first = i
+				break
+			}
+		}
+		if first < 0 {
+			return // Empty document
+		}
+
+		var b strings.Builder
+		b.WriteString(strings.Repeat("\n", start))
+		b.WriteString(strings.Join(lines[start:end], "\n"))
+		doc := yamlDocument{src: []byte(b.String())}
+
+		// When the first line is indented, the separator was put in the middle of mapping
+		if start > 0 && (lines[first][0] == ' ' || lines[first][0] == '\t') {
+			doc.err = &Error{
+				Message: fmt.Sprintf("document separator \"---\" at line %d splits a mapping since the next document starts with indented line. put the separator between top-level mappings", start),
+				Line:    first + 1,
+				Column:  len(lines[first]) - len(strings.TrimLeft(lines[first], " \t")) + 1,
+				Kind:    ErrorKindSyntaxCheck,
+			}
+		}
+
+		docs = append(docs, doc)
+	}
+
+	start := 0
+	for i, l := range lines {
+		if reYAMLDocumentSeparator.MatchString(l) {
+			add(start, i)
+			start = i + 1
+		}
+	}
+	add(start, len(lines))
+
+	return docs
+}
+
+func (l *Linter) check(
+	path string,
+	content []byte,
+	project *Project,
+	proc *concurrentProcess,
+	localActions *LocalActionsCache,
+	localWorkflows *LocalReusableWorkflowCache,
+) ([]*Error, error) {
 	// Note: This method is called to check multiple files in parallel.
 	// It must be thread safe assuming fields of Linter are not modified while running.
 
@@ -446,7 +812,16 @@ func (l *Linter) check(path string, content []byte, project *Project, proc *conc
 		l.debug("No config was found")
 	}
 
-	w, all := Parse(content)
+	var w *Workflow
+	var all []*Error
+	if isActionMetadataFile(path) {
+		// Action metadata file is not a workflow. Only checks for action metadata are applied
+		all = checkActionMetadata(content)
+	} else if l.dependabot && isDependabotConfigFile(path) {
+		all = checkDependabotConfig(content)
+	} else {
+		w, all = Parse(content)
+	}
 
 	if l.logLevel >= LogLevelVerbose {
 		elapsed := time.Since(start)
@@ -457,36 +832,83 @@ func (l *Linter) check(path string, content []byte, project *Project, proc *conc
 		dbg := l.debugWriter()
 
 		var labels []string
+		var hours []int
+		var envs []string
 		if cfg != nil {
 			labels = cfg.SelfHostedRunner.Labels
+			hours = cfg.Schedule.SuspiciousHours
+			envs = cfg.Environments
 		}
 
+		action := NewRuleAction(localActions)
+		action.SetActionsMetadata(l.actionsMeta)
+		expr := NewRuleExpression(localActions)
+		expr.SetActionsMetadata(l.actionsMeta)
+		expr.SetActionOutputsTypes(l.outputsTys)
+		events := NewRuleEvents()
+		events.SetSuspiciousHours(hours)
+		perms := NewRulePermissions()
+		perms.SetLocalReusableWorkflowCache(localWorkflows)
+		secretName := NewRuleSecretName()
+		expr.exprHook = secretName.checkExpr // Secrets referenced in expressions are checked while checking the expressions
+
 		rules := []Rule{
 			NewRuleMatrix(),
 			NewRuleCredentials(),
 			NewRuleShellName(),
 			NewRuleRunnerLabel(labels),
-			NewRuleEvents(),
+			events,
 			NewRuleJobNeeds(),
-			NewRuleAction(localActions),
+			NewRuleEnvironment(envs),
+			action,
 			NewRuleEnvVar(),
+			NewRuleIfCond(),
+			NewRuleSecretsXtrace(),
+			NewRuleWorkflowCommands(),
+			NewRuleCacheKey(),
+			NewRuleSecretsInOutputs(),
+			secretName,
 			NewRuleStepID(),
 			NewRuleGlob(),
-			NewRulePermissions(),
+			perms,
 			NewRuleWorkflowCall(),
-			NewRuleExpression(localActions),
+			NewRuleWorkflowLimits(len(content), localWorkflows),
+			NewRuleTimeoutMinutes(),
+			NewRuleWorkingDirectory(),
+			expr,
+		}
+		names, err := l.enabledRuleNames(cfg)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range l.optionalRules(names, cfg, project) {
+			if r, ok := r.(exprHookRule); ok {
+				h := expr.exprHook
+				expr.exprHook = func(e ExprNode, line, col int) {
+					h(e, line, col)
+					r.checkExpr(e, line, col)
+				}
+			}
+			rules = append(rules, r)
 		}
-		if l.shellcheck != "" {
+		var shellcheck *RuleShellcheck
+		if l.noExternal {
+			l.log("Rule \"shellcheck\" was disabled since external commands were disabled")
+		} else if l.shellcheck != "" {
 			r, err := NewRuleShellcheck(l.shellcheck, proc)
 			if err == nil {
+				r.SetMaxScriptBytes(l.maxShBytes)
 				rules = append(rules, r)
+				shellcheck = r
 			} else {
 				l.log("Rule \"shellcheck\" was disabled:", err)
 			}
 		} else {
 			l.log("Rule \"shellcheck\" was disabled since shellcheck command name was empty")
 		}
-		if l.pyflakes != "" {
+		if l.noExternal {
+			l.log("Rule \"pyflakes\" was disabled since external commands were disabled")
+		} else if l.pyflakes != "" {
 			r, err := NewRulePyflakes(l.pyflakes, proc)
 			if err == nil {
 				rules = append(rules, r)
@@ -513,10 +935,34 @@ func (l *Linter) check(path string, content []byte, project *Project, proc *conc
 			return nil, err
 		}
 
+		if shellcheck != nil {
+			for _, p := range shellcheck.skipped {
+				l.log("Skipped shellcheck for script at", p, "in", path, "since it is larger than", l.maxShBytes, "bytes")
+			}
+		}
+
 		for _, rule := range rules {
 			errs := rule.Errs()
 			l.debug("%s found %d errors", rule.Name(), len(errs))
 			all = append(all, errs...)
+			if f, ok := rule.(Fixer); ok {
+				atomic.StoreInt32(&l.fixerEnabled, 1)
+				resolveFixes(f.Fixes(), content)
+			}
+		}
+
+		if l.postParse != nil {
+			errs := l.postParse(w, path)
+			l.debug("post-parse hook found %d errors", len(errs))
+			for _, err := range errs {
+				if err == nil {
+					continue
+				}
+				if err.Kind == "" {
+					err.Kind = "post-parse"
+				}
+				all = append(all, err)
+			}
 		}
 	}
 
@@ -536,10 +982,38 @@ func (l *Linter) check(path string, content []byte, project *Project, proc *conc
 
 	sort.Sort(ByErrorPosition(all))
 
+	// Errors are filtered by baseline before deduplication since the baseline records each
+	// occurrence and deduplication changes messages
+	if l.baseline != nil {
+		n := len(all)
+		all = l.baseline.Filter(path, all)
+		l.log("Suppressed", n-len(all), "errors in", path, "by baseline")
+	}
+
+	if l.dedup {
+		all = DedupErrors(all)
+	}
+
+	sev, err := l.severityOverrides(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(sev) > 0 {
+		for _, err := range all {
+			if s, ok := sev[err.Kind]; ok {
+				err.SeverityOverride = s
+			}
+		}
+	}
+
 	for _, err := range all {
 		err.Filepath = path // Populate filename in the error
 	}
 
+	if l.sortLess != nil {
+		SortErrors(all, l.sortLess)
+	}
+
 	if l.logLevel >= LogLevelVerbose {
 		elapsed := time.Since(start)
 		l.log("Found total", len(all), "errors in", elapsed.Milliseconds(), "ms for", path)
@@ -548,11 +1022,186 @@ func (l *Linter) check(path string, content []byte, project *Project, proc *conc
 	return all, nil
 }
 
+// baseDir returns the base directory of file paths in errors. Empty string is returned when it is
+// not available.
+func (l *Linter) baseDir() string {
+	if l.relBase != "" {
+		return l.relBase
+	}
+	if wd, err := os.Getwd(); err == nil {
+		return wd
+	}
+	return ""
+}
+
+// relPath converts the path into a path relative to the base directory. When RelativeTo option is
+// given and the path is outside the base directory, its absolute path is returned. Otherwise the
+// path is relative to the current working directory if possible.
+func (l *Linter) relPath(base, path string) string {
+	if base == "" {
+		return path
+	}
+	if l.relBase == "" {
+		if r, err := filepath.Rel(base, path); err == nil {
+			return r // Use relative path if possible
+		}
+		return path
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	r, err := filepath.Rel(base, abs)
+	if err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) {
+		return abs
+	}
+	return r
+}
+
+// severityOverrides returns a mapping from rule names to severities of their errors. Rules given
+// by -error-on flag take precedence over "rules" in config, and "rules" in config takes precedence
+// over the presets. The cfg parameter can be nil.
+func (l *Linter) severityOverrides(cfg *Config) (map[string]string, error) {
+	p, err := l.appliedPreset(cfg)
+	if err != nil {
+		return nil, err
+	}
+	sev := p.Rules
+	if cfg != nil {
+		for n, s := range cfg.Rules {
+			sev[n] = s
+		}
+	}
+	for _, n := range l.errorOn {
+		sev[n] = SeverityError
+	}
+	return sev, nil
+}
+
+// appliedPreset resolves presets given by "extends" in config and -preset flag in this order into
+// one preset. The cfg parameter can be nil.
+func (l *Linter) appliedPreset(cfg *Config) (*Preset, error) {
+	var custom map[string]*Preset
+	var names []string
+	if cfg != nil {
+		custom = cfg.Presets
+		names = cfg.Extends
+	}
+	if _, err := resolvePresets(names, custom); err != nil {
+		return nil, err
+	}
+	// Unknown preset given by -preset flag is an error of the option, not of the config
+	if _, err := resolvePresets(l.presets, custom); err != nil {
+		return nil, &invalidOptionError{err}
+	}
+	return resolvePresets(append(names[:len(names):len(names)], l.presets...), custom)
+}
+
+// enabledRuleNames returns names of optional rules enabled by options and config. Presets given by
+// "extends" in config and -preset flag are resolved first, then rules enabled explicitly by
+// "enable-rules" in config and -enable-rule flag are added. The cfg parameter can be nil.
+func (l *Linter) enabledRuleNames(cfg *Config) ([]string, error) {
+	p, err := l.appliedPreset(cfg)
+	if err != nil {
+		return nil, err
+	}
+	names := p.EnableRules
+	if cfg != nil {
+		names = append(names, cfg.EnableRules...)
+	}
+	return append(names, l.enableRules...), nil
+}
+
+// optionalRules creates rules which are disabled by default but enabled by options or config. The
+// project parameter can be nil.
+func (l *Linter) optionalRules(names []string, cfg *Config, project *Project) []Rule {
+	rules := []Rule{}
+	seen := map[string]struct{}{}
+	for _, n := range names {
+		if _, ok := seen[n]; ok {
+			continue
+		}
+		seen[n] = struct{}{}
+		if f, ok := optionalRules[n]; ok {
+			l.log(fmt.Sprintf("Rule %q was enabled", n))
+			r := f()
+			switch r := r.(type) {
+			case *RuleActionPermissions:
+				r.SetActionsMetadata(l.actionsMeta)
+			case *RuleNodeRuntime:
+				r.SetActionsMetadata(l.actionsMeta)
+			case *RuleDefaultBranch:
+				b := l.defaultBranch
+				if b == "" && cfg != nil {
+					b = cfg.DefaultBranch
+				}
+				if b == "" {
+					l.log("Rule \"default-branch\" checks nothing since the default branch is not given")
+				}
+				r.SetDefaultBranch(b)
+			case *RuleLocalActionPath:
+				r.SetProject(project)
+			case *RulePathFilter:
+				r.SetProject(project)
+			case *RuleWorkingDirectoryExists:
+				r.SetProject(project)
+			}
+			rules = append(rules, r)
+		}
+	}
+	return rules
+}
+
+// limitErrors returns errors to be printed considering the max number of findings. The printed
+// parameter is the number of errors already printed for other files. The second return value is the
+// number of omitted errors.
+func (l *Linter) limitErrors(errs []*Error, printed int) ([]*Error, int) {
+	if l.maxFindings <= 0 {
+		return errs, 0
+	}
+	max := l.maxFindings
+	if !l.maxPerFile {
+		max -= printed
+	}
+	if max < 0 {
+		max = 0
+	}
+	if len(errs) <= max {
+		return errs, 0
+	}
+	return errs[:max], len(errs) - max
+}
+
+func (l *Linter) printOmitted(omitted int, path string) {
+	if omitted == 0 || l.errFmt != nil {
+		return
+	}
+	if path == "" {
+		fmt.Fprintf(l.out, "... and %d more errors\n", omitted)
+	} else {
+		fmt.Fprintf(l.out, "... and %d more errors in %s\n", omitted, path)
+	}
+}
+
+// printFileErrors prints errors in one file considering the max number of findings.
+func (l *Linter) printFileErrors(path string, errs []*Error, src []byte) {
+	errs, omitted := l.limitErrors(errs, 0)
+	if l.errFmt != nil {
+		l.errFmt.PrintErrors(l.out, errs, src)
+		return
+	}
+	l.printErrors(errs, src)
+	if !l.maxPerFile {
+		path = ""
+	}
+	l.printOmitted(omitted, path)
+}
+
 func (l *Linter) printErrors(errs []*Error, src []byte) {
 	if l.oneline {
 		src = nil
 	}
 	for _, err := range errs {
-		err.PrettyPrint(l.out, src)
+		err.PrettyPrint(l.out, src, l.colorful)
 	}
 }
@@ -2,7 +2,10 @@ package actionlint
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"os"
 	"path/filepath"
@@ -11,7 +14,9 @@ import (
 	"sort"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/fatih/color"
 	"github.com/google/go-cmp/cmp"
 	"golang.org/x/sys/execabs"
 )
@@ -131,6 +136,16 @@ func TestLinterLintError(t *testing.T) {
 					opts.Pyflakes = p
 				}
 
+				// Rules disabled by default are enabled when the test name contains their names
+				for n := range optionalRules {
+					if strings.Contains(testName, strings.ReplaceAll(n, "-", "_")) {
+						opts.EnableRules = append(opts.EnableRules, n)
+					}
+				}
+				if strings.Contains(testName, "default_branch") {
+					opts.DefaultBranch = "main"
+				}
+
 				linter, err := NewLinter(ioutil.Discard, &opts)
 				if err != nil {
 					t.Fatal(err)
@@ -183,6 +198,607 @@ func TestLinterLintError(t *testing.T) {
 	}
 }
 
+func TestLinterLintConcurrently(t *testing.T) {
+	dir, infiles, err := testFindAllWorkflowsInDir("examples")
+	if err != nil {
+		panic(err)
+	}
+	proj := &Project{root: dir}
+
+	srcs := make([][]byte, 0, len(infiles))
+	for _, f := range infiles {
+		b, err := ioutil.ReadFile(f)
+		if err != nil {
+			panic(err)
+		}
+		srcs = append(srcs, b)
+	}
+
+	var out strings.Builder
+	l, err := NewLinter(&out, &LinterOptions{Oneline: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.defaultConfig = &Config{}
+
+	// Note: Messages and positions are not compared since some of them are not deterministic. For
+	// example, order of properties in object types is not stable.
+	locs := func(errs []*Error) []string {
+		ret := make([]string, 0, len(errs))
+		for _, e := range errs {
+			ret = append(ret, fmt.Sprintf("%s:%s", e.Filepath, e.Kind))
+		}
+		return ret
+	}
+
+	want := make([][]string, 0, len(infiles))
+	for i, f := range infiles {
+		errs, err := l.Lint(f, srcs[i], proj)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, locs(errs))
+	}
+	wantLines := strings.Count(out.String(), "\n")
+	out.Reset()
+
+	// The same Linter instance is reused across goroutines
+	have := make([][]string, len(infiles))
+	errc := make(chan error, len(infiles))
+	for i, f := range infiles {
+		go func(i int, f string) {
+			errs, err := l.Lint(f, srcs[i], proj)
+			have[i] = locs(errs)
+			errc <- err
+		}(i, f)
+	}
+	for range infiles {
+		if err := <-errc; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// Outputs from each call should not be mixed
+	haveLines := strings.Count(out.String(), "\n")
+	if wantLines != haveLines {
+		t.Fatalf("wanted %d lines in output but got %d lines", wantLines, haveLines)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		if !strings.HasPrefix(line, dir) {
+			t.Fatalf("output line should start with file path: %q", line)
+		}
+	}
+}
+
+func TestLinterMaxFindings(t *testing.T) {
+	files := []string{
+		filepath.Join("testdata", "err", "workflow_call_job.yaml"),  // 11 errors
+		filepath.Join("testdata", "examples", "env_shadowing.yaml"), // 2 errors
+	}
+
+	tests := []struct {
+		what    string
+		max     int
+		perFile bool
+		errors  int
+		notes   []string
+	}{
+		{"no limit", 0, false, 13, nil},
+		{"global limit", 3, false, 3, []string{"... and 10 more errors"}},
+		{"global limit across files", 12, false, 12, []string{"... and 1 more errors"}},
+		{"global limit larger than errors", 13, false, 13, nil},
+		{
+			"per file limit",
+			1,
+			true,
+			2,
+			[]string{
+				"... and 10 more errors in " + files[0],
+				"... and 1 more errors in " + files[1],
+			},
+		},
+		{"per file limit larger than errors in one file", 2, true, 4, []string{"... and 9 more errors in " + files[0]}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.what, func(t *testing.T) {
+			var b strings.Builder
+			opts := LinterOptions{
+				Oneline:            true,
+				MaxFindings:        tc.max,
+				MaxFindingsPerFile: tc.perFile,
+				EnableRules:        []string{"env-shadowing"},
+			}
+			l, err := NewLinter(&b, &opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			l.defaultConfig = &Config{}
+
+			errs, err := l.LintFiles(files, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(errs) != 13 {
+				t.Fatalf("all errors should be returned regardless of the limit but got %d errors", len(errs))
+			}
+
+			printed := 0
+			notes := []string{}
+			for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+				if strings.HasPrefix(line, "... ") {
+					notes = append(notes, line)
+				} else {
+					printed++
+				}
+			}
+			if printed != tc.errors {
+				t.Errorf("wanted %d errors printed but got %d: %q", tc.errors, printed, b.String())
+			}
+			if tc.notes == nil {
+				tc.notes = []string{}
+			}
+			if diff := cmp.Diff(tc.notes, notes); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestLinterSortErrors(t *testing.T) {
+	dir := t.TempDir()
+	srcs := []string{
+		"on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: ls | grep foo\n        shell: sh\n",
+		"on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo ${{ unknown }}\n      - run: ls | grep foo\n        shell: sh\n",
+	}
+	files := make([]string, 0, len(srcs))
+	for i, src := range srcs {
+		f := filepath.Join(dir, fmt.Sprintf("%d.yaml", i))
+		if err := ioutil.WriteFile(f, []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, f)
+	}
+
+	testCases := []struct {
+		what string
+		max  int
+		want []string
+	}{
+		{
+			what: "all errors",
+			want: []string{
+				"1.yaml:6:23: [expression]",
+				"0.yaml:6:9: [pipefail]",
+				"1.yaml:7:9: [pipefail]",
+			},
+		},
+		{
+			what: "max findings",
+			max:  2,
+			want: []string{
+				"1.yaml:6:23: [expression]",
+				"0.yaml:6:9: [pipefail]",
+				"... and 1 more errors",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			var b strings.Builder
+			opts := LinterOptions{
+				Oneline:     true,
+				EnableRules: []string{"pipefail"},
+				MaxFindings: tc.max,
+				RelativeTo:  dir,
+				Sort:        ErrorLessBySeverity,
+			}
+			l, err := NewLinter(&b, &opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			l.defaultConfig = &Config{}
+
+			errs, err := l.LintFiles(files, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(errs) != 3 || errs[0].Kind != "expression" {
+				t.Fatalf("all errors should be returned in sorted order: %v", errs)
+			}
+
+			have := []string{}
+			re := regexp.MustCompile(`^(\S+:\d+:\d+: ).* (\[\S+\])$`)
+			for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+				if m := re.FindStringSubmatch(line); m != nil {
+					line = m[1] + m[2]
+				}
+				have = append(have, line)
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestLinterRelativeTo(t *testing.T) {
+	inside := filepath.Join("testdata", "examples", "env_var_names.yaml")
+	outside := filepath.Join("testdata", "err", "workflow_call_job.yaml")
+	abs, err := filepath.Abs(outside)
+	if err != nil {
+		panic(err)
+	}
+
+	tests := []struct {
+		what  string
+		base  string
+		files []string
+		want  []string
+	}{
+		{"one file", "testdata", []string{inside}, []string{filepath.Join("examples", "env_var_names.yaml")}},
+		{"multiple files", "testdata", []string{inside, outside}, []string{filepath.Join("examples", "env_var_names.yaml"), filepath.Join("err", "workflow_call_job.yaml")}},
+		{"outside base", filepath.Join("testdata", "examples"), []string{inside, outside}, []string{"env_var_names.yaml", abs}},
+		{"current directory", "", []string{inside}, []string{inside}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.what, func(t *testing.T) {
+			l, err := NewLinter(ioutil.Discard, &LinterOptions{RelativeTo: tc.base})
+			if err != nil {
+				t.Fatal(err)
+			}
+			l.defaultConfig = &Config{}
+
+			errs, err := l.LintFiles(tc.files, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			have := []string{}
+			seen := map[string]struct{}{}
+			for _, e := range errs {
+				if _, ok := seen[e.Filepath]; !ok {
+					seen[e.Filepath] = struct{}{}
+					have = append(have, e.Filepath)
+				}
+			}
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
+
+func TestLinterRelativeToCurrentDirectoryByDefault(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "test.yaml")
+	if err := ioutil.WriteFile(path, []byte("on: push\njobs:\n  test:\n    runs-on: linux-latest\n    steps:\n      - run: echo\n"), 0644); err != nil {
+		panic(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	want, err := filepath.Rel(wd, path)
+	if err != nil {
+		t.Skip("temporary directory cannot be relative to current directory:", err)
+	}
+
+	l, err := NewLinter(ioutil.Discard, &LinterOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.defaultConfig = &Config{}
+
+	errs, err := l.LintFiles([]string{path}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) == 0 {
+		t.Fatal("no error was found")
+	}
+	// Paths outside the current directory are relative like ../foo when -relative-to is not given
+	if errs[0].Filepath != want {
+		t.Fatalf("wanted %q but got %q", want, errs[0].Filepath)
+	}
+}
+
+func TestLinterRelativeToNotDirectory(t *testing.T) {
+	_, err := NewLinter(ioutil.Discard, &LinterOptions{RelativeTo: filepath.Join("testdata", "does-not-exist")})
+	if err == nil {
+		t.Fatal("error did not occur")
+	}
+	if !strings.Contains(err.Error(), "is not a directory") {
+		t.Fatalf("unexpected error message: %q", err)
+	}
+}
+
+func TestLinterLintDocuments(t *testing.T) {
+	src := `---
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo ${{ foo }}
+---
+# Comment
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+---
+on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+---
+    steps:
+      - run: echo ${{ bar }}
+`
+
+	testCases := []struct {
+		what  string
+		names []string
+		want  []string
+	}{
+		{
+			what: "synthetic names",
+			want: []string{
+				"<stdin>#1:7:23:",
+				"<stdin>#3:19:3:",
+				"<stdin>#4:22:5: document separator \"---\" at line 21 splits a mapping",
+			},
+		},
+		{
+			what:  "given names",
+			names: []string{"a.yaml", "b.yaml", "c.yaml", "d.yaml"},
+			want: []string{
+				"a.yaml:7:23:",
+				"c.yaml:19:3:",
+				"d.yaml:22:5: document separator \"---\" at line 21 splits a mapping",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			l, err := NewLinter(ioutil.Discard, &LinterOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			errs, err := l.LintDocuments("<stdin>", []byte(src), tc.names, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(errs) != len(tc.want) {
+				t.Fatalf("wanted %d errors but got %d: %v", len(tc.want), len(errs), errs)
+			}
+			for i, want := range tc.want {
+				if have := errs[i].Error(); !strings.HasPrefix(have, want) {
+					t.Errorf("error %d should start with %q but got %q", i, want, have)
+				}
+			}
+		})
+	}
+}
+
+func TestLinterLintDocumentsNamesMismatch(t *testing.T) {
+	l, err := NewLinter(ioutil.Discard, &LinterOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := "on: push\n---\non: push\n"
+	_, err = l.LintDocuments("<stdin>", []byte(src), []string{"a.yaml"}, nil)
+	if err == nil {
+		t.Fatal("error did not occur")
+	}
+	want := "1 file names are given but 2 documents are found in <stdin>"
+	if err.Error() != want {
+		t.Fatalf("wanted error %q but got %q", want, err.Error())
+	}
+}
+
+func TestLinterLintDocumentsSingleDocument(t *testing.T) {
+	l, err := NewLinter(ioutil.Discard, &LinterOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := "---\non: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n"
+	errs, err := l.LintDocuments("<stdin>", []byte(src), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || errs[0].Filepath != "<stdin>" || errs[0].Line != 4 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+}
+
+func TestLinterActionsMetadataFile(t *testing.T) {
+	opts := &LinterOptions{ActionsMetadataFile: filepath.Join("testdata", "actions_metadata_file", "ok.yaml")}
+	l, err := NewLinter(ioutil.Discard, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - id: my
+        uses: my-org/my-action@v1
+        with:
+          tokn: xxx
+      - run: echo ${{ steps.my.outputs.reslt }}
+      - id: other
+        uses: my-org/other-action@v2
+        with:
+          path: foo
+      - run: echo ${{ steps.other.outputs.anything }}
+      - uses: my-org/typed-action@v1
+        with:
+          count: many
+          dry-run: yes
+          mode: medium
+      - uses: my-org/typed-action@v1
+        with:
+          count: 1.5
+          dry-run: ${{ github.event_name == 'pull_request' }}
+          mode: fast
+`
+	errs, err := l.Lint("test.yaml", []byte(src), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`test.yaml:7:15: missing input "token" which is required by action "my-org/my-action@v1"`,
+		`test.yaml:9:11: input "tokn" is not defined in action "my-org/my-action@v1"`,
+		`test.yaml:10:23: property "reslt" is not defined in object type {result: string}`,
+		`test.yaml:18:18: input "count" of action "my-org/typed-action@v1" expects number value but "many" is not a number`,
+		`test.yaml:19:20: input "dry-run" of action "my-org/typed-action@v1" expects boolean value but "yes" is not a boolean`,
+		`test.yaml:20:17: input "mode" of action "my-org/typed-action@v1" expects choice value but "medium" is not one of the options "fast", "slow"`,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("wanted %d errors but got %v", len(want), errs)
+	}
+	for i, w := range want {
+		if have := errs[i].Error(); !strings.HasPrefix(have, w) {
+			t.Errorf("error %d should start with %q but got %q", i, w, have)
+		}
+	}
+}
+
+func TestLinterActionOutputsTypes(t *testing.T) {
+	opts := &LinterOptions{
+		ActionOutputsTypes: map[string]ExprType{
+			"actions/github-script": NewStrictObjectType(map[string]ExprType{"result": StringType{}}),
+			"my-org/my-action@v1": NewStrictObjectType(map[string]ExprType{
+				"count": NumberType{},
+				"files": &ArrayType{Elem: StringType{}},
+			}),
+			"./path/to/action": NewMapObjectType(BoolType{}),
+		},
+	}
+	l, err := NewLinter(ioutil.Discard, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - id: script
+        uses: actions/github-script@v6
+        with:
+          script: return 'hello'
+      - run: echo ${{ steps.script.outputs.result }} ${{ steps.script.outputs.foo }}
+      - id: my
+        uses: my-org/my-action@v1
+      - run: echo ${{ steps.my.outputs.files[0] }} ${{ steps.my.outputs.count.foo }}
+      - id: local
+        uses: ./path/to/action
+      - run: echo ${{ steps.local.outputs.foo.bar }}
+`
+	errs, err := l.Lint("test.yaml", []byte(src), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`test.yaml:10:58: property "foo" is not defined in object type {result: string}`,
+		`test.yaml:13:56: receiver of object dereference "foo" must be type of object but got "number"`,
+		`test.yaml:16:23: receiver of object dereference "bar" must be type of object but got "bool"`,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("wanted %d errors but got %v", len(want), errs)
+	}
+	for i, w := range want {
+		if have := errs[i].Error(); !strings.HasPrefix(have, w) {
+			t.Errorf("error %d should start with %q but got %q", i, w, have)
+		}
+	}
+}
+
+func TestLinterActionsMetadataFileError(t *testing.T) {
+	opts := &LinterOptions{ActionsMetadataFile: filepath.Join("testdata", "actions_metadata_file", "does-not-exist.yaml")}
+	if _, err := NewLinter(ioutil.Discard, opts); err == nil {
+		t.Fatal("error did not occur")
+	}
+}
+
+func TestLinterColorOption(t *testing.T) {
+	f, err := ioutil.TempFile("", "actionlint-color-test-")
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		f.Close()
+		os.Remove(f.Name())
+	}()
+
+	testCases := []struct {
+		what string
+		kind ColorOptionKind
+		out  io.Writer
+		env  map[string]string
+		want bool
+	}{
+		{"auto with non-file writer", ColorOptionKindAuto, &bytes.Buffer{}, nil, false},
+		{"auto with non-terminal file", ColorOptionKindAuto, f, nil, false},
+		{"auto with $NO_COLOR", ColorOptionKindAuto, f, map[string]string{"NO_COLOR": "1"}, false},
+		{"auto with dumb terminal", ColorOptionKindAuto, f, map[string]string{"TERM": "dumb"}, false},
+		{"always", ColorOptionKindAlways, &bytes.Buffer{}, nil, true},
+		{"always overrides $NO_COLOR", ColorOptionKindAlways, &bytes.Buffer{}, map[string]string{"NO_COLOR": "1"}, true},
+		{"never", ColorOptionKindNever, &bytes.Buffer{}, nil, false},
+		{"never with empty $NO_COLOR", ColorOptionKindNever, &bytes.Buffer{}, map[string]string{"NO_COLOR": ""}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			getenv := func(k string) string { return tc.env[k] }
+			if have := isColorEnabled(tc.kind, tc.out, getenv); have != tc.want {
+				t.Fatalf("wanted %v but got %v", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestLinterColorDoesNotChangeGlobalFlag(t *testing.T) {
+	saved := color.NoColor
+	defer func() { color.NoColor = saved }()
+
+	for _, kind := range []ColorOptionKind{ColorOptionKindAlways, ColorOptionKindNever} {
+		color.NoColor = kind == ColorOptionKindAlways // Opposite to the option
+		want := color.NoColor
+
+		var b bytes.Buffer
+		l, err := NewLinter(&b, &LinterOptions{Color: kind})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if color.NoColor != want {
+			t.Fatalf("NewLinter modified the global flag with option %v", kind)
+		}
+		l.defaultConfig = &Config{}
+
+		if _, err := l.Lint("test.yaml", []byte("on: push\njobs:\n  test:\n    runs-on: linux-latest\n    steps:\n      - run: echo\n"), nil); err != nil {
+			t.Fatal(err)
+		}
+		colored := strings.Contains(b.String(), "\x1b[")
+		if colored != (kind == ColorOptionKindAlways) {
+			t.Fatalf("colorful output is %v with option %v: %q", colored, kind, b.String())
+		}
+	}
+}
+
 func TestLinterFormatErrorMessageOK(t *testing.T) {
 	tests := []struct {
 		file   string
@@ -200,6 +816,10 @@ func TestLinterFormatErrorMessageOK(t *testing.T) {
 			file:   "test.jsonl",
 			format: "{{range $err := .}}{{json $err}}{{end}}",
 		},
+		{
+			file:   "test.jsonl",
+			format: ErrorFormatJSONL,
+		},
 		{
 			file:   "test.md",
 			format: "{{range $ := .}}### Error at line {{$.Line}}, col {{$.Column}} of `{{$.Filepath}}`\\n\\n{{$.Message}}\\n\\n```\\n{{$.Snippet}}\\n```\\n\\n{{end}}",
@@ -433,6 +1053,66 @@ func BenchmarkLintWorkflowContent(b *testing.B) {
 	}
 }
 
+func BenchmarkLintWorkflowContentReuseLinter(b *testing.B) {
+	dir, err := os.Getwd()
+	if err != nil {
+		panic(err)
+	}
+	proj := &Project{root: dir}
+
+	// Compare this benchmark with BenchmarkLintWorkflowContent. Linter instance is created only once
+	// and reused for all iterations.
+
+	for _, name := range []string{"minimal", "small", "large"} {
+		var f string
+		switch name {
+		case "minimal":
+			f = filepath.Join(dir, "testdata", "bench", "minimal.yaml")
+		case "small":
+			f = filepath.Join(dir, "testdata", "bench", "small.yaml")
+		case "large":
+			f = filepath.Join(dir, "testdata", "bench", "many_scripts.yaml")
+		}
+		content, err := ioutil.ReadFile(f)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		opts := LinterOptions{}
+		l, err := NewLinter(ioutil.Discard, &opts)
+		if err != nil {
+			b.Fatal(err)
+		}
+		l.defaultConfig = &Config{}
+
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				errs, err := l.Lint(f, content, proj)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if len(errs) > 0 {
+					b.Fatal("some error occurred:", errs)
+				}
+			}
+		})
+
+		b.Run(name+"-parallel", func(b *testing.B) {
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					errs, err := l.Lint(f, content, proj)
+					if err != nil {
+						b.Fatal(err)
+					}
+					if len(errs) > 0 {
+						b.Fatal("some error occurred:", errs)
+					}
+				}
+			})
+		})
+	}
+}
+
 func BenchmarkExamplesLintFiles(b *testing.B) {
 	dir, files, err := testFindAllWorkflowsInDir("examples")
 	if err != nil {
@@ -488,3 +1168,440 @@ func BenchmarkLintRepository(b *testing.B) {
 		}
 	}
 }
+
+func TestLinterLintFilesTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script cannot be used as fake shellcheck command on Windows")
+	}
+
+	dir := t.TempDir()
+	shellcheck := filepath.Join(dir, "shellcheck")
+	if err := ioutil.WriteFile(shellcheck, []byte("#!/bin/sh\nexec sleep 10\n"), 0755); err != nil {
+		panic(err)
+	}
+	ok := filepath.Join(dir, "ok.yaml")
+	if err := ioutil.WriteFile(ok, []byte("on: push\njobs:\n  test:\n    steps:\n      - uses: actions/checkout@v4\n"), 0644); err != nil {
+		panic(err)
+	}
+	hang := filepath.Join(dir, "hang.yaml")
+	if err := ioutil.WriteFile(hang, []byte("on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo hello\n"), 0644); err != nil {
+		panic(err)
+	}
+
+	var out bytes.Buffer
+	opts := LinterOptions{
+		Shellcheck: shellcheck,
+		Timeout:    200 * time.Millisecond,
+		Oneline:    true,
+		Color:      ColorOptionKindNever,
+	}
+	l, err := NewLinter(&out, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.defaultConfig = &Config{}
+
+	start := time.Now()
+	errs, err := l.LintFiles([]string{hang, ok}, nil)
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("wanted timeout error but got %v", err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Fatalf("shellcheck process was not killed on timeout. it took %s", d)
+	}
+	if !strings.Contains(err.Error(), "1 of 2 files were not linted completely") {
+		t.Fatalf("unexpected error message: %q", err)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, `"runs-on" section is missing`) {
+		t.Fatalf("errors in the file linted before timeout were not returned: %v", errs)
+	}
+	if !strings.Contains(out.String(), `"runs-on" section is missing`) {
+		t.Fatalf("errors in the file linted before timeout were not printed: %q", out.String())
+	}
+}
+
+func TestLinterDisableExternal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("shell script cannot be used as fake shellcheck command on Windows")
+	}
+
+	dir := t.TempDir()
+	// Fake commands which fail to run
+	for _, n := range []string{"shellcheck", "pyflakes"} {
+		if err := ioutil.WriteFile(filepath.Join(dir, n), []byte("#!/bin/sh\nexit 2\n"), 0755); err != nil {
+			panic(err)
+		}
+	}
+	f := filepath.Join(dir, "test.yaml")
+	src := "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo hello\n      - run: print('hello')\n        shell: python\n      - run: echo '${{ unknown }}'\n"
+	if err := ioutil.WriteFile(f, []byte(src), 0644); err != nil {
+		panic(err)
+	}
+
+	var log bytes.Buffer
+	opts := LinterOptions{
+		Shellcheck:      filepath.Join(dir, "shellcheck"),
+		Pyflakes:        filepath.Join(dir, "pyflakes"),
+		DisableExternal: true,
+		Verbose:         true,
+		LogWriter:       &log,
+	}
+	l, err := NewLinter(ioutil.Discard, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.defaultConfig = &Config{}
+
+	errs, err := l.LintFile(f, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || errs[0].Kind != "expression" {
+		t.Fatalf("only the error from expression rule was expected but got %v", errs)
+	}
+	for _, r := range []string{"shellcheck", "pyflakes"} {
+		want := fmt.Sprintf("Rule %q was disabled since external commands were disabled", r)
+		if !strings.Contains(log.String(), want) {
+			t.Errorf("log %q does not contain %q", log.String(), want)
+		}
+	}
+}
+
+func TestLinterDefaultBranch(t *testing.T) {
+	src := []byte("on:\n  push:\n    branches: [master]\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n")
+
+	testCases := []struct {
+		what   string
+		opt    string
+		config string
+		want   string
+	}{
+		{"option", "main", "", `default branch "main"`},
+		{"config", "", "main", `default branch "main"`},
+		{"option precedes config", "develop", "main", ""},
+		{"not given", "", "", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			opts := LinterOptions{
+				EnableRules:   []string{"default-branch"},
+				DefaultBranch: tc.opt,
+			}
+			l, err := NewLinter(ioutil.Discard, &opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			l.defaultConfig = &Config{DefaultBranch: tc.config}
+
+			errs, err := l.Lint("test.yaml", src, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Message, tc.want) {
+				t.Fatalf("error message should contain %q but got %v", tc.want, errs)
+			}
+		})
+	}
+}
+
+func TestLinterPresets(t *testing.T) {
+	src := []byte("on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: cat foo | grep bar\n")
+
+	testCases := []struct {
+		what    string
+		presets []string
+		config  *Config
+		want    int
+	}{
+		{"no preset", nil, &Config{}, 0},
+		{"built-in preset", []string{"strict"}, &Config{}, 1},
+		{"built-in preset not enabling rule", []string{"security"}, &Config{}, 0},
+		{"extends in config", nil, &Config{Extends: []string{"strict"}}, 1},
+		{
+			"custom preset",
+			[]string{"mine"},
+			&Config{Presets: map[string]*Preset{"mine": {EnableRules: []string{"pipefail"}}}},
+			1,
+		},
+		{
+			"custom preset disabling rule",
+			[]string{"no-pipefail"},
+			&Config{
+				Extends: []string{"strict"},
+				Presets: map[string]*Preset{"no-pipefail": {DisableRules: []string{"pipefail"}}},
+			},
+			0,
+		},
+		{
+			"rule disabled by preset enabled explicitly",
+			[]string{"no-pipefail"},
+			&Config{
+				EnableRules: []string{"pipefail"},
+				Presets:     map[string]*Preset{"no-pipefail": {DisableRules: []string{"pipefail"}}},
+			},
+			1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			opts := LinterOptions{Presets: tc.presets}
+			l, err := NewLinter(ioutil.Discard, &opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			l.defaultConfig = tc.config
+
+			errs, err := l.Lint("test.yaml", src, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			n := 0
+			for _, e := range errs {
+				if e.Kind == "pipefail" {
+					n++
+				}
+			}
+			if n != tc.want {
+				t.Fatalf("wanted %d pipefail errors but got %v", tc.want, errs)
+			}
+		})
+	}
+}
+
+func TestLinterSeverityOverrides(t *testing.T) {
+	src := []byte("on: push\njobs:\n  test:\n    runs-on: foo\n    steps:\n      - run: cat foo | grep bar\n")
+	opts := LinterOptions{
+		EnableRules: []string{"pipefail"},
+		ErrorOn:     []string{"pipefail"},
+	}
+	l, err := NewLinter(ioutil.Discard, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.defaultConfig = &Config{
+		Rules: map[string]string{"runner-label": SeverityWarning, "pipefail": SeverityWarning},
+	}
+
+	errs, err := l.Lint("test.yaml", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"runner-label": SeverityWarning, "pipefail": SeverityError}
+	if len(errs) != len(want) {
+		t.Fatalf("wanted %d errors but got %v", len(want), errs)
+	}
+	for _, e := range errs {
+		if s := e.Severity(); s != want[e.Kind] {
+			t.Errorf("wanted severity %q for %q but got %q", want[e.Kind], e.Kind, s)
+		}
+	}
+}
+
+func TestLinterPresetSeverities(t *testing.T) {
+	src := []byte("on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: cat foo | grep bar\n        working-directory: /tmp/build\n")
+
+	testCases := []struct {
+		what    string
+		presets []string
+		config  *Config
+		want    map[string]string
+	}{
+		{
+			"default severities",
+			nil,
+			&Config{EnableRules: []string{"pipefail"}},
+			map[string]string{"pipefail": SeverityWarning, "working-directory": SeverityWarning},
+		},
+		{
+			"strict preset reports all errors as errors",
+			[]string{"strict"},
+			&Config{},
+			map[string]string{"pipefail": SeverityError, "working-directory": SeverityError},
+		},
+		{
+			"rules in config take precedence over preset",
+			[]string{"strict"},
+			&Config{Rules: map[string]string{"pipefail": SeverityWarning}},
+			map[string]string{"pipefail": SeverityWarning, "working-directory": SeverityError},
+		},
+		{
+			"custom preset",
+			nil,
+			&Config{
+				Extends: []string{"mine"},
+				Presets: map[string]*Preset{
+					"mine": {EnableRules: []string{"pipefail"}, Rules: map[string]string{"working-directory": SeverityError}},
+				},
+			},
+			map[string]string{"pipefail": SeverityWarning, "working-directory": SeverityError},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			opts := LinterOptions{Presets: tc.presets}
+			l, err := NewLinter(ioutil.Discard, &opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			l.defaultConfig = tc.config
+
+			errs, err := l.Lint("test.yaml", src, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			have := map[string]string{}
+			for _, e := range errs {
+				if _, ok := tc.want[e.Kind]; ok {
+					have[e.Kind] = e.Severity()
+				}
+			}
+			if !cmp.Equal(tc.want, have) {
+				t.Fatal(cmp.Diff(tc.want, have))
+			}
+		})
+	}
+}
+
+func TestLinterUnknownRuleOfErrorOn(t *testing.T) {
+	opts := LinterOptions{ErrorOn: []string{"shellchek"}}
+	_, err := NewLinter(ioutil.Discard, &opts)
+	if err == nil {
+		t.Fatal("error did not occur")
+	}
+	if !strings.Contains(err.Error(), `unknown rule "shellchek". did you mean "shellcheck"?`) {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !errors.Is(err, ErrInvalidOption) {
+		t.Fatalf("error should wrap ErrInvalidOption: %s", err)
+	}
+}
+
+func TestLinterUnknownPreset(t *testing.T) {
+	src := []byte("on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n")
+	opts := LinterOptions{Presets: []string{"secure"}}
+	l, err := NewLinter(ioutil.Discard, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.defaultConfig = &Config{}
+
+	_, err = l.Lint("test.yaml", src, nil)
+	if err == nil {
+		t.Fatal("error did not occur")
+	}
+	if !strings.Contains(err.Error(), `unknown preset "secure"`) {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !errors.Is(err, ErrInvalidOption) {
+		t.Fatalf("error should wrap ErrInvalidOption: %s", err)
+	}
+}
+
+func TestLinterPostParse(t *testing.T) {
+	src := []byte("on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo ${{ unknown }}\n  no-timeout:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n")
+
+	var called []string
+	opts := LinterOptions{
+		PostParse: func(w *Workflow, path string) []*Error {
+			called = append(called, path)
+			errs := []*Error{nil}
+			for _, id := range []string{"no-timeout", "test"} {
+				j := w.Jobs[id]
+				if j.TimeoutMinutes == nil {
+					errs = append(errs, errorfAt(j.ID.Pos, "", "job %q must set timeout-minutes", id))
+				}
+			}
+			errs[0] = &Error{Message: "org policy", Line: 1, Column: 1, Kind: "org-policy"}
+			return errs
+		},
+		IgnorePatterns: []string{`job "test" must`},
+	}
+	l, err := NewLinter(ioutil.Discard, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.defaultConfig = &Config{}
+
+	errs, err := l.Lint("test.yaml", src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(called) != 1 || called[0] != "test.yaml" {
+		t.Fatalf("hook should be called once with the file path but got %v", called)
+	}
+
+	have := make([]string, 0, len(errs))
+	for _, e := range errs {
+		have = append(have, e.Error())
+	}
+	want := []string{
+		"test.yaml:1:1: org policy [org-policy]",
+		`test.yaml:6:23: undefined variable "unknown". available variables are "env", "github", "inputs", "job", "matrix", "needs", "runner", "secrets", "steps", "strategy" [expression]`,
+		`test.yaml:7:3: job "no-timeout" must set timeout-minutes [post-parse]`,
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatal(diff)
+	}
+
+	called = nil
+	if _, err := l.Lint("test.yaml", []byte("on: push\njobs:\n  test:\n    runs-on: [\n"), nil); err != nil {
+		t.Fatal(err)
+	}
+	if len(called) != 0 {
+		t.Fatal("hook should not be called for broken workflow", called)
+	}
+}
+
+func TestLinterDependabot(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []string{".git", filepath.Join(".github", "workflows")} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			panic(err)
+		}
+	}
+	wf := "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo hello\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, ".github", "workflows", "test.yaml"), []byte(wf), 0644); err != nil {
+		panic(err)
+	}
+	cfg := "version: 2\nupdates:\n  - package-ecosystem: github-action\n    directory: /\n    schedule:\n      interval: weekly\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, ".github", "dependabot.yml"), []byte(cfg), 0644); err != nil {
+		panic(err)
+	}
+
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprint(enabled), func(t *testing.T) {
+			l, err := NewLinter(ioutil.Discard, &LinterOptions{Dependabot: enabled})
+			if err != nil {
+				t.Fatal(err)
+			}
+			l.defaultConfig = &Config{}
+
+			errs, err := l.LintRepository(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !enabled {
+				if len(errs) != 0 {
+					t.Fatalf("dependabot.yml should not be linted but got %v", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("wanted 1 error from dependabot.yml but got %v", errs)
+			}
+			e := errs[0]
+			if e.Kind != "dependabot" || e.Line != 3 || e.Column != 24 || filepath.Base(e.Filepath) != "dependabot.yml" {
+				t.Fatalf("unexpected error %v", e)
+			}
+		})
+	}
+}
@@ -0,0 +1,387 @@
+package actionlint
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf16"
+)
+
+// Minimal subset of Language Server Protocol messages.
+// https://microsoft.github.io/language-server-protocol/specifications/specification-3-16/
+
+const (
+	lspErrorParse          = -32700
+	lspErrorMethodNotFound = -32601
+	lspErrorInvalidParams  = -32602
+)
+
+type lspRequest struct {
+	JSONRPC string           `json:"jsonrpc"`
+	ID      *json.RawMessage `json:"id"`
+	Method  string           `json:"method"`
+	Params  json.RawMessage  `json:"params"`
+}
+
+type lspResponseError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type lspResponse struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      *json.RawMessage  `json:"id"`
+	Result  interface{}       `json:"result"`
+	Error   *lspResponseError `json:"error,omitempty"`
+}
+
+type lspNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
+type lspTextDocumentItem struct {
+	URI  string `json:"uri"`
+	Text string `json:"text"`
+}
+
+type lspDidOpenParams struct {
+	TextDocument lspTextDocumentItem `json:"textDocument"`
+}
+
+type lspDidChangeParams struct {
+	TextDocument   lspTextDocumentItem `json:"textDocument"`
+	ContentChanges []struct {
+		Text string `json:"text"`
+	} `json:"contentChanges"`
+}
+
+type lspDidCloseParams struct {
+	TextDocument lspTextDocumentItem `json:"textDocument"`
+}
+
+type lspPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type lspRange struct {
+	Start lspPosition `json:"start"`
+	End   lspPosition `json:"end"`
+}
+
+type lspDiagnostic struct {
+	Range    lspRange `json:"range"`
+	Severity int      `json:"severity"`
+	Code     string   `json:"code"`
+	Source   string   `json:"source"`
+	Message  string   `json:"message"`
+}
+
+type lspShowMessageParams struct {
+	Type    int    `json:"type"`
+	Message string `json:"message"`
+}
+
+type lspPublishDiagnosticsParams struct {
+	URI         string          `json:"uri"`
+	Diagnostics []lspDiagnostic `json:"diagnostics"`
+}
+
+// LanguageServer is a minimal language server of Language Server Protocol. It communicates with a
+// client via the given reader and writer. It only supports publishing diagnostics on opening and
+// changing documents for now.
+// https://microsoft.github.io/language-server-protocol/
+type LanguageServer struct {
+	linter   *Linter
+	in       *bufio.Reader
+	out      io.Writer
+	outMu    sync.Mutex
+	mu       sync.Mutex
+	timers   map[string]*time.Timer
+	shutdown bool
+	// Debounce is a duration to wait for linting a document after it was changed. When the document
+	// is changed again while waiting, the timer is reset.
+	Debounce time.Duration
+}
+
+// NewLanguageServer creates a new LanguageServer instance. The linter is reused for linting
+// documents. Outputs of the linter are not used.
+func NewLanguageServer(linter *Linter, in io.Reader, out io.Writer) *LanguageServer {
+	return &LanguageServer{
+		linter:   linter,
+		in:       bufio.NewReader(in),
+		out:      out,
+		timers:   map[string]*time.Timer{},
+		Debounce: 300 * time.Millisecond,
+	}
+}
+
+// Serve starts handling messages from a client. It returns when it receives "exit" notification or
+// an input stream is closed.
+func (s *LanguageServer) Serve() error {
+	defer s.stopTimers()
+
+	for {
+		body, err := s.readMessage()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+
+		var req lspRequest
+		if err := json.Unmarshal(body, &req); err != nil {
+			if err := s.respondError(nil, lspErrorParse, fmt.Sprintf("could not parse message as JSON: %s", err)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if req.Method == "exit" {
+			if !s.shutdown {
+				return errors.New("language server exited without \"shutdown\" request")
+			}
+			return nil
+		}
+
+		if err := s.handle(&req); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *LanguageServer) handle(req *lspRequest) error {
+	switch req.Method {
+	case "initialize":
+		return s.respond(req.ID, map[string]interface{}{
+			"capabilities": map[string]interface{}{
+				"textDocumentSync": map[string]interface{}{
+					"openClose": true,
+					"change":    1, // Full
+				},
+			},
+			"serverInfo": map[string]interface{}{
+				"name":    "actionlint",
+				"version": getCommandVersion(),
+			},
+		})
+	case "shutdown":
+		s.shutdown = true
+		s.stopTimers()
+		return s.respond(req.ID, nil)
+	case "textDocument/didOpen":
+		var params lspDidOpenParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.respondError(req.ID, lspErrorInvalidParams, err.Error())
+		}
+		return s.lint(params.TextDocument.URI, []byte(params.TextDocument.Text))
+	case "textDocument/didChange":
+		var params lspDidChangeParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.respondError(req.ID, lspErrorInvalidParams, err.Error())
+		}
+		if len(params.ContentChanges) == 0 {
+			return nil
+		}
+		// Only full document sync is supported so the last change is the entire document
+		text := params.ContentChanges[len(params.ContentChanges)-1].Text
+		s.lintLater(params.TextDocument.URI, []byte(text))
+		return nil
+	case "textDocument/didClose":
+		var params lspDidCloseParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return s.respondError(req.ID, lspErrorInvalidParams, err.Error())
+		}
+		s.stopTimer(params.TextDocument.URI)
+		return s.publishDiagnostics(params.TextDocument.URI, []lspDiagnostic{})
+	default:
+		if req.ID == nil {
+			return nil // Unknown notifications are ignored
+		}
+		return s.respondError(req.ID, lspErrorMethodNotFound, fmt.Sprintf("method %q is not supported", req.Method))
+	}
+}
+
+func (s *LanguageServer) readMessage() ([]byte, error) {
+	length := -1
+	for {
+		line, err := s.in.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line == "" && length < 0 {
+				return nil, io.EOF
+			}
+			return nil, fmt.Errorf("could not read header of message: %w", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if v := strings.TrimPrefix(line, "Content-Length:"); v != line {
+			l, err := strconv.Atoi(strings.TrimSpace(v))
+			if err != nil {
+				return nil, fmt.Errorf("invalid Content-Length header %q: %w", line, err)
+			}
+			length = l
+		}
+	}
+
+	if length < 0 {
+		return nil, errors.New("Content-Length header is missing in message")
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(s.in, body); err != nil {
+		return nil, fmt.Errorf("could not read body of message: %w", err)
+	}
+	return body, nil
+}
+
+func (s *LanguageServer) writeMessage(msg interface{}) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("could not encode message into JSON: %w", err)
+	}
+
+	s.outMu.Lock()
+	defer s.outMu.Unlock()
+	if _, err := fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(b), b); err != nil {
+		return fmt.Errorf("could not write message: %w", err)
+	}
+	return nil
+}
+
+func (s *LanguageServer) respond(id *json.RawMessage, result interface{}) error {
+	return s.writeMessage(&lspResponse{JSONRPC: "2.0", ID: id, Result: result})
+}
+
+func (s *LanguageServer) respondError(id *json.RawMessage, code int, msg string) error {
+	if id == nil && code != lspErrorParse {
+		return nil // Notification cannot be responded
+	}
+	return s.writeMessage(&lspResponse{
+		JSONRPC: "2.0",
+		ID:      id,
+		Error:   &lspResponseError{code, msg},
+	})
+}
+
+func (s *LanguageServer) publishDiagnostics(uri string, diags []lspDiagnostic) error {
+	return s.writeMessage(&lspNotification{
+		JSONRPC: "2.0",
+		Method:  "textDocument/publishDiagnostics",
+		Params:  &lspPublishDiagnosticsParams{uri, diags},
+	})
+}
+
+// showError notifies the client of the error message. The client shows the message to users.
+func (s *LanguageServer) showError(msg string) error {
+	return s.writeMessage(&lspNotification{
+		JSONRPC: "2.0",
+		Method:  "window/showMessage",
+		Params:  &lspShowMessageParams{1, msg}, // 1 means Error
+	})
+}
+
+func (s *LanguageServer) lintLater(uri string, src []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, ok := s.timers[uri]; ok {
+		t.Stop()
+	}
+	s.timers[uri] = time.AfterFunc(s.Debounce, func() {
+		s.lint(uri, src) // Error on writing the message cannot be handled here
+	})
+}
+
+func (s *LanguageServer) stopTimer(uri string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t, ok := s.timers[uri]; ok {
+		t.Stop()
+		delete(s.timers, uri)
+	}
+}
+
+func (s *LanguageServer) stopTimers() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for uri, t := range s.timers {
+		t.Stop()
+		delete(s.timers, uri)
+	}
+}
+
+func (s *LanguageServer) lint(uri string, src []byte) error {
+	path := uri
+	var proj *Project
+	if u, err := url.Parse(uri); err == nil && u.Scheme == "file" {
+		path = filepath.FromSlash(u.Path)
+		proj = s.linter.projects.At(path)
+	}
+
+	errs, err := s.linter.Lint(path, src, proj)
+	if err != nil {
+		// Failure on linting one document should not stop the server. Report it to the client
+		return s.showError(fmt.Sprintf("could not lint %s: %s", uri, err))
+	}
+
+	diags := make([]lspDiagnostic, 0, len(errs))
+	for _, e := range errs {
+		sev := 1 // Error
+		if e.Severity() == SeverityWarning {
+			sev = 2 // Warning
+		}
+		diags = append(diags, lspDiagnostic{
+			Range:    e.lspRange(src),
+			Severity: sev,
+			Code:     e.Kind,
+			Source:   "actionlint",
+			Message:  e.Message,
+		})
+	}
+	return s.publishDiagnostics(uri, diags)
+}
+
+// lspRange returns range of the error in source. Line and character in LSP are 0-based and
+// characters are counted in UTF-16 code units. The end of the range is the end of the word at the
+// error position.
+func (e *Error) lspRange(src []byte) lspRange {
+	l, c := e.Line-1, e.Column-1
+	if l < 0 {
+		l = 0
+	}
+	if c < 0 {
+		c = 0
+	}
+	start := lspPosition{l, c}
+	end := start
+
+	if line, ok := e.getLine(src); ok {
+		// Column of the error is counted in characters
+		rs := []rune(line)
+		if c < len(rs) {
+			w := c
+			for w < len(rs) && rs[w] != ' ' && rs[w] != '\t' {
+				w++
+			}
+			start.Character = len(utf16.Encode(rs[:c]))
+			end.Character = start.Character + len(utf16.Encode(rs[c:w]))
+		}
+	}
+
+	return lspRange{start, end}
+}
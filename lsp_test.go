@@ -0,0 +1,349 @@
+package actionlint
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func testLSPInput(msgs ...string) io.Reader {
+	var b strings.Builder
+	for _, m := range msgs {
+		fmt.Fprintf(&b, "Content-Length: %d\r\n\r\n%s", len(m), m)
+	}
+	return strings.NewReader(b.String())
+}
+
+func testLSPOutput(t *testing.T, out []byte) []map[string]interface{} {
+	r := bufio.NewReader(bytes.NewReader(out))
+	ret := []map[string]interface{}{}
+	for {
+		h, err := r.ReadString('\n')
+		if err == io.EOF {
+			return ret
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		l, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(h, "Content-Length:")))
+		if err != nil {
+			t.Fatalf("invalid header %q: %s", h, err)
+		}
+		if _, err := r.ReadString('\n'); err != nil {
+			t.Fatal(err)
+		}
+		body := make([]byte, l)
+		if _, err := io.ReadFull(r, body); err != nil {
+			t.Fatal(err)
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(body, &m); err != nil {
+			t.Fatalf("invalid JSON %q: %s", body, err)
+		}
+		ret = append(ret, m)
+	}
+}
+
+func testNewLanguageServer(t *testing.T, in io.Reader, out io.Writer) *LanguageServer {
+	l, err := NewLinter(ioutil.Discard, &LinterOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.defaultConfig = &Config{}
+	return NewLanguageServer(l, in, out)
+}
+
+func TestLanguageServerPublishDiagnostics(t *testing.T) {
+	src := "on: push\njobs:\n  test:\n    runs-on: linux-latest\n    steps:\n      - run: echo\n"
+	open, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "textDocument/didOpen",
+		"params": map[string]interface{}{
+			"textDocument": map[string]interface{}{
+				"uri":        "untitled:test.yaml",
+				"languageId": "yaml",
+				"version":    1,
+				"text":       src,
+			},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	in := testLSPInput(
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`,
+		`{"jsonrpc":"2.0","method":"initialized","params":{}}`,
+		string(open),
+		`{"jsonrpc":"2.0","id":2,"method":"textDocument/hover","params":{}}`,
+		`{"jsonrpc":"2.0","method":"textDocument/didClose","params":{"textDocument":{"uri":"untitled:test.yaml"}}}`,
+		`{"jsonrpc":"2.0","id":3,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	)
+	var out bytes.Buffer
+	s := testNewLanguageServer(t, in, &out)
+
+	if err := s.Serve(); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := testLSPOutput(t, out.Bytes())
+	if len(msgs) != 5 {
+		t.Fatalf("wanted 5 messages but got %d: %v", len(msgs), msgs)
+	}
+
+	if msgs[0]["id"] != 1.0 || msgs[0]["result"] == nil {
+		t.Fatalf("unexpected response to initialize request: %v", msgs[0])
+	}
+
+	if msgs[1]["method"] != "textDocument/publishDiagnostics" {
+		t.Fatalf("unexpected notification: %v", msgs[1])
+	}
+	params := msgs[1]["params"].(map[string]interface{})
+	diags := params["diagnostics"].([]interface{})
+	if len(diags) != 1 {
+		t.Fatalf("wanted 1 diagnostic but got %v", diags)
+	}
+	d := diags[0].(map[string]interface{})
+	want := map[string]interface{}{
+		"range": map[string]interface{}{
+			"start": map[string]interface{}{"line": 3.0, "character": 13.0},
+			"end":   map[string]interface{}{"line": 3.0, "character": 25.0},
+		},
+		"severity": 1.0,
+		"code":     "runner-label",
+		"source":   "actionlint",
+	}
+	msg := d["message"].(string)
+	delete(d, "message")
+	if diff := cmp.Diff(want, d); diff != "" {
+		t.Fatal(diff)
+	}
+	if !strings.Contains(msg, `label "linux-latest" is unknown`) {
+		t.Fatalf("unexpected message: %q", msg)
+	}
+
+	if msgs[2]["id"] != 2.0 || msgs[2]["error"] == nil {
+		t.Fatalf("unsupported method should be responded with error: %v", msgs[2])
+	}
+
+	params = msgs[3]["params"].(map[string]interface{})
+	if diags := params["diagnostics"].([]interface{}); len(diags) != 0 {
+		t.Fatalf("diagnostics should be cleared on closing the document: %v", diags)
+	}
+
+	if _, ok := msgs[4]["result"]; !ok || msgs[4]["id"] != 3.0 {
+		t.Fatalf("unexpected response to shutdown request: %v", msgs[4])
+	}
+}
+
+func TestLanguageServerDebounceChanges(t *testing.T) {
+	change := func(text string) string {
+		b, err := json.Marshal(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"method":  "textDocument/didChange",
+			"params": map[string]interface{}{
+				"textDocument":   map[string]interface{}{"uri": "untitled:test.yaml", "version": 2},
+				"contentChanges": []interface{}{map[string]interface{}{"text": text}},
+			},
+		})
+		if err != nil {
+			panic(err)
+		}
+		return string(b)
+	}
+
+	r, w := io.Pipe()
+	var out bytes.Buffer
+	s := testNewLanguageServer(t, r, &out)
+	s.Debounce = 50 * time.Millisecond
+
+	done := make(chan error)
+	go func() {
+		done <- s.Serve()
+	}()
+
+	for _, src := range []string{
+		"on: push\njobs:\n  test:\n    runs-on: linux-latest\n",
+		"on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n",
+	} {
+		b, err := ioutil.ReadAll(testLSPInput(change(src)))
+		if err != nil {
+			panic(err)
+		}
+		if _, err := w.Write(b); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	b, err := ioutil.ReadAll(testLSPInput(
+		`{"jsonrpc":"2.0","id":1,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	))
+	if err != nil {
+		panic(err)
+	}
+	if _, err := w.Write(b); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := testLSPOutput(t, out.Bytes())
+	if len(msgs) != 2 {
+		t.Fatalf("only the last change should be linted but got %d messages: %v", len(msgs), msgs)
+	}
+	params := msgs[0]["params"].(map[string]interface{})
+	if diags := params["diagnostics"].([]interface{}); len(diags) != 0 {
+		t.Fatalf("no error should be reported for the last change: %v", diags)
+	}
+}
+
+func TestLanguageServerExitWithoutShutdown(t *testing.T) {
+	in := testLSPInput(`{"jsonrpc":"2.0","method":"exit"}`)
+	s := testNewLanguageServer(t, in, ioutil.Discard)
+	err := s.Serve()
+	if err == nil || !strings.Contains(err.Error(), "without \"shutdown\" request") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLanguageServerInvalidHeader(t *testing.T) {
+	in := strings.NewReader("Content-Type: foo\r\n\r\n{}")
+	s := testNewLanguageServer(t, in, ioutil.Discard)
+	err := s.Serve()
+	if err == nil || !strings.Contains(err.Error(), "Content-Length header is missing") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestLanguageServerReportLintFailure(t *testing.T) {
+	dir := t.TempDir()
+	for _, d := range []string{".git", filepath.Join(".github", "workflows")} {
+		if err := os.MkdirAll(filepath.Join(dir, d), 0755); err != nil {
+			panic(err)
+		}
+	}
+	cfg := filepath.Join(dir, ".github", "actionlint.yaml")
+	if err := ioutil.WriteFile(cfg, []byte("self-hosted-runner: [\n"), 0644); err != nil {
+		panic(err)
+	}
+
+	uri := "file://" + filepath.ToSlash(filepath.Join(dir, ".github", "workflows", "test.yaml"))
+	open, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "textDocument/didOpen",
+		"params": map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": uri, "text": "on: push\n"},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	in := testLSPInput(
+		string(open),
+		`{"jsonrpc":"2.0","id":1,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	)
+	var out bytes.Buffer
+	s := testNewLanguageServer(t, in, &out)
+	s.linter.defaultConfig = nil // Read the broken config file
+
+	if err := s.Serve(); err != nil {
+		t.Fatal("server should not stop on linting failure:", err)
+	}
+
+	msgs := testLSPOutput(t, out.Bytes())
+	if len(msgs) != 2 {
+		t.Fatalf("wanted 2 messages but got %d: %v", len(msgs), msgs)
+	}
+	if msgs[0]["method"] != "window/showMessage" {
+		t.Fatalf("failure should be notified to client: %v", msgs[0])
+	}
+	params := msgs[0]["params"].(map[string]interface{})
+	if params["type"] != 1.0 || !strings.Contains(params["message"].(string), "could not lint") {
+		t.Fatalf("unexpected params: %v", params)
+	}
+}
+
+func TestLanguageServerWarningSeverity(t *testing.T) {
+	src := "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo '::set-output name=foo::bar'\n"
+	open, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "textDocument/didOpen",
+		"params": map[string]interface{}{
+			"textDocument": map[string]interface{}{"uri": "untitled:test.yaml", "text": src},
+		},
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	in := testLSPInput(
+		string(open),
+		`{"jsonrpc":"2.0","id":1,"method":"shutdown"}`,
+		`{"jsonrpc":"2.0","method":"exit"}`,
+	)
+	var out bytes.Buffer
+	s := testNewLanguageServer(t, in, &out)
+	s.linter.enableRules = []string{"deprecated-commands"}
+
+	if err := s.Serve(); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := testLSPOutput(t, out.Bytes())
+	if len(msgs) != 2 || msgs[0]["method"] != "textDocument/publishDiagnostics" {
+		t.Fatalf("unexpected messages: %v", msgs)
+	}
+	diags := msgs[0]["params"].(map[string]interface{})["diagnostics"].([]interface{})
+	if len(diags) != 1 {
+		t.Fatalf("wanted 1 diagnostic but got %v", diags)
+	}
+	d := diags[0].(map[string]interface{})
+	if d["code"] != "deprecated-commands" || d["severity"] != 2.0 {
+		t.Fatalf("error of optional rule should be reported as warning: %v", d)
+	}
+}
+
+func TestLanguageServerRangeUTF16(t *testing.T) {
+	// "日本" is 2 code units and "😀" is 2 code units (surrogate pair) in UTF-16
+	src := []byte("name: 日本 😀 foo bar\n")
+	testCases := []struct {
+		what  string
+		col   int
+		start int
+		end   int
+	}{
+		{"ascii word", 1, 0, 5},
+		{"multi-byte word", 7, 6, 8},
+		{"surrogate pair", 10, 9, 11},
+		{"after surrogate pair", 12, 12, 15},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			e := &Error{Line: 1, Column: tc.col}
+			r := e.lspRange(src)
+			want := lspRange{lspPosition{0, tc.start}, lspPosition{0, tc.end}}
+			if diff := cmp.Diff(want, r); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
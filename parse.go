@@ -40,24 +40,27 @@ func isNull(n *yaml.Node) bool {
 
 func newString(n *yaml.Node) *String {
 	quoted := n.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle) != 0
-	return &String{n.Value, quoted, posAt(n)}
+	literal := n.Style&yaml.LiteralStyle != 0
+	return &String{n.Value, quoted, literal, 0, posAt(n)}
 }
 
 type keyVal struct {
-	key *String
-	val *yaml.Node
+	key  *String
+	val  *yaml.Node
+	orig string // Key in its original case. key is lower-cased for case insensitive comparison
 }
 
 type parser struct {
 	errors []*Error
+	lines  []string // Lines of the source. It is used for computing indentation of block scalars
 }
 
 func (p *parser) error(n *yaml.Node, m string) {
-	p.errors = append(p.errors, &Error{m, "", n.Line, n.Column, "syntax-check"})
+	p.errors = append(p.errors, &Error{Message: m, Line: n.Line, Column: n.Column, Kind: ErrorKindSyntaxCheck})
 }
 
 func (p *parser) errorAt(pos *Pos, m string) {
-	p.errors = append(p.errors, &Error{m, "", pos.Line, pos.Col, "syntax-check"})
+	p.errors = append(p.errors, &Error{Message: m, Line: pos.Line, Column: pos.Col, Kind: ErrorKindSyntaxCheck})
 }
 
 func (p *parser) errorfAt(pos *Pos, format string, args ...interface{}) {
@@ -83,6 +86,40 @@ func (p *parser) unexpectedKey(s *String, sec string, expected []string) {
 	p.errorAt(s.Pos, m)
 }
 
+// Sections which own the keys. They are used for detecting keys put at wrong places due to wrong
+// indentation.
+var keyOwnerSections = map[string]string{
+	"concurrency":       "job",
+	"container":         "job",
+	"defaults":          "job",
+	"environment":       "job",
+	"needs":             "job",
+	"outputs":           "job",
+	"permissions":       "job",
+	"runs-on":           "job",
+	"secrets":           "job",
+	"services":          "job",
+	"steps":             "job",
+	"strategy":          "job",
+	"id":                "step",
+	"run":               "step",
+	"shell":             "step",
+	"working-directory": "step",
+	"continue-on-error": "job or step",
+	"env":               "job or step",
+	"if":                "job or step",
+	"name":              "job or step",
+	"timeout-minutes":   "job or step",
+	"uses":              "job or step",
+	"with":              "job or step",
+}
+
+// misplacedKey reports the key which is likely mis-indented. The owner parameter is the section
+// which owns the key and the fix parameter describes how to fix it.
+func (p *parser) misplacedKey(s *String, sec, owner, fix string) {
+	p.errorfAt(s.Pos, "unexpected key %q for %q section. it is a key of %s so it may be mis-indented. %s", s.Value, sec, owner, fix)
+}
+
 func (p *parser) checkNotEmpty(sec string, len int, n *yaml.Node) bool {
 	if len == 0 {
 		p.errorf(n, "%q section should not be empty", sec)
@@ -132,9 +169,52 @@ func (p *parser) parseExpression(n *yaml.Node, expecting string) *String {
 
 func (p *parser) parseString(n *yaml.Node, allowEmpty bool) *String {
 	if !p.checkString(n, allowEmpty) {
-		return &String{"", false, posAt(n)}
+		return &String{"", false, false, 0, posAt(n)}
 	}
-	return newString(n)
+	s := newString(n)
+	if s.Literal {
+		s.Indent = p.blockIndent(n)
+	}
+	return s
+}
+
+// blockIndent returns the indentation of the content of the block scalar node. The indentation is
+// detected from the first non-empty line of the content as YAML parser does. Indentation indicator
+// like "|2" is not supported and 0 is returned when the indentation cannot be detected.
+// https://yaml.org/spec/1.2.2/#8111-block-indentation-indicator
+func (p *parser) blockIndent(n *yaml.Node) int {
+	if strings.TrimSpace(n.Value) == "" || n.Line < 1 || n.Line > len(p.lines) {
+		return 0 // Lines after the empty block scalar are not its content
+	}
+	if h := []rune(p.lines[n.Line-1]); n.Column >= 1 && n.Column <= len(h) {
+		for _, r := range h[n.Column:] {
+			if r == ' ' || r == '\t' || r == '#' {
+				break
+			}
+			if '1' <= r && r <= '9' {
+				return 0 // Indentation indicator is relative to the parent node's indentation
+			}
+		}
+	}
+	// n.Line is the 1-based line of the "|" indicator. The content starts at the next line
+	for i := n.Line; i < len(p.lines); i++ {
+		l := strings.TrimRight(p.lines[i], " \r")
+		if l == "" {
+			continue
+		}
+		return len(l) - len(strings.TrimLeft(l, " "))
+	}
+	return 0
+}
+
+// parseName parses "name:" section of workflow or job. The name is shown as a title in the Actions
+// UI so it should not be empty.
+func (p *parser) parseName(n *yaml.Node, what string) *String {
+	s := p.parseString(n, true)
+	if n.Kind == yaml.ScalarNode && strings.TrimSpace(s.Value) == "" {
+		p.errorf(n, "\"name\" of %s should not be empty. please remove this section if it's unnecessary", what)
+	}
+	return s
 }
 
 func (p *parser) parseStringSequence(sec string, n *yaml.Node, allowEmpty bool, allowElemEmpty bool) []*String {
@@ -218,6 +298,9 @@ func (p *parser) parseFloat(n *yaml.Node) *Float {
 
 	if n.Tag == "!!str" {
 		e := p.parseExpression(n, "float number literal")
+		if e == nil {
+			return nil // Error was already reported. Invalid value should not be treated as 0
+		}
 		return &Float{
 			Expression: e,
 			Pos:        posAt(n),
@@ -257,6 +340,7 @@ func (p *parser) parseMapping(what string, n *yaml.Node, allowEmpty bool) []keyV
 		if k == nil {
 			continue
 		}
+		orig := k.Value
 
 		// Keys of mappings are case insensitive. For example, following matrix is invalid.
 		// matrix:
@@ -269,7 +353,7 @@ func (p *parser) parseMapping(what string, n *yaml.Node, allowEmpty bool) []keyV
 			p.errorfAt(k.Pos, "key %q is duplicate in %s. previously defined at %s. note that key names are case insensitive", k.Value, what, pos.String())
 			continue
 		}
-		m = append(m, keyVal{k, n.Content[i+1]})
+		m = append(m, keyVal{k, n.Content[i+1], orig})
 		keys[k.Value] = k.Pos
 	}
 
@@ -489,7 +573,9 @@ func (p *parser) parseWorkflowCallEvent(pos *Pos, n *yaml.Node) *WorkflowCallEve
 			ret.Secrets = make(map[*String]*WorkflowCallEventSecret, len(secrets))
 			for _, kv := range secrets {
 				name, spec := kv.key, kv.val
-				secret := &WorkflowCallEventSecret{}
+				secret := &WorkflowCallEventSecret{
+					Name: &String{Value: kv.orig, Quoted: name.Quoted, Pos: name.Pos},
+				}
 
 				for _, attr := range p.parseMapping("secret of workflow_call event", spec, true) {
 					switch attr.key.Value {
@@ -519,7 +605,14 @@ func (p *parser) parseWorkflowCallEvent(pos *Pos, n *yaml.Node) *WorkflowCallEve
 					case "description":
 						output.Description = p.parseString(attr.val, true)
 					case "value":
-						output.Value = p.parseString(attr.val, false)
+						output.Value = p.parseString(attr.val, true)
+						if strings.TrimSpace(output.Value.Value) == "" {
+							p.errorfAt(
+								output.Value.Pos,
+								"\"value\" of %q output of workflow_call event should not be empty. set an expression referencing an output of job like \"${{ jobs.<job_id>.outputs.<output_id> }}\"",
+								name.Value,
+							)
+						}
 					default:
 						p.unexpectedKey(attr.key, "outputs at workflow_call event", []string{"description", "value"})
 					}
@@ -533,7 +626,7 @@ func (p *parser) parseWorkflowCallEvent(pos *Pos, n *yaml.Node) *WorkflowCallEve
 				ret.Outputs[name] = output
 			}
 		default:
-			p.unexpectedKey(kv.key, "workflow_call", []string{"inputs", "secrets"})
+			p.unexpectedKey(kv.key, "workflow_call", []string{"inputs", "secrets", "outputs"})
 		}
 	}
 
@@ -649,8 +742,22 @@ func (p *parser) parseEnv(n *yaml.Node) *Env {
 	vars := make(map[string]*EnvVar, len(m))
 
 	for _, kv := range m {
+		if kv.val.Kind != yaml.ScalarNode {
+			if owner, ok := keyOwnerSections[kv.key.Value]; ok {
+				p.errorfAt(
+					kv.key.Pos,
+					"%q in \"env\" section has %s value but values of environment variables must be strings. it is a key of %s so it may be mis-indented. fix the indentation to move it out of \"env\" section",
+					kv.key.Value,
+					nodeKindName(kv.val.Kind),
+					owner,
+				)
+				continue
+			}
+		}
+		// Names of environment variables are case sensitive on Linux and macOS. Keep the original
+		// case of the name for error messages
 		vars[kv.key.Value] = &EnvVar{
-			Name:  kv.key,
+			Name:  &String{Value: kv.orig, Quoted: kv.key.Quoted, Pos: kv.key.Pos},
 			Value: p.parseString(kv.val, true),
 		}
 	}
@@ -742,6 +849,17 @@ func (p *parser) parseEnvironment(pos *Pos, n *yaml.Node) *Environment {
 	return ret
 }
 
+// https://docs.github.com/en/actions/learn-github-actions/workflow-syntax-for-github-actions#jobsjob_idif
+func (p *parser) parseIfCondition(n *yaml.Node) *String {
+	// `if: !cancelled()` is not a string. YAML parses it as an empty value with "!cancelled()" tag.
+	if n.Kind == yaml.ScalarNode && strings.HasPrefix(n.Tag, "!") && !strings.HasPrefix(n.Tag, "!!") {
+		c := strings.TrimSpace(n.Tag + " " + n.Value)
+		p.errorf(n, "condition %q at \"if\" section is parsed as YAML tag since it starts with \"!\". quote the condition like '%s' or wrap it with ${{ }} like ${{ %s }}", c, c, c)
+		return nil
+	}
+	return p.parseString(n, false)
+}
+
 // https://docs.github.com/en/actions/learn-github-actions/workflow-syntax-for-github-actions#jobsjob_idoutputs
 func (p *parser) parseOutputs(n *yaml.Node) map[string]*Output {
 	outputs := p.parseSectionMapping("outputs", n, false)
@@ -884,6 +1002,33 @@ func (p *parser) parseStrategy(pos *Pos, n *yaml.Node) *Strategy {
 }
 
 // https://docs.github.com/en/actions/learn-github-actions/workflow-syntax-for-github-actions#jobsjob_idcontainer
+// https://docs.github.com/en/actions/using-jobs/choosing-the-runner-for-a-job
+func (p *parser) parseRunsOn(n *yaml.Node) *Runner {
+	if n.Kind != yaml.MappingNode {
+		return &Runner{Labels: p.parseStringOrStringSequence("runs-on", n, false, false)}
+	}
+
+	// runs-on:
+	//   group: my-group
+	//   labels: [self-hosted, linux]
+	r := &Runner{}
+	kvs := p.parseSectionMapping("runs-on", n, false)
+	for _, kv := range kvs {
+		switch kv.key.Value {
+		case "group":
+			r.Group = p.parseString(kv.val, false)
+		case "labels":
+			r.Labels = p.parseStringOrStringSequence("labels", kv.val, false, false)
+		default:
+			p.unexpectedKey(kv.key, "runs-on", []string{"group", "labels"})
+		}
+	}
+	if len(kvs) > 0 && r.Group == nil && len(r.Labels) == 0 {
+		p.error(n, "\"group\" or \"labels\" must be specified in \"runs-on\" section")
+	}
+	return r
+}
+
 func (p *parser) parseContainer(sec string, pos *Pos, n *yaml.Node) *Container {
 	ret := &Container{Pos: pos}
 
@@ -946,7 +1091,7 @@ func (p *parser) parseStep(n *yaml.Node) *Step {
 		case "id":
 			ret.ID = p.parseString(kv.val, false)
 		case "if":
-			ret.If = p.parseString(kv.val, false)
+			ret.If = p.parseIfCondition(kv.val)
 		case "name":
 			ret.Name = p.parseString(kv.val, true)
 		case "env":
@@ -1013,6 +1158,10 @@ func (p *parser) parseStep(n *yaml.Node) *Step {
 				ret.Exec.SetWorkingDir(workDir)
 			}
 		default:
+			if keyOwnerSections[kv.key.Value] == "job" {
+				p.misplacedKey(kv.key, "step", "job", "move it to the job level outside \"steps\" section")
+				continue
+			}
 			p.unexpectedKey(kv.key, "step", []string{
 				"id",
 				"if",
@@ -1047,7 +1196,8 @@ func (p *parser) parseStep(n *yaml.Node) *Step {
 
 // https://docs.github.com/en/actions/learn-github-actions/workflow-syntax-for-github-actions#jobsjob_idsteps
 func (p *parser) parseSteps(n *yaml.Node) []*Step {
-	if ok := p.checkSequence("steps", n, false); !ok {
+	// Empty steps are reported in parseJob with the job ID
+	if ok := p.checkSequence("steps", n, true); !ok {
 		return nil
 	}
 
@@ -1081,12 +1231,14 @@ func (p *parser) parseJob(id *String, n *yaml.Node) *Job {
 
 	var stepsOnlyKey *String
 	var callOnlyKey *String
+	var secretsKey *String
+	noSteps := false
 
 	for _, kv := range p.parseMapping(fmt.Sprintf("%q job", id.Value), n, false) {
 		k, v := kv.key, kv.val
 		switch k.Value {
 		case "name":
-			ret.Name = p.parseString(v, true)
+			ret.Name = p.parseName(v, fmt.Sprintf("job %q", id.Value))
 		case "needs":
 			if v.Kind == yaml.ScalarNode {
 				// needs: job1
@@ -1096,8 +1248,7 @@ func (p *parser) parseJob(id *String, n *yaml.Node) *Job {
 				ret.Needs = p.parseStringSequence("needs", v, false, false)
 			}
 		case "runs-on":
-			labels := p.parseStringOrStringSequence("runs-on", v, false, false)
-			ret.RunsOn = &Runner{labels}
+			ret.RunsOn = p.parseRunsOn(v)
 			stepsOnlyKey = k
 		case "permissions":
 			ret.Permissions = p.parsePermissions(k.Pos, v)
@@ -1117,9 +1268,10 @@ func (p *parser) parseJob(id *String, n *yaml.Node) *Job {
 			ret.Defaults = p.parseDefaults(k.Pos, v)
 			stepsOnlyKey = k
 		case "if":
-			ret.If = p.parseString(v, false)
+			ret.If = p.parseIfCondition(v)
 		case "steps":
 			ret.Steps = p.parseSteps(v)
+			noSteps = v.Kind == yaml.SequenceNode && len(v.Content) == 0
 			stepsOnlyKey = k
 		case "timeout-minutes":
 			ret.TimeoutMinutes = p.parseFloat(v)
@@ -1156,16 +1308,25 @@ func (p *parser) parseJob(id *String, n *yaml.Node) *Job {
 			}
 			callOnlyKey = k
 		case "secrets":
-			secrets := p.parseSectionMapping("secrets", v, false)
-			call.Secrets = make(map[string]*WorkflowCallSecret, len(secrets))
-			for _, s := range secrets {
-				call.Secrets[s.key.Value] = &WorkflowCallSecret{
-					Name:  s.key,
-					Value: p.parseString(s.val, true),
+			if v.Kind == yaml.ScalarNode && v.Value == "inherit" {
+				// secrets: inherit
+				call.InheritSecrets = true
+			} else {
+				secrets := p.parseSectionMapping("secrets", v, false)
+				call.Secrets = make(map[string]*WorkflowCallSecret, len(secrets))
+				for _, s := range secrets {
+					call.Secrets[s.key.Value] = &WorkflowCallSecret{
+						Name:  &String{Value: s.orig, Quoted: s.key.Quoted, Pos: s.key.Pos},
+						Value: p.parseString(s.val, true),
+					}
 				}
 			}
-			callOnlyKey = k
+			secretsKey = k
 		default:
+			if keyOwnerSections[kv.key.Value] == "step" {
+				p.misplacedKey(kv.key, "job", "step", "move it into an item of \"steps\" section")
+				continue
+			}
 			p.unexpectedKey(kv.key, "job", []string{
 				"name",
 				"needs",
@@ -1205,6 +1366,8 @@ func (p *parser) parseJob(id *String, n *yaml.Node) *Job {
 		// When not a reusable call
 		if ret.Steps == nil {
 			p.errorfAt(id.Pos, "\"steps\" section is missing in job %q", id.Value)
+		} else if noSteps {
+			p.errorfAt(id.Pos, "no step is defined at \"steps\" section in job %q. the job does nothing. steps may be commented out by mistake", id.Value)
 		}
 		if ret.RunsOn == nil {
 			p.errorfAt(id.Pos, "\"runs-on\" section is missing in job %q", id.Value)
@@ -1217,6 +1380,13 @@ func (p *parser) parseJob(id *String, n *yaml.Node) *Job {
 				id.Value,
 			)
 		}
+		if secretsKey != nil {
+			p.errorfAt(
+				secretsKey.Pos,
+				"\"secrets\" section in job %q does nothing since secrets can be passed only to a reusable workflow called with \"uses\". steps in the job can access secrets via \"secrets\" context without this section. remove the section or add \"uses\" to call a reusable workflow",
+				id.Value,
+			)
+		}
 	}
 
 	return ret
@@ -1246,7 +1416,7 @@ func (p *parser) parse(n *yaml.Node) *Workflow {
 		k, v := kv.key, kv.val
 		switch k.Value {
 		case "name":
-			w.Name = p.parseString(v, true)
+			w.Name = p.parseName(v, "workflow")
 		case "on":
 			w.On = p.parseEvents(k.Pos, v)
 		case "permissions":
@@ -1260,6 +1430,10 @@ func (p *parser) parse(n *yaml.Node) *Workflow {
 		case "jobs":
 			w.Jobs = p.parseJobs(v)
 		default:
+			if owner, ok := keyOwnerSections[k.Value]; ok {
+				p.misplacedKey(k, "workflow", owner, "move it into a job in \"jobs\" section")
+				continue
+			}
 			p.unexpectedKey(k, "workflow", []string{
 				"name",
 				"on",
@@ -1295,7 +1469,7 @@ func handleYAMLError(err error) []*Error {
 			l, _ = strconv.Atoi(ss[1])
 		}
 		msg = fmt.Sprintf("could not parse as YAML: %s", msg)
-		return &Error{msg, "", l, 0, "yaml-syntax"}
+		return &Error{Message: msg, Line: l, Column: 0, Kind: ErrorKindYAMLSyntax}
 	}
 
 	if te, ok := err.(*yaml.TypeError); ok {
@@ -1322,7 +1496,7 @@ func Parse(b []byte) (*Workflow, []*Error) {
 	// Uncomment for checking YAML tree
 	// dumpYAML(&n, 0)
 
-	p := &parser{}
+	p := &parser{lines: strings.Split(string(b), "\n")}
 	w := p.parse(&n)
 
 	return w, p.errors
@@ -134,6 +134,9 @@ var PopularActions = map[string]*ActionMetadata{
 		Outputs: map[string]struct{}{
 			"DEPLOYMENT_STATUS": {},
 		},
+		Permissions: map[string]string{
+			"contents": "write",
+		},
 	},
 	"JamesIves/github-pages-deploy-action@releases/v4": {
 		Name: "Deploy to GitHub Pages",
@@ -157,6 +160,9 @@ var PopularActions = map[string]*ActionMetadata{
 		Outputs: map[string]struct{}{
 			"deployment-status": {},
 		},
+		Permissions: map[string]string{
+			"contents": "write",
+		},
 	},
 	"ReactiveCircus/android-emulator-runner@v1": {
 		Name: "Android Emulator Runner",
@@ -415,6 +421,7 @@ var PopularActions = map[string]*ActionMetadata{
 		Outputs: map[string]struct{}{
 			"cache-hit": {},
 		},
+		Runs: &ActionMetadataRuns{Using: "node16"},
 	},
 	"actions/checkout@v1": {
 		Name: "Checkout",
@@ -428,6 +435,7 @@ var PopularActions = map[string]*ActionMetadata{
 			"submodules":  false,
 			"token":       false,
 		},
+		Runs: &ActionMetadataRuns{Using: "node12"},
 	},
 	"actions/checkout@v2": {
 		Name: "Checkout",
@@ -445,6 +453,7 @@ var PopularActions = map[string]*ActionMetadata{
 			"submodules":          false,
 			"token":               false,
 		},
+		Runs: &ActionMetadataRuns{Using: "node12"},
 	},
 	"actions/checkout@v3": {
 		Name: "Checkout",
@@ -462,6 +471,31 @@ var PopularActions = map[string]*ActionMetadata{
 			"submodules":          false,
 			"token":               false,
 		},
+		Runs: &ActionMetadataRuns{Using: "node16"},
+	},
+	"actions/create-release@v1": {
+		Name: "Create a Release",
+		Inputs: map[string]ActionMetadataInputRequired{
+			"body":         false,
+			"body_path":    false,
+			"commitish":    false,
+			"draft":        false,
+			"owner":        false,
+			"prerelease":   false,
+			"release_name": true,
+			"repo":         false,
+			"tag_name":     true,
+		},
+		Outputs: map[string]struct{}{
+			"html_url":   {},
+			"id":         {},
+			"upload_url": {},
+		},
+		Permissions: map[string]string{
+			"contents": "write",
+		},
+		Deprecated:  "this repository was archived and is no longer maintained",
+		Replacement: "softprops/action-gh-release@v1",
 	},
 	"actions/delete-package-versions@v1": {
 		Name: "Delete Package Versions",
@@ -522,6 +556,7 @@ var PopularActions = map[string]*ActionMetadata{
 			"name": false,
 			"path": false,
 		},
+		Runs: &ActionMetadataRuns{Using: "node16"},
 	},
 	"actions/first-interaction@v1": {
 		Name: "First interaction",
@@ -600,6 +635,7 @@ var PopularActions = map[string]*ActionMetadata{
 		Outputs: map[string]struct{}{
 			"result": {},
 		},
+		Runs: &ActionMetadataRuns{Using: "node12"},
 	},
 	"actions/github-script@v6": {
 		Name: "GitHub Script",
@@ -614,6 +650,7 @@ var PopularActions = map[string]*ActionMetadata{
 		Outputs: map[string]struct{}{
 			"result": {},
 		},
+		Runs: &ActionMetadataRuns{Using: "node16"},
 	},
 	"actions/labeler@v2": {
 		Name: "Pull Request Labeler",
@@ -622,6 +659,10 @@ var PopularActions = map[string]*ActionMetadata{
 			"repo-token":         false,
 			"sync-labels":        false,
 		},
+		Permissions: map[string]string{
+			"contents":      "read",
+			"pull-requests": "write",
+		},
 	},
 	"actions/labeler@v3": {
 		Name: "Labeler",
@@ -630,6 +671,10 @@ var PopularActions = map[string]*ActionMetadata{
 			"repo-token":         false,
 			"sync-labels":        false,
 		},
+		Permissions: map[string]string{
+			"contents":      "read",
+			"pull-requests": "write",
+		},
 	},
 	"actions/labeler@v4": {
 		Name: "Labeler",
@@ -638,6 +683,11 @@ var PopularActions = map[string]*ActionMetadata{
 			"repo-token":         false,
 			"sync-labels":        false,
 		},
+		Runs: &ActionMetadataRuns{Using: "node16"},
+		Permissions: map[string]string{
+			"contents":      "read",
+			"pull-requests": "write",
+		},
 	},
 	"actions/setup-dotnet@v1": {
 		Name: "Setup .NET Core SDK",
@@ -682,6 +732,7 @@ var PopularActions = map[string]*ActionMetadata{
 			"go-version":   false,
 			"token":        false,
 		},
+		Runs: &ActionMetadataRuns{Using: "node16"},
 	},
 	"actions/setup-java@v1": {
 		Name: "Setup Java JDK",
@@ -751,6 +802,7 @@ var PopularActions = map[string]*ActionMetadata{
 			"path":         {},
 			"version":      {},
 		},
+		Runs: &ActionMetadataRuns{Using: "node16"},
 	},
 	"actions/setup-node@v1": {
 		Name: "Setup Node.js environment",
@@ -761,6 +813,9 @@ var PopularActions = map[string]*ActionMetadata{
 			"scope":        false,
 			"version":      false,
 		},
+		Runs:        &ActionMetadataRuns{Using: "node12"},
+		Deprecated:  "this version runs on Node.js 12 which is no longer supported by GitHub-hosted runners",
+		Replacement: "actions/setup-node@v3",
 	},
 	"actions/setup-node@v2": {
 		Name: "Setup Node.js environment",
@@ -798,6 +853,7 @@ var PopularActions = map[string]*ActionMetadata{
 		Outputs: map[string]struct{}{
 			"cache-hit": {},
 		},
+		Runs: &ActionMetadataRuns{Using: "node16"},
 	},
 	"actions/setup-python@v1": {
 		Name: "Setup Python",
@@ -834,6 +890,7 @@ var PopularActions = map[string]*ActionMetadata{
 		Outputs: map[string]struct{}{
 			"python-version": {},
 		},
+		Runs: &ActionMetadataRuns{Using: "node16"},
 	},
 	"actions/stale@v1": {
 		Name: "Close Stale Issues",
@@ -849,6 +906,10 @@ var PopularActions = map[string]*ActionMetadata{
 			"stale-pr-label":      false,
 			"stale-pr-message":    false,
 		},
+		Permissions: map[string]string{
+			"issues":        "write",
+			"pull-requests": "write",
+		},
 	},
 	"actions/stale@v2": {
 		Name: "Close Stale Issues",
@@ -866,6 +927,10 @@ var PopularActions = map[string]*ActionMetadata{
 			"stale-pr-label":      false,
 			"stale-pr-message":    false,
 		},
+		Permissions: map[string]string{
+			"issues":        "write",
+			"pull-requests": "write",
+		},
 	},
 	"actions/stale@v3": {
 		Name: "Close Stale Issues",
@@ -917,6 +982,10 @@ var PopularActions = map[string]*ActionMetadata{
 			"stale-pr-message":                false,
 			"start-date":                      false,
 		},
+		Permissions: map[string]string{
+			"issues":        "write",
+			"pull-requests": "write",
+		},
 	},
 	"actions/stale@v4": {
 		Name: "Close Stale Issues",
@@ -976,6 +1045,10 @@ var PopularActions = map[string]*ActionMetadata{
 			"closed-issues-prs": {},
 			"staled-issues-prs": {},
 		},
+		Permissions: map[string]string{
+			"issues":        "write",
+			"pull-requests": "write",
+		},
 	},
 	"actions/stale@v5": {
 		Name: "Close Stale Issues",
@@ -1035,6 +1108,11 @@ var PopularActions = map[string]*ActionMetadata{
 			"closed-issues-prs": {},
 			"staled-issues-prs": {},
 		},
+		Runs: &ActionMetadataRuns{Using: "node16"},
+		Permissions: map[string]string{
+			"issues":        "write",
+			"pull-requests": "write",
+		},
 	},
 	"actions/upload-artifact@v1": {
 		Name: "Upload a Build Artifact",
@@ -1060,6 +1138,24 @@ var PopularActions = map[string]*ActionMetadata{
 			"path":              true,
 			"retention-days":    false,
 		},
+		Runs: &ActionMetadataRuns{Using: "node16"},
+	},
+	"actions/upload-release-asset@v1": {
+		Name: "Upload a Release Asset",
+		Inputs: map[string]ActionMetadataInputRequired{
+			"asset_content_type": true,
+			"asset_name":         true,
+			"asset_path":         true,
+			"upload_url":         true,
+		},
+		Outputs: map[string]struct{}{
+			"browser_download_url": {},
+		},
+		Permissions: map[string]string{
+			"contents": "write",
+		},
+		Deprecated:  "this repository was archived and is no longer maintained",
+		Replacement: "softprops/action-gh-release@v1",
 	},
 	"aws-actions/configure-aws-credentials@v1": {
 		Name: "\"Configure AWS Credentials\" Action For GitHub Actions",
@@ -1619,6 +1715,9 @@ var PopularActions = map[string]*ActionMetadata{
 			"db-locations": {},
 			"sarif-id":     {},
 		},
+		Permissions: map[string]string{
+			"security-events": "write",
+		},
 	},
 	"github/codeql-action/autobuild@v1": {
 		Name: "CodeQL: Autobuild",
@@ -1653,9 +1752,11 @@ var PopularActions = map[string]*ActionMetadata{
 	},
 	"github/super-linter@v3": {
 		Name: "Super-Linter",
+		Runs: &ActionMetadataRuns{Using: "docker"},
 	},
 	"github/super-linter@v4": {
 		Name: "Super-Linter",
+		Runs: &ActionMetadataRuns{Using: "docker"},
 	},
 	"githubocto/flat@v1": {
 		Name: "Flat Data",
@@ -1804,6 +1905,9 @@ var PopularActions = map[string]*ActionMetadata{
 			"automatic_releases_tag": {},
 			"upload_url":             {},
 		},
+		Permissions: map[string]string{
+			"contents": "write",
+		},
 	},
 	"microsoft/playwright-github-action@v1": {
 		Name: "Run Playwright tests",
@@ -1914,6 +2018,9 @@ var PopularActions = map[string]*ActionMetadata{
 			"id":         {},
 			"upload_url": {},
 		},
+		Permissions: map[string]string{
+			"contents": "write",
+		},
 	},
 	"nwtgck/actions-netlify@v1": {
 		Name: "Netlify Actions",
@@ -1969,6 +2076,9 @@ var PopularActions = map[string]*ActionMetadata{
 			"useremail":     false,
 			"username":      false,
 		},
+		Permissions: map[string]string{
+			"contents": "write",
+		},
 	},
 	"peaceiris/actions-gh-pages@v3": {
 		Name: "GitHub Pages action",
@@ -1994,6 +2104,9 @@ var PopularActions = map[string]*ActionMetadata{
 			"user_email":          false,
 			"user_name":           false,
 		},
+		Permissions: map[string]string{
+			"contents": "write",
+		},
 	},
 	"peter-evans/create-pull-request@v1": {
 		Name: "Create Pull Request",
@@ -2020,6 +2133,10 @@ var PopularActions = map[string]*ActionMetadata{
 		Outputs: map[string]struct{}{
 			"pr_number": {},
 		},
+		Permissions: map[string]string{
+			"contents":      "write",
+			"pull-requests": "write",
+		},
 	},
 	"peter-evans/create-pull-request@v2": {
 		Name: "Create Pull Request",
@@ -2047,6 +2164,10 @@ var PopularActions = map[string]*ActionMetadata{
 		Outputs: map[string]struct{}{
 			"pull-request-number": {},
 		},
+		Permissions: map[string]string{
+			"contents":      "write",
+			"pull-requests": "write",
+		},
 	},
 	"peter-evans/create-pull-request@v3": {
 		Name: "Create Pull Request",
@@ -2078,6 +2199,10 @@ var PopularActions = map[string]*ActionMetadata{
 			"pull-request-operation": {},
 			"pull-request-url":       {},
 		},
+		Permissions: map[string]string{
+			"contents":      "write",
+			"pull-requests": "write",
+		},
 	},
 	"peter-evans/create-pull-request@v4": {
 		Name: "Create Pull Request",
@@ -2109,6 +2234,10 @@ var PopularActions = map[string]*ActionMetadata{
 			"pull-request-operation": {},
 			"pull-request-url":       {},
 		},
+		Permissions: map[string]string{
+			"contents":      "write",
+			"pull-requests": "write",
+		},
 	},
 	"preactjs/compressed-size-action@v1": {
 		Name: "compressed-size-action",
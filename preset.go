@@ -0,0 +1,113 @@
+package actionlint
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Preset is a named bundle of rule settings. Presets are selected with "extends" in config file or
+// -preset flag to share rule settings among repositories. When multiple presets are applied, they
+// are applied in order so settings of later presets take precedence.
+type Preset struct {
+	// EnableRules is names of optional rules to enable.
+	EnableRules []string `yaml:"enable-rules"`
+	// DisableRules is names of optional rules to disable. It disables rules enabled by the presets
+	// applied before, or by EnableRules of the same preset.
+	DisableRules []string `yaml:"disable-rules"`
+	// Rules is a mapping from names of rules to severities of their errors. The severity is "error"
+	// or "warning". It is overridden by "rules" in config file and -error-on flag.
+	Rules map[string]string `yaml:"rules"`
+}
+
+// BuiltinPresets is a mapping from names of presets bundled with actionlint to the presets.
+//
+// - minimal: No optional rule is enabled. It is the same as the default
+// - security: Optional rules to detect security issues like leaking secrets and excessive permissions
+// - strict: All optional rules are enabled and errors of all rules are reported as errors, not as
+// warnings
+var BuiltinPresets = map[string]*Preset{
+	"minimal": {
+		EnableRules: []string{},
+	},
+	"security": {
+		EnableRules: []string{
+			"action-permissions",
+			"secrets-in-args",
+		},
+	},
+	"strict": {
+		EnableRules: allOptionalRuleNames(),
+		Rules:       allRuleSeverities(SeverityError),
+	},
+}
+
+func allOptionalRuleNames() []string {
+	names := make([]string, 0, len(optionalRules))
+	for n := range optionalRules {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// allRuleSeverities returns a mapping from names of all rules to the severity.
+func allRuleSeverities(severity string) map[string]string {
+	sev := make(map[string]string, len(builtinRuleNames)+len(optionalRules))
+	for _, n := range builtinRuleNames {
+		sev[n] = severity
+	}
+	for n := range optionalRules {
+		sev[n] = severity
+	}
+	return sev
+}
+
+// lookupPreset finds the preset by its name. Custom presets defined in config file are looked up
+// before built-in presets.
+func lookupPreset(name string, custom map[string]*Preset) (*Preset, error) {
+	if p, ok := custom[name]; ok {
+		return p, nil
+	}
+	if p, ok := BuiltinPresets[name]; ok {
+		return p, nil
+	}
+	names := make([]string, 0, len(BuiltinPresets)+len(custom))
+	for n := range BuiltinPresets {
+		names = append(names, n)
+	}
+	for n := range custom {
+		names = append(names, n)
+	}
+	return nil, fmt.Errorf("unknown preset %q. available presets are %s", name, sortedQuotes(names))
+}
+
+// resolvePresets applies the presets in order and returns one preset merging them. Rules disabled by
+// some preset are removed from EnableRules of the result unless they are enabled again by later
+// presets. Severities of later presets take precedence.
+func resolvePresets(names []string, custom map[string]*Preset) (*Preset, error) {
+	ret := &Preset{EnableRules: []string{}, Rules: map[string]string{}}
+	for _, n := range names {
+		p, err := lookupPreset(n, custom)
+		if err != nil {
+			return nil, err
+		}
+		ret.EnableRules = append(ret.EnableRules, p.EnableRules...)
+		if len(p.DisableRules) > 0 {
+			disabled := make(map[string]struct{}, len(p.DisableRules))
+			for _, r := range p.DisableRules {
+				disabled[r] = struct{}{}
+			}
+			enabled := make([]string, 0, len(ret.EnableRules))
+			for _, r := range ret.EnableRules {
+				if _, ok := disabled[r]; !ok {
+					enabled = append(enabled, r)
+				}
+			}
+			ret.EnableRules = enabled
+		}
+		for r, s := range p.Rules {
+			ret.Rules[r] = s
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,100 @@
+package actionlint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPresetBuiltinPresetsEnableKnownRules(t *testing.T) {
+	for name, p := range BuiltinPresets {
+		for _, r := range p.EnableRules {
+			if err := checkOptionalRuleName(r, "enable"); err != nil {
+				t.Errorf("built-in preset %q enables unknown rule: %s", name, err)
+			}
+		}
+		for r := range p.Rules {
+			if err := checkRuleName(r); err != nil {
+				t.Errorf("built-in preset %q sets severity of unknown rule: %s", name, err)
+			}
+		}
+	}
+	strict := BuiltinPresets["strict"]
+	if !cmp.Equal(strict.EnableRules, allOptionalRuleNames()) {
+		t.Error("\"strict\" preset should enable all optional rules:", strict.EnableRules)
+	}
+	for _, r := range append(allOptionalRuleNames(), builtinRuleNames...) {
+		if s := strict.Rules[r]; s != SeverityError {
+			t.Errorf("\"strict\" preset should report errors of rule %q as errors but got %q", r, s)
+		}
+	}
+}
+
+func TestPresetResolve(t *testing.T) {
+	custom := map[string]*Preset{
+		"mine":     {EnableRules: []string{"pipefail", "cd-in-run"}, Rules: map[string]string{"pipefail": SeverityError}},
+		"security": {EnableRules: []string{"secrets-in-args"}},
+		"quiet": {
+			DisableRules: []string{"pipefail"},
+			Rules:        map[string]string{"pipefail": SeverityWarning, "expression": SeverityWarning},
+		},
+	}
+
+	testCases := []struct {
+		what  string
+		names []string
+		want  []string
+		sev   map[string]string
+	}{
+		{"no preset", []string{}, []string{}, map[string]string{}},
+		{"built-in preset", []string{"minimal"}, []string{}, map[string]string{}},
+		{"custom preset", []string{"mine"}, []string{"pipefail", "cd-in-run"}, map[string]string{"pipefail": SeverityError}},
+		{"custom preset overrides built-in", []string{"security"}, []string{"secrets-in-args"}, map[string]string{}},
+		{
+			"multiple presets",
+			[]string{"mine", "security"},
+			[]string{"pipefail", "cd-in-run", "secrets-in-args"},
+			map[string]string{"pipefail": SeverityError},
+		},
+		{
+			"disable rules enabled by previous preset",
+			[]string{"mine", "quiet"},
+			[]string{"cd-in-run"},
+			map[string]string{"pipefail": SeverityWarning, "expression": SeverityWarning},
+		},
+		{
+			"enable rules disabled by previous preset",
+			[]string{"quiet", "mine"},
+			[]string{"pipefail", "cd-in-run"},
+			map[string]string{"pipefail": SeverityError, "expression": SeverityWarning},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			have, err := resolvePresets(tc.names, custom)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(tc.want, have.EnableRules) {
+				t.Fatal(cmp.Diff(tc.want, have.EnableRules))
+			}
+			if !cmp.Equal(tc.sev, have.Rules) {
+				t.Fatal(cmp.Diff(tc.sev, have.Rules))
+			}
+		})
+	}
+}
+
+func TestPresetResolveUnknown(t *testing.T) {
+	custom := map[string]*Preset{"mine": {}}
+	_, err := resolvePresets([]string{"security", "secure"}, custom)
+	if err == nil {
+		t.Fatal("error did not occur")
+	}
+	want := `unknown preset "secure". available presets are "mine", "minimal", "security", "strict"`
+	if !strings.Contains(err.Error(), want) {
+		t.Fatalf("%q is not contained in error message %q", want, err.Error())
+	}
+}
@@ -24,14 +24,20 @@ type concurrentProcess struct {
 }
 
 func newConcurrentProcess(par int) *concurrentProcess {
+	return newConcurrentProcessWithContext(context.Background(), par)
+}
+
+// newConcurrentProcessWithContext creates new concurrentProcess instance whose processes are
+// killed when the context is cancelled or its deadline is exceeded.
+func newConcurrentProcessWithContext(ctx context.Context, par int) *concurrentProcess {
 	return &concurrentProcess{
-		ctx:  context.Background(),
+		ctx:  ctx,
 		sema: semaphore.NewWeighted(int64(par)),
 	}
 }
 
-func runProcessWithStdin(exe string, args []string, stdin string) ([]byte, error) {
-	cmd := exec.Command(exe, args...)
+func runProcessWithStdin(ctx context.Context, exe string, args []string, stdin string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, exe, args...)
 	cmd.Stderr = nil
 
 	p, err := cmd.StdinPipe()
@@ -46,6 +52,9 @@ func runProcessWithStdin(exe string, args []string, stdin string) ([]byte, error
 
 	stdout, err := cmd.Output()
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%s was cancelled: %w", exe, ctx.Err())
+		}
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			code := exitErr.ExitCode()
 			if code < 0 {
@@ -64,11 +73,17 @@ func runProcessWithStdin(exe string, args []string, stdin string) ([]byte, error
 }
 
 func (proc *concurrentProcess) run(eg *errgroup.Group, exe string, args []string, stdin string, callback func([]byte, error) error) {
-	proc.sema.Acquire(proc.ctx, 1)
+	if err := proc.sema.Acquire(proc.ctx, 1); err != nil {
+		// The context was cancelled while waiting for other processes
+		eg.Go(func() error {
+			return callback(nil, fmt.Errorf("%s was cancelled: %w", exe, err))
+		})
+		return
+	}
 	proc.wg.Add(1)
 	eg.Go(func() error {
 		defer proc.wg.Done()
-		stdout, err := runProcessWithStdin(exe, args, stdin)
+		stdout, err := runProcessWithStdin(proc.ctx, exe, args, stdin)
 		proc.sema.Release(1)
 		return callback(stdout, err)
 	})
@@ -1,6 +1,8 @@
 package actionlint
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"runtime"
 	"strings"
@@ -290,3 +292,27 @@ func TestProcessErrorLinterFailed(t *testing.T) {
 		t.Fatal("a command following the error did not run")
 	}
 }
+
+func TestProcessCancelByContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	p := newConcurrentProcessWithContext(ctx, 1)
+	sleep := testSkipIfNoCommand(t, p, "sleep")
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		// The second process waits for the first one and is cancelled before starting
+		sleep.run([]string{"10"}, "", func(b []byte, err error) error {
+			return err
+		})
+	}
+
+	err := sleep.wait()
+	p.wait()
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("wanted deadline exceeded error but got %v", err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Fatalf("process was not killed by the context. it took %s", d)
+	}
+}
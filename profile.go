@@ -0,0 +1,59 @@
+package actionlint
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/pprof"
+)
+
+// profiler records CPU profile and memory profile of one run of actionlint command in pprof format.
+// The profiles can be analyzed with `go tool pprof`.
+type profiler struct {
+	cpu *os.File
+	mem string
+}
+
+// startProfiler starts CPU profiling when cpu is not empty. Memory profile is written to mem on
+// stopping the profiler when mem is not empty.
+func startProfiler(cpu, mem string) (*profiler, error) {
+	p := &profiler{mem: mem}
+	if cpu == "" {
+		return p, nil
+	}
+
+	f, err := os.Create(cpu)
+	if err != nil {
+		return nil, fmt.Errorf("could not create CPU profile file %q: %w", cpu, err)
+	}
+	if err := pprof.StartCPUProfile(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("could not start CPU profiling: %w", err)
+	}
+	p.cpu = f
+	return p, nil
+}
+
+// stop stops CPU profiling and writes the profiles to the files.
+func (p *profiler) stop() error {
+	if p.cpu != nil {
+		pprof.StopCPUProfile()
+		if err := p.cpu.Close(); err != nil {
+			return fmt.Errorf("could not write CPU profile file %q: %w", p.cpu.Name(), err)
+		}
+	}
+
+	if p.mem == "" {
+		return nil
+	}
+	f, err := os.Create(p.mem)
+	if err != nil {
+		return fmt.Errorf("could not create memory profile file %q: %w", p.mem, err)
+	}
+	defer f.Close()
+	runtime.GC() // Get up-to-date statistics of allocations
+	if err := pprof.WriteHeapProfile(f); err != nil {
+		return fmt.Errorf("could not write memory profile file %q: %w", p.mem, err)
+	}
+	return nil
+}
@@ -5,12 +5,14 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 )
 
 // Project represents one GitHub project. One Git repository corresponds to one project.
 type Project struct {
 	root   string
 	config *Config
+	mu     sync.Mutex
 }
 
 func absPath(path string) string {
@@ -52,6 +54,19 @@ func (p *Project) WorkflowsDir() string {
 	return filepath.Join(p.root, ".github", "workflows")
 }
 
+// DependabotConfigFile returns a path to Dependabot configuration file ".github/dependabot.yml" or
+// ".github/dependabot.yaml" of the GitHub project repository. It returns an empty string when the
+// file does not exist.
+func (p *Project) DependabotConfigFile() string {
+	for _, f := range []string{"dependabot.yml", "dependabot.yaml"} {
+		path := filepath.Join(p.root, ".github", f)
+		if s, err := os.Stat(path); err == nil && !s.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
 // Knows returns true when the project knows the given file. When a file is included in the
 // project's directory, the project knows the file.
 func (p *Project) Knows(path string) bool {
@@ -60,8 +75,12 @@ func (p *Project) Knows(path string) bool {
 }
 
 // Config returns config object of the GitHub project repository. The config file is read from
-// ".github/actionlint.yaml" or ".github/actionlint.yml".
+// ".github/actionlint.yaml" or ".github/actionlint.yml". The config is read only once and cached.
+// This method is safe to be called concurrently.
 func (p *Project) Config() (*Config, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
 	if p.config != nil {
 		return p.config, nil
 	}
@@ -84,9 +103,10 @@ func (p *Project) Config() (*Config, error) {
 }
 
 // Projects represents set of projects. It caches Project instances which was created previously
-// and reuses them.
+// and reuses them. Its methods are safe to be called concurrently.
 type Projects struct {
 	known []*Project
+	mu    sync.Mutex
 }
 
 // NewProjects creates new Projects instance.
@@ -96,6 +116,9 @@ func NewProjects() *Projects {
 
 // At returns the Project instance which the path belongs to.
 func (ps *Projects) At(path string) *Project {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
 	for _, p := range ps.known {
 		if p.Knows(path) {
 			return p
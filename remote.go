@@ -0,0 +1,140 @@
+package actionlint
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// fetchRepository fetches the repository given as git URL or path to tarball into a temporary
+// directory. It returns the root directory of the repository which contains ".github/workflows"
+// directory and a function to remove the temporary directory. The function must be called even if
+// linting the repository failed. Only public repositories which don't require authentication can
+// be cloned.
+func fetchRepository(src string) (string, func(), error) {
+	tmp, err := ioutil.TempDir("", "actionlint-repo-")
+	if err != nil {
+		return "", nil, fmt.Errorf("could not create temporary directory to fetch repository %q: %w", src, err)
+	}
+	cleanup := func() { os.RemoveAll(tmp) }
+
+	var root string
+	if s, err := os.Stat(src); err == nil && s.Mode().IsRegular() {
+		root, err = extractRepositoryTarball(src, tmp)
+		if err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	} else {
+		root = filepath.Join(tmp, "repo")
+		if err := cloneRepository(src, root); err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	}
+
+	if s, err := os.Stat(filepath.Join(root, ".github", "workflows")); err != nil || !s.IsDir() {
+		cleanup()
+		return "", nil, fmt.Errorf("no \".github/workflows\" directory was found in repository %q", src)
+	}
+
+	return root, cleanup, nil
+}
+
+func cloneRepository(url, dir string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", "clone", "--depth", "1", "--quiet", "--", url, dir)
+	cmd.Stderr = &stderr
+	// Fail instead of prompting credentials for private repositories
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return fmt.Errorf("could not clone git repository %q: %s", url, msg)
+	}
+	return nil
+}
+
+// extractRepositoryTarball extracts the tarball into the directory and returns the root directory
+// of the repository. Tarballs downloaded from GitHub have one top-level directory like
+// "owner-repo-sha/" so the directory is the root in the case. The tarball can be compressed with
+// gzip. Only regular files and directories are extracted.
+func extractRepositoryTarball(path, dir string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("could not open tarball %q: %w", path, err)
+	}
+	defer f.Close()
+
+	b := bufio.NewReader(f)
+	var r io.Reader = b
+	if magic, err := b.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		z, err := gzip.NewReader(b)
+		if err != nil {
+			return "", fmt.Errorf("could not read gzip-compressed tarball %q: %w", path, err)
+		}
+		defer z.Close()
+		r = z
+	}
+
+	t := tar.NewReader(r)
+	for {
+		h, err := t.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("could not extract tarball %q: %w", path, err)
+		}
+
+		dst := filepath.Join(dir, filepath.FromSlash(h.Name))
+		if !strings.HasPrefix(dst, dir+string(filepath.Separator)) {
+			return "", fmt.Errorf("could not extract tarball %q: file path %q is outside the archive", path, h.Name)
+		}
+
+		switch h.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(dst, 0755); err != nil {
+				return "", fmt.Errorf("could not extract tarball %q: %w", path, err)
+			}
+		case tar.TypeReg, tar.TypeRegA:
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return "", fmt.Errorf("could not extract tarball %q: %w", path, err)
+			}
+			if err := writeTarEntry(t, dst); err != nil {
+				return "", fmt.Errorf("could not extract tarball %q: %w", path, err)
+			}
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, ".github")); err == nil {
+		return dir, nil
+	}
+	entries, err := ioutil.ReadDir(dir)
+	if err == nil && len(entries) == 1 && entries[0].IsDir() {
+		return filepath.Join(dir, entries[0].Name()), nil
+	}
+	return dir, nil
+}
+
+func writeTarEntry(r io.Reader, path string) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,165 @@
+package actionlint
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testRemoteWorkflow = "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo '${{ unknown }}'\n"
+
+func testWriteTarball(t *testing.T, files map[string]string, compress bool) string {
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	for n, c := range files {
+		h := &tar.Header{Name: n, Mode: 0644, Size: int64(len(c)), Typeflag: tar.TypeReg}
+		if err := w.WriteHeader(h); err != nil {
+			panic(err)
+		}
+		if _, err := w.Write([]byte(c)); err != nil {
+			panic(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		panic(err)
+	}
+
+	b := buf.Bytes()
+	if compress {
+		var z bytes.Buffer
+		g := gzip.NewWriter(&z)
+		if _, err := g.Write(b); err != nil {
+			panic(err)
+		}
+		if err := g.Close(); err != nil {
+			panic(err)
+		}
+		b = z.Bytes()
+	}
+
+	p := filepath.Join(t.TempDir(), "repo.tar.gz")
+	if err := ioutil.WriteFile(p, b, 0644); err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func TestRemoteFetchRepositoryTarball(t *testing.T) {
+	testCases := []struct {
+		what     string
+		files    map[string]string
+		compress bool
+		root     string
+	}{
+		{
+			what:     "tarball downloaded from GitHub",
+			files:    map[string]string{"owner-repo-1234567/.github/workflows/ci.yaml": testRemoteWorkflow},
+			compress: true,
+			root:     "owner-repo-1234567",
+		},
+		{
+			what:  "uncompressed tarball without top-level directory",
+			files: map[string]string{".github/workflows/ci.yaml": testRemoteWorkflow, "README.md": "hello"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			root, cleanup, err := fetchRepository(testWriteTarball(t, tc.files, tc.compress))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if filepath.Base(root) != tc.root && tc.root != "" {
+				t.Fatalf("wanted root directory %q but got %q", tc.root, root)
+			}
+			b, err := ioutil.ReadFile(filepath.Join(root, ".github", "workflows", "ci.yaml"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(b) != testRemoteWorkflow {
+				t.Fatalf("extracted file is broken: %q", b)
+			}
+			cleanup()
+			if _, err := os.Stat(root); !os.IsNotExist(err) {
+				t.Fatalf("temporary directory %q was not removed: %v", root, err)
+			}
+		})
+	}
+}
+
+func TestRemoteFetchRepositoryError(t *testing.T) {
+	testCases := []struct {
+		what  string
+		files map[string]string
+		want  string
+	}{
+		{
+			what:  "no workflows directory",
+			files: map[string]string{"README.md": "hello"},
+			want:  `no ".github/workflows" directory was found in repository`,
+		},
+		{
+			what:  "file outside archive",
+			files: map[string]string{"../evil.yaml": "hello"},
+			want:  `file path "../evil.yaml" is outside the archive`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			_, _, err := fetchRepository(testWriteTarball(t, tc.files, true))
+			if err == nil {
+				t.Fatal("error did not occur")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("error %q does not contain %q", err.Error(), tc.want)
+			}
+		})
+	}
+}
+
+func TestRemoteFetchRepositoryGitClone(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git command is necessary to run this test:", err)
+	}
+
+	src := t.TempDir()
+	wd := filepath.Join(src, ".github", "workflows")
+	if err := os.MkdirAll(wd, 0755); err != nil {
+		panic(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(wd, "ci.yaml"), []byte(testRemoteWorkflow), 0644); err != nil {
+		panic(err)
+	}
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "init"},
+	} {
+		c := exec.Command("git", args...)
+		c.Dir = src
+		if out, err := c.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %s: %s", args, err, out)
+		}
+	}
+
+	root, cleanup, err := fetchRepository("file://" + filepath.ToSlash(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	if _, err := os.Stat(filepath.Join(root, ".github", "workflows", "ci.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = fetchRepository(filepath.Join(src, "does-not-exist"))
+	if err == nil || !strings.Contains(err.Error(), "could not clone git repository") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
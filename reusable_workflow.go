@@ -0,0 +1,89 @@
+package actionlint
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// LocalReusableWorkflowCache is cache for local reusable workflows called by jobs. It avoids
+// repeating to read/parse the same workflow file called from multiple jobs.
+// https://docs.github.com/en/actions/using-workflows/reusing-workflows
+type LocalReusableWorkflowCache struct {
+	mu    sync.RWMutex
+	proj  *Project // might be nil
+	cache map[string]*Workflow
+	dbg   io.Writer
+}
+
+// NewLocalReusableWorkflowCache creates new LocalReusableWorkflowCache instance.
+func NewLocalReusableWorkflowCache(proj *Project, dbg io.Writer) *LocalReusableWorkflowCache {
+	return &LocalReusableWorkflowCache{
+		proj:  proj,
+		cache: map[string]*Workflow{},
+		dbg:   dbg,
+	}
+}
+
+func (c *LocalReusableWorkflowCache) debug(format string, args ...interface{}) {
+	if c.dbg == nil {
+		return
+	}
+	format = "[LocalReusableWorkflowCache] " + format + "\n"
+	fmt.Fprintf(c.dbg, format, args...)
+}
+
+func (c *LocalReusableWorkflowCache) readCache(key string) (*Workflow, bool) {
+	c.mu.RLock()
+	w, ok := c.cache[key]
+	c.mu.RUnlock()
+	return w, ok
+}
+
+func (c *LocalReusableWorkflowCache) writeCache(key string, val *Workflow) {
+	c.mu.Lock()
+	c.cache[key] = val
+	c.mu.Unlock()
+}
+
+// FindWorkflow finds the reusable workflow for given spec and returns its syntax tree. The spec
+// should indicate a local reusable workflow hence it should start with "./". The first return
+// value can be nil even if error did not occur. As well as LocalActionsCache, the failure is cached
+// and an error is returned only at the first search.
+// Calling this method is thread-safe.
+func (c *LocalReusableWorkflowCache) FindWorkflow(spec string) (*Workflow, error) {
+	if c.proj == nil || !strings.HasPrefix(spec, "./") || strings.Contains(spec, "${{") {
+		return nil, nil
+	}
+
+	if w, ok := c.readCache(spec); ok {
+		c.debug("Cache hit for %s", spec)
+		return w, nil
+	}
+
+	path := filepath.Join(c.proj.RootDir(), filepath.FromSlash(spec))
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		c.writeCache(spec, nil) // Remember the workflow was not found
+		return nil, fmt.Errorf("could not read reusable workflow file for %q: %w", spec, err)
+	}
+
+	// Errors in the workflow are reported when the workflow file itself is checked
+	w, errs := Parse(b)
+	if w == nil {
+		c.writeCache(spec, nil) // Remember the workflow was broken
+		msg := ""
+		if len(errs) > 0 {
+			msg = ": " + errs[0].Message
+		}
+		return nil, fmt.Errorf("could not parse reusable workflow file for %q%s", spec, msg)
+	}
+
+	c.debug("New workflow parsed from %s", path)
+
+	c.writeCache(spec, w)
+	return w, nil
+}
@@ -0,0 +1,156 @@
+package actionlint
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalReusableWorkflowCacheFindWorkflow(t *testing.T) {
+	proj := &Project{root: filepath.Join("testdata", "reusable_workflows")}
+	c := NewLocalReusableWorkflowCache(proj, nil)
+
+	for i := 0; i < 3; i++ {
+		w, err := c.FindWorkflow("./.github/workflows/reusable.yaml")
+		if err != nil {
+			t.Fatal(i, err)
+		}
+		if w == nil {
+			t.Fatal(i, "workflow is nil")
+		}
+		if _, ok := w.Jobs["release"]; !ok {
+			t.Fatal(i, "job is not found in workflow", w.Jobs)
+		}
+	}
+
+	if _, ok := c.cache["./.github/workflows/reusable.yaml"]; !ok {
+		t.Fatal("workflow was not cached", c.cache)
+	}
+}
+
+func TestLocalReusableWorkflowCacheIgnoreNonLocalWorkflows(t *testing.T) {
+	proj := &Project{root: filepath.Join("testdata", "reusable_workflows")}
+	c := NewLocalReusableWorkflowCache(proj, nil)
+
+	for _, spec := range []string{
+		"owner/repo/.github/workflows/reusable.yaml@v1",
+		"./.github/workflows/${{ env.NAME }}.yaml",
+	} {
+		w, err := c.FindWorkflow(spec)
+		if err != nil {
+			t.Fatal(spec, err)
+		}
+		if w != nil {
+			t.Fatal(spec, "workflow should not be found", w)
+		}
+	}
+
+	c = NewLocalReusableWorkflowCache(nil, nil)
+	w, err := c.FindWorkflow("./.github/workflows/reusable.yaml")
+	if err != nil || w != nil {
+		t.Fatal("workflow should not be found without project", w, err)
+	}
+}
+
+func TestLocalReusableWorkflowCacheErrors(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{"./.github/workflows/not-exist.yaml", "could not read reusable workflow file"},
+		{"./.github/workflows/broken.yaml", "could not parse reusable workflow file"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.spec, func(t *testing.T) {
+			proj := &Project{root: filepath.Join("testdata", "reusable_workflows")}
+			c := NewLocalReusableWorkflowCache(proj, nil)
+
+			_, err := c.FindWorkflow(tc.spec)
+			if err == nil {
+				t.Fatal("error did not occur")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("wanted %q in error message but have %q", tc.want, err.Error())
+			}
+
+			// The failure is cached. Error is not returned at the second search
+			w, err := c.FindWorkflow(tc.spec)
+			if err != nil || w != nil {
+				t.Fatal("failure was not cached", w, err)
+			}
+		})
+	}
+}
+
+func TestRulePermissionsReusableWorkflow(t *testing.T) {
+	tests := []struct {
+		what   string
+		caller string
+		want   []string
+	}{
+		{
+			what: "granted",
+			caller: `permissions:
+      contents: read
+      pull-requests: write`,
+		},
+		{
+			what:   "write-all",
+			caller: `permissions: write-all`,
+		},
+		{
+			what: "not granted",
+			caller: `permissions:
+      contents: read`,
+			want: []string{
+				`requests "write" permission of scope "pull-requests" but the caller grants only "none"`,
+			},
+		},
+		{
+			what: "downgraded",
+			caller: `permissions:
+      contents: read
+      pull-requests: read`,
+			want: []string{
+				`requests "write" permission of scope "pull-requests" but the caller grants only "read"`,
+			},
+		},
+		{
+			what:   "read-all",
+			caller: `permissions: read-all`,
+			want: []string{
+				`requests "write" permission of scope "pull-requests" but the caller grants only "read"`,
+			},
+		},
+		{
+			what:   "no permission",
+			caller: ``,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.what, func(t *testing.T) {
+			src := `on: push
+jobs:
+  caller:
+    uses: ./.github/workflows/reusable.yaml
+    ` + tc.caller + "\n"
+			proj := &Project{root: filepath.Join("testdata", "reusable_workflows")}
+			r := NewRulePermissions()
+			r.SetLocalReusableWorkflowCache(NewLocalReusableWorkflowCache(proj, nil))
+			errs, err := RunRule(r, []byte(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(errs) != len(tc.want) {
+				t.Fatalf("wanted %d errors but have %d: %v", len(tc.want), len(errs), errs)
+			}
+			for i, want := range tc.want {
+				if !strings.Contains(errs[i].Message, want) {
+					t.Errorf("wanted %q in error message but have %q", want, errs[i].Message)
+				}
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package actionlint
 import (
 	"fmt"
 	"io"
+	"sort"
 )
 
 // RuleBase is a struct to be a base of rule structs. Embed this struct to define default methods
@@ -38,6 +39,14 @@ func (r *RuleBase) errorf(pos *Pos, format string, args ...interface{}) {
 	r.errs = append(r.errs, err)
 }
 
+// warnf reports an error whose default severity is SeverityWarning. It is used for errors which
+// may be false positives in rules enabled by default.
+func (r *RuleBase) warnf(pos *Pos, format string, args ...interface{}) {
+	err := errorfAt(pos, r.name, format, args...)
+	err.DefaultSeverity = SeverityWarning
+	r.errs = append(r.errs, err)
+}
+
 func (r *RuleBase) debug(format string, args ...interface{}) {
 	if r.dbg == nil {
 		return
@@ -69,3 +78,121 @@ type Rule interface {
 	Name() string
 	EnableDebug(out io.Writer)
 }
+
+// RunRule parses the source as a workflow and applies only the given rule to it. It returns parse
+// errors and errors found by the rule sorted by their positions. Fixes suggested by the rule are
+// resolved when the rule implements Fixer interface. The rule is not applied when the source could
+// not be parsed. This function is useful to test a rule in isolation.
+func RunRule(rule Rule, src []byte) ([]*Error, error) {
+	w, errs := Parse(src)
+	if w == nil {
+		return errs, nil
+	}
+
+	v := NewVisitor()
+	v.AddPass(rule)
+	if err := v.Visit(w); err != nil {
+		return nil, err
+	}
+
+	errs = append(errs, rule.Errs()...)
+	if f, ok := rule.(Fixer); ok {
+		resolveFixes(f.Fixes(), src)
+	}
+	sort.Stable(ByErrorPosition(errs))
+	return errs, nil
+}
+
+// optionalRules is a mapping from names of rules which are disabled by default to functions to
+// create them. These rules are heuristic or advisory so they may report false positives. They can be
+// enabled with EnableRules option of LinterOptions or "enable-rules" in config file.
+var optionalRules = map[string]func() Rule{
+	"action-permissions":       func() Rule { return NewRuleActionPermissions() },
+	"cd-in-run":                func() Rule { return NewRuleCdInRun() },
+	"continue-on-error":        func() Rule { return NewRuleContinueOnError() },
+	"default-branch":           func() Rule { return NewRuleDefaultBranch() },
+	"deprecated-commands":      func() Rule { return NewRuleDeprecatedCommands() },
+	"env-shadowing":            func() Rule { return NewRuleEnvShadowing() },
+	"event-inputs":             func() Rule { return NewRuleEventInputs() },
+	"fetch-depth":              func() Rule { return NewRuleFetchDepth() },
+	"final-job":                func() Rule { return NewRuleFinalJob() },
+	"hash-files":               func() Rule { return NewRuleHashFiles() },
+	"job-name":                 func() Rule { return NewRuleJobName() },
+	"local-action-path":        func() Rule { return NewRuleLocalActionPath() },
+	"matrix-augment":           func() Rule { return NewRuleMatrixAugment() },
+	"node-runtime":             func() Rule { return NewRuleNodeRuntime() },
+	"path-filter":              func() Rule { return NewRulePathFilter() },
+	"pipefail":                 func() Rule { return NewRulePipefail() },
+	"push-filter":              func() Rule { return NewRulePushFilter() },
+	"secrets-in-args":          func() Rule { return NewRuleSecretsInArgs() },
+	"setup-version":            func() Rule { return NewRuleSetupVersion() },
+	"unused-step-outputs":      func() Rule { return NewRuleUnusedStepOutputs() },
+	"working-directory-exists": func() Rule { return NewRuleWorkingDirectoryExists() },
+	"yaml-boolean":             func() Rule { return NewRuleYAMLBoolean() },
+}
+
+// exprHookRule is a rule which checks expressions while RuleExpression checks them. The checkExpr
+// method is called with each expression and its position.
+type exprHookRule interface {
+	checkExpr(expr ExprNode, line, col int)
+}
+
+// builtinRuleNames is a list of names of rules which are enabled by default. Kinds of errors
+// reported by the parser and the checks of files other than workflows are also included.
+var builtinRuleNames = []string{
+	ErrorKindYAMLSyntax,
+	ErrorKindSyntaxCheck,
+	"action",
+	"cache-key",
+	"composite-action",
+	"credentials",
+	"dependabot",
+	"env-var",
+	"environment",
+	"events",
+	"expression",
+	"glob",
+	"if-cond",
+	"job-needs",
+	"matrix",
+	"permissions",
+	"post-parse",
+	"pyflakes",
+	"runner-label",
+	"secret-name",
+	"secrets-in-outputs",
+	"secrets-xtrace",
+	"shell-name",
+	"shellcheck",
+	"step-id",
+	"timeout-minutes",
+	"workflow-call",
+	"workflow-commands",
+	"workflow-limits",
+	"working-directory",
+}
+
+// checkRuleName checks the name is a name of rule enabled by default or an optional rule.
+func checkRuleName(name string) error {
+	if _, ok := optionalRules[name]; ok || contains(builtinRuleNames, name) {
+		return nil
+	}
+	names := append(allOptionalRuleNames(), builtinRuleNames...)
+	if ss := findSimilarStrings(name, names); len(ss) > 0 {
+		return fmt.Errorf("unknown rule %q. did you mean %s?", name, sortedQuotes(ss))
+	}
+	return fmt.Errorf("unknown rule %q. available rules are %s", name, sortedQuotes(names))
+}
+
+// checkOptionalRuleName checks the name is a name of optional rule. The action parameter is "enable"
+// or "disable" to describe the error.
+func checkOptionalRuleName(name, action string) error {
+	if _, ok := optionalRules[name]; ok {
+		return nil
+	}
+	names := make([]string, 0, len(optionalRules))
+	for n := range optionalRules {
+		names = append(names, n)
+	}
+	return fmt.Errorf("unknown rule %q to %s. available rules are %s", name, action, sortedQuotes(names))
+}
@@ -11,7 +11,8 @@ import (
 // https://docs.github.com/en/actions/learn-github-actions/workflow-syntax-for-github-actions#jobsjob_idstepsuses
 type RuleAction struct {
 	RuleBase
-	cache *LocalActionsCache
+	cache       *LocalActionsCache
+	actionsMeta map[string]*ActionMetadata
 }
 
 // NewRuleAction creates new RuleAction instance.
@@ -22,6 +23,13 @@ func NewRuleAction(cache *LocalActionsCache) *RuleAction {
 	}
 }
 
+// SetActionsMetadata sets metadata of additional actions which are not included in the popular
+// actions dataset. Keys of the map are action specs like "owner/repo@ref" or "owner/repo". The
+// metadata is used for checking inputs of the actions.
+func (rule *RuleAction) SetActionsMetadata(m map[string]*ActionMetadata) {
+	rule.actionsMeta = m
+}
+
 // VisitStep is callback when visiting Step node.
 func (rule *RuleAction) VisitStep(n *Step) error {
 	e, ok := n.Exec.(*ExecAction)
@@ -81,11 +89,14 @@ func (rule *RuleAction) checkRepoAction(spec string, exec *ExecAction) {
 		rule.invalidActionFormat(exec.Uses.Pos, spec, "owner and repo and ref should not be empty")
 	}
 
-	meta, ok := PopularActions[spec]
+	meta, ok := findRepoActionMetadata(spec, rule.actionsMeta)
 	if !ok {
 		rule.debug("This action is not found in popular actions data set: %s", spec)
 		return
 	}
+	if meta.Deprecated != "" {
+		rule.checkDeprecatedAction(spec, meta, exec)
+	}
 	if meta.SkipInputs {
 		rule.debug("This action skips to check inputs: %s", spec)
 		return
@@ -96,6 +107,36 @@ func (rule *RuleAction) checkRepoAction(spec string, exec *ExecAction) {
 	})
 }
 
+func (rule *RuleAction) checkInputType(meta *ActionMetadata, name string, input *Input, describe func(*ActionMetadata) string) {
+	if input.Value == nil || strings.Contains(input.Value.Value, "${{") {
+		return // Value is determined at runtime
+	}
+	for n, t := range meta.InputTypes {
+		if !strings.EqualFold(n, name) {
+			continue
+		}
+		if msg := t.check(input.Value.Value); msg != "" {
+			rule.errorf(
+				input.Value.Pos,
+				"input %q of action %s expects %s value but %s",
+				n,
+				describe(meta),
+				t.Type,
+				msg,
+			)
+		}
+		return
+	}
+}
+
+func (rule *RuleAction) checkDeprecatedAction(spec string, meta *ActionMetadata, exec *ExecAction) {
+	if meta.Replacement == "" {
+		rule.errorf(exec.Uses.Pos, "action %q is deprecated: %s", spec, meta.Deprecated)
+		return
+	}
+	rule.errorf(exec.Uses.Pos, "action %q is deprecated: %s. use %q instead", spec, meta.Deprecated, meta.Replacement)
+}
+
 func (rule *RuleAction) invalidActionFormat(pos *Pos, spec string, why string) {
 	rule.errorf(pos, "specifying action %q in invalid format because %s. available formats are \"{owner}/{repo}@{ref}\" or \"{owner}/{repo}/{path}@{ref}\"", spec, why)
 }
@@ -171,6 +212,13 @@ Outer:
 		)
 	}
 
+	// Check values of inputs match to their types declared in actions metadata file
+	if len(meta.InputTypes) > 0 {
+		for name, val := range exec.Inputs {
+			rule.checkInputType(meta, name, val, describe)
+		}
+	}
+
 	// Check mandatory inputs are specified
 	for name, required := range meta.Inputs {
 		if required {
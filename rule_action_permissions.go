@@ -0,0 +1,106 @@
+package actionlint
+
+import (
+	"sort"
+	"strings"
+)
+
+// RuleActionPermissions is a rule to detect steps using actions which require permissions of
+// GITHUB_TOKEN not granted by 'permissions:' of the job or the workflow. Such steps fail at runtime.
+// Required permissions are known only for some popular actions and actions described in actions
+// metadata file. Since the dataset is incomplete, this rule is disabled by default.
+// https://docs.github.com/en/actions/security-guides/automatic-token-authentication#permissions-for-the-github_token
+type RuleActionPermissions struct {
+	RuleBase
+	actionsMeta   map[string]*ActionMetadata
+	workflowPerms *Permissions
+	perms         *Permissions // Permissions effective in the current job
+}
+
+// NewRuleActionPermissions creates new RuleActionPermissions instance.
+func NewRuleActionPermissions() *RuleActionPermissions {
+	return &RuleActionPermissions{
+		RuleBase: RuleBase{name: "action-permissions"},
+	}
+}
+
+// SetActionsMetadata sets additional metadata of actions. Required permissions in the metadata are
+// prioritized over the popular actions dataset.
+func (rule *RuleActionPermissions) SetActionsMetadata(m map[string]*ActionMetadata) {
+	rule.actionsMeta = m
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RuleActionPermissions) VisitWorkflowPre(n *Workflow) error {
+	rule.workflowPerms = n.Permissions
+	return nil
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleActionPermissions) VisitJobPre(n *Job) error {
+	rule.perms = n.Permissions
+	if rule.perms == nil {
+		rule.perms = rule.workflowPerms
+	}
+	return nil
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RuleActionPermissions) VisitStep(n *Step) error {
+	if rule.perms == nil {
+		return nil // When no permission is set, default permissions are used. They depend on repository settings
+	}
+	e, ok := n.Exec.(*ExecAction)
+	if !ok || e.Uses == nil || strings.Contains(e.Uses.Value, "${{") {
+		return nil
+	}
+	meta, ok := findRepoActionMetadata(e.Uses.Value, rule.actionsMeta)
+	if !ok || len(meta.Permissions) == 0 || usesOtherCredential(e) {
+		return nil
+	}
+
+	scopes := make([]string, 0, len(meta.Permissions))
+	for s := range meta.Permissions {
+		scopes = append(scopes, s)
+	}
+	sort.Strings(scopes)
+
+	for _, s := range scopes {
+		r := meta.Permissions[s]
+		g, pos := grantedPermission(rule.perms, s)
+		if permissionLevel(r) <= permissionLevel(g) {
+			continue
+		}
+		rule.errorf(
+			n.Pos,
+			"action %q requires %q permission of scope %q but only %q is granted by \"permissions\" at line %d, col %d. the step will fail since GITHUB_TOKEN does not have enough permissions",
+			e.Uses.Value,
+			r,
+			s,
+			g,
+			pos.Line,
+			pos.Col,
+		)
+	}
+	return nil
+}
+
+// usesOtherCredential returns whether the action step is given some credential other than
+// GITHUB_TOKEN such as personal access token or deploy key. Permissions of GITHUB_TOKEN don't
+// matter in the case.
+func usesOtherCredential(e *ExecAction) bool {
+	for n, i := range e.Inputs {
+		if i.Value == nil {
+			continue
+		}
+		n = strings.ToLower(n)
+		if !strings.Contains(n, "token") && !strings.HasSuffix(n, "key") {
+			continue
+		}
+		v := strings.ToLower(i.Value.Value)
+		if !strings.Contains(v, "github.token") && !strings.Contains(v, "secrets.github_token") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package actionlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleActionPermissions(t *testing.T) {
+	testCases := []struct {
+		what  string
+		perms string
+		step  string
+		want  string
+	}{
+		{
+			what:  "read-only contents",
+			perms: "permissions:\n  contents: read\n",
+			step:  "uses: peaceiris/actions-gh-pages@v3",
+			want:  `action "peaceiris/actions-gh-pages@v3" requires "write" permission of scope "contents" but only "read" is granted`,
+		},
+		{
+			what:  "scope not listed",
+			perms: "permissions:\n  issues: write\n",
+			step:  "uses: actions/create-release@v1",
+			want:  `scope "contents" but only "none" is granted`,
+		},
+		{
+			what:  "read-all",
+			perms: "permissions: read-all\n",
+			step:  "uses: github/codeql-action/analyze@v1",
+			want:  `scope "security-events" but only "read" is granted`,
+		},
+		{
+			what:  "write-all",
+			perms: "permissions: write-all\n",
+			step:  "uses: peaceiris/actions-gh-pages@v3",
+		},
+		{
+			what:  "enough permissions",
+			perms: "permissions:\n  contents: write\n",
+			step:  "uses: peaceiris/actions-gh-pages@v3",
+		},
+		{
+			what: "default permissions",
+			step: "uses: peaceiris/actions-gh-pages@v3",
+		},
+		{
+			what:  "GITHUB_TOKEN given explicitly",
+			perms: "permissions:\n  contents: read\n",
+			step:  "uses: peaceiris/actions-gh-pages@v3\n        with:\n          github_token: ${{ secrets.GITHUB_TOKEN }}",
+			want:  `scope "contents" but only "read" is granted`,
+		},
+		{
+			what:  "personal access token",
+			perms: "permissions:\n  contents: read\n",
+			step:  "uses: peaceiris/actions-gh-pages@v3\n        with:\n          personal_token: ${{ secrets.PAT }}",
+		},
+		{
+			what:  "deploy key",
+			perms: "permissions:\n  contents: read\n",
+			step:  "uses: peaceiris/actions-gh-pages@v3\n        with:\n          deploy_key: ${{ secrets.DEPLOY_KEY }}",
+		},
+		{
+			what:  "action without required permissions",
+			perms: "permissions:\n  contents: read\n",
+			step:  "uses: actions/checkout@v3",
+		},
+		{
+			what:  "unknown action",
+			perms: "permissions:\n  contents: read\n",
+			step:  "uses: my-org/my-action@v1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			src := "on: push\n" + tc.perms + "jobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - " + tc.step + "\n"
+			errs, err := RunRule(NewRuleActionPermissions(), []byte(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("wanted 1 error but got %v", errs)
+			}
+			if !strings.Contains(errs[0].Message, tc.want) {
+				t.Fatalf("error message %q does not contain %q", errs[0].Message, tc.want)
+			}
+		})
+	}
+}
+
+func TestRuleActionPermissionsJobPermissions(t *testing.T) {
+	src := `on: push
+permissions: write-all
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    permissions:
+      contents: read
+    steps:
+      - uses: peaceiris/actions-gh-pages@v3
+`
+	errs, err := RunRule(NewRuleActionPermissions(), []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, "granted by \"permissions\" at line 7, col 17") {
+		t.Fatalf("permissions of job should be prioritized over workflow: %v", errs)
+	}
+}
+
+func TestRuleActionPermissionsActionsMetadata(t *testing.T) {
+	src := `on: push
+permissions:
+  contents: read
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: my-org/my-action@v1
+`
+	r := NewRuleActionPermissions()
+	r.SetActionsMetadata(map[string]*ActionMetadata{
+		"my-org/my-action": {Name: "My action", Permissions: map[string]string{"issues": "write"}},
+	})
+	errs, err := RunRule(r, []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, `requires "write" permission of scope "issues"`) {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+}
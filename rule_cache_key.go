@@ -0,0 +1,101 @@
+package actionlint
+
+import (
+	"strings"
+)
+
+// Properties whose values change on every workflow run. When a cache key contains them, the cache
+// never hits.
+var uniqueValuesPerRun = map[string]struct{}{
+	"github.run_id":                      {},
+	"github.run_number":                  {},
+	"github.event.repository.pushed_at":  {},
+	"github.event.repository.updated_at": {},
+}
+
+// RuleCacheKey is a rule to detect keys of actions/cache which include values changing on every
+// workflow run such as github.run_id. The cache with such key is saved on every run but never hits.
+// The key is OK when "restore-keys" input is set since the latest cache is restored by prefix.
+// https://github.com/actions/cache#creating-a-cache-key
+type RuleCacheKey struct {
+	RuleBase
+}
+
+// NewRuleCacheKey creates new RuleCacheKey instance.
+func NewRuleCacheKey() *RuleCacheKey {
+	return &RuleCacheKey{
+		RuleBase: RuleBase{name: "cache-key"},
+	}
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RuleCacheKey) VisitStep(n *Step) error {
+	e, ok := n.Exec.(*ExecAction)
+	if !ok || e.Uses == nil || !isCacheAction(e.Uses.Value) {
+		return nil
+	}
+
+	if _, ok := e.Inputs["restore-keys"]; ok {
+		return nil
+	}
+	key, ok := e.Inputs["key"]
+	if !ok || key.Value == nil {
+		return nil
+	}
+
+	visitExprsInString(key.Value, false, func(expr ExprNode, _, _ int) {
+		VisitExprNode(expr, func(n, _ ExprNode, entering bool) {
+			if !entering {
+				return
+			}
+			p, ok := propertyPathOf(n)
+			if !ok {
+				return
+			}
+			if _, ok := uniqueValuesPerRun[p]; !ok {
+				return
+			}
+			rule.errorf(
+				key.Value.Pos,
+				"cache key of %q contains %q which changes on every workflow run. the cache never hits with this key. use hash of files like hashFiles('**/go.sum') instead or set \"restore-keys\" input to restore the latest cache by prefix",
+				e.Uses.Value,
+				p,
+			)
+		})
+	})
+
+	return nil
+}
+
+// isCacheAction returns whether the action restores a cache with "key" input.
+func isCacheAction(spec string) bool {
+	s := strings.ToLower(spec)
+	return strings.HasPrefix(s, "actions/cache@") || strings.HasPrefix(s, "actions/cache/restore@")
+}
+
+// propertyPathOf returns the path of property access like "github.event.action". The second
+// return value is false when the node is not a property access from a context.
+func propertyPathOf(n ExprNode) (string, bool) {
+	switch n := n.(type) {
+	case *VariableNode:
+		return strings.ToLower(n.Name), true
+	case *ObjectDerefNode:
+		p, ok := propertyPathOf(n.Receiver)
+		if !ok {
+			return "", false
+		}
+		return p + "." + n.Property, true
+	case *IndexAccessNode:
+		s, ok := n.Index.(*StringNode)
+		if !ok {
+			return "", false
+		}
+		p, ok := propertyPathOf(n.Operand)
+		if !ok {
+			return "", false
+		}
+		return p + "." + strings.ToLower(s.Value), true
+	default:
+		return "", false
+	}
+}
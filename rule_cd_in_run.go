@@ -0,0 +1,80 @@
+package actionlint
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Matches a line which only changes the current directory like "cd ./app" or "pushd app || exit 1".
+// The first submatch is the directory.
+var reCdCommandLine = regexp.MustCompile(`^(?:cd|pushd|Set-Location|Push-Location)\s+("[^"]*"|'[^']*'|[^\s;&|]+)\s*(?:;|\|\|\s*exit(?:\s+\d+)?)?$`)
+
+// RuleCdInRun is a rule to detect 'run:' scripts which end with 'cd' command followed by another
+// 'run:' step. Since each 'run:' step runs in a new shell, the current directory changed by 'cd' is
+// not inherited by the next step. 'working-directory:' should be used instead. Since whether the
+// next step assumes the directory is guessed heuristically, this rule is disabled by default.
+// https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#jobsjob_idstepsworking-directory
+type RuleCdInRun struct {
+	RuleBase
+}
+
+// NewRuleCdInRun creates new RuleCdInRun instance.
+func NewRuleCdInRun() *RuleCdInRun {
+	return &RuleCdInRun{
+		RuleBase: RuleBase{name: "cd-in-run"},
+	}
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleCdInRun) VisitJobPre(n *Job) error {
+	for i, s := range n.Steps {
+		if i+1 == len(n.Steps) {
+			break
+		}
+		e, ok := s.Exec.(*ExecRun)
+		if !ok || e.Run == nil {
+			continue
+		}
+		dir, ok := trailingCdDir(e.Run.Value)
+		if !ok {
+			continue
+		}
+
+		next := n.Steps[i+1]
+		r, ok := next.Exec.(*ExecRun)
+		if !ok || r.WorkingDirectory != nil {
+			continue // The next step explicitly sets its working directory
+		}
+		rule.errorf(
+			e.RunPos,
+			"\"cd %s\" at the end of this script does not affect the next step at line %d, col %d since each \"run:\" step runs in a new shell. set \"working-directory: %s\" to the next step instead",
+			dir,
+			next.Pos.Line,
+			next.Pos.Col,
+			dir,
+		)
+	}
+	return nil
+}
+
+// trailingCdDir returns the directory changed by the last command in the script. The second return
+// value is false when the last command is not 'cd'.
+func trailingCdDir(script string) (string, bool) {
+	lines := strings.Split(script, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		l := strings.TrimSpace(lines[i])
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		m := reCdCommandLine.FindStringSubmatch(l)
+		if m == nil {
+			return "", false
+		}
+		d := m[1]
+		if d == "-" || d == "~" || d == ".." {
+			return "", false // Going back to some directory is not an error
+		}
+		return strings.Trim(d, `"'`), true
+	}
+	return "", false
+}
@@ -0,0 +1,39 @@
+package actionlint
+
+import "testing"
+
+func TestRuleCdInRunTrailingCdDir(t *testing.T) {
+	testCases := []struct {
+		script string
+		want   string
+	}{
+		{"cd app", "app"},
+		{"cd ./app/frontend", "./app/frontend"},
+		{"npm ci\ncd app\n", "app"},
+		{"cd app\n# move to app\n\n", "app"},
+		{"cd 'my app'", "my app"},
+		{`cd "$GITHUB_WORKSPACE/app"`, "$GITHUB_WORKSPACE/app"},
+		{"cd app || exit 1", "app"},
+		{"cd app;", "app"},
+		{"pushd app", "app"},
+		{"Set-Location app", "app"},
+		{"cd app\nnpm ci", ""},
+		{"cd app && npm ci", ""},
+		{"cd -", ""},
+		{"cd ..", ""},
+		{"echo cd app", ""},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.script, func(t *testing.T) {
+			have, ok := trailingCdDir(tc.script)
+			if ok != (tc.want != "") {
+				t.Fatalf("wanted %q but got %q (ok=%v)", tc.want, have, ok)
+			}
+			if have != tc.want {
+				t.Fatalf("wanted %q but got %q", tc.want, have)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package actionlint
+
+import (
+	"strings"
+)
+
+// RuleContinueOnError is a rule to detect outputs of steps with 'continue-on-error: true' which are
+// used by later steps without checking the outcome of the steps. When such step fails, the job
+// continues and its outputs are empty or stale. The steps using the outputs should check
+// 'steps.<id>.outcome' before using them. Since whether the failure is handled properly is guessed
+// heuristically, this rule is disabled by default.
+// https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#jobsjob_idstepscontinue-on-error
+type RuleContinueOnError struct {
+	RuleBase
+}
+
+// NewRuleContinueOnError creates new RuleContinueOnError instance.
+func NewRuleContinueOnError() *RuleContinueOnError {
+	return &RuleContinueOnError{
+		RuleBase: RuleBase{name: "continue-on-error"},
+	}
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleContinueOnError) VisitJobPre(n *Job) error {
+	// Steps with 'continue-on-error: true' by their lower-cased IDs
+	steps := map[string]*Step{}
+	for _, s := range n.Steps {
+		if len(steps) > 0 {
+			rule.checkStep(s, steps)
+		}
+		if s.ID != nil && s.ContinueOnError != nil && s.ContinueOnError.Expression == nil && s.ContinueOnError.Value {
+			steps[strings.ToLower(s.ID.Value)] = s
+		}
+	}
+	return nil
+}
+
+func (rule *RuleContinueOnError) checkStep(s *Step, steps map[string]*Step) {
+	// Outcomes checked at 'if:' guard all outputs used in the step
+	guarded := map[string]struct{}{}
+	visitExprsInString(s.If, true, func(expr ExprNode, _, _ int) {
+		for _, r := range findStepRefsInExpr(expr, "outcome") {
+			guarded[r.id] = struct{}{}
+		}
+	})
+
+	check := func(str *String, bare bool) {
+		visitExprsInString(str, bare, func(expr ExprNode, line, col int) {
+			// Outcome checked in the same expression like `steps.foo.outcome == 'success' && steps.foo.outputs.bar`
+			checked := map[string]struct{}{}
+			for _, r := range findStepRefsInExpr(expr, "outcome") {
+				checked[r.id] = struct{}{}
+			}
+			for _, r := range findStepRefsInExpr(expr, "outputs") {
+				step, ok := steps[r.id]
+				if !ok {
+					continue
+				}
+				if _, ok := guarded[r.id]; ok {
+					continue
+				}
+				if _, ok := checked[r.id]; ok {
+					continue
+				}
+				t := r.node.Token()
+				rule.errorf(
+					convertExprLineColToPos(t.Line, t.Column, line, col),
+					"outputs of step %q are used without checking its outcome. the step has \"continue-on-error: true\" at line %d, col %d so the outputs may be empty or stale when the step failed. check \"steps.%s.outcome\" before using the outputs",
+					step.ID.Value,
+					step.ContinueOnError.Pos.Line,
+					step.ContinueOnError.Pos.Col,
+					step.ID.Value,
+				)
+			}
+		})
+	}
+
+	check(s.If, true)
+	check(s.Name, false)
+	switch e := s.Exec.(type) {
+	case *ExecRun:
+		check(e.Run, false)
+		check(e.WorkingDirectory, false)
+	case *ExecAction:
+		for _, i := range e.Inputs {
+			check(i.Value, false)
+		}
+		check(e.Entrypoint, false)
+		check(e.Args, false)
+	}
+	if s.Env != nil {
+		for _, v := range s.Env.Vars {
+			check(v.Value, false)
+		}
+	}
+}
+
+type stepRef struct {
+	id   string
+	node ExprNode
+}
+
+// findStepRefsInExpr finds property accesses like steps.<id>.<prop> in the expression. IDs in the
+// returned values are lower-cased.
+func findStepRefsInExpr(expr ExprNode, prop string) []stepRef {
+	refs := []stepRef{}
+	VisitExprNode(expr, func(n, _ ExprNode, entering bool) {
+		if !entering {
+			return
+		}
+		d, ok := n.(*ObjectDerefNode)
+		if !ok || !strings.EqualFold(d.Property, prop) {
+			return
+		}
+		s, ok := d.Receiver.(*ObjectDerefNode)
+		if !ok {
+			return
+		}
+		if v, ok := s.Receiver.(*VariableNode); ok && strings.EqualFold(v.Name, "steps") {
+			refs = append(refs, stepRef{strings.ToLower(s.Property), n})
+		}
+	})
+	return refs
+}
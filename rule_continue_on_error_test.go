@@ -0,0 +1,78 @@
+package actionlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleContinueOnError(t *testing.T) {
+	testCases := []struct {
+		what     string
+		consumer string
+		want     string
+	}{
+		{
+			what:     "outputs in run",
+			consumer: "- run: echo '${{ steps.build.outputs.path }}'",
+			want:     `outputs of step "build" are used without checking its outcome`,
+		},
+		{
+			what:     "outputs in with",
+			consumer: "- uses: actions/upload-artifact@v4\n        with:\n          path: ${{ steps.build.outputs.path }}",
+			want:     `outputs of step "build" are used without checking its outcome`,
+		},
+		{
+			what:     "outputs in if without outcome",
+			consumer: "- run: echo ok\n        if: steps.build.outputs.path != ''",
+			want:     `outputs of step "build" are used without checking its outcome`,
+		},
+		{
+			what:     "outcome checked at if",
+			consumer: "- run: echo '${{ steps.build.outputs.path }}'\n        if: steps.build.outcome == 'success'",
+		},
+		{
+			what:     "outcome checked in the same expression",
+			consumer: "- run: echo '${{ steps.build.outcome == 'success' && steps.build.outputs.path || 'none' }}'",
+		},
+		{
+			what:     "other step",
+			consumer: "- run: echo '${{ steps.other.outputs.path }}'",
+		},
+		{
+			what:     "outcome only",
+			consumer: "- run: echo '${{ steps.build.outcome }}'",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: ./build.sh
+        id: build
+        continue-on-error: true
+      - run: ./other.sh
+        id: other
+      ` + tc.consumer + "\n"
+			errs, err := RunRule(NewRuleContinueOnError(), []byte(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("wanted 1 error but got %v", errs)
+			}
+			if !strings.Contains(errs[0].Message, tc.want) {
+				t.Fatalf("error message %q does not contain %q", errs[0].Message, tc.want)
+			}
+		})
+	}
+}
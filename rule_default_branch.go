@@ -0,0 +1,77 @@
+package actionlint
+
+import (
+	"strings"
+)
+
+// commonDefaultBranches is a set of branch names commonly used as default branches. Repositories
+// created before 2020 use "master" and newer repositories use "main" by default.
+var commonDefaultBranches = map[string]struct{}{
+	"main":   {},
+	"master": {},
+}
+
+// RuleDefaultBranch is a rule to detect branch filters of events which are likely typos of the
+// default branch of the repository. For example, when a workflow filters "master" branch but the
+// default branch is "main", the workflow is never triggered on the default branch. The default
+// branch is given by "default-branch" in config file or -default-branch flag. Since branch names
+// are arbitrary and this check is heuristic, this rule is disabled by default.
+// https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#onpushpull_requestpull_request_targetpathspaths-ignore
+type RuleDefaultBranch struct {
+	RuleBase
+	branch string
+}
+
+// NewRuleDefaultBranch creates new RuleDefaultBranch instance.
+func NewRuleDefaultBranch() *RuleDefaultBranch {
+	return &RuleDefaultBranch{
+		RuleBase: RuleBase{name: "default-branch"},
+	}
+}
+
+// SetDefaultBranch sets the name of the default branch of the repository. When it is empty, this
+// rule checks nothing.
+func (rule *RuleDefaultBranch) SetDefaultBranch(b string) {
+	rule.branch = b
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RuleDefaultBranch) VisitWorkflowPre(n *Workflow) error {
+	if rule.branch == "" {
+		return nil
+	}
+	for _, e := range n.On {
+		if e, ok := e.(*WebhookEvent); ok {
+			rule.checkFilter(e.Hook.Value, "branches", e.Branches)
+			rule.checkFilter(e.Hook.Value, "branches-ignore", e.BranchesIgnore)
+		}
+	}
+	return nil
+}
+
+func (rule *RuleDefaultBranch) checkFilter(event, filter string, branches []*String) {
+	for _, b := range branches {
+		if rule.isLikelyTypo(strings.TrimPrefix(b.Value, "!")) {
+			rule.errorf(
+				b.Pos,
+				"branch %q at %q filter of %q event is likely a mistake of the default branch %q of this repository. the filter does not match the default branch",
+				b.Value,
+				filter,
+				event,
+				rule.branch,
+			)
+		}
+	}
+}
+
+func (rule *RuleDefaultBranch) isLikelyTypo(b string) bool {
+	if b == rule.branch || b == "" || strings.ContainsAny(b, "*?+[") || strings.Contains(b, "${{") {
+		return false
+	}
+	if _, ok := commonDefaultBranches[b]; ok {
+		if _, ok := commonDefaultBranches[rule.branch]; ok {
+			return true // "master" is used but the default branch is "main", or vice versa
+		}
+	}
+	return len(findSimilarStrings(strings.ToLower(b), []string{strings.ToLower(rule.branch)})) > 0
+}
@@ -0,0 +1,95 @@
+package actionlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleDefaultBranch(t *testing.T) {
+	testCases := []struct {
+		what   string
+		branch string
+		on     string
+		want   string
+	}{
+		{
+			what:   "master for main",
+			branch: "main",
+			on:     "pull_request:\n    branches: [master]",
+			want:   `branch "master" at "branches" filter of "pull_request" event is likely a mistake of the default branch "main"`,
+		},
+		{
+			what:   "main for master",
+			branch: "master",
+			on:     "push:\n    branches: [main]",
+			want:   `branch "main" at "branches" filter of "push" event is likely a mistake of the default branch "master"`,
+		},
+		{
+			what:   "typo",
+			branch: "develop",
+			on:     "push:\n    branches: [devlop]",
+			want:   `branch "devlop" at "branches" filter`,
+		},
+		{
+			what:   "case mismatch",
+			branch: "main",
+			on:     "push:\n    branches: [Main]",
+			want:   `branch "Main" at "branches" filter`,
+		},
+		{
+			what:   "negated pattern",
+			branch: "main",
+			on:     "push:\n    branches: ['releases/**', '!mian']",
+			want:   `branch "!mian" at "branches" filter`,
+		},
+		{
+			what:   "branches-ignore",
+			branch: "main",
+			on:     "pull_request_target:\n    branches-ignore: [master]",
+			want:   `branch "master" at "branches-ignore" filter of "pull_request_target" event`,
+		},
+		{
+			what:   "default branch",
+			branch: "main",
+			on:     "push:\n    branches: [main]",
+		},
+		{
+			what:   "other branches",
+			branch: "main",
+			on:     "push:\n    branches: [release, gh-pages, dev]",
+		},
+		{
+			what:   "glob patterns",
+			branch: "main",
+			on:     "push:\n    branches: ['mai*', 'mai?', 'master-*']",
+		},
+		{
+			what: "default branch is unknown",
+			on:   "push:\n    branches: [master]",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			src := "on:\n  " + tc.on + "\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n"
+			r := NewRuleDefaultBranch()
+			r.SetDefaultBranch(tc.branch)
+			errs, err := RunRule(r, []byte(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("wanted 1 error but got %v", errs)
+			}
+			if !strings.Contains(errs[0].Message, tc.want) {
+				t.Fatalf("error message %q does not contain %q", errs[0].Message, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package actionlint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	reDeprecatedCommand = regexp.MustCompile(`::(set-output|save-state|set-env|add-path)[ :]`)
+	// echo "::set-output name={name}::{value}"
+	reDeprecatedCommandWithName = regexp.MustCompile(`^echo\s+(["']?)::(set-output|save-state|set-env)\s+name=([\w.-]+)::(.*)$`)
+	// echo "::add-path::{path}"
+	reDeprecatedCommandAddPath = regexp.MustCompile(`^echo\s+(["']?)::add-path::(.*)$`)
+)
+
+// Files to write outputs of workflow commands instead of deprecated workflow commands.
+var deprecatedCommandFiles = map[string]string{
+	"set-output": "GITHUB_OUTPUT",
+	"save-state": "GITHUB_STATE",
+	"set-env":    "GITHUB_ENV",
+	"add-path":   "GITHUB_PATH",
+}
+
+// RuleDeprecatedCommands is a rule to check deprecated workflow commands in scripts at 'run:'.
+// Errors for the commands can be fixed by replacing them with writing to files. Since workflows
+// using the commands still work, this rule is disabled by default.
+// https://github.blog/changelog/2022-10-11-github-actions-deprecating-save-state-and-set-output-commands/
+// https://github.blog/changelog/2020-10-01-github-actions-deprecating-set-env-and-add-path-commands/
+type RuleDeprecatedCommands struct {
+	RuleBase
+	fixes []*Fix
+	shell shellResolver
+}
+
+// NewRuleDeprecatedCommands creates new RuleDeprecatedCommands instance.
+func NewRuleDeprecatedCommands() *RuleDeprecatedCommands {
+	return &RuleDeprecatedCommands{
+		RuleBase: RuleBase{name: "deprecated-commands"},
+	}
+}
+
+// Fixes returns fixes of errors found by the rule.
+func (rule *RuleDeprecatedCommands) Fixes() []*Fix {
+	return rule.fixes
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RuleDeprecatedCommands) VisitWorkflowPre(n *Workflow) error {
+	rule.shell.enterWorkflow(n)
+	return nil
+}
+
+// VisitWorkflowPost is callback when visiting Workflow node after visiting its children.
+func (rule *RuleDeprecatedCommands) VisitWorkflowPost(n *Workflow) error {
+	rule.shell.leaveWorkflow()
+	return nil
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleDeprecatedCommands) VisitJobPre(n *Job) error {
+	rule.shell.enterJob(n)
+	return nil
+}
+
+// VisitJobPost is callback when visiting Job node after visiting its children.
+func (rule *RuleDeprecatedCommands) VisitJobPost(n *Job) error {
+	rule.shell.leaveJob()
+	return nil
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RuleDeprecatedCommands) VisitStep(n *Step) error {
+	run, ok := n.Exec.(*ExecRun)
+	if !ok || run.Run == nil {
+		return nil
+	}
+
+	src := run.Run.Value
+	sh := rule.shell.name(run)
+	// Fix is available when each line of the script is at the same line in source. It is not
+	// available when the script is in quoted string since replacing the text may require escaping
+	// quotes. Lines in folded block scalar like `run: >` or multi-line plain scalar are joined.
+	// Columns in literal block scalar are unknown when its indentation is given by indicator.
+	fixable := (sh == "bash" || sh == "sh") && !run.Run.Quoted && (run.Run.Literal && run.Run.Indent > 0 || !strings.Contains(src, "\n"))
+
+	// In literal block scalar like `run: |`, the first line of the script is at the next line of
+	// the "run:" key and each line is indented.
+	lineBase, colBase := run.Run.Pos.Line, run.Run.Pos.Col
+	if run.Run.Literal {
+		lineBase++
+		colBase = run.Run.Indent + 1
+	}
+
+	for i, line := range strings.Split(src, "\n") {
+		m := reDeprecatedCommand.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		cmd := m[1]
+
+		err := errorfAt(
+			run.RunPos,
+			rule.name,
+			"workflow command %q at line %d in this script was deprecated. use `%s` instead. see https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions",
+			cmd,
+			i+1,
+			deprecatedCommandAlternative(cmd),
+		)
+		rule.errs = append(rule.errs, err)
+
+		if !fixable {
+			continue
+		}
+		trimmed := strings.TrimLeft(line, " \t")
+		old := strings.TrimRight(trimmed, " \t\r")
+		if fixed, ok := fixDeprecatedCommand(old); ok {
+			rule.fixes = append(rule.fixes, &Fix{
+				Error:  err,
+				Line:   lineBase + i,
+				Column: colBase + len(line) - len(trimmed),
+				Old:    old,
+				New:    fixed,
+			})
+		}
+	}
+
+	return nil
+}
+
+func deprecatedCommandAlternative(cmd string) string {
+	f := deprecatedCommandFiles[cmd]
+	if cmd == "add-path" {
+		return fmt.Sprintf(`echo "{path}" >> "$%s"`, f)
+	}
+	return fmt.Sprintf(`echo "{name}={value}" >> "$%s"`, f)
+}
+
+// fixDeprecatedCommand converts the deprecated workflow command in the line of shell script into
+// writing to file. Only simple echo commands are supported.
+func fixDeprecatedCommand(line string) (string, bool) {
+	var quote, cmd, name, value string
+	if m := reDeprecatedCommandWithName.FindStringSubmatch(line); m != nil {
+		quote, cmd, name, value = m[1], m[2], m[3], m[4]
+	} else if m := reDeprecatedCommandAddPath.FindStringSubmatch(line); m != nil {
+		quote, cmd, value = m[1], "add-path", m[2]
+	} else {
+		return "", false
+	}
+
+	if quote != "" {
+		if !strings.HasSuffix(value, quote) {
+			return "", false
+		}
+		value = strings.TrimSuffix(value, quote)
+		if strings.Contains(value, quote) {
+			return "", false // Multiple quoted strings like echo "::set-output name=foo::"'bar'
+		}
+	} else if strings.ContainsAny(value, " \t;&|<>`\"'#") {
+		return "", false // The value is not a simple word
+	}
+
+	// Values of workflow commands are encoded: '%' as %25, '\r' as %0D and '\n' as %0A. Values
+	// written to the files are not encoded. Multi-line values need delimiters to be written to the
+	// files so they are not fixed.
+	// https://github.com/actions/toolkit/blob/main/packages/core/src/command.ts
+	if u := strings.ToUpper(value); strings.Contains(u, "%0A") || strings.Contains(u, "%0D") {
+		return "", false
+	}
+	value = strings.ReplaceAll(value, "%25", "%")
+	if name != "" {
+		value = name + "=" + value
+	}
+
+	return fmt.Sprintf(`echo %s%s%s >> "$%s"`, quote, value, quote, deprecatedCommandFiles[cmd]), true
+}
@@ -0,0 +1,131 @@
+package actionlint
+
+import "testing"
+
+func TestRuleDeprecatedCommandsFixCommand(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  string
+	}{
+		{`echo "::set-output name=foo::bar"`, `echo "foo=bar" >> "$GITHUB_OUTPUT"`},
+		{`echo '::set-output name=foo::bar'`, `echo 'foo=bar' >> "$GITHUB_OUTPUT"`},
+		{`echo ::set-output name=foo::bar`, `echo foo=bar >> "$GITHUB_OUTPUT"`},
+		{`echo "::set-output name=foo::${{ matrix.os }}"`, `echo "foo=${{ matrix.os }}" >> "$GITHUB_OUTPUT"`},
+		{`echo "::save-state name=pid::$PID"`, `echo "pid=$PID" >> "$GITHUB_STATE"`},
+		{`echo "::set-env name=FOO::bar"`, `echo "FOO=bar" >> "$GITHUB_ENV"`},
+		{`echo "::add-path::/path/to/bin"`, `echo "/path/to/bin" >> "$GITHUB_PATH"`},
+		{`echo  "::set-output name=foo::"`, `echo "foo=" >> "$GITHUB_OUTPUT"`},
+		{`echo "::set-output name=rate::100%25"`, `echo "rate=100%" >> "$GITHUB_OUTPUT"`},
+		{`echo "::add-path::/path/%25/bin"`, `echo "/path/%/bin" >> "$GITHUB_PATH"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			have, ok := fixDeprecatedCommand(tc.input)
+			if !ok {
+				t.Fatal("could not fix")
+			}
+			if have != tc.want {
+				t.Fatalf("wanted %q but got %q", tc.want, have)
+			}
+		})
+	}
+}
+
+func TestRuleDeprecatedCommandsCannotFixCommand(t *testing.T) {
+	testCases := []string{
+		`echo "::set-output name=foo::bar" > out.txt`,
+		`echo "::set-output name=foo::\"bar\""`,
+		`echo "::set-output name=foo::"'bar'`,
+		`echo ::set-output name=foo::bar baz`,
+		`echo ::set-output name=foo::bar; echo ok`,
+		`printf "::set-output name=foo::bar\n"`,
+		`Write-Output "::set-output name=foo::bar"`,
+		`echo "::set-output name=foo::bar`,
+		`echo "::set-output name=foo::line1%0Aline2"`,
+		`echo "::set-output name=foo::line1%0d%0aline2"`,
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc, func(t *testing.T) {
+			if have, ok := fixDeprecatedCommand(tc); ok {
+				t.Fatalf("should not be fixed but got %q", have)
+			}
+		})
+	}
+}
+
+func TestRuleDeprecatedCommandsFixLineOfRunForms(t *testing.T) {
+	testCases := []struct {
+		what string
+		src  string
+		line int
+	}{
+		{
+			what: "plain scalar",
+			src:  "      - run: echo '::set-output name=foo::bar'\n",
+			line: 6,
+		},
+		{
+			what: "literal block scalar",
+			src:  "      - run: |\n          echo ok\n          echo '::set-output name=foo::bar'\n",
+			line: 8,
+		},
+		{
+			what: "literal block scalar with single line",
+			src:  "      - run: |-\n          echo '::set-output name=foo::bar'\n",
+			line: 7,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			src := "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n" + tc.src
+			w, errs := Parse([]byte(src))
+			if len(errs) > 0 {
+				t.Fatal(errs)
+			}
+			rule := NewRuleDeprecatedCommands()
+			v := NewVisitor()
+			v.AddPass(rule)
+			if err := v.Visit(w); err != nil {
+				t.Fatal(err)
+			}
+			fixes := rule.Fixes()
+			if len(fixes) != 1 {
+				t.Fatalf("wanted 1 fix but got %d: %v", len(fixes), fixes)
+			}
+			if fixes[0].Line != tc.line {
+				t.Fatalf("wanted line %d but got %d", tc.line, fixes[0].Line)
+			}
+		})
+	}
+}
+
+func TestRuleDeprecatedCommandsNoFixForFoldedScalar(t *testing.T) {
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: >
+          echo '::set-output name=foo::bar'
+          echo ok
+`
+	w, errs := Parse([]byte(src))
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	rule := NewRuleDeprecatedCommands()
+	v := NewVisitor()
+	v.AddPass(rule)
+	if err := v.Visit(w); err != nil {
+		t.Fatal(err)
+	}
+	if len(rule.Errs()) != 1 {
+		t.Fatalf("wanted 1 error but got %v", rule.Errs())
+	}
+	if fixes := rule.Fixes(); len(fixes) != 0 {
+		t.Fatalf("lines of folded scalar are joined so no fix should be suggested: %v", fixes)
+	}
+}
@@ -0,0 +1,79 @@
+package actionlint
+
+// RuleEnvShadowing is a rule checker to detect environment variables at step which shadow the same
+// variables defined at job or workflow with different values. Environment variables at narrower
+// scope override ones at wider scope. The shadowing is not an error but it is easy to lose track of
+// which value is actually used. Since this rule is informational, it is disabled by default.
+// https://docs.github.com/en/actions/learn-github-actions/environment-variables
+type RuleEnvShadowing struct {
+	RuleBase
+	workflowEnv *Env
+	jobEnv      *Env
+}
+
+// NewRuleEnvShadowing creates new RuleEnvShadowing instance.
+func NewRuleEnvShadowing() *RuleEnvShadowing {
+	return &RuleEnvShadowing{
+		RuleBase: RuleBase{name: "env-shadowing"},
+	}
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RuleEnvShadowing) VisitWorkflowPre(n *Workflow) error {
+	rule.workflowEnv = n.Env
+	return nil
+}
+
+// VisitWorkflowPost is callback when visiting Workflow node after visiting its children.
+func (rule *RuleEnvShadowing) VisitWorkflowPost(n *Workflow) error {
+	rule.workflowEnv = nil
+	return nil
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleEnvShadowing) VisitJobPre(n *Job) error {
+	rule.jobEnv = n.Env
+	return nil
+}
+
+// VisitJobPost is callback when visiting Job node after visiting its children.
+func (rule *RuleEnvShadowing) VisitJobPost(n *Job) error {
+	rule.jobEnv = nil
+	return nil
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RuleEnvShadowing) VisitStep(n *Step) error {
+	if n.Env == nil {
+		return nil
+	}
+
+	for name, v := range n.Env.Vars {
+		// Variable at job level is used instead of one at workflow level. So it is the shadowed
+		// definition when both are defined.
+		s, where := lookupEnvVar(rule.jobEnv, name), "job"
+		if s == nil {
+			s, where = lookupEnvVar(rule.workflowEnv, name), "workflow"
+		}
+		if s == nil || v.Value == nil || s.Value == nil || v.Value.Value == s.Value.Value {
+			continue
+		}
+		rule.errorf(
+			v.Name.Pos,
+			"environment variable %q at step shadows the same variable defined at %s with different value %q at %s. make sure the shadowing is intentional",
+			v.Name.Value,
+			where,
+			s.Value.Value,
+			s.Name.Pos,
+		)
+	}
+
+	return nil
+}
+
+func lookupEnvVar(env *Env, name string) *EnvVar {
+	if env == nil || env.Vars == nil {
+		return nil
+	}
+	return env.Vars[name]
+}
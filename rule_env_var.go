@@ -1,8 +1,17 @@
 package actionlint
 
-import "strings"
+import (
+	"regexp"
+	"strings"
+)
 
-// RuleEnvVar is a rule checker to check environment variables setup.
+// reShellVarName is a pattern of environment variable names which can be referenced as $NAME in
+// shell. Names are matched case-insensitively since keys in "env:" are lower-cased.
+var reShellVarName = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// RuleEnvVar is a rule checker to check environment variables setup. It also warns names which are
+// not valid identifiers in shell like "my-var" since they cannot be referenced as $NAME in "run:".
+// They are reported with warning severity.
 type RuleEnvVar struct {
 	RuleBase
 }
@@ -49,6 +58,14 @@ func (rule *RuleEnvVar) checkEnv(env *Env) {
 				"environment variable name %q is invalid. '&', '=' and spaces should not be contained",
 				v.Name.Value,
 			)
+		} else if !reShellVarName.MatchString(strings.ToLower(v.Name.Value)) {
+			// This is a warning since the variable may be referenced only via env context
+			rule.warnf(
+				v.Name.Pos,
+				"environment variable name %q is not a valid identifier so it cannot be referenced as $NAME in shell. use a name matching [A-Za-z_][A-Za-z0-9_]*. note that it can still be accessed via env context like ${{ env['%s'] }}",
+				v.Name.Value,
+				v.Name.Value,
+			)
 		}
 	}
 }
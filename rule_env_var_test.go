@@ -0,0 +1,38 @@
+package actionlint
+
+import (
+	"testing"
+)
+
+func TestRuleEnvVarSeverity(t *testing.T) {
+	testCases := []struct {
+		name     string
+		severity string
+	}{
+		{"FOO=BAR", SeverityError},
+		{"FOO BAR", SeverityError},
+		{"MY-VAR", SeverityWarning},
+		{"2FA", SeverityWarning},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewRuleEnvVar()
+			env := &Env{
+				Vars: map[string]*EnvVar{
+					tc.name: {Name: &String{Value: tc.name, Pos: &Pos{}}, Value: &String{Value: "foo", Pos: &Pos{}}},
+				},
+			}
+			if err := r.VisitWorkflowPre(&Workflow{Env: env}); err != nil {
+				t.Fatal(err)
+			}
+			errs := r.Errs()
+			if len(errs) != 1 {
+				t.Fatalf("wanted 1 error but got %v", errs)
+			}
+			if s := errs[0].Severity(); s != tc.severity {
+				t.Fatalf("wanted severity %q but got %q: %s", tc.severity, s, errs[0])
+			}
+		})
+	}
+}
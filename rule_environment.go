@@ -0,0 +1,58 @@
+package actionlint
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RuleEnvironment is a rule to check environment names at 'environment' section of jobs. Names are
+// validated with the list of environments in the repository configured in actionlint.yaml. A job
+// using an unknown environment runs on a newly created environment without protection rules and
+// secrets, which is usually not intended.
+// https://docs.github.com/en/actions/deployment/targeting-different-environments/using-environments-for-deployment
+type RuleEnvironment struct {
+	RuleBase
+	envs []string
+}
+
+// NewRuleEnvironment creates new RuleEnvironment instance. The envs parameter is a list of
+// environment names in the repository. When it is empty, environment names are not checked.
+func NewRuleEnvironment(envs []string) *RuleEnvironment {
+	return &RuleEnvironment{
+		RuleBase: RuleBase{name: "environment"},
+		envs:     envs,
+	}
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleEnvironment) VisitJobPre(n *Job) error {
+	if len(rule.envs) == 0 || n.Environment == nil || n.Environment.Name == nil {
+		return nil
+	}
+
+	name := n.Environment.Name
+	if strings.Contains(name.Value, "${{") {
+		return nil // Environment name is dynamically determined
+	}
+
+	// Environment names are case-insensitive
+	for _, e := range rule.envs {
+		if strings.EqualFold(e, name.Value) {
+			return nil
+		}
+	}
+
+	hint := ""
+	if ss := findSimilarStrings(name.Value, rule.envs); len(ss) > 0 {
+		hint = fmt.Sprintf(" did you mean %s?", sortedQuotes(ss))
+	}
+	rule.errorf(
+		name.Pos,
+		"environment %q is unknown in job %q.%s available environments are %s. if it is a new environment, add it to \"environments\" in actionlint.yaml config file",
+		name.Value,
+		n.ID.Value,
+		hint,
+		sortedQuotes(rule.envs),
+	)
+	return nil
+}
@@ -0,0 +1,76 @@
+package actionlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleEnvironmentCheckNames(t *testing.T) {
+	testCases := []struct {
+		what  string
+		name  string
+		known []string
+		errs  []string
+	}{
+		{
+			what:  "known environment",
+			name:  "production",
+			known: []string{"production", "staging"},
+		},
+		{
+			what:  "known environment in different case",
+			name:  "Production",
+			known: []string{"production", "staging"},
+		},
+		{
+			what:  "expression",
+			name:  "${{ inputs.env }}",
+			known: []string{"production", "staging"},
+		},
+		{
+			what: "no known environment",
+			name: "production",
+		},
+		{
+			what:  "unknown environment",
+			name:  "development",
+			known: []string{"production", "staging"},
+			errs: []string{
+				`environment "development" is unknown in job "test". available environments are "production", "staging"`,
+			},
+		},
+		{
+			what:  "typo in environment",
+			name:  "prodution",
+			known: []string{"production", "staging"},
+			errs: []string{
+				`environment "prodution" is unknown in job "test". did you mean "production"? available environments are "production", "staging"`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			r := NewRuleEnvironment(tc.known)
+			j := &Job{
+				ID: &String{Value: "test", Pos: &Pos{}},
+				Environment: &Environment{
+					Name: &String{Value: tc.name, Pos: &Pos{Line: 3, Col: 5}},
+				},
+			}
+			if err := r.VisitJobPre(j); err != nil {
+				t.Fatal(err)
+			}
+
+			errs := r.Errs()
+			if len(errs) != len(tc.errs) {
+				t.Fatalf("wanted %d errors but got %d: %v", len(tc.errs), len(errs), errs)
+			}
+			for i, want := range tc.errs {
+				if !strings.Contains(errs[i].Message, want) {
+					t.Errorf("wanted %q in error message but got %q", want, errs[i].Message)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package actionlint
+
+import (
+	"strings"
+)
+
+// RuleEventInputs is a rule to detect github.event.inputs which is the legacy way to access inputs
+// of "workflow_dispatch" event. The inputs context is preferred since it is available in both
+// "workflow_dispatch" and "workflow_call" events and preserves types of the inputs while values in
+// github.event.inputs are always strings. github.event.inputs is always empty in workflows triggered
+// only by "workflow_call" event. Since github.event.inputs still works with "workflow_dispatch"
+// event, this rule is disabled by default. Expressions are checked when the checkExpr method is set
+// to the expression hook of RuleExpression.
+// https://docs.github.com/en/actions/learn-github-actions/contexts#inputs-context
+type RuleEventInputs struct {
+	RuleBase
+	dispatch bool
+	call     bool
+}
+
+// NewRuleEventInputs creates new RuleEventInputs instance.
+func NewRuleEventInputs() *RuleEventInputs {
+	return &RuleEventInputs{
+		RuleBase: RuleBase{name: "event-inputs"},
+	}
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RuleEventInputs) VisitWorkflowPre(n *Workflow) error {
+	rule.dispatch, rule.call = false, false
+	for _, e := range n.On {
+		switch e.(type) {
+		case *WorkflowDispatchEvent:
+			rule.dispatch = true
+		case *WorkflowCallEvent:
+			rule.call = true
+		}
+	}
+	return nil
+}
+
+// checkExpr checks github.event.inputs referenced in the expression. The line and col parameters
+// are the position of the expression.
+func (rule *RuleEventInputs) checkExpr(expr ExprNode, line, col int) {
+	if !rule.dispatch && !rule.call {
+		return // Other events don't have inputs
+	}
+
+	VisitExprNode(expr, func(n, p ExprNode, entering bool) {
+		if !entering || !isGitHubEventInputs(n) {
+			return
+		}
+
+		want := "inputs"
+		if p != nil {
+			if r, prop, ok := propertyAccess(p); ok && r == n {
+				want += "." + prop
+			}
+		}
+		t := n.Token()
+		pos := convertExprLineColToPos(t.Line, t.Column, line, col)
+
+		if !rule.dispatch {
+			rule.errorf(
+				pos,
+				"\"github.event.inputs\" is always empty since this workflow is triggered only by \"workflow_call\" event. use %q instead",
+				want,
+			)
+			return
+		}
+		rule.errorf(
+			pos,
+			"\"github.event.inputs\" is the legacy way to access inputs of \"workflow_dispatch\" event. use %q instead. note that values in \"github.event.inputs\" are always strings while \"inputs\" preserves types of the inputs",
+			want,
+		)
+	})
+}
+
+// propertyAccess returns the receiver and the property name of the property access like foo.bar
+// or foo['bar'].
+func propertyAccess(n ExprNode) (ExprNode, string, bool) {
+	switch n := n.(type) {
+	case *ObjectDerefNode:
+		return n.Receiver, n.Property, true
+	case *IndexAccessNode:
+		if s, ok := n.Index.(*StringNode); ok {
+			return n.Operand, s.Value, true
+		}
+	}
+	return nil, "", false
+}
+
+// isGitHubEventInputs returns if the node accesses github.event.inputs.
+func isGitHubEventInputs(n ExprNode) bool {
+	r, prop, ok := propertyAccess(n)
+	if !ok || !strings.EqualFold(prop, "inputs") {
+		return false
+	}
+	r, prop, ok = propertyAccess(r)
+	if !ok || !strings.EqualFold(prop, "event") {
+		return false
+	}
+	v, ok := r.(*VariableNode)
+	return ok && strings.EqualFold(v.Name, "github")
+}
@@ -0,0 +1,32 @@
+package actionlint
+
+import "testing"
+
+func TestRuleEventInputsIsGitHubEventInputs(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  bool
+	}{
+		{"github.event.inputs", true},
+		{"GitHub.Event.Inputs", true},
+		{"github['event']['inputs']", true},
+		{"github.event['inputs']", true},
+		{"github.event", false},
+		{"github.inputs", false},
+		{"inputs", false},
+		{"foo.event.inputs", false},
+		{"github[matrix.key].inputs", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			n, err := NewExprParser().Parse(NewExprLexer(tc.input + "}}"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if have := isGitHubEventInputs(n); have != tc.want {
+				t.Fatalf("wanted %v but got %v", tc.want, have)
+			}
+		})
+	}
+}
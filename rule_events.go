@@ -1,6 +1,7 @@
 package actionlint
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 	"time"
@@ -14,6 +15,7 @@ import (
 // https://docs.github.com/en/actions/learn-github-actions/events-that-trigger-workflows
 type RuleEvents struct {
 	RuleBase
+	suspiciousHours []int
 }
 
 // NewRuleEvents creates new RuleEvents instance.
@@ -23,6 +25,13 @@ func NewRuleEvents() *RuleEvents {
 	}
 }
 
+// SetSuspiciousHours sets hours (0-23) in UTC which are suspicious for cron schedules. Cron
+// schedules running at the hours are reported since they are likely written in local time. When it
+// is empty, schedules are not checked for the hours.
+func (rule *RuleEvents) SetSuspiciousHours(hours []int) {
+	rule.suspiciousHours = hours
+}
+
 // VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
 func (rule *RuleEvents) VisitWorkflowPre(n *Workflow) error {
 	for _, e := range n.On {
@@ -52,6 +61,16 @@ func (rule *RuleEvents) checkEvent(event Event) {
 
 // https://docs.github.com/en/actions/learn-github-actions/workflow-syntax-for-github-actions#onschedule
 func (rule *RuleEvents) checkCron(spec *String) {
+	if tz, offset, ok := findTimezoneInCron(spec.Value); ok {
+		rule.errorf(
+			cronFieldPos(spec, offset),
+			"timezone %q cannot be specified in CRON format %q of schedule event. schedule is always evaluated in UTC",
+			tz,
+			spec.Value,
+		)
+		return
+	}
+
 	p := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
 	sched, err := p.Parse(spec.Value)
 	if err != nil {
@@ -59,6 +78,10 @@ func (rule *RuleEvents) checkCron(spec *String) {
 		return
 	}
 
+	if s, ok := sched.(*cron.SpecSchedule); ok {
+		rule.checkCronHours(spec, s)
+	}
+
 	start := sched.Next(time.Unix(0, 0))
 	next := sched.Next(start)
 	diff := next.Sub(start).Seconds()
@@ -71,6 +94,76 @@ func (rule *RuleEvents) checkCron(spec *String) {
 	}
 }
 
+// checkCronHours reports the schedule when it runs at some suspicious hour. Schedules are evaluated
+// in UTC but they are often written in local time by mistake.
+func (rule *RuleEvents) checkCronHours(spec *String, sched *cron.SpecSchedule) {
+	// robfig/cron sets the most significant bit when the field is *
+	if len(rule.suspiciousHours) == 0 || sched.Hour&(1<<63) != 0 {
+		return // Do nothing when hour is * (every hour)
+	}
+
+	for _, h := range rule.suspiciousHours {
+		if h < 0 || 23 < h || sched.Hour&(1<<uint(h)) == 0 {
+			continue
+		}
+		_, offset, _ := cronField(spec.Value, 1)
+		rule.errorf(
+			cronFieldPos(spec, offset),
+			"schedule %q runs at %d:00 in UTC, which is configured as suspicious hour. note that schedule is evaluated in UTC, not in local time",
+			spec.Value,
+			h,
+		)
+		return
+	}
+}
+
+// cronField returns n-th (0-based) field of the CRON format and its offset in the string.
+func cronField(spec string, n int) (string, int, bool) {
+	i := 0
+	for {
+		for i < len(spec) && (spec[i] == ' ' || spec[i] == '\t') {
+			i++
+		}
+		if i >= len(spec) {
+			return "", 0, false
+		}
+		start := i
+		for i < len(spec) && spec[i] != ' ' && spec[i] != '\t' {
+			i++
+		}
+		if n == 0 {
+			return spec[start:i], start, true
+		}
+		n--
+	}
+}
+
+// findTimezoneInCron finds timezone in the CRON format. Some CRON implementations accept timezone
+// like "CRON_TZ=Asia/Tokyo 0 9 * * *" or "0 9 * * * UTC", but GitHub Actions does not.
+func findTimezoneInCron(spec string) (string, int, bool) {
+	for n := 0; ; n++ {
+		f, offset, ok := cronField(spec, n)
+		if !ok {
+			return "", 0, false
+		}
+		if strings.HasPrefix(f, "TZ=") || strings.HasPrefix(f, "CRON_TZ=") {
+			return f[strings.IndexByte(f, '=')+1:], offset, true
+		}
+		// Fields after the 5th field are not allowed. When it contains an alphabet, it is likely a timezone
+		if n >= 5 && strings.IndexFunc(f, func(r rune) bool { return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' }) >= 0 {
+			return f, offset, true
+		}
+	}
+}
+
+func cronFieldPos(spec *String, offset int) *Pos {
+	col := spec.Pos.Col + offset
+	if spec.Quoted {
+		col++
+	}
+	return &Pos{Line: spec.Pos.Line, Col: col}
+}
+
 // https://docs.github.com/en/actions/learn-github-actions/events-that-trigger-workflows#webhook-events
 func (rule *RuleEvents) checkWebhookEvent(event *WebhookEvent) {
 	hook := event.Hook.Value
@@ -82,6 +175,9 @@ func (rule *RuleEvents) checkWebhookEvent(event *WebhookEvent) {
 	}
 
 	rule.checkTypes(event.Hook, event.Types, types)
+	rule.checkIgnoreFilter(event, "branches", event.Branches, event.BranchesIgnore)
+	rule.checkIgnoreFilter(event, "tags", event.Tags, event.TagsIgnore)
+	rule.checkIgnoreFilter(event, "paths", event.Paths, event.PathsIgnore)
 
 	if hook == "workflow_run" {
 		if len(event.Workflows) == 0 {
@@ -94,6 +190,28 @@ func (rule *RuleEvents) checkWebhookEvent(event *WebhookEvent) {
 	}
 }
 
+// checkIgnoreFilter reports the filter and its '-ignore' variant specified for the same event. GitHub
+// rejects such workflow. Negative patterns prefixed with '!' should be used instead.
+// https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#onpushpull_requestpull_request_targetpathspaths-ignore
+func (rule *RuleEvents) checkIgnoreFilter(event *WebhookEvent, name string, filter, ignore []*String) {
+	if filter == nil || ignore == nil {
+		return
+	}
+	pos := event.Pos
+	if len(ignore) > 0 {
+		pos = ignore[0].Pos
+	}
+	rule.errorf(
+		pos,
+		"both %q and \"%s-ignore\" filters are specified for %q event. they cannot be used together for the same event. use patterns prefixed with \"!\" in %q filter to exclude some %s instead",
+		name,
+		name,
+		event.Hook.Value,
+		name,
+		name,
+	)
+}
+
 func (rule *RuleEvents) checkTypes(hook *String, types []*String, expected []string) {
 	if len(expected) == 0 && len(types) > 0 {
 		rule.errorf(hook.Pos, "\"types\" cannot be specified for %q Webhook event", hook.Value)
@@ -109,11 +227,18 @@ func (rule *RuleEvents) checkTypes(hook *String, types []*String, expected []str
 			}
 		}
 		if !valid {
+			// Typo in activity type makes the filter never match silently. Suggest similar types
+			// to make it easier to find out the correct one.
+			hint := ""
+			if ss := findSimilarStrings(ty.Value, expected); len(ss) > 0 {
+				hint = fmt.Sprintf(" did you mean %s?", sortedQuotes(ss))
+			}
 			rule.errorf(
 				ty.Pos,
-				"invalid activity type %q for %q Webhook event. available types are %s",
+				"invalid activity type %q for %q Webhook event.%s available types are %s",
 				ty.Value,
 				hook.Value,
+				hint,
 				sortedQuotes(expected),
 			)
 		}
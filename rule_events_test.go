@@ -0,0 +1,93 @@
+package actionlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleEventsSuspiciousHoursInCron(t *testing.T) {
+	testCases := []struct {
+		what  string
+		cron  string
+		hours []int
+		col   int
+		msg   string
+	}{
+		{
+			what:  "hour matches",
+			cron:  "0 9 * * 1-5",
+			hours: []int{9, 10, 11},
+			col:   3,
+			msg:   "runs at 9:00 in UTC",
+		},
+		{
+			what:  "one of hours matches",
+			cron:  "30 2,12 * * *",
+			hours: []int{9, 10, 11, 12},
+			col:   4,
+			msg:   "runs at 12:00 in UTC",
+		},
+		{
+			what:  "range matches",
+			cron:  "0  8-10 * * *",
+			hours: []int{10},
+			col:   4,
+			msg:   "runs at 10:00 in UTC",
+		},
+		{
+			what:  "hour does not match",
+			cron:  "0 3 * * *",
+			hours: []int{9, 10, 11},
+		},
+		{
+			what:  "every hour",
+			cron:  "0 * * * *",
+			hours: []int{9, 10, 11},
+		},
+		{
+			what:  "not configured",
+			cron:  "0 9 * * *",
+			hours: nil,
+		},
+		{
+			what:  "hour out of range",
+			cron:  "0 9 * * *",
+			hours: []int{-1, 24},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			spec := &String{Value: tc.cron, Pos: &Pos{Line: 1, Col: 1}}
+			w := &Workflow{
+				On: []Event{
+					&ScheduledEvent{Cron: []*String{spec}},
+				},
+			}
+
+			rule := NewRuleEvents()
+			rule.SetSuspiciousHours(tc.hours)
+			if err := rule.VisitWorkflowPre(w); err != nil {
+				t.Fatal(err)
+			}
+
+			errs := rule.Errs()
+			if tc.msg == "" {
+				if len(errs) > 0 {
+					t.Fatalf("no error was expected but got %v", errs)
+				}
+				return
+			}
+
+			if len(errs) != 1 {
+				t.Fatalf("one error was expected but got %v", errs)
+			}
+			if !strings.Contains(errs[0].Message, tc.msg) {
+				t.Fatalf("%q is not contained in error message %q", tc.msg, errs[0].Message)
+			}
+			if errs[0].Column != tc.col {
+				t.Fatalf("wanted column %d but got %d", tc.col, errs[0].Column)
+			}
+		})
+	}
+}
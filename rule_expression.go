@@ -24,11 +24,26 @@ type RuleExpression struct {
 	secretsTy        *ObjectType
 	inputsTy         *ObjectType
 	dispatchInputsTy *ObjectType
+	noInputs         bool
+	eventTy          *ObjectType
+	eventName        string
 	jobsTy           *ObjectType
 	workflow         *Workflow
 	localActions     *LocalActionsCache
+	typeHook         ExprTypeHook
+	actionsMeta      map[string]*ActionMetadata
+	actionOutputsTys map[string]ExprType
+	availableCtxs    []string
+	job              *Job
+	shell            shellResolver
+	exprHook         func(expr ExprNode, line, col int) // Called with each expression before checking it
 }
 
+// ExprTypeHook is a callback called when a type of expression is inferred by RuleExpression. The
+// pos parameter is a position of the expression. The src parameter is a source of the expression
+// without ${{ }}.
+type ExprTypeHook func(pos *Pos, src string, ty ExprType)
+
 // NewRuleExpression creates new RuleExpression instance.
 func NewRuleExpression(cache *LocalActionsCache) *RuleExpression {
 	return &RuleExpression{
@@ -39,15 +54,40 @@ func NewRuleExpression(cache *LocalActionsCache) *RuleExpression {
 		secretsTy:        nil,
 		inputsTy:         nil,
 		dispatchInputsTy: nil,
+		eventTy:          nil,
+		eventName:        "",
 		jobsTy:           nil,
 		workflow:         nil,
 		localActions:     cache,
 	}
 }
 
+// SetExprTypeHook sets a callback called each time when a type of expression is inferred. This is
+// useful for debugging type checks.
+func (rule *RuleExpression) SetExprTypeHook(h ExprTypeHook) {
+	rule.typeHook = h
+}
+
+// SetActionsMetadata sets metadata of additional actions which are not included in the popular
+// actions dataset. Keys of the map are action specs like "owner/repo@ref" or "owner/repo". The
+// metadata is used for typing outputs of the actions.
+func (rule *RuleExpression) SetActionsMetadata(m map[string]*ActionMetadata) {
+	rule.actionsMeta = m
+}
+
+// SetActionOutputsTypes sets types of outputs of actions. Keys of the map are action specs like
+// "owner/repo@ref" or "owner/repo", or local action paths like "./path/to/action". Values are types
+// of `steps.<step_id>.outputs` of steps which run the actions. The types are prioritized over the
+// types deduced from actions metadata.
+func (rule *RuleExpression) SetActionOutputsTypes(m map[string]ExprType) {
+	rule.actionOutputsTys = m
+}
+
 // VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
 func (rule *RuleExpression) VisitWorkflowPre(n *Workflow) error {
-	rule.checkString(n.Name)
+	rule.checkName(n.Name, "workflow")
+	rule.eventName, rule.eventTy = eventPayloadType(n.On)
+	rule.shell.enterWorkflow(n)
 
 	for _, e := range n.On {
 		switch e := e.(type) {
@@ -82,6 +122,7 @@ func (rule *RuleExpression) VisitWorkflowPre(n *Workflow) error {
 				ity.Props[i.Name.Value] = ty
 			}
 			rule.dispatchInputsTy = ity
+			rule.mergeInputsTy(ity)
 		case *RepositoryDispatchEvent:
 			rule.checkStrings(e.Types)
 		case *WorkflowCallEvent:
@@ -103,7 +144,7 @@ func (rule *RuleExpression) VisitWorkflowPre(n *Workflow) error {
 				rule.checkString(i.Description)
 				rule.checkString(i.Default)
 			}
-			rule.inputsTy = ity
+			rule.mergeInputsTy(ity)
 
 			sty := NewEmptyStrictObjectType()
 			for n, s := range e.Secrets {
@@ -118,6 +159,9 @@ func (rule *RuleExpression) VisitWorkflowPre(n *Workflow) error {
 		}
 	}
 
+	// 'inputs' context is always empty when the workflow is not triggered by events with inputs
+	rule.noInputs = len(n.On) > 0 && rule.inputsTy == nil
+
 	rule.checkEnv(n.Env)
 
 	rule.checkDefaults(n.Defaults)
@@ -127,17 +171,42 @@ func (rule *RuleExpression) VisitWorkflowPre(n *Workflow) error {
 	return nil
 }
 
+// mergeInputsTy merges the type of inputs of "workflow_call" or "workflow_dispatch" event into the
+// type of 'inputs' context. When the workflow is triggered by both events, inputs of both events
+// are available.
+func (rule *RuleExpression) mergeInputsTy(ty *ObjectType) {
+	if rule.inputsTy == nil {
+		rule.inputsTy = ty
+		return
+	}
+	props := make(map[string]ExprType, len(rule.inputsTy.Props)+len(ty.Props))
+	for n, t := range rule.inputsTy.Props {
+		props[n] = t
+	}
+	for n, t := range ty.Props {
+		if p, ok := props[n]; ok {
+			t = p.Merge(t)
+		}
+		props[n] = t
+	}
+	rule.inputsTy = NewStrictObjectType(props)
+}
+
 // VisitWorkflowPost is callback when visiting Workflow node after visiting its children
 func (rule *RuleExpression) VisitWorkflowPost(n *Workflow) error {
 	if e, ok := n.FindWorkflowCallEvent(); ok {
 		rule.checkWorkflowCallOutputs(e.Outputs, n.Jobs)
 	}
 	rule.workflow = nil
+	rule.shell.leaveWorkflow()
 	return nil
 }
 
 // VisitJobPre is callback when visiting Job node before visiting its children.
 func (rule *RuleExpression) VisitJobPre(n *Job) error {
+	rule.job = n
+	rule.shell.enterJob(n)
+
 	// Type of needs must be resolved before resolving type of matrix because `needs` context can
 	// be used in matrix configuration.
 	rule.needsTy = rule.calcNeedsType(n)
@@ -154,13 +223,11 @@ func (rule *RuleExpression) VisitJobPre(n *Job) error {
 		rule.matrixTy = rule.guessTypeOfMatrix(n.Strategy.Matrix)
 	}
 
-	rule.checkString(n.Name)
+	rule.checkName(n.Name, "job")
 	rule.checkStrings(n.Needs)
 
 	if n.RunsOn != nil {
-		for _, l := range n.RunsOn.Labels {
-			rule.checkString(l)
-		}
+		rule.checkRunsOn(n.RunsOn)
 	}
 
 	rule.checkConcurrency(n.Concurrency)
@@ -168,7 +235,7 @@ func (rule *RuleExpression) VisitJobPre(n *Job) error {
 	rule.checkEnv(n.Env)
 
 	rule.checkDefaults(n.Defaults)
-	rule.checkIfCondition(n.If)
+	rule.checkIfCondition(n.If, jobIfContexts)
 
 	if n.Strategy != nil {
 		if n.Strategy.Matrix != nil {
@@ -177,7 +244,7 @@ func (rule *RuleExpression) VisitJobPre(n *Job) error {
 					rule.checkRawYAMLValue(v)
 				}
 			}
-			rule.checkMatrixCombinations(n.Strategy.Matrix.Include, "include")
+			// Note: 'include' section was checked by guessTypeOfMatrix()
 			rule.checkMatrixCombinations(n.Strategy.Matrix.Exclude, "exclude")
 		}
 		rule.checkBool(n.Strategy.FailFast)
@@ -185,7 +252,7 @@ func (rule *RuleExpression) VisitJobPre(n *Job) error {
 	}
 
 	rule.checkBool(n.ContinueOnError)
-	rule.checkFloat(n.TimeoutMinutes)
+	rule.checkFloat(n.TimeoutMinutes, "timeout-minutes")
 	rule.checkContainer(n.Container)
 
 	for _, s := range n.Services {
@@ -213,25 +280,28 @@ func (rule *RuleExpression) VisitJobPost(n *Job) error {
 	rule.matrixTy = nil
 	rule.stepsTy = nil
 	rule.needsTy = nil
+	rule.job = nil
+	rule.shell.leaveJob()
 
 	return nil
 }
 
 // VisitStep is callback when visiting Step node.
 func (rule *RuleExpression) VisitStep(n *Step) error {
-	rule.checkIfCondition(n.If)
+	rule.checkIfCondition(n.If, stepIfContexts)
 	rule.checkString(n.Name)
 
 	var spec *String
 	switch e := n.Exec.(type) {
 	case *ExecRun:
-		rule.checkScriptString(e.Run)
+		rule.checkRunScript(e)
 		rule.checkString(e.Shell)
-		rule.checkString(e.WorkingDirectory)
+		rule.checkStringTypedExprs(e.WorkingDirectory, "\"working-directory\"")
 	case *ExecAction:
 		rule.checkString(e.Uses)
 		for n, i := range e.Inputs {
-			if e.Uses != nil && strings.HasPrefix(e.Uses.Value, "actions/github-script@") && n == "script" {
+			// Owner and repository names of actions are case-insensitive
+			if e.Uses != nil && strings.HasPrefix(strings.ToLower(e.Uses.Value), "actions/github-script@") && n == "script" {
 				rule.checkScriptString(i.Value)
 			} else {
 				rule.checkString(i.Value)
@@ -244,27 +314,44 @@ func (rule *RuleExpression) VisitStep(n *Step) error {
 
 	rule.checkEnv(n.Env)
 	rule.checkBool(n.ContinueOnError)
-	rule.checkFloat(n.TimeoutMinutes)
+	rule.checkFloat(n.TimeoutMinutes, "timeout-minutes")
 
 	if n.ID != nil {
 		// Step ID is case insensitive
 		id := strings.ToLower(n.ID.Value)
 		rule.stepsTy.Props[id] = NewStrictObjectType(map[string]ExprType{
 			"outputs":    rule.getActionOutputsType(spec),
-			"conclusion": StringType{},
-			"outcome":    StringType{},
+			"conclusion": newJobResultType(),
+			"outcome":    newJobResultType(),
 		})
 	}
 
 	return nil
 }
 
+// newJobResultType creates a type of results of steps and jobs. It is used for steps.<step_id>.conclusion,
+// steps.<step_id>.outcome and needs.<job_id>.result.
+// https://docs.github.com/en/actions/learn-github-actions/contexts#steps-context
+// https://docs.github.com/en/actions/learn-github-actions/contexts#needs-context
+func newJobResultType() StringType {
+	return NewStringEnumType("success", "failure", "cancelled", "skipped")
+}
+
 // Get type of `outputs.<output name>`
-func (rule *RuleExpression) getActionOutputsType(spec *String) *ObjectType {
+func (rule *RuleExpression) getActionOutputsType(spec *String) ExprType {
 	if spec == nil {
 		return NewMapObjectType(StringType{})
 	}
 
+	if ty, ok := rule.actionOutputsTys[spec.Value]; ok {
+		return ty
+	}
+	if i := strings.IndexRune(spec.Value, '@'); i >= 0 {
+		if ty, ok := rule.actionOutputsTys[spec.Value[:i]]; ok {
+			return ty
+		}
+	}
+
 	if strings.HasPrefix(spec.Value, "./") {
 		meta, err := rule.localActions.FindMetadata(spec.Value)
 		if err != nil {
@@ -280,13 +367,13 @@ func (rule *RuleExpression) getActionOutputsType(spec *String) *ObjectType {
 
 	// github-script action allows to set any outputs through calling `core.setOutput` directly.
 	// So any `outputs.*` properties should be accepted (#104)
-	if strings.HasPrefix(spec.Value, "actions/github-script@") {
+	if strings.HasPrefix(strings.ToLower(spec.Value), "actions/github-script@") {
 		return NewEmptyObjectType()
 	}
 
 	// When the action run at this step is a popular action, we know what outputs are set by it.
 	// Set the output names to `steps.{step_id}.outputs.{name}`.
-	if meta, ok := PopularActions[spec.Value]; ok {
+	if meta, ok := findRepoActionMetadata(spec.Value, rule.actionsMeta); ok {
 		return typeOfActionOutputs(meta)
 	}
 
@@ -438,7 +525,7 @@ func (rule *RuleExpression) checkDefaults(d *Defaults) {
 		return
 	}
 	rule.checkString(d.Run.Shell)
-	rule.checkString(d.Run.WorkingDirectory)
+	rule.checkStringTypedExprs(d.Run.WorkingDirectory, "\"working-directory\"")
 }
 
 func (rule *RuleExpression) checkWorkflowCall(c *WorkflowCall) {
@@ -454,17 +541,65 @@ func (rule *RuleExpression) checkWorkflowCall(c *WorkflowCall) {
 	}
 }
 
+// checkRunsOn checks expressions at "runs-on". When only one label is given and it consists of one
+// ${{ }} expression like ${{ matrix.os }}, the value of the expression is used as a label or a list
+// of labels. So the type of the expression must be string or array of strings.
+func (rule *RuleExpression) checkRunsOn(r *Runner) {
+	rule.checkString(r.Group)
+
+	if len(r.Labels) != 1 || !isExprAssigned(r.Labels[0].Value) {
+		for _, l := range r.Labels {
+			rule.checkString(l)
+		}
+		return
+	}
+
+	l := r.Labels[0]
+	ty := rule.checkOneExpression(l, "runs-on")
+	switch ty := ty.(type) {
+	case nil, StringType, AnyType:
+		// ok
+	case *ArrayType:
+		switch ty.Elem.(type) {
+		case StringType, AnyType:
+			// ok
+		default:
+			rule.errorf(l.Pos, "type of expression at \"runs-on\" must be string or array<string> but found type %s", ty.String())
+		}
+	default:
+		rule.errorf(l.Pos, "type of expression at \"runs-on\" must be string or array<string> but found type %s", ty.String())
+	}
+}
+
+// isExprAssigned returns true when the whole string is one ${{ }} expression like "${{ matrix.os }}".
+func isExprAssigned(s string) bool {
+	s = strings.TrimSpace(s)
+	return strings.HasPrefix(s, "${{") && strings.HasSuffix(s, "}}") && strings.Count(s, "${{") == 1 && strings.Count(s, "}}") == 1
+}
+
 func (rule *RuleExpression) checkStrings(ss []*String) {
 	for _, s := range ss {
 		rule.checkString(s)
 	}
 }
 
-func (rule *RuleExpression) checkIfCondition(str *String) {
+// Contexts available in "if" conditions of jobs and steps.
+// https://docs.github.com/en/actions/learn-github-actions/contexts#context-availability
+var (
+	jobIfContexts  = []string{"github", "inputs", "needs"}
+	stepIfContexts = []string{"env", "github", "inputs", "job", "matrix", "needs", "runner", "steps", "strategy"}
+	// Contexts available in 'on.workflow_call.outputs.<output_id>.value'
+	workflowCallOutputContexts = []string{"github", "inputs", "jobs"}
+)
+
+func (rule *RuleExpression) checkIfCondition(str *String, avail []string) {
 	if str == nil {
 		return
 	}
 
+	rule.availableCtxs = avail
+	defer func() { rule.availableCtxs = nil }()
+
 	// Note:
 	// https://docs.github.com/en/actions/learn-github-actions/workflow-syntax-for-github-actions#jobsjob_idif
 	//
@@ -506,12 +641,67 @@ func (rule *RuleExpression) checkIfCondition(str *String) {
 			return
 		}
 
+		if lit, ok := expr.(*StringNode); ok && lit.Value != "" {
+			rule.errorf(
+				str.Pos,
+				"\"if\" condition %q is a string literal which is always evaluated to true. note that the condition without ${{ }} is evaluated as expression. remove quotes if it is intended to be an expression",
+				str.Value,
+			)
+			return
+		}
+
 		condTy = rule.checkSemanticsOfExprNode(expr, line, col, false)
+		if rule.typeHook != nil && condTy != nil {
+			rule.typeHook(str.Pos, str.Value, condTy)
+		}
 	}
 
 	if condTy != nil && !(BoolType{}).Assignable(condTy) {
 		rule.errorf(str.Pos, "\"if\" condition should be type \"bool\" but got type %q", condTy.String())
 	}
+
+	rule.checkConstantFuncCalls(str)
+}
+
+// checkConstantFuncCalls checks calls of string built-in functions in "if" condition whose all
+// arguments are constants such as contains('abc', 'a'). Their results are always the same so the
+// condition may be trivial.
+func (rule *RuleExpression) checkConstantFuncCalls(str *String) {
+	visitExprsInString(str, true, func(expr ExprNode, line, col int) {
+		VisitExprNode(expr, func(n, parent ExprNode, entering bool) {
+			if !entering {
+				return
+			}
+			f, ok := n.(*FuncCallNode)
+			if !ok {
+				return
+			}
+			v, ok := foldConstantFuncCall(f)
+			if !ok {
+				return
+			}
+			if p, ok := parent.(*FuncCallNode); ok {
+				if _, ok := foldConstantFuncCall(p); ok {
+					return // Already reported at the outer function call
+				}
+			}
+
+			var desc string
+			switch v := v.(type) {
+			case *BoolNode:
+				desc = strconv.FormatBool(v.Value)
+			case *StringNode:
+				desc = fmt.Sprintf("string %q", v.Value)
+			}
+			t := f.Token()
+			rule.errorf(
+				convertExprLineColToPos(t.Line, t.Column, line, col),
+				"%s() call in \"if\" condition is always evaluated to %s since all its arguments are constants. the condition may be trivial",
+				f.Callee,
+				desc,
+			)
+		})
+	})
 }
 
 func (rule *RuleExpression) checkTemplateEvaluatedType(ts []typedExpr) {
@@ -532,6 +722,25 @@ func (rule *RuleExpression) checkString(str *String) []typedExpr {
 	return ts
 }
 
+// checkName checks expressions in "name:" section of workflow or job. Since the name is shown as a
+// title in the Actions UI, all expressions in the name must be evaluated to strings.
+func (rule *RuleExpression) checkName(str *String, what string) {
+	rule.checkStringTypedExprs(str, "\"name\" of "+what)
+}
+
+// checkStringTypedExprs checks all expressions in the string are evaluated to strings. The where
+// parameter describes the section of the string like `"working-directory"`.
+func (rule *RuleExpression) checkStringTypedExprs(str *String, where string) {
+	if str == nil {
+		return
+	}
+	for _, t := range rule.checkExprsIn(str.Value, str.Pos, str.Quoted, false) {
+		if !(StringType{}).Assignable(t.ty) {
+			rule.errorf(&t.pos, "type of expression at %s must be string but found type %s", where, t.ty.String())
+		}
+	}
+}
+
 func (rule *RuleExpression) checkScriptString(str *String) []typedExpr {
 	if str == nil {
 		return nil
@@ -541,6 +750,79 @@ func (rule *RuleExpression) checkScriptString(str *String) []typedExpr {
 	return ts
 }
 
+// checkRunScript checks expressions in the script at "run:". When the script contains heredocs,
+// untrusted inputs put in the heredocs are reported with explanations of the risks. Unquoted heredoc
+// like <<EOF expands $(...) in the input as command substitution. Even if the delimiter is quoted
+// like <<'EOF', the input can end the heredoc with the delimiter line and inject commands after it.
+func (rule *RuleExpression) checkRunScript(run *ExecRun) {
+	str := run.Run
+	if str == nil {
+		return
+	}
+
+	// Heredoc is syntax of sh and bash. Other shells like pwsh are not supported
+	heredocs := []*heredoc{}
+	if sh := rule.shell.name(run); sh == "bash" || sh == "sh" {
+		heredocs = findHeredocs(strings.Split(str.Value, "\n"))
+	}
+	if len(heredocs) == 0 {
+		rule.checkScriptString(str)
+		return
+	}
+
+	// Check untrusted inputs separately to explain the risks in heredocs
+	ts := rule.checkExprsIn(str.Value, str.Pos, str.Quoted, false)
+	rule.checkTemplateEvaluatedType(ts)
+
+	col := str.Pos.Col
+	if str.Quoted {
+		col++
+	}
+	u := NewUntrustedInputChecker(BuiltinUntrustedInputs)
+	offset := 0
+	for {
+		idx := strings.Index(str.Value[offset:], "${{")
+		if idx == -1 {
+			return
+		}
+		start := offset + idx + 3 // 3 means removing "${{"
+		l := NewExprLexer(str.Value[start:])
+		expr, err := NewExprParser().Parse(l)
+		if err != nil {
+			return // Parse error was already reported
+		}
+		offset = start + l.Offset()
+
+		u.Init()
+		u.setHint("")
+		line := strings.Count(str.Value[:start], "\n")
+		errLine, errCol := str.Pos.Line, col+start
+		// Offset of the start of the expression content in the line
+		o := start - strings.LastIndexByte(str.Value[:start], '\n') - 1
+		if str.Literal && str.Indent > 0 {
+			// The position of the expression in the literal block scalar is known
+			errLine, errCol = str.Pos.Line+1+line, str.Indent+o+1
+		}
+		if h := heredocAt(heredocs, line); h != nil {
+			c := o - 2
+			if h.quoted {
+				u.setHint(fmt.Sprintf(" it is put in heredoc %q at line %d, col %d in this script. though the delimiter is quoted and the input is not expanded by shell, the input can end the heredoc with a line %q and inject commands after it.", h.delim, line+1, c, h.delim))
+			} else {
+				u.setHint(fmt.Sprintf(" it is put in heredoc %q at line %d, col %d in this script. since the delimiter is not quoted, $(...) and backquotes in the input are executed as commands by shell.", h.delim, line+1, c))
+			}
+		}
+		VisitExprNode(expr, func(n, _ ExprNode, entering bool) {
+			if !entering {
+				u.OnVisitNodeLeave(n)
+			}
+		})
+		u.OnVisitEnd()
+		for _, err := range u.Errs() {
+			rule.exprError(err, errLine, errCol)
+		}
+	}
+}
+
 func (rule *RuleExpression) checkBool(b *Bool) {
 	if b == nil || b.Expression == nil {
 		return
@@ -561,11 +843,11 @@ func (rule *RuleExpression) checkInt(i *Int) {
 	rule.checkNumberExpression(i.Expression, "integer value")
 }
 
-func (rule *RuleExpression) checkFloat(f *Float) {
+func (rule *RuleExpression) checkFloat(f *Float, what string) {
 	if f == nil {
 		return
 	}
-	rule.checkNumberExpression(f.Expression, "float number value")
+	rule.checkNumberExpression(f.Expression, what)
 }
 
 func (rule *RuleExpression) checkExprsIn(s string, pos *Pos, quoted bool, checkUntrusted bool) []typedExpr {
@@ -624,6 +906,10 @@ func (rule *RuleExpression) exprError(err *ExprError, lineBase, colBase int) {
 }
 
 func (rule *RuleExpression) checkSemanticsOfExprNode(expr ExprNode, line, col int, checkUntrusted bool) ExprType {
+	if rule.exprHook != nil {
+		rule.exprHook(expr, line, col)
+	}
+
 	c := NewExprSemanticsChecker(checkUntrusted)
 	if rule.matrixTy != nil {
 		c.UpdateMatrix(rule.matrixTy)
@@ -632,7 +918,18 @@ func (rule *RuleExpression) checkSemanticsOfExprNode(expr ExprNode, line, col in
 		c.UpdateSteps(rule.stepsTy)
 	}
 	if rule.needsTy != nil {
-		c.UpdateNeeds(rule.needsTy)
+		ty := rule.needsTy
+		if extra := rule.checkUndeclaredNeeds(expr, line, col); len(extra) > 0 {
+			props := make(map[string]ExprType, len(ty.Props)+len(extra))
+			for n, t := range ty.Props {
+				props[n] = t
+			}
+			for n, t := range extra {
+				props[n] = t
+			}
+			ty = NewStrictObjectType(props)
+		}
+		c.UpdateNeeds(ty)
 	}
 	if rule.secretsTy != nil {
 		c.UpdateSecrets(rule.secretsTy)
@@ -640,12 +937,21 @@ func (rule *RuleExpression) checkSemanticsOfExprNode(expr ExprNode, line, col in
 	if rule.inputsTy != nil {
 		c.UpdateInputs(rule.inputsTy)
 	}
+	if rule.noInputs {
+		c.SetUnavailableContext("inputs", "the workflow is not triggered by \"workflow_call\" nor \"workflow_dispatch\" event. the context is always empty. add the event to \"on:\" section or remove the reference")
+	}
+	if rule.eventTy != nil {
+		c.UpdateEvent(rule.eventName, rule.eventTy)
+	}
 	if rule.dispatchInputsTy != nil {
 		c.UpdateDispatchInputs(rule.dispatchInputsTy)
 	}
 	if rule.jobsTy != nil {
 		c.UpdateJobs(rule.jobsTy)
 	}
+	if rule.availableCtxs != nil {
+		c.SetContextAvailability(rule.availableCtxs)
+	}
 
 	ty, errs := c.Check(expr)
 	for _, err := range errs {
@@ -663,50 +969,110 @@ func (rule *RuleExpression) checkSemantics(src string, line, col int, checkUntru
 		rule.exprError(err, line, col)
 		return nil, l.Offset()
 	}
-	return rule.checkSemanticsOfExprNode(expr, line, col, checkUntrusted), l.Offset()
+	ty := rule.checkSemanticsOfExprNode(expr, line, col, checkUntrusted)
+	if rule.typeHook != nil && ty != nil {
+		// l.Offset() points the position after "}}"
+		e := strings.TrimSpace(strings.TrimSuffix(src[:l.Offset()], "}}"))
+		rule.typeHook(&Pos{Line: line, Col: col - 3}, e, ty) // 3 for "${{"
+	}
+	return ty, l.Offset()
 }
 
+// calcNeedsType calculates type of 'needs' context of the job. The context only contains the jobs
+// listed in 'needs:' of the job. Jobs which the listed jobs depend on are not included.
+// https://docs.github.com/en/actions/learn-github-actions/contexts#needs-context
 func (rule *RuleExpression) calcNeedsType(job *Job) *ObjectType {
-	// https://docs.github.com/en/actions/learn-github-actions/contexts#needs-context
 	o := NewEmptyStrictObjectType()
-	rule.populateDependantNeedsTypes(o, job, job)
-	return o
-}
-
-func (rule *RuleExpression) populateDependantNeedsTypes(out *ObjectType, job *Job, root *Job) {
 	for _, id := range job.Needs {
 		i := strings.ToLower(id.Value) // ID is case insensitive
-		if i == root.ID.Value {
+		if i == job.ID.Value {
 			continue // When cyclic dependency exists. This does not happen normally.
 		}
-		if _, ok := out.Props[i]; ok {
-			continue // Already added
+		if j, ok := rule.workflow.Jobs[i]; ok {
+			o.Props[i] = typeOfNeedsJob(j)
 		}
+	}
+	return o
+}
 
-		j, ok := rule.workflow.Jobs[i]
-		if !ok {
-			continue
+func typeOfNeedsJob(j *Job) *ObjectType {
+	var outputs *ObjectType
+	if j.WorkflowCall == nil {
+		outputs = NewEmptyStrictObjectType()
+		for name := range j.Outputs {
+			outputs.Props[name] = StringType{}
+		}
+	} else {
+		// When the outputs are the result of reusable workflow call, their names are not defined in the job's
+		// configuration (instead, they are defined in the reusable workflow). Fall back to a loose object. (#121)
+		outputs = NewEmptyObjectType()
+	}
+
+	return NewStrictObjectType(map[string]ExprType{
+		"outputs": outputs,
+		"result":  newJobResultType(),
+	})
+}
+
+// checkUndeclaredNeeds reports accesses to jobs in 'needs' context like needs.build when the jobs
+// exist in the workflow but are not listed in 'needs:' of the current job. It returns types of
+// the reported jobs so that the same access is not reported again as undefined property.
+func (rule *RuleExpression) checkUndeclaredNeeds(expr ExprNode, line, col int) map[string]ExprType {
+	if rule.job == nil || rule.workflow == nil || rule.needsTy == nil {
+		return nil
+	}
+
+	var undeclared map[string]ExprType
+	VisitExprNode(expr, func(n, parent ExprNode, entering bool) {
+		if !entering {
+			return
 		}
 
-		var outputs *ObjectType
-		if j.WorkflowCall == nil {
-			outputs = NewEmptyStrictObjectType()
-			for name := range j.Outputs {
-				outputs.Props[name] = StringType{}
+		var recv ExprNode
+		var id string
+		switch n := n.(type) {
+		case *ObjectDerefNode:
+			recv, id = n.Receiver, n.Property
+		case *IndexAccessNode:
+			s, ok := n.Index.(*StringNode)
+			if !ok {
+				return
 			}
-		} else {
-			// When the outputs are the result of reusable workflow call, their names are not defined in the job's
-			// configuration (instead, they are defined in the reusable workflow). Fall back to a loose object. (#121)
-			outputs = NewEmptyObjectType()
+			recv, id = n.Operand, s.Value
+		default:
+			return
+		}
+		if v, ok := recv.(*VariableNode); !ok || !strings.EqualFold(v.Name, "needs") {
+			return
 		}
 
-		out.Props[i] = NewStrictObjectType(map[string]ExprType{
-			"outputs": outputs,
-			"result":  StringType{},
-		})
+		id = strings.ToLower(id)
+		if _, ok := rule.needsTy.Props[id]; ok {
+			return
+		}
+		j, ok := rule.workflow.Jobs[id]
+		if !ok || j == rule.job {
+			return
+		}
+		if _, ok := undeclared[id]; ok {
+			return
+		}
 
-		rule.populateDependantNeedsTypes(out, j, root) // Add necessary needs props recursively
-	}
+		t := recv.Token()
+		rule.errorf(
+			convertExprLineColToPos(t.Line, t.Column, line, col),
+			"job %q is not listed in \"needs\" section of job %q. only jobs listed in \"needs\" section are available in \"needs\" context. add %q to the \"needs\" section",
+			j.ID.Value,
+			rule.job.ID.Value,
+			j.ID.Value,
+		)
+		if undeclared == nil {
+			undeclared = map[string]ExprType{}
+		}
+		undeclared[id] = typeOfNeedsJob(j)
+	})
+
+	return undeclared
 }
 
 func (rule *RuleExpression) guessTypeOfMatrixExpression(expr *String) *ObjectType {
@@ -719,27 +1085,66 @@ func (rule *RuleExpression) guessTypeOfMatrixExpression(expr *String) *ObjectTyp
 		return NewEmptyObjectType()
 	}
 
+	// Matrix object is a mapping from names of matrix rows to arrays of their values. Type of each
+	// matrix value is an element type of the array.
+	//   matrix: ${{ fromJSON('{"os": ["ubuntu-latest", "windows-latest"]}') }}
+
+	if !matTy.IsStrict() {
+		switch t := matTy.Mapped.(type) {
+		case *ArrayType:
+			return NewMapObjectType(t.Elem)
+		case AnyType:
+			return NewEmptyObjectType()
+		default:
+			rule.errorf(expr.Pos, "type of matrix rows must be array but found type %s at object %s", t.String(), matTy.String())
+			return NewEmptyObjectType()
+		}
+	}
+
+	// Note: Do not modify matTy since it may be a type of some context object
+	o := NewEmptyStrictObjectType()
+	for n, p := range matTy.Props {
+		if n == "include" || n == "exclude" {
+			switch p.(type) {
+			case *ArrayType, AnyType:
+				// ok
+			default:
+				rule.errorf(expr.Pos, "type of %q section in matrix must be array but found type %s", n, p.String())
+			}
+			continue
+		}
+		switch p := p.(type) {
+		case *ArrayType:
+			o.Props[n] = p.Elem
+		case AnyType:
+			o.Props[n] = AnyType{}
+		default:
+			rule.errorf(expr.Pos, "type of matrix row %q must be array but found type %s", n, p.String())
+			o.Props[n] = AnyType{}
+		}
+	}
+
 	// Consider properties in include section elements since 'include' section adds matrix values
-	incTy, ok := matTy.Props["include"]
-	if ok {
-		delete(matTy.Props, "include")
-		if a, ok := incTy.(*ArrayType); ok {
-			if o, ok := a.Elem.(*ObjectType); ok {
-				for n, p := range o.Props {
-					t, ok := matTy.Props[n]
-					if !ok {
-						matTy.Props[n] = p
-						continue
-					}
-					matTy.Props[n] = t.Merge(p)
+	if a, ok := matTy.Props["include"].(*ArrayType); ok {
+		switch e := a.Elem.(type) {
+		case *ObjectType:
+			for n, p := range e.Props {
+				t, ok := o.Props[n]
+				if !ok {
+					o.Props[n] = p
+					continue
 				}
+				o.Props[n] = t.Merge(p)
+			}
+			if !e.IsStrict() {
+				o.Loose() // Unknown matrix values may be added by 'include' section
 			}
+		case AnyType:
+			o.Loose()
 		}
 	}
 
-	delete(matTy.Props, "exclude")
-
-	return matTy
+	return o
 }
 
 func (rule *RuleExpression) guessTypeOfMatrix(m *Matrix) *ObjectType {
@@ -753,36 +1158,33 @@ func (rule *RuleExpression) guessTypeOfMatrix(m *Matrix) *ObjectType {
 		o.Props[n] = rule.guessTypeOfMatrixRow(r)
 	}
 
-	// Note: Type check in 'include' section duplicates with checkMatrixCombinations() method
+	// Note: Expressions and values in 'include' section are checked here since their types are
+	// necessary for guessing the type of matrix. checkMatrixCombinations() is not used for the section.
 
 	if m.Include == nil {
 		return o
 	}
 
 	if m.Include.Expression != nil {
-		if ty, ok := rule.checkOneExpression(m.Include.Expression, "include").(*ArrayType); ok {
-			if ret, ok := o.Merge(ty.Elem).(*ObjectType); ok {
-				return ret
-			}
+		// Combinations are given dynamically like `include: ${{ fromJSON(needs.setup.outputs.include) }}`.
+		// Keep the types of matrix rows and allow other properties.
+		if a, ok := rule.checkArrayExpression(m.Include.Expression, "include").(*ArrayType); ok {
+			rule.mergeMatrixCombinationType(o, rule.checkObjectTy(a.Elem, m.Include.Expression.Pos, "include"))
+		} else {
+			o.Loose()
 		}
-		return NewEmptyObjectType()
+		return o
 	}
 
 	for _, combi := range m.Include.Combinations {
 		if combi.Expression != nil {
-			ty := rule.checkOneExpression(m.Include.Expression, "matrix combination at element of include section")
-			if ty == nil {
-				continue
-			}
-			if merged, ok := o.Merge(ty).(*ObjectType); ok {
-				o = merged
-			} else {
-				o.Loose()
-			}
+			ty := rule.checkObjectExpression(combi.Expression, "matrix combination at element of include section")
+			rule.mergeMatrixCombinationType(o, ty)
 			continue
 		}
 
 		for n, assign := range combi.Assigns {
+			rule.checkRawYAMLValue(assign.Value)
 			ty := guessTypeOfRawYAMLValue(assign.Value)
 			if t, ok := o.Props[n]; ok {
 				// When the combination exists in 'matrix' section, merge type with existing one
@@ -797,16 +1199,43 @@ func (rule *RuleExpression) guessTypeOfMatrix(m *Matrix) *ObjectType {
 	return o
 }
 
+// mergeMatrixCombinationType merges the type of matrix combination given by expression into the
+// matrix type. When properties of the combination are unknown, the matrix type allows any property.
+func (rule *RuleExpression) mergeMatrixCombinationType(matTy *ObjectType, combiTy ExprType) {
+	o, ok := combiTy.(*ObjectType)
+	if !ok {
+		matTy.Loose()
+		return
+	}
+	for n, p := range o.Props {
+		if t, ok := matTy.Props[n]; ok {
+			p = t.Merge(p)
+		}
+		matTy.Props[n] = p
+	}
+	if !o.IsStrict() {
+		matTy.Loose()
+	}
+}
+
 func (rule *RuleExpression) guessTypeOfMatrixRow(r *MatrixRow) ExprType {
 	if r.Expression != nil {
+		// Type of matrix value is the element type of the array. When the expression resolves to
+		// any type (e.g. `fromJSON` of unknown string), the element type is unknown.
 		if a, ok := rule.checkArrayExpression(r.Expression, "matrix row").(*ArrayType); ok {
-			return a
+			return a.Elem
 		}
 		return AnyType{}
 	}
 
+	return guessTypeOfMatrixRowValues(r.Values)
+}
+
+// guessTypeOfMatrixRowValues fuses types of values in the matrix row into one type. When the types
+// of values conflict, the fused type is any.
+func guessTypeOfMatrixRowValues(vs []RawYAMLValue) ExprType {
 	var ty ExprType
-	for _, v := range r.Values {
+	for _, v := range vs {
 		t := guessTypeOfRawYAMLValue(v)
 		if ty == nil {
 			ty = t
@@ -848,6 +1277,8 @@ func (rule *RuleExpression) checkWorkflowCallOutputs(outputs map[*String]*Workfl
 	}
 	rule.jobsTy = NewStrictObjectType(props)
 
+	rule.availableCtxs = workflowCallOutputContexts
+	defer func() { rule.availableCtxs = nil }()
 	for _, o := range outputs {
 		rule.checkString(o.Value)
 	}
@@ -891,6 +1322,51 @@ func guessTypeFromString(s string) ExprType {
 	return StringType{}
 }
 
+// visitExprsInString parses expressions in ${{ }} placeholders in the string and calls the callback
+// with each parsed expression and the base line and column to convert positions in the expression
+// with convertExprLineColToPos. When bare is true, the string is parsed as one expression if it
+// does not contain ${{ }} like 'if:' conditions. Expressions which cannot be parsed are ignored.
+func visitExprsInString(str *String, bare bool, f func(expr ExprNode, line, col int)) {
+	if str == nil {
+		return
+	}
+
+	line, col := str.Pos.Line, str.Pos.Col
+	if str.Quoted {
+		col++
+	}
+
+	if bare && !strings.Contains(str.Value, "${{") {
+		expr, err := NewExprParser().Parse(NewExprLexer(str.Value + "}}")) // }} is necessary since lexer lexes it as end of tokens
+		if err == nil {
+			f(expr, line, col)
+		}
+		return
+	}
+
+	s := str.Value
+	offset := 0
+	for {
+		idx := strings.Index(s, "${{")
+		if idx == -1 {
+			return
+		}
+		start := idx + 3 // 3 means removing "${{"
+		s = s[start:]
+		offset += start
+
+		l := NewExprLexer(s)
+		expr, err := NewExprParser().Parse(l)
+		if err != nil {
+			return
+		}
+		f(expr, line, col+offset)
+
+		s = s[l.Offset():]
+		offset += l.Offset()
+	}
+}
+
 func convertExprLineColToPos(line, col, lineBase, colBase int) *Pos {
 	// Line and column in ExprError are 1-based
 	return &Pos{
@@ -0,0 +1,78 @@
+package actionlint
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Commands which need full git history. In shallow clone, they fail or produce wrong results since
+// older commits and tags are not fetched.
+var reFullHistoryCommand = regexp.MustCompile(`(?:^|[\s;&|(]|\$\()(git\s+(?:describe|log|rev-list|shortlog|merge-base)|git-cliff|semantic-release|goreleaser|standard-version)\b`)
+
+// RuleFetchDepth is a rule to detect commands which need full git history in 'run:' scripts after
+// the repository is checked out by actions/checkout without 'fetch-depth: 0'. actions/checkout
+// fetches only one commit by default. Since the commands are detected heuristically, this rule is
+// disabled by default.
+// https://github.com/actions/checkout#fetch-all-history-for-all-tags-and-branches
+type RuleFetchDepth struct {
+	RuleBase
+	checkout *ExecAction // The last actions/checkout step in the current job
+}
+
+// NewRuleFetchDepth creates new RuleFetchDepth instance.
+func NewRuleFetchDepth() *RuleFetchDepth {
+	return &RuleFetchDepth{
+		RuleBase: RuleBase{name: "fetch-depth"},
+	}
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleFetchDepth) VisitJobPre(n *Job) error {
+	rule.checkout = nil
+	return nil
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RuleFetchDepth) VisitStep(n *Step) error {
+	switch e := n.Exec.(type) {
+	case *ExecAction:
+		if e.Uses != nil && strings.HasPrefix(strings.ToLower(e.Uses.Value), "actions/checkout@") {
+			rule.checkout = e
+		}
+	case *ExecRun:
+		if e.Run == nil || rule.checkout == nil || fetchesFullHistory(rule.checkout) {
+			return nil
+		}
+		for i, line := range strings.Split(e.Run.Value, "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "#") {
+				continue
+			}
+			m := reFullHistoryCommand.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			rule.errorf(
+				e.RunPos,
+				"%q at line %d in this script needs full git history but the repository was checked out by %q at line %d, col %d without \"fetch-depth: 0\". add \"fetch-depth: 0\" to the \"with\" section to fetch all history",
+				strings.Join(strings.Fields(m[1]), " "),
+				i+1,
+				rule.checkout.Uses.Value,
+				rule.checkout.Uses.Pos.Line,
+				rule.checkout.Uses.Pos.Col,
+			)
+			return nil // Report only the first command in the script
+		}
+	}
+	return nil
+}
+
+// fetchesFullHistory returns whether the actions/checkout step fetches all history. 'fetch-depth'
+// given by expression is considered to fetch all history since its value is unknown.
+func fetchesFullHistory(checkout *ExecAction) bool {
+	i, ok := checkout.Inputs["fetch-depth"]
+	if !ok || i.Value == nil {
+		return false
+	}
+	v := strings.TrimSpace(i.Value.Value)
+	return v == "0" || strings.Contains(v, "${{")
+}
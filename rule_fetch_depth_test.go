@@ -0,0 +1,36 @@
+package actionlint
+
+import "testing"
+
+func TestRuleFetchDepthFullHistoryCommand(t *testing.T) {
+	testCases := []struct {
+		line string
+		want string
+	}{
+		{"git describe --tags", "git describe"},
+		{"VERSION=$(git describe --tags --abbrev=0)", "git describe"},
+		{"git log --oneline v1.0.0..HEAD", "git log"},
+		{"test -n foo && git  rev-list --count HEAD", "git  rev-list"},
+		{"git shortlog -sn", "git shortlog"},
+		{"git merge-base origin/main HEAD", "git merge-base"},
+		{"git-cliff --latest", "git-cliff"},
+		{"npx semantic-release", "semantic-release"},
+		{"goreleaser release --clean", "goreleaser"},
+		{"git status", ""},
+		{"git logout", ""},
+		{"echo legit log", ""},
+		{"mygit describe", ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.line, func(t *testing.T) {
+			have := ""
+			if m := reFullHistoryCommand.FindStringSubmatch(tc.line); m != nil {
+				have = m[1]
+			}
+			if have != tc.want {
+				t.Fatalf("wanted %q but got %q", tc.want, have)
+			}
+		})
+	}
+}
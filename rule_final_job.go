@@ -0,0 +1,80 @@
+package actionlint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Words in job IDs or names which suggest that the job is run at the end of the workflow.
+var reFinalJobName = regexp.MustCompile(`(?i)(?:^|[^a-z])(release|notify|notification|cleanup|clean-up|teardown|report|summary|finalize)(?:$|[^a-z])`)
+
+// Jobs which depend on this number of jobs or more are considered as final jobs.
+const minFinalJobNeeds = 3
+
+// Status check functions which make a job run or be skipped regardless of results of its needs.
+var statusCheckFuncs = []string{"always", "cancelled", "failure", "success"}
+
+// RuleFinalJob is a rule to detect jobs which look like final or cleanup jobs but have no status
+// check function like always() in their 'if:' conditions. Without the function, a job is skipped
+// when some of jobs in its 'needs:' failed or were cancelled. Since whether the job is intended to
+// run at the end of workflow is guessed from its name and the number of its dependencies, this
+// rule is disabled by default.
+// https://docs.github.com/en/actions/using-jobs/using-jobs-in-a-workflow#defining-prerequisite-jobs
+type RuleFinalJob struct {
+	RuleBase
+}
+
+// NewRuleFinalJob creates new RuleFinalJob instance.
+func NewRuleFinalJob() *RuleFinalJob {
+	return &RuleFinalJob{
+		RuleBase: RuleBase{name: "final-job"},
+	}
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleFinalJob) VisitJobPre(n *Job) error {
+	if len(n.Needs) == 0 || hasStatusCheckFunc(n.If) {
+		return nil
+	}
+
+	reason := ""
+	if m := reFinalJobName.FindStringSubmatch(n.ID.Value); m != nil {
+		reason = fmt.Sprintf("its ID contains %q", m[1])
+	} else if n.Name != nil {
+		if m := reFinalJobName.FindStringSubmatch(n.Name.Value); m != nil {
+			reason = fmt.Sprintf("its name contains %q", m[1])
+		}
+	}
+	if reason == "" && len(n.Needs) >= minFinalJobNeeds {
+		reason = fmt.Sprintf("it depends on %d jobs", len(n.Needs))
+	}
+	if reason == "" {
+		return nil
+	}
+
+	rule.errorf(
+		n.Pos,
+		"job %q looks like a final job since %s but its \"if:\" condition has no status check function. the job is skipped when some job in \"needs:\" fails or is cancelled. add \"if: always()\" to run it regardless of the results, or \"if: success()\" to confirm that it should be skipped",
+		n.ID.Value,
+		reason,
+	)
+	return nil
+}
+
+// hasStatusCheckFunc returns whether the condition calls some status check function like always().
+func hasStatusCheckFunc(cond *String) bool {
+	found := false
+	visitExprsInString(cond, true, func(expr ExprNode, _, _ int) {
+		VisitExprNode(expr, func(n, _ ExprNode, entering bool) {
+			if f, ok := n.(*FuncCallNode); ok && entering {
+				for _, s := range statusCheckFuncs {
+					if strings.EqualFold(f.Callee, s) {
+						found = true
+					}
+				}
+			}
+		})
+	})
+	return found
+}
@@ -0,0 +1,86 @@
+package actionlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleFinalJob(t *testing.T) {
+	testCases := []struct {
+		what string
+		job  string
+		want string
+	}{
+		{
+			what: "name of final job",
+			job:  "notify:\n    needs: [build]",
+			want: `job "notify" looks like a final job since its ID contains "notify"`,
+		},
+		{
+			what: "name with separator",
+			job:  "post-cleanup:\n    needs: [build]",
+			want: `its ID contains "cleanup"`,
+		},
+		{
+			what: "job name",
+			job:  "final:\n    name: Send report\n    needs: [build]",
+			want: `its name contains "report"`,
+		},
+		{
+			what: "many dependencies",
+			job:  "final:\n    needs: [build, test, lint]",
+			want: `since it depends on 3 jobs`,
+		},
+		{
+			what: "always()",
+			job:  "notify:\n    needs: [build]\n    if: always()",
+		},
+		{
+			what: "negated cancelled() in ${{ }}",
+			job:  "notify:\n    needs: [build]\n    if: ${{ !cancelled() }}",
+		},
+		{
+			what: "explicit success()",
+			job:  "release:\n    needs: [build, test, lint]\n    if: success() && github.ref_type == 'tag'",
+		},
+		{
+			what: "condition without status check",
+			job:  "release:\n    needs: [build]\n    if: github.ref_type == 'tag'",
+			want: `job "release" looks like a final job`,
+		},
+		{
+			what: "no needs",
+			job:  "notify:\n    if: github.event_name == 'push'",
+		},
+		{
+			what: "word in other word",
+			job:  "reporter-tool:\n    needs: [build]",
+		},
+		{
+			what: "normal job",
+			job:  "test:\n    needs: [build]",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			src := "on: push\njobs:\n  " + tc.job + "\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n"
+			errs, err := RunRule(NewRuleFinalJob(), []byte(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("wanted 1 error but got %v", errs)
+			}
+			if !strings.Contains(errs[0].Message, tc.want) {
+				t.Fatalf("error message %q does not contain %q", errs[0].Message, tc.want)
+			}
+		})
+	}
+}
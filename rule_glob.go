@@ -1,5 +1,10 @@
 package actionlint
 
+import (
+	"fmt"
+	"strings"
+)
+
 // RuleGlob is a rule to check glob syntax.
 // https://docs.github.com/en/actions/learn-github-actions/workflow-syntax-for-github-actions#filter-pattern-cheat-sheet
 type RuleGlob struct {
@@ -23,6 +28,7 @@ func (rule *RuleGlob) VisitWorkflowPre(n *Workflow) error {
 			rule.checkGitRefGlobs(w.TagsIgnore)
 			rule.checkFilePathGlobs(w.Paths)
 			rule.checkFilePathGlobs(w.PathsIgnore)
+			rule.checkAllPathsExcluded(w)
 		}
 	}
 	return nil
@@ -40,6 +46,66 @@ func (rule *RuleGlob) checkFilePathGlobs(paths []*String) {
 	}
 }
 
+// checkAllPathsExcluded checks all paths included by "paths" filter are excluded by negative patterns
+// after them like ["docs/**", "!docs/**"]. In the case, the workflow is never triggered by the
+// event. Since a later pattern overrides earlier ones, a path is included only when the last
+// pattern matching it is positive. So when every positive pattern is subsumed by some negative
+// pattern after it, no path is included.
+func (rule *RuleGlob) checkAllPathsExcluded(event *WebhookEvent) {
+	paths := event.Paths
+	if len(paths) == 0 {
+		return
+	}
+
+	excludes := []*String{}
+	seen := map[*String]struct{}{}
+	positive := 0
+	for i, p := range paths {
+		if strings.Contains(p.Value, "${{") {
+			return
+		}
+		if strings.HasPrefix(p.Value, "!") {
+			continue
+		}
+		positive++
+
+		var ex *String
+		for _, n := range paths[i+1:] {
+			if strings.HasPrefix(n.Value, "!") && globSubsumes(n.Value[1:], p.Value) {
+				ex = n
+				break
+			}
+		}
+		if ex == nil {
+			return
+		}
+		if _, ok := seen[ex]; !ok {
+			seen[ex] = struct{}{}
+			excludes = append(excludes, ex)
+		}
+	}
+	if positive == 0 {
+		return
+	}
+
+	qs := make([]string, 0, len(excludes))
+	for _, e := range excludes {
+		qs = append(qs, fmt.Sprintf("%q at line:%d,col:%d", e.Value, e.Pos.Line, e.Pos.Col))
+	}
+	s := "pattern"
+	if len(qs) > 1 {
+		s = "patterns"
+	}
+	rule.errorf(
+		paths[0].Pos,
+		"all paths included by \"paths\" filter of %q event are excluded by negative %s %s after them. the workflow is never triggered by this event. remove or narrow the negative %s",
+		event.Hook.Value,
+		s,
+		strings.Join(qs, ", "),
+		s,
+	)
+}
+
 func (rule *RuleGlob) globErrors(errs []InvalidGlobPattern, pos *Pos, quoted bool) {
 	for i := range errs {
 		err := &errs[i]
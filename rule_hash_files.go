@@ -0,0 +1,122 @@
+package actionlint
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Commands in 'run:' which are considered to create a working tree in the workspace.
+var reWorkingTreeCommand = regexp.MustCompile(`\b(git\s+(clone|init|checkout)|gh\s+repo\s+clone)\b`)
+
+// RuleHashFiles is a rule to detect hashFiles() calls in steps which run before the repository is
+// checked out in the job. hashFiles() calculates a hash of files in the workspace so it returns an
+// empty string when no file exists. This is usually a bug of cache keys. Since whether the files
+// exist in the workspace is guessed heuristically, this rule is disabled by default.
+// https://docs.github.com/en/actions/learn-github-actions/expressions#hashfiles
+type RuleHashFiles struct {
+	RuleBase
+}
+
+// NewRuleHashFiles creates new RuleHashFiles instance.
+func NewRuleHashFiles() *RuleHashFiles {
+	return &RuleHashFiles{
+		RuleBase: RuleBase{name: "hash-files"},
+	}
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleHashFiles) VisitJobPre(n *Job) error {
+	calls := []*Pos{}
+	var checkout *Step
+	for _, s := range n.Steps {
+		// Note: Expressions in the step are evaluated before running the step. So hashFiles() in the
+		// checkout step is also reported.
+		calls = append(calls, findHashFilesCallsInStep(s)...)
+		if stepCreatesWorkingTree(s) {
+			checkout = s
+			break
+		}
+	}
+
+	for _, p := range calls {
+		if checkout == nil {
+			rule.errorf(
+				p,
+				"hashFiles() is used but no step checks out the repository in job %q. hashFiles() calculates a hash of files in the workspace so it always returns an empty string. add \"actions/checkout\" step before this step",
+				n.ID.Value,
+			)
+		} else {
+			rule.errorf(
+				p,
+				"hashFiles() is used before the repository is checked out by the step at %s in job %q. hashFiles() calculates a hash of files in the workspace so it returns an empty string here. move the checkout before this step",
+				checkout.Pos,
+				n.ID.Value,
+			)
+		}
+	}
+
+	return nil
+}
+
+// stepCreatesWorkingTree returns if files are likely put in the workspace after running the step.
+func stepCreatesWorkingTree(s *Step) bool {
+	switch e := s.Exec.(type) {
+	case *ExecAction:
+		if e.Uses == nil {
+			return false
+		}
+		u := strings.ToLower(e.Uses.Value)
+		// Local actions are only available after the repository is checked out
+		return strings.HasPrefix(u, "actions/checkout@") ||
+			strings.HasPrefix(u, "actions/download-artifact@") ||
+			strings.HasPrefix(u, "./")
+	case *ExecRun:
+		return e.Run != nil && reWorkingTreeCommand.MatchString(e.Run.Value)
+	default:
+		return false
+	}
+}
+
+func findHashFilesCallsInStep(s *Step) []*Pos {
+	ps := findHashFilesCalls(s.If, true)
+	ps = append(ps, findHashFilesCalls(s.Name, false)...)
+	switch e := s.Exec.(type) {
+	case *ExecRun:
+		ps = append(ps, findHashFilesCalls(e.Run, false)...)
+		ps = append(ps, findHashFilesCalls(e.WorkingDirectory, false)...)
+	case *ExecAction:
+		for _, i := range e.Inputs {
+			ps = append(ps, findHashFilesCalls(i.Value, false)...)
+		}
+		ps = append(ps, findHashFilesCalls(e.Entrypoint, false)...)
+		ps = append(ps, findHashFilesCalls(e.Args, false)...)
+	}
+	if s.Env != nil {
+		for _, v := range s.Env.Vars {
+			ps = append(ps, findHashFilesCalls(v.Value, false)...)
+		}
+	}
+	return ps
+}
+
+// findHashFilesCalls finds positions of hashFiles() calls in ${{ }} placeholders in the string.
+// When bare is true, the string is parsed as an expression if it does not contain ${{ }} like
+// 'if:' conditions.
+func findHashFilesCalls(str *String, bare bool) []*Pos {
+	ps := []*Pos{}
+	visitExprsInString(str, bare, func(expr ExprNode, line, col int) {
+		ps = append(ps, findHashFilesCallsInExpr(expr, line, col)...)
+	})
+	return ps
+}
+
+func findHashFilesCallsInExpr(expr ExprNode, line, col int) []*Pos {
+	ps := []*Pos{}
+	VisitExprNode(expr, func(n, _ ExprNode, entering bool) {
+		if f, ok := n.(*FuncCallNode); ok && entering && strings.EqualFold(f.Callee, "hashFiles") {
+			t := f.Token()
+			ps = append(ps, convertExprLineColToPos(t.Line, t.Column, line, col))
+		}
+	})
+	return ps
+}
@@ -0,0 +1,95 @@
+package actionlint
+
+import (
+	"strings"
+)
+
+// Operators of expression syntax. Longer operators must come first so that "!=" is not detected
+// as "!".
+var bareIfCondOperators = []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!"}
+
+// RuleIfCond is a rule to check "if:" conditions which mix ${{ }} expressions and bare operators
+// outside them like `${{ github.ref == 'refs/heads/main' }} && always()`. When a condition contains
+// ${{ }}, the value is evaluated as a string by embedding the results of the expressions. Operators
+// outside ${{ }} are not evaluated and the result like "true && true" is always truthy.
+// https://docs.github.com/en/actions/using-jobs/using-conditions-to-control-job-execution
+type RuleIfCond struct {
+	RuleBase
+}
+
+// NewRuleIfCond creates new RuleIfCond instance.
+func NewRuleIfCond() *RuleIfCond {
+	return &RuleIfCond{
+		RuleBase: RuleBase{name: "if-cond"},
+	}
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleIfCond) VisitJobPre(n *Job) error {
+	rule.checkIf(n.If)
+	return nil
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RuleIfCond) VisitStep(n *Step) error {
+	rule.checkIf(n.If)
+	return nil
+}
+
+func (rule *RuleIfCond) checkIf(cond *String) {
+	if cond == nil || !strings.Contains(cond.Value, "${{") {
+		return
+	}
+
+	op, offset, ok := findBareOperatorOutsideExprs(cond.Value)
+	if !ok {
+		return
+	}
+
+	pos := cond.Pos
+	if !strings.Contains(cond.Value[:offset], "\n") {
+		col := cond.Pos.Col + offset
+		if cond.Quoted {
+			col++
+		}
+		pos = &Pos{Line: cond.Pos.Line, Col: col}
+	}
+	rule.errorf(
+		pos,
+		"operator %q is outside of ${{ }} in \"if\" condition %q. the condition is evaluated as a string where results of ${{ }} are embedded so it is always truthy. put the whole condition in one ${{ }} or remove ${{ }}",
+		op,
+		cond.Value,
+	)
+}
+
+// findBareOperatorOutsideExprs finds the first operator outside of ${{ }} in the string. It returns
+// the operator and its byte offset in the string.
+func findBareOperatorOutsideExprs(s string) (string, int, bool) {
+	offset := 0
+	for {
+		end := len(s)
+		idx := strings.Index(s[offset:], "${{")
+		if idx >= 0 {
+			end = offset + idx
+		}
+
+		for i := offset; i < end; i++ {
+			for _, op := range bareIfCondOperators {
+				if strings.HasPrefix(s[i:end], op) {
+					return op, i, true
+				}
+			}
+		}
+
+		if idx < 0 {
+			return "", 0, false
+		}
+
+		start := end + 3 // 3 means removing "${{"
+		_, n, err := LexExpression(s[start:])
+		if err != nil {
+			return "", 0, false
+		}
+		offset = start + n
+	}
+}
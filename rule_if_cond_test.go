@@ -0,0 +1,54 @@
+package actionlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleIfCond(t *testing.T) {
+	testCases := []struct {
+		cond string
+		want string
+	}{
+		{"${{ github.ref == 'refs/heads/main' }}", ""},
+		{"github.ref == 'refs/heads/main' && always()", ""},
+		{"${{ github.ref == 'refs/heads/main' && always() }}", ""},
+		{"${{ github.ref }}-${{ github.sha }}", ""},
+		{"${{ github.ref == 'refs/heads/main' }} && always()", `:6:52: operator "&&" is outside of ${{ }}`},
+		{"always() || ${{ github.event_name == 'push' }}", `:6:22: operator "||" is outside of ${{ }}`},
+		{"${{ github.ref }} == 'refs/heads/main'", `:6:31: operator "==" is outside of ${{ }}`},
+		{"${{ github.ref }} != 'refs/heads/main'", `:6:31: operator "!=" is outside of ${{ }}`},
+		{"'!${{ cancelled() }}'", `:6:14: operator "!" is outside of ${{ }}`},
+		{"'${{ github.event.number }} > 10'", `:6:41: operator ">" is outside of ${{ }}`},
+		{"${{ contains(github.ref, '&&') }} || ${{ success() }}", `:6:47: operator "||" is outside of ${{ }}`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.cond, func(t *testing.T) {
+			src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - if: ` + tc.cond + `
+        run: echo
+`
+			errs, err := RunRule(NewRuleIfCond(), []byte(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("wanted 1 error but got %v", errs)
+			}
+			if !strings.Contains(errs[0].Error(), tc.want) {
+				t.Fatalf("error %q does not contain %q", errs[0].Error(), tc.want)
+			}
+		})
+	}
+}
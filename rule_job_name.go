@@ -0,0 +1,108 @@
+package actionlint
+
+import (
+	"sort"
+	"strings"
+)
+
+// RuleJobName is a rule to detect jobs which have the same name in a workflow. The name of job is
+// shown in GitHub UI and used as the name of status check. When multiple jobs have the same name,
+// they cannot be distinguished. Job ID is used as the name when 'name:' is omitted. Since this is
+// not an error of the workflow, this rule is disabled by default.
+// https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#jobsjob_idname
+type RuleJobName struct {
+	RuleBase
+}
+
+// NewRuleJobName creates new RuleJobName instance.
+func NewRuleJobName() *RuleJobName {
+	return &RuleJobName{
+		RuleBase: RuleBase{name: "job-name"},
+	}
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RuleJobName) VisitWorkflowPre(n *Workflow) error {
+	jobs := make([]*Job, 0, len(n.Jobs))
+	for _, j := range n.Jobs {
+		jobs = append(jobs, j)
+	}
+	sort.Slice(jobs, func(i, j int) bool {
+		if jobs[i].Pos.Line != jobs[j].Pos.Line {
+			return jobs[i].Pos.Line < jobs[j].Pos.Line
+		}
+		return jobs[i].Pos.Col < jobs[j].Pos.Col
+	})
+
+	seen := map[string]*Job{}
+	for _, j := range jobs {
+		if j.Strategy != nil && j.Strategy.Matrix != nil {
+			continue // Names of matrix jobs are suffixed with matrix values like "test (ubuntu-latest, 18)"
+		}
+		name, pos, ok := resolveJobName(j)
+		if !ok {
+			continue
+		}
+		prev, ok := seen[name]
+		if !ok {
+			seen[name] = j
+			continue
+		}
+		_, prevPos, _ := resolveJobName(prev)
+		rule.errorf(
+			pos,
+			"name %q of job %q is the same as job %q at line %d, col %d. jobs with the same name cannot be distinguished in GitHub UI and status checks",
+			name,
+			j.ID.Value,
+			prev.ID.Value,
+			prevPos.Line,
+			prevPos.Col,
+		)
+	}
+
+	return nil
+}
+
+// resolveJobName returns the name of the job shown in GitHub UI and its position. The name is
+// resolved when ${{ }} placeholders in it are constants like ${{ 'build' }}. The third return value
+// is false when the name cannot be resolved statically.
+func resolveJobName(j *Job) (string, *Pos, bool) {
+	if j.Name == nil {
+		return j.ID.Value, j.ID.Pos, true
+	}
+
+	s := j.Name.Value
+	if !strings.Contains(s, "${{") {
+		return s, j.Name.Pos, true
+	}
+
+	var b strings.Builder
+	for {
+		idx := strings.Index(s, "${{")
+		if idx == -1 {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:idx])
+		s = s[idx+3:] // 3 means removing "${{"
+
+		l := NewExprLexer(s)
+		expr, err := NewExprParser().Parse(l)
+		if err != nil {
+			return "", nil, false
+		}
+		if f, ok := expr.(*FuncCallNode); ok {
+			if v, ok := foldConstantFuncCall(f); ok {
+				expr = v
+			}
+		}
+		v, ok := literalToString(expr)
+		if !ok {
+			return "", nil, false
+		}
+		b.WriteString(v)
+		s = s[l.Offset():]
+	}
+
+	return b.String(), j.Name.Pos, true
+}
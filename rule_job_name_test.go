@@ -0,0 +1,37 @@
+package actionlint
+
+import "testing"
+
+func TestRuleJobNameResolveJobName(t *testing.T) {
+	testCases := []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{"", "job-id", true},
+		{"Build", "Build", true},
+		{"${{ 'Build' }}", "Build", true},
+		{"Build ${{ 1 }}", "Build 1", true},
+		{"${{ format('{0} on {1}', 'Test', 'Linux') }}", "Test on Linux", true},
+		{"Test (${{ 'linux' }}, ${{ true }})", "Test (linux, true)", true},
+		{"Test on ${{ matrix.os }}", "", false},
+		{"Test on ${{ format('{0}', github.ref) }}", "", false},
+		{"${{ 'broken", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			j := &Job{ID: &String{Value: "job-id", Pos: &Pos{}}}
+			if tc.name != "" {
+				j.Name = &String{Value: tc.name, Pos: &Pos{}}
+			}
+			have, _, ok := resolveJobName(j)
+			if ok != tc.ok {
+				t.Fatalf("wanted ok=%v but got ok=%v", tc.ok, ok)
+			}
+			if have != tc.want {
+				t.Fatalf("wanted %q but got %q", tc.want, have)
+			}
+		})
+	}
+}
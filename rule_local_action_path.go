@@ -0,0 +1,87 @@
+package actionlint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RuleLocalActionPath is a rule to detect local actions at 'uses:' of steps which don't exist in the
+// repository. It catches paths which are not updated after moving or renaming the actions. Since
+// local actions may be checked out at runtime (e.g. from a private repository or a Git submodule),
+// steps after checking out other repositories are not checked and this rule is disabled by
+// default. This rule checks nothing when the repository is unknown, for example on linting stdin.
+// https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#example-using-an-action-in-the-same-repository-as-the-workflow
+type RuleLocalActionPath struct {
+	RuleBase
+	proj *Project
+}
+
+// NewRuleLocalActionPath creates new RuleLocalActionPath instance.
+func NewRuleLocalActionPath() *RuleLocalActionPath {
+	return &RuleLocalActionPath{
+		RuleBase: RuleBase{name: "local-action-path"},
+	}
+}
+
+// SetProject sets the project where local actions are searched. When it is nil, this rule checks
+// nothing.
+func (rule *RuleLocalActionPath) SetProject(p *Project) {
+	rule.proj = p
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleLocalActionPath) VisitJobPre(n *Job) error {
+	if rule.proj == nil {
+		return nil
+	}
+	for _, s := range n.Steps {
+		e, ok := s.Exec.(*ExecAction)
+		if !ok || e.Uses == nil {
+			continue
+		}
+		if checksOutOtherRepository(e) {
+			return nil // Local actions after this step may be put by the checkout
+		}
+		if strings.HasPrefix(e.Uses.Value, "./") && !strings.Contains(e.Uses.Value, "${{") {
+			rule.checkLocalAction(e.Uses)
+		}
+	}
+	return nil
+}
+
+func (rule *RuleLocalActionPath) checkLocalAction(uses *String) {
+	dir := filepath.Join(rule.proj.RootDir(), filepath.FromSlash(uses.Value))
+	if s, err := os.Stat(dir); err != nil || !s.IsDir() {
+		rule.errorf(
+			uses.Pos,
+			"directory of local action %q does not exist in the repository. the action may have been moved or renamed",
+			uses.Value,
+		)
+		return
+	}
+	for _, f := range []string{"action.yml", "action.yaml"} {
+		if _, err := os.Stat(filepath.Join(dir, f)); err == nil {
+			return
+		}
+	}
+	rule.errorf(
+		uses.Pos,
+		"neither action.yml nor action.yaml is found in directory of local action %q",
+		uses.Value,
+	)
+}
+
+// checksOutOtherRepository returns true when the step checks out some other repository or checks
+// out the repository into a different directory with actions/checkout.
+func checksOutOtherRepository(e *ExecAction) bool {
+	if !strings.HasPrefix(e.Uses.Value, "actions/checkout@") {
+		return false
+	}
+	for _, n := range []string{"repository", "path", "submodules"} {
+		if _, ok := e.Inputs[n]; ok {
+			return true
+		}
+	}
+	return false
+}
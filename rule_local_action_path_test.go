@@ -0,0 +1,89 @@
+package actionlint
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRuleLocalActionPath(t *testing.T) {
+	proj := &Project{root: filepath.Join("testdata", "examples")}
+
+	testCases := []struct {
+		what  string
+		steps string
+		want  string
+	}{
+		{
+			what:  "existing action",
+			steps: "- uses: ./.github/actions/my-action",
+		},
+		{
+			what:  "missing directory",
+			steps: "- uses: ./.github/actions/moved-action",
+			want:  `directory of local action "./.github/actions/moved-action" does not exist`,
+		},
+		{
+			what:  "missing action.yml",
+			steps: "- uses: ./.github",
+			want:  `neither action.yml nor action.yaml is found in directory of local action "./.github"`,
+		},
+		{
+			what:  "file instead of directory",
+			steps: "- uses: ./.github/actions/my-action/action.yaml",
+			want:  `directory of local action "./.github/actions/my-action/action.yaml" does not exist`,
+		},
+		{
+			what:  "expression in path",
+			steps: "- uses: ./.github/actions/${{ matrix.name }}",
+		},
+		{
+			what:  "after checking out other repository",
+			steps: "- uses: actions/checkout@v3\n        with:\n          repository: owner/repo\n      - uses: ./.github/actions/moved-action",
+		},
+		{
+			what:  "after checking out into directory",
+			steps: "- uses: actions/checkout@v3\n        with:\n          path: foo\n      - uses: ./foo/action",
+		},
+		{
+			what:  "before checking out other repository",
+			steps: "- uses: ./.github/actions/moved-action\n      - uses: actions/checkout@v3\n        with:\n          repository: owner/repo",
+			want:  `directory of local action "./.github/actions/moved-action" does not exist`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			src := "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      " + tc.steps + "\n"
+			r := NewRuleLocalActionPath()
+			r.SetProject(proj)
+			errs, err := RunRule(r, []byte(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("wanted 1 error but got %v", errs)
+			}
+			if !strings.Contains(errs[0].Message, tc.want) {
+				t.Fatalf("error message %q does not contain %q", errs[0].Message, tc.want)
+			}
+		})
+	}
+}
+
+func TestRuleLocalActionPathWithoutProject(t *testing.T) {
+	src := "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - uses: ./path/to/action\n"
+	errs, err := RunRule(NewRuleLocalActionPath(), []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) > 0 {
+		t.Fatal("nothing should be checked when project is unknown:", errs)
+	}
+}
@@ -1,6 +1,18 @@
 package actionlint
 
-import "strings"
+import (
+	"sort"
+	"strings"
+)
+
+// https://docs.github.com/en/actions/using-jobs/using-a-matrix-for-your-jobs
+//
+// > A matrix will generate a maximum of 256 jobs per workflow run.
+const maxMatrixJobs = 256
+
+// Matrix combinations are enumerated only when the number of them is less than or equal to this
+// value. Otherwise, the number of jobs is estimated.
+const maxEnumeratedMatrixCombinations = 65536
 
 // RuleMatrix is a rule checker to check 'matrix' field of job.
 type RuleMatrix struct {
@@ -37,6 +49,8 @@ func (rule *RuleMatrix) VisitJobPre(n *Job) error {
 	//       sh: pwsh
 
 	rule.checkExclude(m)
+	rule.checkIncludeTypes(m)
+	rule.checkNumberOfJobs(m)
 	return nil
 }
 
@@ -87,8 +101,14 @@ func (rule *RuleMatrix) checkExclude(m *Matrix) {
 	}
 
 	vals := make(map[string][]RawYAMLValue, len(rows))
+	dynamic := map[string]struct{}{}
 	for name, row := range rows {
 		vals[name] = row.Values
+		if row.Expression != nil {
+			// Values of the row are determined dynamically by ${{ }}. For example,
+			//   os: ${{ fromJSON(needs.setup.outputs.os) }}
+			dynamic[name] = struct{}{}
+		}
 	}
 	if m.Include != nil {
 		for _, combi := range m.Include.Combinations {
@@ -122,6 +142,10 @@ func (rule *RuleMatrix) checkExclude(m *Matrix) {
 				continue
 			}
 
+			if _, ok := dynamic[k]; ok {
+				continue
+			}
+
 			if findYAMLValueInArray(vs, a.Value) {
 				continue
 			}
@@ -140,3 +164,246 @@ func (rule *RuleMatrix) checkExclude(m *Matrix) {
 		}
 	}
 }
+
+// checkIncludeTypes checks that values in "include" section which override existing matrix rows
+// have the same type as the values of the rows.
+func (rule *RuleMatrix) checkIncludeTypes(m *Matrix) {
+	if m.Include == nil || m.Include.Expression != nil {
+		return
+	}
+
+	tys := make(map[string]ExprType, len(m.Rows))
+	for n, r := range m.Rows {
+		if r.Expression != nil || len(r.Values) == 0 {
+			continue
+		}
+		ty := guessTypeOfMatrixRowValues(r.Values)
+		if _, ok := ty.(AnyType); ok {
+			continue // Values of the row have mixed types
+		}
+		tys[n] = ty
+	}
+	if len(tys) == 0 {
+		return
+	}
+
+	for _, combi := range m.Include.Combinations {
+		for n, a := range combi.Assigns {
+			ty, ok := tys[n]
+			if !ok {
+				continue
+			}
+			if s, ok := a.Value.(*RawYAMLString); ok && strings.Contains(s.Value, "${{") {
+				continue // Type of the value is determined dynamically
+			}
+			vt := guessTypeOfRawYAMLValue(a.Value)
+			if isSameKindOfExprType(ty, vt) {
+				continue
+			}
+			rule.errorf(
+				a.Value.Pos(),
+				"type of value %s for matrix %q in \"include\" section is %s but values of the matrix row are type of %s. this value is treated differently from other values of the row in expressions",
+				a.Value.String(),
+				n,
+				vt.String(),
+				ty.String(),
+			)
+		}
+	}
+}
+
+// isSameKindOfExprType returns whether both types are the same kind of type. Properties of objects
+// and elements of arrays are not compared.
+func isSameKindOfExprType(a, b ExprType) bool {
+	switch a.(type) {
+	case StringType:
+		_, ok := b.(StringType)
+		return ok
+	case NumberType:
+		_, ok := b.(NumberType)
+		return ok
+	case BoolType:
+		_, ok := b.(BoolType)
+		return ok
+	case NullType:
+		_, ok := b.(NullType)
+		return ok
+	case *ObjectType:
+		_, ok := b.(*ObjectType)
+		return ok
+	case *ArrayType:
+		_, ok := b.(*ArrayType)
+		return ok
+	default:
+		return true
+	}
+}
+
+// checkNumberOfJobs computes the number of jobs generated by the matrix and reports an error when
+// it exceeds the limit. Jobs are computed as follows:
+//
+//  1. Combinations are generated as Cartesian product of all rows
+//  2. Combinations matching to some combination in "exclude" are removed
+//  3. Each combination in "include" is merged into remaining combinations which do not overwrite
+//     any original matrix value. When it cannot be merged into any combination, it is added as a
+//     new combination
+func (rule *RuleMatrix) checkNumberOfJobs(m *Matrix) {
+	if m.Include != nil && m.Include.ContainsExpression() {
+		return
+	}
+	if m.Exclude != nil && m.Exclude.ContainsExpression() {
+		return
+	}
+
+	names := make([]string, 0, len(m.Rows))
+	for n, r := range m.Rows {
+		if r.Expression != nil {
+			return // Number of values is not known statically
+		}
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	total := 1
+	for _, n := range names {
+		total *= len(m.Rows[n].Values)
+		if total == 0 || total > maxEnumeratedMatrixCombinations {
+			break
+		}
+	}
+
+	if total > maxEnumeratedMatrixCombinations {
+		rule.estimateNumberOfJobs(m, names)
+		return
+	}
+
+	var excludes []*MatrixCombination
+	if m.Exclude != nil {
+		excludes = m.Exclude.Combinations
+	}
+
+	// Each combination is represented as indices of values in rows
+	combis := [][]int{}
+	if len(names) > 0 {
+		idx := make([]int, len(names))
+	Loop:
+		for {
+			if !isMatrixCombinationExcluded(m, names, idx, excludes) {
+				c := make([]int, len(idx))
+				copy(c, idx)
+				combis = append(combis, c)
+			}
+			// Increment indices like an odometer
+			for i := len(idx) - 1; ; i-- {
+				if i < 0 {
+					break Loop
+				}
+				idx[i]++
+				if idx[i] < len(m.Rows[names[i]].Values) {
+					break
+				}
+				idx[i] = 0
+			}
+		}
+	}
+
+	jobs := len(combis)
+	if m.Include != nil {
+		for _, inc := range m.Include.Combinations {
+			merged := false
+			for _, c := range combis {
+				if matchMatrixCombination(m, names, c, inc) {
+					merged = true
+					break
+				}
+			}
+			if !merged {
+				jobs++
+			}
+		}
+	}
+
+	if jobs > maxMatrixJobs {
+		rule.errorf(
+			m.Pos,
+			"matrix generates %d jobs. it exceeds the limit of %d jobs per workflow run. reduce the number of combinations with \"exclude\" or split the matrix into multiple jobs",
+			jobs,
+			maxMatrixJobs,
+		)
+	}
+}
+
+// estimateNumberOfJobs is used when the matrix is too large to enumerate its combinations. It
+// computes the lower bound of the number of jobs assuming that combinations removed by each
+// "exclude" item never overlap.
+func (rule *RuleMatrix) estimateNumberOfJobs(m *Matrix, names []string) {
+	// Use float to avoid overflow
+	total := 1.0
+	for _, n := range names {
+		total *= float64(len(m.Rows[n].Values))
+	}
+
+	jobs := total
+	if m.Exclude != nil {
+		for _, ex := range m.Exclude.Combinations {
+			if len(ex.Assigns) == 0 {
+				continue
+			}
+			removed := total
+			for k, a := range ex.Assigns {
+				r, ok := m.Rows[k]
+				if !ok || !findYAMLValueInArray(r.Values, a.Value) {
+					removed = 0
+					break
+				}
+				removed /= float64(len(r.Values))
+			}
+			jobs -= removed
+		}
+	}
+
+	if jobs > maxMatrixJobs {
+		rule.errorf(
+			m.Pos,
+			"matrix generates at least %.0f jobs. it exceeds the limit of %d jobs per workflow run. reduce the number of combinations with \"exclude\" or split the matrix into multiple jobs",
+			jobs,
+			maxMatrixJobs,
+		)
+	}
+}
+
+// isMatrixCombinationExcluded returns if the combination represented by indices of row values is
+// excluded. Note that an exclude item which has a key not in matrix rows does not match to any
+// combination. It is reported by checkExclude.
+func isMatrixCombinationExcluded(m *Matrix, names []string, idx []int, excludes []*MatrixCombination) bool {
+Loop:
+	for _, ex := range excludes {
+		if len(ex.Assigns) == 0 {
+			continue
+		}
+		for k := range ex.Assigns {
+			if _, ok := m.Rows[k]; !ok {
+				continue Loop
+			}
+		}
+		if matchMatrixCombination(m, names, idx, ex) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchMatrixCombination returns if the combination represented by indices of row values matches to
+// the given combination. Keys which are not in matrix rows are ignored.
+func matchMatrixCombination(m *Matrix, names []string, idx []int, c *MatrixCombination) bool {
+	for i, n := range names {
+		a, ok := c.Assigns[n]
+		if !ok {
+			continue
+		}
+		if !m.Rows[n].Values[idx[i]].Equals(a.Value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,161 @@
+package actionlint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RuleMatrixAugment is a rule to detect items in "include" section of matrix whose values all match
+// existing matrix combinations. Such item does not add a new combination. It is merged into the
+// existing combinations and only adds extra values to them. People often misunderstand it and
+// expect a new job. Since this is not an error of the workflow, this rule is disabled by default.
+// https://docs.github.com/en/actions/using-jobs/using-a-matrix-for-your-jobs#expanding-or-adding-matrix-configurations
+type RuleMatrixAugment struct {
+	RuleBase
+}
+
+// NewRuleMatrixAugment creates new RuleMatrixAugment instance.
+func NewRuleMatrixAugment() *RuleMatrixAugment {
+	return &RuleMatrixAugment{
+		RuleBase: RuleBase{name: "matrix-augment"},
+	}
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleMatrixAugment) VisitJobPre(n *Job) error {
+	if n.Strategy == nil || n.Strategy.Matrix == nil || n.Strategy.Matrix.Expression != nil {
+		return nil
+	}
+	m := n.Strategy.Matrix
+	if m.Include == nil || m.Include.Expression != nil || len(m.Rows) == 0 {
+		return nil
+	}
+	if m.Exclude != nil && m.Exclude.ContainsExpression() {
+		return nil // Remaining combinations cannot be known statically
+	}
+
+	names := make([]string, 0, len(m.Rows))
+	for n, r := range m.Rows {
+		if r.Expression != nil {
+			return nil // Values of the row are determined dynamically
+		}
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, inc := range m.Include.Combinations {
+		rule.checkInclude(m, names, inc)
+	}
+	return nil
+}
+
+func (rule *RuleMatrixAugment) checkInclude(m *Matrix, names []string, inc *MatrixCombination) {
+	if inc.Expression != nil || len(inc.Assigns) == 0 {
+		return
+	}
+
+	// Indices of the values in rows. -1 means that the row is not assigned by the item.
+	idx := make([]int, len(names))
+	matched := []string{}
+	for i, n := range names {
+		idx[i] = -1
+		a, ok := inc.Assigns[n]
+		if !ok {
+			continue
+		}
+		if s, ok := a.Value.(*RawYAMLString); ok && strings.Contains(s.Value, "${{") {
+			return // The value is determined dynamically
+		}
+		for j, v := range m.Rows[n].Values {
+			if v.Equals(a.Value) {
+				idx[i] = j
+				break
+			}
+		}
+		if idx[i] < 0 {
+			return // The item has a new value so it adds a new combination
+		}
+		matched = append(matched, fmt.Sprintf("%s: %s", n, a.Value.String()))
+	}
+	if len(matched) == 0 || !hasRemainingMatrixCombination(m, names, idx) {
+		return
+	}
+
+	extra := []string{}
+	var pos *Pos
+	for n, a := range inc.Assigns {
+		if _, ok := m.Rows[n]; !ok {
+			extra = append(extra, n)
+		}
+		if pos == nil || a.Key.Pos.Line < pos.Line || a.Key.Pos.Line == pos.Line && a.Key.Pos.Col < pos.Col {
+			pos = a.Key.Pos
+		}
+	}
+
+	cond := strings.Join(matched, ", ")
+	if len(extra) == 0 {
+		rule.errorf(
+			pos,
+			"item in \"include\" section matches existing matrix combinations with %s. it adds neither a new combination nor new values. remove the item or change its values to add a new combination",
+			cond,
+		)
+		return
+	}
+	rule.errorf(
+		pos,
+		"item in \"include\" section matches existing matrix combinations with %s. it does not add a new combination but adds %s to the existing combinations. to add a new combination, put a value which does not exist in the matrix",
+		cond,
+		sortedQuotes(extra),
+	)
+}
+
+// hasRemainingMatrixCombination returns if some combination matching to the given indices of row
+// values remains after applying "exclude" section. Rows whose indices are -1 can take any value.
+// When there are too many combinations to enumerate, it assumes some combination remains.
+func hasRemainingMatrixCombination(m *Matrix, names []string, idx []int) bool {
+	free := []int{}
+	total := 1
+	for i, n := range names {
+		if idx[i] >= 0 {
+			continue
+		}
+		l := len(m.Rows[n].Values)
+		if l == 0 {
+			return false // No combination is generated
+		}
+		total *= l
+		if total > maxEnumeratedMatrixCombinations {
+			return true
+		}
+		free = append(free, i)
+	}
+
+	if m.Exclude == nil || len(m.Exclude.Combinations) == 0 {
+		return true
+	}
+
+	c := make([]int, len(idx))
+	copy(c, idx)
+	for _, i := range free {
+		c[i] = 0
+	}
+	for {
+		if !isMatrixCombinationExcluded(m, names, c, m.Exclude.Combinations) {
+			return true
+		}
+		// Increment indices of free rows like an odometer
+		i := len(free) - 1
+		for ; i >= 0; i-- {
+			r := free[i]
+			c[r]++
+			if c[r] < len(m.Rows[names[r]].Values) {
+				break
+			}
+			c[r] = 0
+		}
+		if i < 0 {
+			return false
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package actionlint
+
+import "testing"
+
+func TestRuleMatrixAugmentHasRemainingCombination(t *testing.T) {
+	str := func(s string) RawYAMLValue { return &RawYAMLString{Value: s, pos: &Pos{}} }
+	row := func(name string, vs ...string) *MatrixRow {
+		r := &MatrixRow{Name: &String{Value: name, Pos: &Pos{}}}
+		for _, v := range vs {
+			r.Values = append(r.Values, str(v))
+		}
+		return r
+	}
+	exclude := func(kvs ...string) *MatrixCombination {
+		c := &MatrixCombination{Assigns: map[string]*MatrixAssign{}}
+		for i := 0; i < len(kvs); i += 2 {
+			c.Assigns[kvs[i]] = &MatrixAssign{Key: &String{Value: kvs[i], Pos: &Pos{}}, Value: str(kvs[i+1])}
+		}
+		return c
+	}
+
+	testCases := []struct {
+		what     string
+		rows     []*MatrixRow
+		excludes []*MatrixCombination
+		idx      []int
+		want     bool
+	}{
+		{"no exclude", []*MatrixRow{row("os", "linux", "mac"), row("node", "18", "20")}, nil, []int{-1, 0}, true},
+		{"empty row", []*MatrixRow{row("os", "linux"), row("node")}, nil, []int{0, -1}, false},
+		{
+			"all combinations excluded",
+			[]*MatrixRow{row("os", "linux", "mac"), row("node", "18", "20")},
+			[]*MatrixCombination{exclude("os", "mac")},
+			[]int{1, -1},
+			false,
+		},
+		{
+			"some combination remains",
+			[]*MatrixRow{row("os", "linux", "mac"), row("node", "18", "20")},
+			[]*MatrixCombination{exclude("os", "mac", "node", "18")},
+			[]int{-1, 1},
+			true,
+		},
+		{
+			"all free combinations excluded one by one",
+			[]*MatrixRow{row("os", "linux", "mac"), row("node", "18", "20")},
+			[]*MatrixCombination{exclude("os", "linux", "node", "18"), exclude("os", "linux", "node", "20")},
+			[]int{0, -1},
+			false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			m := &Matrix{Rows: map[string]*MatrixRow{}}
+			names := []string{}
+			for _, r := range tc.rows {
+				m.Rows[r.Name.Value] = r
+				names = append(names, r.Name.Value)
+			}
+			if tc.excludes != nil {
+				m.Exclude = &MatrixCombinations{Combinations: tc.excludes}
+			}
+			if have := hasRemainingMatrixCombination(m, names, tc.idx); have != tc.want {
+				t.Fatalf("wanted %v but got %v", tc.want, have)
+			}
+		})
+	}
+}
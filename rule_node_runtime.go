@@ -0,0 +1,63 @@
+package actionlint
+
+import (
+	"strings"
+)
+
+// deprecatedNodeRuntimes is a set of Node.js runtimes of JavaScript actions which are deprecated on
+// GitHub-hosted runners.
+// https://github.blog/changelog/2023-09-22-github-actions-transitioning-from-node-16-to-node-20/
+var deprecatedNodeRuntimes = map[string]struct{}{
+	"node12": {},
+	"node16": {},
+}
+
+// latestNodeRuntime is the Node.js runtime recommended for JavaScript actions.
+const latestNodeRuntime = "node20"
+
+// RuleNodeRuntime is a rule to detect actions whose versions run on deprecated Node.js runtimes
+// like node16. Runtimes are checked with "runs.using" in the popular actions dataset and actions
+// metadata file. Composite actions and Docker actions are not checked. Since the runtimes are known
+// only for some versions of popular actions, this rule is disabled by default.
+// https://docs.github.com/en/actions/creating-actions/metadata-syntax-for-github-actions#runs-for-javascript-actions
+type RuleNodeRuntime struct {
+	RuleBase
+	actionsMeta map[string]*ActionMetadata
+}
+
+// NewRuleNodeRuntime creates new RuleNodeRuntime instance.
+func NewRuleNodeRuntime() *RuleNodeRuntime {
+	return &RuleNodeRuntime{
+		RuleBase: RuleBase{name: "node-runtime"},
+	}
+}
+
+// SetActionsMetadata sets additional metadata of actions. Runtimes in the metadata are prioritized
+// over the popular actions dataset.
+func (rule *RuleNodeRuntime) SetActionsMetadata(m map[string]*ActionMetadata) {
+	rule.actionsMeta = m
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RuleNodeRuntime) VisitStep(n *Step) error {
+	e, ok := n.Exec.(*ExecAction)
+	if !ok || e.Uses == nil || strings.Contains(e.Uses.Value, "${{") {
+		return nil
+	}
+	meta, ok := findRepoActionMetadata(e.Uses.Value, rule.actionsMeta)
+	if !ok || meta.Runs == nil {
+		return nil
+	}
+	using := strings.ToLower(meta.Runs.Using)
+	if _, ok := deprecatedNodeRuntimes[using]; !ok {
+		return nil
+	}
+	rule.errorf(
+		e.Uses.Pos,
+		"action %q runs on %q runtime which is deprecated. GitHub-hosted runners run the action on newer Node.js forcibly and the action may not work. upgrade the action to a newer version which runs on %q",
+		e.Uses.Value,
+		using,
+		latestNodeRuntime,
+	)
+	return nil
+}
@@ -0,0 +1,93 @@
+package actionlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleNodeRuntime(t *testing.T) {
+	testCases := []struct {
+		what string
+		step string
+		want string
+	}{
+		{
+			what: "node12",
+			step: "uses: actions/checkout@v1",
+			want: `action "actions/checkout@v1" runs on "node12" runtime which is deprecated`,
+		},
+		{
+			what: "node16",
+			step: "uses: actions/setup-node@v3",
+			want: `action "actions/setup-node@v3" runs on "node16" runtime which is deprecated`,
+		},
+		{
+			what: "docker action",
+			step: "uses: github/super-linter@v4",
+		},
+		{
+			what: "runtime unknown",
+			step: "uses: peaceiris/actions-gh-pages@v3",
+		},
+		{
+			what: "unknown action",
+			step: "uses: my-org/my-action@v1",
+		},
+		{
+			what: "local action",
+			step: "uses: ./path/to/action",
+		},
+		{
+			what: "run step",
+			step: "run: echo hello",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			src := "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - " + tc.step + "\n"
+			errs, err := RunRule(NewRuleNodeRuntime(), []byte(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("wanted 1 error but got %v", errs)
+			}
+			if !strings.Contains(errs[0].Message, tc.want) {
+				t.Fatalf("error message %q does not contain %q", errs[0].Message, tc.want)
+			}
+			if errs[0].Line != 6 || errs[0].Column != 15 {
+				t.Fatalf("error should be reported at \"uses\" value but got line %d, col %d", errs[0].Line, errs[0].Column)
+			}
+		})
+	}
+}
+
+func TestRuleNodeRuntimeActionsMetadata(t *testing.T) {
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: my-org/my-action@v1
+      - uses: actions/checkout@v1
+`
+	r := NewRuleNodeRuntime()
+	r.SetActionsMetadata(map[string]*ActionMetadata{
+		"my-org/my-action":    {Name: "My action", Runs: &ActionMetadataRuns{Using: "node16"}},
+		"actions/checkout@v1": {Name: "Checkout", Runs: &ActionMetadataRuns{Using: "node20"}},
+	})
+	errs, err := RunRule(r, []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, `action "my-org/my-action@v1" runs on "node16" runtime`) {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+}
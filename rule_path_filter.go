@@ -0,0 +1,109 @@
+package actionlint
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// RulePathFilter is a rule to detect path filters of events which match no file in the repository.
+// The literal prefix of each filter like "src/" of "src/**/*.ts" is checked against the files in
+// the repository. Such filters are likely typos or stale filters which were not updated after
+// moving or removing the files. Since files may be added later and filters may be written for
+// files in the future, this rule is disabled by default. This rule checks nothing when the
+// repository is unknown, for example on linting stdin.
+// https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#onpushpull_requestpull_request_targetpathspaths-ignore
+type RulePathFilter struct {
+	RuleBase
+	proj *Project
+}
+
+// NewRulePathFilter creates new RulePathFilter instance.
+func NewRulePathFilter() *RulePathFilter {
+	return &RulePathFilter{
+		RuleBase: RuleBase{name: "path-filter"},
+	}
+}
+
+// SetProject sets the project where files matched by path filters are searched. When it is nil,
+// this rule checks nothing.
+func (rule *RulePathFilter) SetProject(p *Project) {
+	rule.proj = p
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RulePathFilter) VisitWorkflowPre(n *Workflow) error {
+	if rule.proj == nil {
+		return nil
+	}
+	for _, e := range n.On {
+		if e, ok := e.(*WebhookEvent); ok {
+			rule.checkFilter(e.Hook.Value, "paths", e.Paths)
+			rule.checkFilter(e.Hook.Value, "paths-ignore", e.PathsIgnore)
+		}
+	}
+	return nil
+}
+
+func (rule *RulePathFilter) checkFilter(event, filter string, pats []*String) {
+	for _, p := range pats {
+		if strings.Contains(p.Value, "${{") {
+			continue
+		}
+		if reason := rule.missingReason(p.Value); reason != "" {
+			rule.errorf(
+				p.Pos,
+				"path %q at %q filter of %q event matches no file in the repository since %s. the filter may be a typo or stale",
+				p.Value,
+				filter,
+				event,
+				reason,
+			)
+		}
+	}
+}
+
+// missingReason returns why the glob pattern matches no file. It returns an empty string when the
+// pattern may match some file.
+func (rule *RulePathFilter) missingReason(pat string) string {
+	prefix, literal := globLiteralPrefix(pat)
+	prefix = strings.TrimPrefix(prefix, "./")
+	if prefix == "" || strings.HasPrefix(prefix, "/") {
+		return ""
+	}
+	root := rule.proj.RootDir()
+
+	if literal {
+		if _, err := os.Stat(filepath.Join(root, filepath.FromSlash(strings.TrimSuffix(prefix, "/")))); err != nil {
+			return "file or directory \"" + prefix + "\" does not exist"
+		}
+		return ""
+	}
+
+	dir, part := path.Split(prefix)
+	if dir != "" {
+		if s, err := os.Stat(filepath.Join(root, filepath.FromSlash(dir))); err != nil || !s.IsDir() {
+			return "directory \"" + dir + "\" does not exist"
+		}
+	}
+	if part == "" {
+		return ""
+	}
+
+	entries, err := ioutil.ReadDir(filepath.Join(root, filepath.FromSlash(dir)))
+	if err != nil {
+		rule.debug("Could not read directory %q: %v", dir, err)
+		return ""
+	}
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), part) {
+			return ""
+		}
+	}
+	if dir == "" {
+		dir = "./"
+	}
+	return "no file or directory whose name starts with \"" + part + "\" exists in \"" + dir + "\""
+}
@@ -0,0 +1,102 @@
+package actionlint
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRulePathFilter(t *testing.T) {
+	proj := &Project{root: filepath.Join("testdata", "examples")}
+
+	testCases := []struct {
+		what   string
+		filter string
+		want   string
+	}{
+		{
+			what:   "existing directory",
+			filter: "paths: ['.github/actions/**']",
+		},
+		{
+			what:   "existing file",
+			filter: "paths: ['.github/actions/my-action/action.yaml']",
+		},
+		{
+			what:   "existing directory with trailing slash",
+			filter: "paths: ['.github/actions/']",
+		},
+		{
+			what:   "existing prefix of file name",
+			filter: "paths: ['.github/actions/my-*/index.js']",
+		},
+		{
+			what:   "leading wildcard",
+			filter: "paths: ['**/*.js']",
+		},
+		{
+			what:   "expression",
+			filter: "paths: ['${{ github.ref }}/**']",
+		},
+		{
+			what:   "missing directory",
+			filter: "paths: ['src/**']",
+			want:   `path "src/**" at "paths" filter of "push" event matches no file in the repository since directory "src/" does not exist`,
+		},
+		{
+			what:   "missing nested directory",
+			filter: "paths: ['.github/action/**']",
+			want:   `since directory ".github/action/" does not exist`,
+		},
+		{
+			what:   "missing file",
+			filter: "paths-ignore: ['.github/actions/my-action/README.md']",
+			want:   `path ".github/actions/my-action/README.md" at "paths-ignore" filter of "push" event matches no file in the repository since file or directory ".github/actions/my-action/README.md" does not exist`,
+		},
+		{
+			what:   "missing prefix of file name",
+			filter: "paths: ['.github/actions/other-*/**']",
+			want:   `since no file or directory whose name starts with "other-" exists in ".github/actions/"`,
+		},
+		{
+			what:   "negated missing directory",
+			filter: "paths: ['.github/**', '!src/**']",
+			want:   `path "!src/**" at "paths" filter`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			src := "on:\n  push:\n    " + tc.filter + "\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n"
+			r := NewRulePathFilter()
+			r.SetProject(proj)
+			errs, err := RunRule(r, []byte(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("wanted 1 error but got %v", errs)
+			}
+			if !strings.Contains(errs[0].Message, tc.want) {
+				t.Fatalf("error message %q does not contain %q", errs[0].Message, tc.want)
+			}
+		})
+	}
+}
+
+func TestRulePathFilterWithoutProject(t *testing.T) {
+	src := "on:\n  push:\n    paths: ['src/**']\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n"
+	errs, err := RunRule(NewRulePathFilter(), []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) > 0 {
+		t.Fatal("nothing should be checked when project is unknown:", errs)
+	}
+}
@@ -1,5 +1,9 @@
 package actionlint
 
+import (
+	"sort"
+)
+
 var allPermissionScopes = map[string]struct{}{
 	"actions":             {},
 	"checks":              {},
@@ -15,10 +19,13 @@ var allPermissionScopes = map[string]struct{}{
 	"statuses":            {},
 }
 
-// RulePermissions is a rule checker to check permission configurations in a workflow.
+// RulePermissions is a rule checker to check permission configurations in a workflow. It also checks
+// that permissions requested by local reusable workflows are granted by the jobs calling them.
 // https://docs.github.com/en/actions/security-guides/automatic-token-authentication#permissions-for-the-github_token
 type RulePermissions struct {
 	RuleBase
+	cache         *LocalReusableWorkflowCache
+	workflowPerms *Permissions
 }
 
 // NewRulePermissions creates new RulePermissions instance.
@@ -28,18 +35,138 @@ func NewRulePermissions() *RulePermissions {
 	}
 }
 
+// SetLocalReusableWorkflowCache sets the cache of local reusable workflows. It is used for checking
+// permissions requested by the reusable workflows are granted by their callers. When it is not set,
+// permissions of reusable workflows are not checked.
+func (rule *RulePermissions) SetLocalReusableWorkflowCache(c *LocalReusableWorkflowCache) {
+	rule.cache = c
+}
+
 // VisitJobPre is callback when visiting Job node before visiting its children.
 func (rule *RulePermissions) VisitJobPre(n *Job) error {
 	rule.checkPermissions(n.Permissions)
+	if n.WorkflowCall != nil && n.WorkflowCall.Uses != nil {
+		p := n.Permissions
+		if p == nil {
+			p = rule.workflowPerms
+		}
+		rule.checkReusableWorkflowPermissions(n.WorkflowCall.Uses, p)
+	}
 	return nil
 }
 
 // VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
 func (rule *RulePermissions) VisitWorkflowPre(n *Workflow) error {
 	rule.checkPermissions(n.Permissions)
+	rule.workflowPerms = n.Permissions
 	return nil
 }
 
+// VisitWorkflowPost is callback when visiting Workflow node after visiting its children.
+func (rule *RulePermissions) VisitWorkflowPost(n *Workflow) error {
+	rule.workflowPerms = nil
+	return nil
+}
+
+// checkReusableWorkflowPermissions checks permissions requested by the local reusable workflow are
+// granted by the caller. Permissions of GITHUB_TOKEN in the called workflow can only be downgraded
+// from the caller. When the called workflow requests more permissions, the workflow run fails.
+// https://docs.github.com/en/actions/using-workflows/reusing-workflows#access-and-permissions
+func (rule *RulePermissions) checkReusableWorkflowPermissions(uses *String, granted *Permissions) {
+	if rule.cache == nil || granted == nil {
+		return // When no permission is set to the caller, default permissions are used. They depend on repository settings
+	}
+	w, err := rule.cache.FindWorkflow(uses.Value)
+	if err != nil {
+		rule.debug("Could not check permissions of reusable workflow %q: %v", uses.Value, err)
+		return
+	}
+	if w == nil {
+		return
+	}
+
+	requested := map[string]string{}
+	request := func(p *Permissions) {
+		if p == nil {
+			return
+		}
+		if p.All != nil {
+			v := permissionOfAllScopes(p.All.Value)
+			for s := range allPermissionScopes {
+				if permissionLevel(v) > permissionLevel(requested[s]) {
+					requested[s] = v
+				}
+			}
+			return
+		}
+		for s, p := range p.Scopes {
+			if p.Value != nil && permissionLevel(p.Value.Value) > permissionLevel(requested[s]) {
+				requested[s] = p.Value.Value
+			}
+		}
+	}
+	request(w.Permissions)
+	for _, j := range w.Jobs {
+		request(j.Permissions)
+	}
+
+	scopes := make([]string, 0, len(requested))
+	for s := range requested {
+		scopes = append(scopes, s)
+	}
+	sort.Strings(scopes)
+
+	for _, s := range scopes {
+		r := requested[s]
+		g, pos := grantedPermission(granted, s)
+		if permissionLevel(r) <= permissionLevel(g) {
+			continue
+		}
+		rule.errorf(
+			pos,
+			"reusable workflow %q requests %q permission of scope %q but the caller grants only %q. permissions of GITHUB_TOKEN in reusable workflow can only be downgraded from the caller so running the workflow fails",
+			uses.Value,
+			r,
+			s,
+			g,
+		)
+	}
+}
+
+func permissionLevel(v string) int {
+	switch v {
+	case "write":
+		return 2
+	case "read":
+		return 1
+	default:
+		return 0
+	}
+}
+
+func permissionOfAllScopes(v string) string {
+	switch v {
+	case "write-all":
+		return "write"
+	case "read-all":
+		return "read"
+	default:
+		return "none"
+	}
+}
+
+// grantedPermission returns the permission of the scope granted by the permissions and the position
+// where the permission is granted. Scopes which are not listed in the permissions are "none".
+func grantedPermission(p *Permissions, scope string) (string, *Pos) {
+	if p.All != nil {
+		return permissionOfAllScopes(p.All.Value), p.All.Pos
+	}
+	if s, ok := p.Scopes[scope]; ok && s.Value != nil {
+		return s.Value.Value, s.Value.Pos
+	}
+	return "none", p.Pos
+}
+
 func (rule *RulePermissions) checkPermissions(p *Permissions) {
 	if p == nil {
 		return
@@ -64,7 +191,11 @@ func (rule *RulePermissions) checkPermissions(p *Permissions) {
 			rule.errorf(p.Name.Pos, "unknown permission scope %q. all available permission scopes are %s", n, sortedQuotes(ss))
 		}
 		switch p.Value.Value {
-		case "read", "write", "none":
+		case "read":
+			if n == "id-token" {
+				rule.errorf(p.Value.Pos, "\"read\" is not available for permission of scope \"id-token\" since the scope only allows fetching an OpenID Connect token. available values are \"write\" or \"none\"")
+			}
+		case "write", "none":
 			// OK
 		default:
 			rule.errorf(p.Value.Pos, "%q is invalid for permission of scope %q. available values are \"read\", \"write\" or \"none\"", p.Value.Value, n)
@@ -0,0 +1,143 @@
+package actionlint
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Commands in scripts which enable pipefail option like `set -o pipefail` or `set -euo pipefail`.
+var reSetPipefail = regexp.MustCompile(`\bset\s+(-[a-zA-Z]*o\s+pipefail|-o\s+pipefail|-[a-zA-Z]+\s+-o\s+pipefail)\b`)
+
+// Patterns in case statement like `foo|bar)`. '|' in them is not a pipe.
+var reCasePattern = regexp.MustCompile(`^\s*\(?[^\s()|]+(\|[^\s()|]+)+\)`)
+
+// RulePipefail is a rule to detect pipelines in scripts at 'run:' which run without pipefail
+// option. When 'shell:' is not specified, the script runs with `bash -e {0}` on Linux and macOS. And
+// `sh -e {0}` is used for 'shell: sh'. Only 'shell: bash' enables pipefail option. Without the
+// option, failures of commands in the middle of pipeline are ignored. Since this is advisory, this
+// rule is disabled by default.
+// https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#exit-codes-and-error-action-preference
+type RulePipefail struct {
+	RuleBase
+	shell shellResolver
+}
+
+// NewRulePipefail creates new RulePipefail instance.
+func NewRulePipefail() *RulePipefail {
+	return &RulePipefail{
+		RuleBase: RuleBase{name: "pipefail"},
+	}
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RulePipefail) VisitWorkflowPre(n *Workflow) error {
+	rule.shell.enterWorkflow(n)
+	return nil
+}
+
+// VisitWorkflowPost is callback when visiting Workflow node after visiting its children.
+func (rule *RulePipefail) VisitWorkflowPost(n *Workflow) error {
+	rule.shell.leaveWorkflow()
+	return nil
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RulePipefail) VisitJobPre(n *Job) error {
+	rule.shell.enterJob(n)
+	return nil
+}
+
+// VisitJobPost is callback when visiting Job node after visiting its children.
+func (rule *RulePipefail) VisitJobPost(n *Job) error {
+	rule.shell.leaveJob()
+	return nil
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RulePipefail) VisitStep(n *Step) error {
+	run, ok := n.Exec.(*ExecRun)
+	if !ok || run.Run == nil {
+		return nil
+	}
+
+	var desc string
+	if shell := rule.shell.resolve(run); shell == nil {
+		if rule.shell.name(run) != "bash" {
+			return nil // Default shell on Windows is PowerShell
+		}
+		desc = `default shell "bash -e {0}"`
+	} else {
+		s := shell.Value
+		if strings.Contains(s, "${{") || strings.Contains(s, "pipefail") {
+			return nil
+		}
+		switch name := rule.shell.name(run); {
+		case s == "sh":
+			desc = `shell "sh" which runs "sh -e {0}"`
+		case s == "bash":
+			return nil // "bash" runs "bash --noprofile --norc -eo pipefail {0}"
+		case name == "bash" || name == "sh":
+			desc = `shell "` + s + `"` // Custom shell command does not enable pipefail
+		default:
+			return nil // Other shells are not POSIX shells
+		}
+	}
+
+	if reSetPipefail.MatchString(run.Run.Value) {
+		return nil
+	}
+
+	for i, line := range strings.Split(run.Run.Value, "\n") {
+		col := findPipe(line)
+		if col < 0 {
+			continue
+		}
+		rule.errorf(
+			run.RunPos,
+			"pipeline at line %d, col %d in this script does not fail when a command other than the last one fails since %s does not enable \"pipefail\" option. use \"shell: bash\" or add \"set -o pipefail\" to the script",
+			i+1,
+			col+1,
+			desc,
+		)
+	}
+
+	return nil
+}
+
+// findPipe finds the index of the first pipe operator '|' in the line of shell script. Logical
+// operator '||' and characters in quotes or comments are ignored. It returns -1 when no pipe is
+// found.
+func findPipe(line string) int {
+	if reCasePattern.MatchString(line) {
+		return -1
+	}
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		if quote != 0 {
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\\':
+			i++
+		case '\'', '"':
+			quote = c
+		case '#':
+			if i == 0 || line[i-1] == ' ' || line[i-1] == '\t' {
+				return -1 // Comment
+			}
+		case '|':
+			if i+1 < len(line) && line[i+1] == '|' {
+				i++ // Logical OR
+				continue
+			}
+			return i
+		}
+	}
+	return -1
+}
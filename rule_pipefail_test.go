@@ -0,0 +1,31 @@
+package actionlint
+
+import "testing"
+
+func TestRulePipefailFindPipe(t *testing.T) {
+	testCases := []struct {
+		line string
+		want int
+	}{
+		{"cat foo | grep bar", 8},
+		{"cat foo|grep bar", 7},
+		{"make |& tee log.txt", 5},
+		{"test -f foo || exit 1", -1},
+		{"echo 'a | b'", -1},
+		{`echo "a | \" b"`, -1},
+		{`echo \| foo`, -1},
+		{"echo foo # a | b", -1},
+		{"echo foo#bar | cat", 13},
+		{"  foo|bar)", -1},
+		{"  (foo|bar) echo hi ;;", -1},
+		{"", -1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.line, func(t *testing.T) {
+			if have := findPipe(tc.line); have != tc.want {
+				t.Fatalf("wanted %d but got %d", tc.want, have)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package actionlint
+
+// RulePushFilter is a rule to detect filters of push event which are likely misunderstood. Path
+// filters are not evaluated for pushes of tags. So when 'paths' or 'paths-ignore' is specified with
+// 'tags' or 'tags-ignore', the workflow runs on every push of the matching tags regardless of the
+// changed files. Since combining them is sometimes intentional, this rule is disabled by default.
+// https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#onpushpull_requestpull_request_targetpathspaths-ignore
+type RulePushFilter struct {
+	RuleBase
+}
+
+// NewRulePushFilter creates new RulePushFilter instance.
+func NewRulePushFilter() *RulePushFilter {
+	return &RulePushFilter{
+		RuleBase: RuleBase{name: "push-filter"},
+	}
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RulePushFilter) VisitWorkflowPre(n *Workflow) error {
+	for _, e := range n.On {
+		if e, ok := e.(*WebhookEvent); ok && e.Hook.Value == "push" {
+			rule.checkPushEvent(e)
+		}
+	}
+	return nil
+}
+
+func (rule *RulePushFilter) checkPushEvent(e *WebhookEvent) {
+	if e.Tags == nil && e.TagsIgnore == nil {
+		return
+	}
+
+	name, paths := "paths", e.Paths
+	if paths == nil {
+		name, paths = "paths-ignore", e.PathsIgnore
+	}
+	if paths == nil {
+		return
+	}
+	pos := e.Pos
+	if len(paths) > 0 {
+		pos = paths[0].Pos
+	}
+
+	if e.Branches == nil && e.BranchesIgnore == nil {
+		rule.errorf(
+			pos,
+			"%q filter of \"push\" event is never evaluated. only tag filter is specified so the workflow runs only on pushes of tags, but path filters are not evaluated for pushes of tags",
+			name,
+		)
+		return
+	}
+
+	rule.errorf(
+		pos,
+		"%q filter of \"push\" event is only applied to pushes of branches. path filters are not evaluated for pushes of tags so the workflow runs on every push of tags matching the tag filter regardless of changed files",
+		name,
+	)
+}
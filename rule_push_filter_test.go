@@ -0,0 +1,66 @@
+package actionlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRulePushFilter(t *testing.T) {
+	testCases := []struct {
+		what   string
+		filter string
+		want   string
+	}{
+		{
+			what:   "paths with tags",
+			filter: "tags: ['v*']\n    paths: ['src/**']",
+			want:   `"paths" filter of "push" event is never evaluated`,
+		},
+		{
+			what:   "paths-ignore with tags-ignore",
+			filter: "tags-ignore: ['v*']\n    paths-ignore: ['docs/**']",
+			want:   `"paths-ignore" filter of "push" event is never evaluated`,
+		},
+		{
+			what:   "paths with branches and tags",
+			filter: "branches: [main]\n    tags: ['v*']\n    paths: ['src/**']",
+			want:   `"paths" filter of "push" event is only applied to pushes of branches`,
+		},
+		{
+			what:   "paths with branches",
+			filter: "branches: [main]\n    paths: ['src/**']",
+		},
+		{
+			what:   "tags only",
+			filter: "tags: ['v*']",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			src := "on:\n  push:\n    " + tc.filter + `
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+`
+			errs, err := RunRule(NewRulePushFilter(), []byte(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("wanted 1 error but got %v", errs)
+			}
+			if !strings.Contains(errs[0].Message, tc.want) {
+				t.Fatalf("error message %q does not contain %q", errs[0].Message, tc.want)
+			}
+		})
+	}
+}
@@ -6,32 +6,13 @@ import (
 	"sync"
 )
 
-type shellIsPythonKind int
-
-const (
-	shellIsPythonKindUnspecified shellIsPythonKind = iota
-	shellIsPythonKindPython
-	shellIsPythonKindNotPython
-)
-
-func getShellIsPythonKind(shell *String) shellIsPythonKind {
-	if shell == nil {
-		return shellIsPythonKindUnspecified
-	}
-	if shell.Value == "python" {
-		return shellIsPythonKindPython
-	}
-	return shellIsPythonKindNotPython
-}
-
 // RulePyflakes is a rule to check Python scripts at 'run:' using pyflakes.
 // https://github.com/PyCQA/pyflakes
 type RulePyflakes struct {
 	RuleBase
-	cmd                   *externalCommand
-	workflowShellIsPython shellIsPythonKind
-	jobShellIsPython      shellIsPythonKind
-	mu                    sync.Mutex
+	cmd   *externalCommand
+	shell shellResolver
+	mu    sync.Mutex
 }
 
 // NewRulePyflakes creates new RulePyflakes instance. Parameter executable can be command name
@@ -43,40 +24,34 @@ func NewRulePyflakes(executable string, proc *concurrentProcess) (*RulePyflakes,
 		return nil, err
 	}
 	r := &RulePyflakes{
-		RuleBase:              RuleBase{name: "pyflakes"},
-		cmd:                   cmd,
-		workflowShellIsPython: shellIsPythonKindUnspecified,
-		jobShellIsPython:      shellIsPythonKindUnspecified,
+		RuleBase: RuleBase{name: "pyflakes"},
+		cmd:      cmd,
 	}
 	return r, nil
 }
 
 // VisitJobPre is callback when visiting Job node before visiting its children.
 func (rule *RulePyflakes) VisitJobPre(n *Job) error {
-	if n.Defaults != nil && n.Defaults.Run != nil {
-		rule.jobShellIsPython = getShellIsPythonKind(n.Defaults.Run.Shell)
-	}
+	rule.shell.enterJob(n)
 	return nil
 }
 
 // VisitJobPost is callback when visiting Job node after visiting its children.
 func (rule *RulePyflakes) VisitJobPost(n *Job) error {
-	rule.jobShellIsPython = shellIsPythonKindUnspecified // reset
+	rule.shell.leaveJob()
 	return nil
 }
 
 // VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
 func (rule *RulePyflakes) VisitWorkflowPre(n *Workflow) error {
-	if n.Defaults != nil && n.Defaults.Run != nil {
-		rule.workflowShellIsPython = getShellIsPythonKind(n.Defaults.Run.Shell)
-	}
+	rule.shell.enterWorkflow(n)
 	return nil
 }
 
 // VisitWorkflowPost is callback when visiting Workflow node after visiting its children.
 func (rule *RulePyflakes) VisitWorkflowPost(n *Workflow) error {
-	rule.workflowShellIsPython = shellIsPythonKindUnspecified // reset
-	return rule.cmd.wait()                                    // Wait until all processes running for this rule
+	rule.shell.leaveWorkflow()
+	return rule.cmd.wait() // Wait until all processes running for this rule
 }
 
 // VisitStep is callback when visiting Step node.
@@ -86,7 +61,7 @@ func (rule *RulePyflakes) VisitStep(n *Step) error {
 		return nil
 	}
 
-	if !rule.isPythonShell(run) {
+	if rule.shell.name(run) != "python" {
 		return nil
 	}
 
@@ -94,18 +69,6 @@ func (rule *RulePyflakes) VisitStep(n *Step) error {
 	return nil
 }
 
-func (rule *RulePyflakes) isPythonShell(r *ExecRun) bool {
-	if r.Shell != nil {
-		return r.Shell.Value == "python"
-	}
-
-	if rule.jobShellIsPython != shellIsPythonKindUnspecified {
-		return rule.jobShellIsPython == shellIsPythonKindPython
-	}
-
-	return rule.workflowShellIsPython == shellIsPythonKindPython
-}
-
 func (rule *RulePyflakes) runPyflakes(src string, pos *Pos) {
 	src = sanitizeExpressionsInScript(src) // Defiend at rule_shellcheck.go
 	rule.debug("%s: Running %s for Python script:\n%s", pos, rule.cmd.exe, src)
@@ -181,7 +181,7 @@ func (rule *RuleRunnerLabel) tryToGetLabelsInMatrix(l string, m *Matrix) []*Stri
 	l = strings.TrimSpace(l)
 
 	// Only when the form of "${{...}}", evaluate the expression
-	if strings.Count(l, "${{") != 1 || strings.Count(l, "}}") != 1 || !strings.HasPrefix(l, "${{") || !strings.HasSuffix(l, "}}") {
+	if !isExprAssigned(l) {
 		return nil
 	}
 
@@ -209,10 +209,7 @@ func (rule *RuleRunnerLabel) tryToGetLabelsInMatrix(l string, m *Matrix) []*Stri
 	if m.Rows != nil {
 		if row, ok := m.Rows[prop]; ok {
 			for _, v := range row.Values {
-				if s, ok := v.(*RawYAMLString); ok && !strings.Contains(s.Value, "${{") {
-					// When the value does not have expression syntax ${{ }}
-					labels = append(labels, &String{s.Value, false, s.Pos()})
-				}
+				labels = appendLabelsInMatrixValue(labels, v)
 			}
 		}
 	}
@@ -221,10 +218,7 @@ func (rule *RuleRunnerLabel) tryToGetLabelsInMatrix(l string, m *Matrix) []*Stri
 		for _, combi := range m.Include.Combinations {
 			if combi.Assigns != nil {
 				if assign, ok := combi.Assigns[prop]; ok {
-					if s, ok := assign.Value.(*RawYAMLString); ok && !strings.Contains(s.Value, "${{") {
-						// When the value does not have expression syntax ${{ }}
-						labels = append(labels, &String{s.Value, false, s.Pos()})
-					}
+					labels = appendLabelsInMatrixValue(labels, assign.Value)
 				}
 			}
 		}
@@ -233,6 +227,25 @@ func (rule *RuleRunnerLabel) tryToGetLabelsInMatrix(l string, m *Matrix) []*Stri
 	return labels
 }
 
+// appendLabelsInMatrixValue appends labels in the value of matrix to the slice. The value is one
+// label or an array of labels like [self-hosted, linux].
+func appendLabelsInMatrixValue(labels []*String, v RawYAMLValue) []*String {
+	switch v := v.(type) {
+	case *RawYAMLString:
+		// When the value does not have expression syntax ${{ }}
+		if !strings.Contains(v.Value, "${{") {
+			labels = append(labels, &String{v.Value, false, false, 0, v.Pos()})
+		}
+	case *RawYAMLArray:
+		for _, e := range v.Elems {
+			if s, ok := e.(*RawYAMLString); ok {
+				labels = appendLabelsInMatrixValue(labels, s)
+			}
+		}
+	}
+	return labels
+}
+
 func (rule *RuleRunnerLabel) checkConflict(comp runnerOSCompat, label *String) bool {
 	for c, l := range rule.compats {
 		if c&comp == 0 {
@@ -203,7 +203,7 @@ func TestRuleRunnerLabelCheckLabels(t *testing.T) {
 			pos := &Pos{}
 			labels := make([]*String, 0, len(tc.labels))
 			for _, l := range tc.labels {
-				labels = append(labels, &String{l, false, pos})
+				labels = append(labels, &String{l, false, false, 0, pos})
 			}
 			node := &Job{
 				RunsOn: &Runner{
@@ -212,7 +212,7 @@ func TestRuleRunnerLabelCheckLabels(t *testing.T) {
 			}
 
 			if tc.matrix != nil {
-				n := &String{"os", false, pos}
+				n := &String{"os", false, false, 0, pos}
 				row := make([]RawYAMLValue, 0, len(tc.matrix))
 				for _, m := range tc.matrix {
 					row = append(row, &RawYAMLString{m, pos})
@@ -0,0 +1,110 @@
+package actionlint
+
+import (
+	"regexp"
+	"strings"
+)
+
+var reSecretName = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// RuleSecretName is a rule to check names of secrets follow the naming rules of GitHub. Names can
+// only contain alphanumeric characters and underscores, must not start with a number, and must not
+// start with the reserved "GITHUB_" prefix. Names are case-insensitive. This rule checks secrets
+// defined at "workflow_call" event and passed to reusable workflows. Secrets referenced in
+// expressions like secrets.foo are also checked when the checkExpr method is set to the expression
+// hook of RuleExpression.
+// https://docs.github.com/en/actions/security-guides/encrypted-secrets#naming-your-secrets
+type RuleSecretName struct {
+	RuleBase
+}
+
+// NewRuleSecretName creates new RuleSecretName instance.
+func NewRuleSecretName() *RuleSecretName {
+	return &RuleSecretName{
+		RuleBase: RuleBase{name: "secret-name"},
+	}
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RuleSecretName) VisitWorkflowPre(n *Workflow) error {
+	for _, e := range n.On {
+		if e, ok := e.(*WorkflowCallEvent); ok {
+			for _, s := range e.Secrets {
+				rule.checkDefinedName(s.Name, `defined at "workflow_call" event`)
+			}
+		}
+	}
+	return nil
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleSecretName) VisitJobPre(n *Job) error {
+	if n.WorkflowCall == nil || n.WorkflowCall.Uses == nil {
+		return nil
+	}
+	for _, s := range n.WorkflowCall.Secrets {
+		rule.checkDefinedName(s.Name, `passed to reusable workflow "`+n.WorkflowCall.Uses.Value+`"`)
+	}
+	return nil
+}
+
+func (rule *RuleSecretName) checkDefinedName(name *String, where string) {
+	if name == nil {
+		return
+	}
+	if !reSecretName.MatchString(name.Value) {
+		rule.errorf(
+			name.Pos,
+			"secret name %q %s is invalid. secret names can only contain alphanumeric characters or underscores, and must not start with a number",
+			name.Value,
+			where,
+		)
+		return
+	}
+	if hasReservedSecretPrefix(name.Value) {
+		rule.errorf(
+			name.Pos,
+			"secret name %q %s is invalid. secret names must not start with \"GITHUB_\" prefix since it is reserved by GitHub",
+			name.Value,
+			where,
+		)
+	}
+}
+
+// checkExpr checks names of secrets referenced in the expression like secrets.foo or
+// secrets['foo']. The line and col parameters are the position of the expression.
+func (rule *RuleSecretName) checkExpr(expr ExprNode, line, col int) {
+	VisitExprNode(expr, func(n, _ ExprNode, entering bool) {
+		if !entering {
+			return
+		}
+		name, ok := contextProperty(n, "secrets")
+		if !ok {
+			return
+		}
+		if d, ok := n.(*ObjectDerefNode); ok && d.orig != "" {
+			name = d.orig // Report the name as written in the source
+		}
+		t := n.Token()
+		pos := convertExprLineColToPos(t.Line, t.Column, line, col)
+		if !reSecretName.MatchString(name) {
+			rule.errorf(
+				pos,
+				"secret name %q referenced in expression is invalid. secret names can only contain alphanumeric characters or underscores, and must not start with a number so this secret is always empty",
+				name,
+			)
+			return
+		}
+		if hasReservedSecretPrefix(name) && !strings.EqualFold(name, "github_token") {
+			rule.errorf(
+				pos,
+				"secret %q referenced in expression is always empty since secret names starting with \"GITHUB_\" prefix are reserved by GitHub. only \"GITHUB_TOKEN\" is available",
+				name,
+			)
+		}
+	})
+}
+
+func hasReservedSecretPrefix(name string) bool {
+	return len(name) >= 7 && strings.EqualFold(name[:7], "github_")
+}
@@ -0,0 +1,93 @@
+package actionlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleSecretNameDefinitions(t *testing.T) {
+	src := `on:
+  workflow_call:
+    secrets:
+      deploy_key:
+        description: OK
+      npm-token:
+        description: Invalid character
+      1st_key:
+        description: Starts with number
+      GITHUB_APP_KEY:
+        description: Reserved prefix
+jobs:
+  call:
+    uses: owner/repo/.github/workflows/reusable.yaml@main
+    secrets:
+      DEPLOY_KEY: ${{ secrets.deploy_key }}
+      Github_Token: ${{ secrets.GITHUB_TOKEN }}
+`
+	errs, err := RunRule(NewRuleSecretName(), []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`:6:7: secret name "npm-token" defined at "workflow_call" event is invalid`,
+		`:8:7: secret name "1st_key" defined at "workflow_call" event is invalid`,
+		`:10:7: secret name "GITHUB_APP_KEY" defined at "workflow_call" event is invalid. secret names must not start with "GITHUB_"`,
+		`:17:7: secret name "Github_Token" passed to reusable workflow "owner/repo/.github/workflows/reusable.yaml@main" is invalid`,
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("wanted %d errors but got %v", len(want), errs)
+	}
+	for i, err := range errs {
+		if !strings.Contains(err.Error(), want[i]) {
+			t.Errorf("error %q does not contain %q", err.Error(), want[i])
+		}
+	}
+}
+
+func TestRuleSecretNameReferences(t *testing.T) {
+	testCases := []struct {
+		expr string
+		want string
+	}{
+		{"secrets.DEPLOY_KEY", ""},
+		{"secrets.GITHUB_TOKEN", ""},
+		{"secrets.github_token", ""},
+		{"secrets['_key1']", ""},
+		{"github.event.secrets.foo-bar", ""},
+		{"secrets.npm-token", `secret name "npm-token" referenced in expression is invalid`},
+		{"secrets.Npm-Token", `secret name "Npm-Token" referenced in expression is invalid`},
+		{"secrets['deploy key']", `secret name "deploy key" referenced in expression is invalid`},
+		{"secrets['1st']", `secret name "1st" referenced in expression is invalid`},
+		{"secrets.GITHUB_APP_KEY", `secret "GITHUB_APP_KEY" referenced in expression is always empty`},
+		{"format('{0}', secrets['GITHUB_KEY'])", `secret "GITHUB_KEY" referenced in expression is always empty`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expr, func(t *testing.T) {
+			l := NewExprLexer(tc.expr + "}}")
+			p := NewExprParser()
+			expr, err := p.Parse(l)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rule := NewRuleSecretName()
+			rule.checkExpr(expr, 1, 1)
+			errs := rule.Errs()
+
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("wanted 1 error but got %v", errs)
+			}
+			if !strings.Contains(errs[0].Message, tc.want) {
+				t.Fatalf("error %q does not contain %q", errs[0].Message, tc.want)
+			}
+		})
+	}
+}
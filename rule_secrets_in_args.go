@@ -0,0 +1,186 @@
+package actionlint
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Matches shell variable assignment like `TOKEN=...` or `TOKEN+=...`.
+var reShellAssignment = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\+?=`)
+
+// Shell builtin commands. Arguments of builtins don't appear in process list since no new process
+// is spawned.
+var shellBuiltinCommands = map[string]struct{}{
+	"echo":     {},
+	"printf":   {},
+	"export":   {},
+	"local":    {},
+	"declare":  {},
+	"readonly": {},
+	"typeset":  {},
+	"test":     {},
+	"[":        {},
+	"[[":       {},
+}
+
+// RuleSecretsInArgs is a rule to detect secrets which are put in command line arguments in scripts
+// at 'run:' like `deploy --token ${{ secrets.TOKEN }}`. Command line arguments of processes can be
+// seen by other users on the same machine via `ps` command. On shared runners such as self-hosted
+// runners, the secret may be leaked. Secrets should be passed via environment variables or stdin.
+// Assigning secrets to shell variables, passing them to shell builtins like `echo`, and putting
+// them in heredoc are not reported. Since the check is heuristic, this rule is disabled by default.
+// https://docs.github.com/en/actions/security-guides/security-hardening-for-github-actions#using-secrets
+type RuleSecretsInArgs struct {
+	RuleBase
+}
+
+// NewRuleSecretsInArgs creates new RuleSecretsInArgs instance.
+func NewRuleSecretsInArgs() *RuleSecretsInArgs {
+	return &RuleSecretsInArgs{
+		RuleBase: RuleBase{name: "secrets-in-args"},
+	}
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RuleSecretsInArgs) VisitStep(n *Step) error {
+	run, ok := n.Exec.(*ExecRun)
+	if !ok || run.Run == nil || !strings.Contains(run.Run.Value, "${{") {
+		return nil
+	}
+
+	src := run.Run.Value
+	multiline := strings.Contains(src, "\n")
+	lines := strings.Split(src, "\n")
+	heredocs := findHeredocs(lines)
+	for i, line := range lines {
+		if heredocAt(heredocs, i) != nil {
+			continue // Lines in heredoc are passed via stdin
+		}
+
+		for _, col := range findSecretsInArgs(line) {
+			pos := run.RunPos
+			if !multiline {
+				c := run.Run.Pos.Col + col
+				if run.Run.Quoted {
+					c++
+				}
+				pos = &Pos{Line: run.Run.Pos.Line, Col: c}
+			}
+			rule.errorf(
+				pos,
+				"secret is put in command line argument at line %d, col %d in this script. command line arguments can be seen by other processes via `ps` command so the secret may be leaked on shared runners. pass the secret via environment variable with \"env:\" section or via stdin instead",
+				i+1,
+				col+1,
+			)
+		}
+	}
+
+	return nil
+}
+
+// findSecretsInArgs finds ${{ }} placeholders referring secrets context which are put in command
+// line arguments of the line. It returns 0-based byte offsets of the placeholders.
+func findSecretsInArgs(line string) []int {
+	ret := []int{}
+	offset := 0
+	for {
+		idx := strings.Index(line[offset:], "${{")
+		if idx == -1 {
+			return ret
+		}
+		start := offset + idx
+		l := NewExprLexer(line[start+3:])
+		expr, err := NewExprParser().Parse(l)
+		if err != nil {
+			return ret
+		}
+		offset = start + 3 + l.Offset()
+
+		if refersSecretsContext(expr) && isCommandArgAt(line, start) {
+			ret = append(ret, start)
+		}
+	}
+}
+
+// isCommandArgAt returns if the word at the offset of the line is an argument of some external
+// command. Words in assignments, arguments of builtins, and comments are not command arguments.
+func isCommandArgAt(line string, offset int) bool {
+	words := []string{} // Words of the current command before the offset
+	start := -1         // Start of the current word
+	var quote byte
+
+	for i := 0; i < offset; i++ {
+		c := line[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' {
+				i++
+			}
+			continue
+		}
+
+		switch c {
+		case '\'', '"':
+			quote = c
+			if start < 0 {
+				start = i
+			}
+		case '\\':
+			if start < 0 {
+				start = i
+			}
+			i++
+		case ' ', '\t':
+			if start >= 0 {
+				words = append(words, line[start:i])
+				start = -1
+			}
+		case ';', '|', '&', '(', '`':
+			// Start of next command
+			words = words[:0]
+			start = -1
+		case '#':
+			if start < 0 {
+				return false // Comment
+			}
+		default:
+			if start < 0 {
+				start = i
+			}
+		}
+	}
+
+	word := ""
+	if start >= 0 {
+		word = line[start:offset]
+	}
+
+	// Skip assignments before command like `TOKEN=xxx cmd`. They are set to environment variables
+	for len(words) > 0 && isShellAssignment(words[0]) {
+		words = words[1:]
+	}
+
+	if strings.HasPrefix(word, "<<<") || len(words) > 0 && words[len(words)-1] == "<<<" {
+		return false // Here string is passed via stdin
+	}
+
+	if len(words) == 0 {
+		// The secret is put at command name or assignment
+		return !isShellAssignment(word)
+	}
+
+	// Assignment in PowerShell like `$env:TOKEN = '${{ secrets.TOKEN }}'`
+	if strings.HasPrefix(words[0], "$") && len(words) >= 2 && words[1] == "=" {
+		return false
+	}
+
+	_, ok := shellBuiltinCommands[words[0]]
+	return !ok
+}
+
+// isShellAssignment returns if the word is an assignment like `TOKEN=xxx` in sh or
+// `$env:TOKEN=xxx` in PowerShell.
+func isShellAssignment(w string) bool {
+	return reShellAssignment.MatchString(w) || strings.HasPrefix(w, "$") && strings.Contains(w, "=")
+}
@@ -0,0 +1,83 @@
+package actionlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleSecretsInArgsFindSecrets(t *testing.T) {
+	testCases := []struct {
+		line string
+		want []int
+	}{
+		{"deploy --token ${{ secrets.TOKEN }}", []int{15}},
+		{"deploy --token=${{ secrets.TOKEN }}", []int{15}},
+		{`curl -H "Authorization: Bearer ${{ secrets.TOKEN }}" https://example.com`, []int{31}},
+		{"cmd ${{ secrets.A }} ${{ secrets.B }}", []int{4, 21}},
+		{"cmd ${{ format('{0}', secrets.A) }}", []int{4}},
+		{"make && deploy ${{ secrets.TOKEN }}", []int{15}},
+		{"${{ secrets.COMMAND }} --help", []int{0}},
+		{"deploy --token ${{ github.token }}", []int{}},
+		{"deploy --name ${{ github.repository }}", []int{}},
+		{"TOKEN=${{ secrets.TOKEN }}", []int{}},
+		{`TOKEN="${{ secrets.TOKEN }}"`, []int{}},
+		{"export TOKEN=${{ secrets.TOKEN }}", []int{}},
+		{"TOKEN=${{ secrets.TOKEN }} deploy", []int{}},
+		{"echo ${{ secrets.TOKEN }} | docker login --password-stdin", []int{}},
+		{"printf '%s' '${{ secrets.TOKEN }}' > token.txt", []int{}},
+		{"deploy <<< '${{ secrets.TOKEN }}'", []int{}},
+		{"deploy # --token ${{ secrets.TOKEN }}", []int{}},
+		{"$env:TOKEN = '${{ secrets.TOKEN }}'", []int{}},
+		{"$env:TOKEN='${{ secrets.TOKEN }}'", []int{}},
+		{"echo ${{ secrets.A }}; deploy ${{ secrets.B }}", []int{30}},
+		{"deploy 'quoted ; arg' ${{ secrets.TOKEN }}", []int{22}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.line, func(t *testing.T) {
+			have := findSecretsInArgs(tc.line)
+			if len(have) != len(tc.want) {
+				t.Fatalf("wanted %v but got %v", tc.want, have)
+			}
+			for i := range have {
+				if have[i] != tc.want[i] {
+					t.Fatalf("wanted %v but got %v", tc.want, have)
+				}
+			}
+		})
+	}
+}
+
+func TestRuleSecretsInArgsHeredoc(t *testing.T) {
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: |
+          cat <<EOF > config.json
+          {"token": "${{ secrets.TOKEN }}"}
+          EOF
+          deploy --config config.json --key ${{ secrets.KEY }}
+      - run: deploy --token ${{ secrets.TOKEN }}
+      - run: 'deploy --token ${{ secrets.TOKEN }}'
+`
+	errs, err := RunRule(NewRuleSecretsInArgs(), []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		":6:9: secret is put in command line argument at line 4, col 35 in this script",
+		":11:29: secret is put in command line argument at line 1, col 16 in this script",
+		":12:30: secret is put in command line argument at line 1, col 16 in this script",
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("wanted %d errors but got %v", len(want), errs)
+	}
+	for i, err := range errs {
+		if !strings.Contains(err.Error(), want[i]) {
+			t.Errorf("error %q does not contain %q", err.Error(), want[i])
+		}
+	}
+}
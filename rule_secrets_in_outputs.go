@@ -0,0 +1,52 @@
+package actionlint
+
+// RuleSecretsInOutputs is a rule to detect job outputs and reusable workflow outputs which include
+// secrets. Outputs are passed to downstream jobs and workflows as plain text so secrets in them are
+// exposed to the jobs and their logs.
+// https://docs.github.com/en/actions/using-jobs/defining-outputs-for-jobs
+// https://docs.github.com/en/actions/using-workflows/reusing-workflows#using-outputs-from-a-reusable-workflow
+type RuleSecretsInOutputs struct {
+	RuleBase
+}
+
+// NewRuleSecretsInOutputs creates new RuleSecretsInOutputs instance.
+func NewRuleSecretsInOutputs() *RuleSecretsInOutputs {
+	return &RuleSecretsInOutputs{
+		RuleBase: RuleBase{name: "secrets-in-outputs"},
+	}
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RuleSecretsInOutputs) VisitWorkflowPre(n *Workflow) error {
+	for _, e := range n.On {
+		c, ok := e.(*WorkflowCallEvent)
+		if !ok {
+			continue
+		}
+		for name, o := range c.Outputs {
+			if containsSecretsContext(o.Value) {
+				rule.errorf(
+					o.Value.Pos,
+					"output %q of reusable workflow includes secrets. outputs are passed to caller workflows as plain text so the secrets are exposed to them and their logs",
+					name.Value,
+				)
+			}
+		}
+	}
+	return nil
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleSecretsInOutputs) VisitJobPre(n *Job) error {
+	for _, o := range n.Outputs {
+		if containsSecretsContext(o.Value) {
+			rule.errorf(
+				o.Value.Pos,
+				"output %q of job %q includes secrets. outputs are passed to dependent jobs as plain text so the secrets are exposed to them and their logs",
+				o.Name.Value,
+				n.ID.Value,
+			)
+		}
+	}
+	return nil
+}
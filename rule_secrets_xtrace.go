@@ -0,0 +1,191 @@
+package actionlint
+
+import (
+	"strings"
+)
+
+// RuleSecretsXtrace is a rule to detect `set -x` in scripts at 'run:' while some secret is set to
+// environment variables. Trace output prints expanded command lines so the secret value may be
+// leaked in logs.
+type RuleSecretsXtrace struct {
+	RuleBase
+	workflowEnv *Env
+	jobEnv      *Env
+	shell       shellResolver
+	// reported is a set of environment variables already reported at their values. The same
+	// variable at workflow or job level is reported only once even if many steps enable trace.
+	reported map[*EnvVar]struct{}
+}
+
+// NewRuleSecretsXtrace creates new RuleSecretsXtrace instance.
+func NewRuleSecretsXtrace() *RuleSecretsXtrace {
+	return &RuleSecretsXtrace{
+		RuleBase: RuleBase{name: "secrets-xtrace"},
+	}
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RuleSecretsXtrace) VisitWorkflowPre(n *Workflow) error {
+	rule.workflowEnv = n.Env
+	rule.shell.enterWorkflow(n)
+	rule.reported = map[*EnvVar]struct{}{}
+	return nil
+}
+
+// VisitWorkflowPost is callback when visiting Workflow node after visiting its children.
+func (rule *RuleSecretsXtrace) VisitWorkflowPost(n *Workflow) error {
+	rule.workflowEnv = nil
+	rule.shell.leaveWorkflow()
+	rule.reported = nil
+	return nil
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleSecretsXtrace) VisitJobPre(n *Job) error {
+	rule.jobEnv = n.Env
+	rule.shell.enterJob(n)
+	return nil
+}
+
+// VisitJobPost is callback when visiting Job node after visiting its children.
+func (rule *RuleSecretsXtrace) VisitJobPost(n *Job) error {
+	rule.jobEnv = nil
+	rule.shell.leaveJob()
+	return nil
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RuleSecretsXtrace) VisitStep(n *Step) error {
+	run, ok := n.Exec.(*ExecRun)
+	if !ok || run.Run == nil {
+		return nil
+	}
+
+	if sh := rule.shell.name(run); sh != "bash" && sh != "sh" {
+		return nil
+	}
+
+	line, ok := findXtraceInScript(run.Run.Value)
+	if !ok {
+		return nil
+	}
+
+	v := rule.findSecretEnvVar(n.Env)
+	if v == nil {
+		return nil
+	}
+
+	rule.errorf(
+		run.RunPos,
+		"\"set -x\" at line %d in this script enables trace output while secret is set to environment variable %q at %s. the secret value may be leaked in logs",
+		line,
+		v.Name.Value,
+		v.Name.Pos.String(),
+	)
+	if _, ok := rule.reported[v]; ok {
+		return nil
+	}
+	rule.reported[v] = struct{}{}
+	rule.errorf(
+		v.Value.Pos,
+		"secret is set to environment variable %q here while \"set -x\" at line %d in the script at %s enables trace output. the secret value may be leaked in logs",
+		v.Name.Value,
+		line,
+		run.RunPos.String(),
+	)
+	return nil
+}
+
+// findSecretEnvVar finds an environment variable whose value refers secrets context. Inner env
+// shadows outer env with the same name.
+func (rule *RuleSecretsXtrace) findSecretEnvVar(stepEnv *Env) *EnvVar {
+	vars := map[string]*EnvVar{}
+	for _, env := range []*Env{rule.workflowEnv, rule.jobEnv, stepEnv} {
+		if env == nil {
+			continue
+		}
+		for n, v := range env.Vars {
+			vars[n] = v
+		}
+	}
+
+	var found *EnvVar
+	for _, v := range vars {
+		if !containsSecretsContext(v.Value) {
+			continue
+		}
+		// Choose the first one in source to make error message deterministic
+		if found == nil || v.Name.Pos.Line < found.Name.Pos.Line {
+			found = v
+		}
+	}
+	return found
+}
+
+// containsSecretsContext returns if expressions in ${{ }} placeholders in the given string refer
+// secrets context.
+func containsSecretsContext(s *String) bool {
+	found := false
+	visitExprsInString(s, false, func(expr ExprNode, _, _ int) {
+		if !found {
+			found = refersSecretsContext(expr)
+		}
+	})
+	return found
+}
+
+// refersSecretsContext returns if the expression refers secrets context.
+func refersSecretsContext(expr ExprNode) bool {
+	found := false
+	VisitExprNode(expr, func(n, _ ExprNode, entering bool) {
+		if v, ok := n.(*VariableNode); ok && entering && strings.EqualFold(v.Name, "secrets") {
+			found = true
+		}
+	})
+	return found
+}
+
+// findXtraceInScript finds `set -x` or `set -o xtrace` in the script and returns its line number.
+// Combined flags like `set -ex` are also detected. The line number is 1-based.
+func findXtraceInScript(src string) (int, bool) {
+	for i, line := range strings.Split(src, "\n") {
+		for _, cmd := range splitShellCommands(line) {
+			if isSetXtraceCommand(cmd) {
+				return i + 1, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func splitShellCommands(line string) []string {
+	if i := strings.Index(line, "#"); i >= 0 && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+		line = line[:i] // Remove comment
+	}
+	r := strings.NewReplacer("&&", ";", "||", ";")
+	return strings.Split(r.Replace(line), ";")
+}
+
+func isSetXtraceCommand(cmd string) bool {
+	fs := strings.Fields(cmd)
+	if len(fs) < 2 || fs[0] != "set" {
+		return false
+	}
+	for i := 1; i < len(fs); i++ {
+		f := fs[i]
+		if !strings.HasPrefix(f, "-") || strings.HasPrefix(f, "--") {
+			return false
+		}
+		if strings.ContainsRune(f, 'x') {
+			return true
+		}
+		if strings.HasSuffix(f, "o") {
+			// Option name follows -o flag like `set -eo xtrace`
+			i++
+			if i < len(fs) && fs[i] == "xtrace" {
+				return true
+			}
+		}
+	}
+	return false
+}
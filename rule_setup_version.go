@@ -0,0 +1,118 @@
+package actionlint
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Version spec following semver range syntax like "1.2.3", "^1.2", ">=1.19 <1.21", "1.x" or "3.11-dev".
+// https://github.com/npm/node-semver#ranges
+var reSemverComparator = regexp.MustCompile(`^(\^|~|>=|<=|>|<|=)?v?(\d+|[xX*])(\.(\d+|[xX*])){0,2}(-[0-9A-Za-z.-]+)?(\+[0-9A-Za-z.-]+)?$`)
+
+// setupActionVersionInput is a version input of setup action.
+type setupActionVersionInput struct {
+	// input is a name of the version input.
+	input string
+	// aliases is a pattern of aliases which are accepted in addition to semver ranges.
+	aliases *regexp.Regexp
+	// examples is examples of the aliases shown in error message.
+	examples []string
+}
+
+// Version inputs of setup actions. The key is an action name without version.
+var setupActionVersionInputs = map[string]*setupActionVersionInput{
+	// https://github.com/actions/setup-node#supported-version-syntax
+	"actions/setup-node": {
+		input:    "node-version",
+		aliases:  regexp.MustCompile(`^(latest|current|node|lts/(\*|[a-z]+|-\d+))$`),
+		examples: []string{"latest", "current", "node", "lts/*", "lts/hydrogen", "lts/-1"},
+	},
+	// https://github.com/actions/setup-python/blob/main/docs/advanced-usage.md#using-the-python-version-input
+	"actions/setup-python": {
+		input:    "python-version",
+		aliases:  regexp.MustCompile(`^((pypy|graalpy)-?\d+(\.\d+)*(-v[0-9A-Za-z.]+)?|\d+\.\d+t)$`),
+		examples: []string{"pypy3.10", "pypy-3.10-v7.3.x", "graalpy-22.3", "3.13t"},
+	},
+	// https://github.com/actions/setup-go#supported-version-syntax
+	"actions/setup-go": {
+		input:    "go-version",
+		aliases:  regexp.MustCompile(`^(stable|oldstable)$`),
+		examples: []string{"stable", "oldstable"},
+	},
+}
+
+// RuleSetupVersion is a rule to check version inputs of setup actions like 'node-version' input of
+// actions/setup-node. The version is validated with simple grammar of semver ranges and known
+// aliases of the action. Since the actions may accept other syntax, this rule is disabled by default.
+type RuleSetupVersion struct {
+	RuleBase
+}
+
+// NewRuleSetupVersion creates new RuleSetupVersion instance.
+func NewRuleSetupVersion() *RuleSetupVersion {
+	return &RuleSetupVersion{
+		RuleBase: RuleBase{name: "setup-version"},
+	}
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RuleSetupVersion) VisitStep(n *Step) error {
+	e, ok := n.Exec.(*ExecAction)
+	if !ok || e.Uses == nil {
+		return nil
+	}
+
+	spec := strings.ToLower(e.Uses.Value)
+	if i := strings.IndexRune(spec, '@'); i >= 0 {
+		spec = spec[:i]
+	}
+	v, ok := setupActionVersionInputs[spec]
+	if !ok {
+		return nil
+	}
+
+	i, ok := e.Inputs[v.input]
+	if !ok || i.Value == nil || strings.Contains(i.Value.Value, "${{") {
+		return nil
+	}
+
+	// Some actions accept multiple versions separated with newlines
+	for _, l := range strings.Split(i.Value.Value, "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || isValidSetupVersion(l, v.aliases) {
+			continue
+		}
+		rule.errorf(
+			i.Value.Pos,
+			"%q is not a valid version at %q input of action %q. version should be semver version or range like \"1.2.3\", \"^1.2\", \">=1.2 <1.4\" or \"1.x\", or one of aliases like %s",
+			l,
+			v.input,
+			e.Uses.Value,
+			quotesAll(v.examples),
+		)
+	}
+
+	return nil
+}
+
+func isValidSetupVersion(v string, aliases *regexp.Regexp) bool {
+	if aliases.MatchString(v) {
+		return true
+	}
+	for _, r := range strings.Split(v, "||") {
+		cs := strings.Fields(r)
+		if len(cs) == 0 {
+			return false
+		}
+		// Hyphen range like "1.2.3 - 2.3.4"
+		if len(cs) == 3 && cs[1] == "-" {
+			cs = []string{cs[0], cs[2]}
+		}
+		for _, c := range cs {
+			if !reSemverComparator.MatchString(c) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -15,14 +15,19 @@ type shellcheckError struct {
 	Message string `json:"message"`
 }
 
+// DefaultMaxShellcheckScriptBytes is the default max size of script in bytes to be checked by
+// shellcheck. Larger scripts are not checked since shellcheck takes too long time to check them.
+const DefaultMaxShellcheckScriptBytes = 256 * 1024
+
 // RuleShellcheck is a rule to check shell scripts at 'run:' using shellcheck.
 // https://github.com/koalaman/shellcheck
 type RuleShellcheck struct {
 	RuleBase
-	cmd           *externalCommand
-	workflowShell string
-	jobShell      string
-	mu            sync.Mutex
+	cmd      *externalCommand
+	shell    shellResolver
+	mu       sync.Mutex
+	maxBytes int
+	skipped  []*Pos // Positions of 'run:' whose scripts were not checked due to their sizes
 }
 
 // NewRuleShellcheck craetes new RuleShellcheck instance. Parameter executable can be command name
@@ -34,14 +39,19 @@ func NewRuleShellcheck(executable string, proc *concurrentProcess) (*RuleShellch
 		return nil, err
 	}
 	r := &RuleShellcheck{
-		RuleBase:      RuleBase{name: "shellcheck"},
-		cmd:           cmd,
-		workflowShell: "",
-		jobShell:      "",
+		RuleBase: RuleBase{name: "shellcheck"},
+		cmd:      cmd,
+		maxBytes: DefaultMaxShellcheckScriptBytes,
 	}
 	return r, nil
 }
 
+// SetMaxScriptBytes sets the max size of script in bytes to be checked by shellcheck. Scripts larger
+// than the size are skipped. Zero or negative value means no limit.
+func (rule *RuleShellcheck) SetMaxScriptBytes(n int) {
+	rule.maxBytes = n
+}
+
 // VisitStep is callback when visiting Step node.
 func (rule *RuleShellcheck) VisitStep(n *Step) error {
 	run, ok := n.Exec.(*ExecRun)
@@ -49,71 +59,45 @@ func (rule *RuleShellcheck) VisitStep(n *Step) error {
 		return nil
 	}
 
-	name := rule.getShellName(run)
+	name := rule.shell.name(run)
 	if name != "bash" && name != "sh" {
 		return nil
 	}
 
+	if rule.maxBytes > 0 && len(run.Run.Value) > rule.maxBytes {
+		rule.debug("%s: Skip running shellcheck since the script is %d bytes, which is larger than %d bytes", run.RunPos, len(run.Run.Value), rule.maxBytes)
+		rule.skipped = append(rule.skipped, run.RunPos)
+		return nil
+	}
+
 	rule.runShellcheck(run.Run.Value, name, run.RunPos)
 	return nil
 }
 
 // VisitJobPre is callback when visiting Job node before visiting its children.
 func (rule *RuleShellcheck) VisitJobPre(n *Job) error {
-	if n.Defaults != nil && n.Defaults.Run != nil && n.Defaults.Run.Shell != nil {
-		rule.jobShell = n.Defaults.Run.Shell.Value
-		return nil
-	}
-
-	if n.RunsOn == nil {
-		return nil
-	}
-
-	for _, label := range n.RunsOn.Labels {
-		l := strings.ToLower(label.Value)
-		// Default shell on Windows is PowerShell.
-		// https://docs.github.com/en/actions/learn-github-actions/workflow-syntax-for-github-actions#using-a-specific-shell
-		if l == "windows" || strings.HasPrefix(l, "windows-") {
-			return nil
-		}
-	}
-
-	// TODO: When bash is not found, GitHub-hosted runner fallbacks to sh. What OSes require this behavior?
-	rule.jobShell = "bash"
-
+	rule.shell.enterJob(n)
 	return nil
 }
 
 // VisitJobPost is callback when visiting Job node after visiting its children.
 func (rule *RuleShellcheck) VisitJobPost(n *Job) error {
-	rule.jobShell = ""
+	rule.shell.leaveJob()
 	return nil
 }
 
 // VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
 func (rule *RuleShellcheck) VisitWorkflowPre(n *Workflow) error {
-	if n.Defaults != nil && n.Defaults.Run != nil && n.Defaults.Run.Shell != nil {
-		rule.workflowShell = n.Defaults.Run.Shell.Value
-	}
+	rule.shell.enterWorkflow(n)
 	return nil
 }
 
 // VisitWorkflowPost is callback when visiting Workflow node after visiting its children.
 func (rule *RuleShellcheck) VisitWorkflowPost(n *Workflow) error {
-	rule.workflowShell = ""
+	rule.shell.leaveWorkflow()
 	return rule.cmd.wait() // Wait until all processes running for this rule
 }
 
-func (rule *RuleShellcheck) getShellName(exec *ExecRun) string {
-	if exec.Shell != nil {
-		return exec.Shell.Value
-	}
-	if rule.jobShell != "" {
-		return rule.jobShell
-	}
-	return rule.workflowShell
-}
-
 // Replace ${{ ... }} with underscores like __________
 // Note: replacing with spaces sometimes causes syntax error. For example,
 //   if ${{ contains(xs, s) }}; then
@@ -73,3 +73,32 @@ func TestRuleShellcheckSanitizeExpressionsInScript(t *testing.T) {
 		})
 	}
 }
+
+func TestRuleShellcheckSkipLargeScript(t *testing.T) {
+	proc := newConcurrentProcess(1)
+	defer proc.wait()
+	// Any command is OK since shellcheck is not run for the large script
+	rule, err := NewRuleShellcheck("echo", proc)
+	if err != nil {
+		t.Skipf("echo command is not available: %s", err)
+	}
+	rule.SetMaxScriptBytes(10)
+
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo 'this script is larger than 10 bytes'
+`
+	errs, err := RunRule(rule, []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) > 0 {
+		t.Fatal("unexpected errors:", errs)
+	}
+	if len(rule.skipped) != 1 || rule.skipped[0].Line != 6 {
+		t.Fatalf("the script was not skipped: %v", rule.skipped)
+	}
+}
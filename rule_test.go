@@ -0,0 +1,78 @@
+package actionlint
+
+import (
+	"testing"
+)
+
+func TestRuleRunRule(t *testing.T) {
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "::set-output name=foo::bar"
+      - run: echo "::set-env name=FOO::bar"
+`
+	errs, err := RunRule(NewRuleDeprecatedCommands(), []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("wanted 2 errors but got %d: %v", len(errs), errs)
+	}
+	for i, line := range []int{6, 7} {
+		e := errs[i]
+		if e.Line != line || e.Kind != "deprecated-commands" {
+			t.Errorf("unexpected error at %d: %v", i, e)
+		}
+		if len(e.Fixes) != 1 {
+			t.Errorf("fix of error at %d was not resolved: %v", i, e.Fixes)
+		}
+	}
+}
+
+func TestRuleRunRuleNoError(t *testing.T) {
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "foo=bar" >> "$GITHUB_OUTPUT"
+`
+	errs, err := RunRule(NewRuleDeprecatedCommands(), []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) > 0 {
+		t.Fatal("unexpected errors:", errs)
+	}
+}
+
+func TestRuleRunRuleParseError(t *testing.T) {
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo "::set-output name=foo::bar"
+        foo: bar
+`
+	errs, err := RunRule(NewRuleDeprecatedCommands(), []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("wanted 2 errors but got %d: %v", len(errs), errs)
+	}
+	if errs[0].Kind != "deprecated-commands" || errs[1].Kind != ErrorKindSyntaxCheck {
+		t.Fatalf("errors are not sorted by position: %v", errs)
+	}
+
+	errs, err = RunRule(NewRuleDeprecatedCommands(), []byte("on: push\njobs: [\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) != 1 || errs[0].Kind != ErrorKindYAMLSyntax {
+		t.Fatalf("wanted one YAML syntax error but got %v", errs)
+	}
+}
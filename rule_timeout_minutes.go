@@ -0,0 +1,94 @@
+package actionlint
+
+import (
+	"strings"
+)
+
+// Max execution time of jobs in minutes.
+// https://docs.github.com/en/actions/learn-github-actions/usage-limits-billing-and-administration#usage-limits
+const (
+	// maxTimeoutMinutesGitHubHosted is the max execution time of each job on GitHub-hosted runners
+	// (6 hours).
+	maxTimeoutMinutesGitHubHosted = 6 * 60
+	// maxTimeoutMinutesSelfHosted is the max execution time of each job on self-hosted runners (5
+	// days).
+	maxTimeoutMinutesSelfHosted = 5 * 24 * 60
+)
+
+// RuleTimeoutMinutes is a rule to check values of "timeout-minutes" of jobs and steps. The values
+// must be positive. And jobs are cancelled when they exceed the max execution time of the runner
+// even if larger timeout is set. The max time is 6 hours on GitHub-hosted runners and 5 days on
+// self-hosted runners. When the runner of the job cannot be determined statically, the larger limit
+// is used. Values given as expressions are not checked by this rule. Their types are checked by
+// RuleExpression.
+// https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#jobsjob_idtimeout-minutes
+type RuleTimeoutMinutes struct {
+	RuleBase
+	max    int
+	runner string
+}
+
+// NewRuleTimeoutMinutes creates new RuleTimeoutMinutes instance.
+func NewRuleTimeoutMinutes() *RuleTimeoutMinutes {
+	return &RuleTimeoutMinutes{
+		RuleBase: RuleBase{name: "timeout-minutes"},
+	}
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleTimeoutMinutes) VisitJobPre(n *Job) error {
+	if isGitHubHostedRunner(n.RunsOn) {
+		rule.max, rule.runner = maxTimeoutMinutesGitHubHosted, "GitHub-hosted runners (6 hours)"
+	} else {
+		rule.max, rule.runner = maxTimeoutMinutesSelfHosted, "self-hosted runners (5 days)"
+	}
+	rule.check(n.TimeoutMinutes, "job")
+	return nil
+}
+
+// VisitJobPost is callback when visiting Job node after visiting its children.
+func (rule *RuleTimeoutMinutes) VisitJobPost(n *Job) error {
+	rule.max, rule.runner = 0, ""
+	return nil
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RuleTimeoutMinutes) VisitStep(n *Step) error {
+	rule.check(n.TimeoutMinutes, "step")
+	return nil
+}
+
+func (rule *RuleTimeoutMinutes) check(f *Float, what string) {
+	if f == nil || f.Expression != nil {
+		return
+	}
+	if f.Value <= 0 {
+		rule.errorf(f.Pos, "value of \"timeout-minutes\" of %s must be positive but got %g", what, f.Value)
+		return
+	}
+	if rule.max > 0 && f.Value > float64(rule.max) {
+		rule.errorf(
+			f.Pos,
+			"value of \"timeout-minutes\" of %s is %g but it exceeds the max execution time %d minutes of jobs on %s. the job is cancelled when it reaches the max execution time",
+			what,
+			f.Value,
+			rule.max,
+			rule.runner,
+		)
+	}
+}
+
+// isGitHubHostedRunner returns true when the job obviously runs on GitHub-hosted runner. When the
+// runner is given by expressions or runner group, it returns false since the runner cannot be
+// determined statically.
+func isGitHubHostedRunner(r *Runner) bool {
+	if r == nil || r.Group != nil || len(r.Labels) == 0 {
+		return false
+	}
+	for _, l := range r.Labels {
+		if strings.Contains(l.Value, "${{") || !contains(allGitHubHostedRunnerLabels, strings.ToLower(l.Value)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,200 @@
+package actionlint
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// echo "{name}={value}" >> "$GITHUB_OUTPUT" or echo "{name}<<{delimiter}" >> "$GITHUB_OUTPUT"
+	reWriteStepOutput = regexp.MustCompile(`\becho\s+(?:-\w+\s+)*["']?([A-Za-z_][\w-]*)(?:=|<<)[^>]*>>\s*["']?(?:\$\{?|\$env:|%)GITHUB_OUTPUT\b`)
+	// echo "::set-output name={name}::{value}"
+	reSetOutputCommand = regexp.MustCompile(`::set-output\s+name=([A-Za-z_][\w-]*)::`)
+)
+
+// RuleUnusedStepOutputs is a rule to detect outputs of steps which are never referenced. It scans
+// scripts at "run:" for outputs written to $GITHUB_OUTPUT or set by the deprecated set-output
+// command, and reports outputs which are not referenced by any steps.<step_id>.outputs.<name>
+// in the job including outputs of the job. Steps whose output names cannot be determined
+// statically are not checked. Since unused outputs are harmless, this rule is disabled by default.
+// Expressions are checked when the checkExpr method is set to the expression hook of
+// RuleExpression.
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#setting-an-output-parameter
+type RuleUnusedStepOutputs struct {
+	RuleBase
+	// outputs is a mapping from lower-cased step IDs to names of outputs set by the steps.
+	outputs map[string][]string
+	// refs is a mapping from lower-cased step IDs to lower-cased output names referenced in the
+	// job. When the value is nil, all outputs of the step are considered referenced.
+	refs map[string]map[string]struct{}
+	// all is true when the steps context is referenced as a whole.
+	all bool
+}
+
+// NewRuleUnusedStepOutputs creates new RuleUnusedStepOutputs instance.
+func NewRuleUnusedStepOutputs() *RuleUnusedStepOutputs {
+	return &RuleUnusedStepOutputs{
+		RuleBase: RuleBase{name: "unused-step-outputs"},
+	}
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleUnusedStepOutputs) VisitJobPre(n *Job) error {
+	rule.outputs = map[string][]string{}
+	rule.refs = map[string]map[string]struct{}{}
+	rule.all = false
+
+	for _, s := range n.Steps {
+		if s.ID == nil || strings.Contains(s.ID.Value, "${{") {
+			continue
+		}
+		e, ok := s.Exec.(*ExecRun)
+		if !ok || e.Run == nil {
+			continue
+		}
+		names, ok := stepOutputNames(e.Run.Value)
+		if !ok || len(names) == 0 {
+			continue
+		}
+		rule.outputs[strings.ToLower(s.ID.Value)] = names
+	}
+	return nil
+}
+
+// VisitJobPost is callback when visiting Job node after visiting its children.
+func (rule *RuleUnusedStepOutputs) VisitJobPost(n *Job) error {
+	// Iterate steps instead of the map to report errors in a stable order
+	for _, s := range n.Steps {
+		if s.ID == nil || rule.all {
+			continue
+		}
+		id := strings.ToLower(s.ID.Value)
+		names, ok := rule.outputs[id]
+		if !ok {
+			continue
+		}
+		refs, ok := rule.refs[id]
+		if ok && refs == nil {
+			continue // All outputs of the step are referenced
+		}
+		seen := map[string]struct{}{}
+		for _, name := range names {
+			l := strings.ToLower(name)
+			if _, ok := seen[l]; ok {
+				continue
+			}
+			seen[l] = struct{}{}
+			if _, ok := refs[l]; ok {
+				continue
+			}
+			rule.errorf(
+				s.Pos,
+				"output %q of step %q is set but never referenced as \"steps.%s.outputs.%s\" in job %q. remove the unused output or use it",
+				name,
+				s.ID.Value,
+				s.ID.Value,
+				name,
+				n.ID.Value,
+			)
+		}
+	}
+
+	rule.outputs = nil
+	rule.refs = nil
+	rule.all = false
+	return nil
+}
+
+// checkExpr collects outputs of steps referenced in the expression. The line and col parameters
+// are the position of the expression.
+func (rule *RuleUnusedStepOutputs) checkExpr(expr ExprNode, line, col int) {
+	if rule.refs == nil {
+		return // Outside jobs
+	}
+
+	VisitExprNode(expr, func(n, p ExprNode, entering bool) {
+		if !entering {
+			return
+		}
+
+		// steps
+		if v, ok := n.(*VariableNode); ok {
+			if strings.EqualFold(v.Name, "steps") {
+				if r, _, ok := propertyAccess(p); !ok || r != n {
+					rule.all = true // The steps context is used as a whole like toJSON(steps)
+				}
+			}
+			return
+		}
+
+		r, prop, ok := propertyAccess(n)
+		if !ok {
+			return
+		}
+
+		// steps.<step_id>
+		if v, ok := r.(*VariableNode); ok && strings.EqualFold(v.Name, "steps") {
+			if r, _, ok := propertyAccess(p); !ok || r != n {
+				rule.refs[strings.ToLower(prop)] = nil // The step is used as a whole like toJSON(steps.foo)
+			}
+			return
+		}
+
+		// steps.<step_id>.outputs
+		if !strings.EqualFold(prop, "outputs") {
+			return
+		}
+		s, id, ok := propertyAccess(r)
+		if !ok {
+			return
+		}
+		if v, ok := s.(*VariableNode); !ok || !strings.EqualFold(v.Name, "steps") {
+			return
+		}
+		id = strings.ToLower(id)
+		if refs, ok := rule.refs[id]; ok && refs == nil {
+			return // Already all outputs are considered referenced
+		}
+
+		// steps.<step_id>.outputs.<name>
+		r, name, ok := propertyAccess(p)
+		if !ok || r != n {
+			rule.refs[id] = nil // The outputs are used as a whole like toJSON(steps.foo.outputs)
+			return
+		}
+		refs, ok := rule.refs[id]
+		if !ok {
+			refs = map[string]struct{}{}
+			rule.refs[id] = refs
+		}
+		refs[strings.ToLower(name)] = struct{}{}
+	})
+}
+
+// stepOutputNames extracts names of outputs set by the script. The second return value is false
+// when some output is set in a way which cannot be analyzed statically, for example, the name is
+// given by a variable or the output is written by a command other than echo.
+func stepOutputNames(script string) ([]string, bool) {
+	names := []string{}
+	for _, line := range strings.Split(script, "\n") {
+		if n := strings.Count(line, "GITHUB_OUTPUT"); n > 0 {
+			ms := reWriteStepOutput.FindAllStringSubmatch(line, -1)
+			if len(ms) != n {
+				return nil, false
+			}
+			for _, m := range ms {
+				names = append(names, m[1])
+			}
+		}
+		if n := strings.Count(line, "::set-output"); n > 0 {
+			ms := reSetOutputCommand.FindAllStringSubmatch(line, -1)
+			if len(ms) != n {
+				return nil, false
+			}
+			for _, m := range ms {
+				names = append(names, m[1])
+			}
+		}
+	}
+	return names, true
+}
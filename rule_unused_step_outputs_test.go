@@ -0,0 +1,50 @@
+package actionlint
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRuleUnusedStepOutputsOutputNames(t *testing.T) {
+	testCases := []struct {
+		what   string
+		script string
+		want   []string
+	}{
+		{"no output", "echo hello", []string{}},
+		{"double quotes", `echo "foo=bar" >> "$GITHUB_OUTPUT"`, []string{"foo"}},
+		{"single quotes", `echo 'foo=bar' >> $GITHUB_OUTPUT`, []string{"foo"}},
+		{"no quote", `echo foo=bar >> $GITHUB_OUTPUT`, []string{"foo"}},
+		{"braces", `echo "foo=bar" >> "${GITHUB_OUTPUT}"`, []string{"foo"}},
+		{"echo option", `echo -n "foo=bar" >> "$GITHUB_OUTPUT"`, []string{"foo"}},
+		{"PowerShell", `echo "foo=bar" >> $env:GITHUB_OUTPUT`, []string{"foo"}},
+		{"cmd", `echo foo=bar >> %GITHUB_OUTPUT%`, []string{"foo"}},
+		{"delimiter of multi-line value", "echo 'foo<<EOF' >> $GITHUB_OUTPUT\necho 'EOF' >> $GITHUB_OUTPUT", nil},
+		{"multiple outputs", "echo a=1 >> $GITHUB_OUTPUT; echo b=2 >> $GITHUB_OUTPUT\necho c=3 >> $GITHUB_OUTPUT", []string{"a", "b", "c"}},
+		{"in if statement", `if true; then echo "foo=bar" >> "$GITHUB_OUTPUT"; fi`, []string{"foo"}},
+		{"set-output command", `echo "::set-output name=foo::bar"`, []string{"foo"}},
+		{"dynamic name", `echo "$NAME=bar" >> "$GITHUB_OUTPUT"`, nil},
+		{"other command", `cat out.txt >> "$GITHUB_OUTPUT"`, nil},
+		{"tee", `echo "foo=bar" | tee -a "$GITHUB_OUTPUT"`, nil},
+		{"dynamic name of set-output command", `echo "::set-output name=$NAME::bar"`, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			have, ok := stepOutputNames(tc.script)
+			if tc.want == nil {
+				if ok {
+					t.Fatalf("wanted failure but got %q", have)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("names could not be extracted")
+			}
+			if !cmp.Equal(tc.want, have) {
+				t.Fatal(cmp.Diff(tc.want, have))
+			}
+		})
+	}
+}
@@ -44,55 +44,87 @@ func (rule *RuleWorkflowCall) VisitJobPre(n *Job) error {
 		rule.errorf(u.Pos, "reusable workflow cannot be nested. but this workflow hooks \"workflow_call\" event at %s", rule.workflowCallEventPos)
 	}
 
-	if !strings.Contains(u.Value, "${{") && !(checkWorkflowCallUsesLocalFormat(u.Value) || checkWorkflowCallUsesRepoFormat(u.Value)) {
-		rule.errorf(u.Pos, "reusable workflow call %q at \"uses\" is not following the format \"owner/repo/path/to/workflow.yml@ref\" nor \"./path/to/workflow.yml\". see https://docs.github.com/en/actions/learn-github-actions/reusing-workflows for more details", u.Value)
+	if !strings.Contains(u.Value, "${{") {
+		rule.checkWorkflowCallUsesFormat(u)
 	}
 
 	return nil
 }
 
-// Parse ./{path/{filename}
-// https://docs.github.com/en/actions/learn-github-actions/reusing-workflows#calling-a-reusable-workflow
-func checkWorkflowCallUsesLocalFormat(u string) bool {
-	if !strings.HasPrefix(u, "./") {
-		return false
+func (rule *RuleWorkflowCall) checkWorkflowCallUsesFormat(u *String) {
+	var reason string
+	if strings.HasPrefix(u.Value, "./") {
+		reason = checkWorkflowCallUsesLocalFormat(u.Value)
+	} else {
+		reason = checkWorkflowCallUsesRepoFormat(u.Value)
+	}
+	if reason == "" {
+		return
+	}
+	rule.errorf(
+		u.Pos,
+		"reusable workflow call %q at \"uses\" is not following the format \"owner/repo/.github/workflows/workflow.yml@ref\" nor \"./.github/workflows/workflow.yml\": %s. see https://docs.github.com/en/actions/learn-github-actions/reusing-workflows for more details",
+		u.Value,
+		reason,
+	)
+}
+
+// checkWorkflowFilePath checks the path to a workflow file. Reusable workflows must be put directly
+// in .github/workflows directory. Sub directories are not supported.
+func checkWorkflowFilePath(p string) string {
+	if !strings.HasPrefix(p, ".github/workflows/") {
+		return "workflow file must be in \".github/workflows\" directory"
+	}
+	f := strings.TrimPrefix(p, ".github/workflows/")
+	if f == "" {
+		return "workflow file name is empty"
 	}
+	if strings.ContainsRune(f, '/') {
+		return "sub directories of \".github/workflows\" directory are not supported"
+	}
+	if !strings.HasSuffix(f, ".yml") && !strings.HasSuffix(f, ".yaml") {
+		return "workflow file name must end with \".yml\" or \".yaml\""
+	}
+	return ""
+}
+
+// Parse ./.github/workflows/{filename}
+// https://docs.github.com/en/actions/learn-github-actions/reusing-workflows#calling-a-reusable-workflow
+func checkWorkflowCallUsesLocalFormat(u string) string {
 	u = strings.TrimPrefix(u, "./")
 
-	// Cannot container a ref
-	idx := strings.IndexRune(u, '@')
-	if idx > 0 {
-		return false
+	// Cannot contain a ref
+	if strings.ContainsRune(u, '@') {
+		return "ref cannot be specified for local reusable workflow since it is always the same commit as the caller workflow"
 	}
 
-	return len(u) > 0
+	return checkWorkflowFilePath(u)
 }
 
-// Parse {owner}/{repo}/{path to workflow.yml}@{ref}
+// Parse {owner}/{repo}/.github/workflows/{filename}@{ref}
 // https://docs.github.com/en/actions/learn-github-actions/reusing-workflows#calling-a-reusable-workflow
-func checkWorkflowCallUsesRepoFormat(u string) bool {
+func checkWorkflowCallUsesRepoFormat(u string) string {
 	// Repo reference must start with owner
-	if strings.HasPrefix(u, ".") {
-		return false
+	if strings.HasPrefix(u, ".") || strings.HasPrefix(u, "/") {
+		return "remote reusable workflow must start with owner. local workflow must start with \"./\""
 	}
 
 	idx := strings.IndexRune(u, '/')
 	if idx <= 0 {
-		return false
+		return "repository name is missing"
 	}
 	u = u[idx+1:] // Eat owner
 
 	idx = strings.IndexRune(u, '/')
 	if idx <= 0 {
-		return false
+		return "repository name or path to workflow file is missing"
 	}
 	u = u[idx+1:] // Eat repo
 
 	idx = strings.IndexRune(u, '@')
-	if idx <= 0 {
-		return false
+	if idx < 0 || idx == len(u)-1 {
+		return "ref is missing"
 	}
-	u = u[idx+1:] // Eat workflow path
 
-	return len(u) > 0
+	return checkWorkflowFilePath(u[:idx])
 }
@@ -10,22 +10,33 @@ func TestRuleWorkflowCallCheckWorkflowCallUsesFormat(t *testing.T) {
 		uses string
 		ok   bool
 	}{
-		{"owner/repo/x.yml@ref", true},
-		{"owner/repo/x.yml@@", true},
-		{"owner/repo/x.yml@release/v1", true},
-		{"./path/to/x.yml", true},
+		{"owner/repo/.github/workflows/x.yml@ref", true},
+		{"owner/repo/.github/workflows/x.yaml@ref", true},
+		{"owner/repo/.github/workflows/x.yml@@", true},
+		{"owner/repo/.github/workflows/x.yml@release/v1", true},
+		{"owner/repo/.github/workflows/x.yml@0123456789abcdef0123456789abcdef01234567", true},
+		{"./.github/workflows/x.yml", true},
+		{"./.github/workflows/x.yaml", true},
 		{"${{ env.FOO }}", true},
-		{"./path/to/x.yml@ref", false},
+		{"./.github/workflows/x.yml@ref", false},
+		{"./path/to/x.yml", false},
+		{"./.github/workflows/", false},
+		{"./.github/workflows/sub/x.yml", false},
+		{"./.github/workflows/x.json", false},
 		{"/path/to/x.yml@ref", false},
 		{"./", false},
 		{".", false},
 		{"owner/x.yml@ref", false},
 		{"owner/repo@ref", false},
-		{"owner/repo/x.yml", false},
-		{"/repo/x.yml@ref", false},
-		{"owner//x.yml@ref", false},
+		{"owner/repo/.github/workflows/x.yml", false},
+		{"/repo/.github/workflows/x.yml@ref", false},
+		{"owner//.github/workflows/x.yml@ref", false},
 		{"owner/repo/@ref", false},
-		{"owner/repo/x.yml@", false},
+		{"owner/repo/.github/workflows/x.yml@", false},
+		{"owner/repo/x.yml@ref", false},
+		{"owner/repo/path/to/x.yml@ref", false},
+		{"owner/repo/.github/workflows/sub/x.yml@ref", false},
+		{"owner/repo/.github/workflows/x@ref", false},
 	}
 
 	for _, tc := range tests {
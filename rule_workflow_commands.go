@@ -0,0 +1,201 @@
+package actionlint
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Workflow command like "::error file=app.js,line=1::message". The first submatch is the command
+// name and the second submatch is its parameters.
+var reWorkflowCommand = regexp.MustCompile(`(?:^|[\s"'])::([a-zA-Z][a-zA-Z-]*)(?: ([^:]*))?::`)
+
+// Delimiters of words which start or end blocks in shell script. For example, `foo(){` is split
+// into `foo ( ) {`.
+var reShellBlockDelim = regexp.MustCompile(`[;(){}]|&&|\|\|`)
+
+// Quoted strings and parameter expansions which may contain braces or keywords but do not start or
+// end blocks.
+var reShellNonBlock = regexp.MustCompile(`'[^']*'|"[^"]*"|\$\{\{.*?\}\}|\$\{[^}]*\}`)
+
+// Parameters of ::error, ::warning and ::notice workflow commands.
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions#setting-an-error-message
+var annotationCommandParams = []string{"col", "endColumn", "endLine", "file", "line", "title"}
+
+// RuleWorkflowCommands is a rule to check syntax of workflow commands in scripts at 'run:'. It
+// checks that each ::group:: command is closed by ::endgroup:: command and that parameters of
+// annotation commands like ::error are known. Since groups are matched textually, unmatched groups
+// are reported as warnings.
+// https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions
+type RuleWorkflowCommands struct {
+	RuleBase
+	// Group opened by the previous step which is not closed yet. It may be closed by the following
+	// steps in the same job.
+	openGroup *workflowCommandGroup
+}
+
+type workflowCommandGroup struct {
+	pos  *Pos
+	line int
+}
+
+// NewRuleWorkflowCommands creates new RuleWorkflowCommands instance.
+func NewRuleWorkflowCommands() *RuleWorkflowCommands {
+	return &RuleWorkflowCommands{
+		RuleBase: RuleBase{name: "workflow-commands"},
+	}
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleWorkflowCommands) VisitJobPre(n *Job) error {
+	rule.openGroup = nil
+	return nil
+}
+
+// VisitJobPost is callback when visiting Job node after visiting its children.
+func (rule *RuleWorkflowCommands) VisitJobPost(n *Job) error {
+	rule.reportUnclosedGroup()
+	return nil
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RuleWorkflowCommands) VisitStep(n *Step) error {
+	run, ok := n.Exec.(*ExecRun)
+	if !ok || run.Run == nil {
+		return nil
+	}
+
+	// Order of lines in the script is not the order of execution when groups are started or ended
+	// in conditional branches or functions. Groups in such script are not checked.
+	checkGroups := !hasWorkflowCommandGroupInBranch(run.Run.Value)
+	if !checkGroups {
+		rule.openGroup = nil
+	}
+
+	group := 0 // Line number of the last ::group:: command which is not closed yet
+Lines:
+	for i, line := range strings.Split(run.Run.Value, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		for _, m := range reWorkflowCommand.FindAllStringSubmatch(line, -1) {
+			switch m[1] {
+			case "group":
+				if !checkGroups {
+					continue
+				}
+				rule.reportUnclosedGroup()
+				if group != 0 {
+					rule.warnf(
+						run.RunPos,
+						"\"::group::\" at line %d in this script is not closed by \"::endgroup::\" before starting another group at line %d. groups of log lines cannot be nested",
+						group,
+						i+1,
+					)
+				}
+				group = i + 1
+			case "endgroup":
+				if !checkGroups {
+					continue
+				}
+				if group == 0 && rule.openGroup == nil {
+					rule.warnf(
+						run.RunPos,
+						"\"::endgroup::\" at line %d in this script has no matching \"::group::\". start a group with \"::group::{title}\" before ending it",
+						i+1,
+					)
+				}
+				rule.openGroup = nil // Closed the group started by the previous step
+				group = 0
+			case "error", "warning", "notice":
+				rule.checkAnnotationParams(m[1], m[2], i+1, run.RunPos)
+			case "stop-commands":
+				break Lines // Workflow commands are not processed until the token is echoed
+			}
+		}
+	}
+
+	if group != 0 {
+		rule.openGroup = &workflowCommandGroup{run.RunPos, group}
+	}
+
+	return nil
+}
+
+func (rule *RuleWorkflowCommands) reportUnclosedGroup() {
+	g := rule.openGroup
+	if g == nil {
+		return
+	}
+	rule.warnf(
+		g.pos,
+		"\"::group::\" at line %d in this script is not closed by \"::endgroup::\". add \"::endgroup::\" to the end of the group",
+		g.line,
+	)
+	rule.openGroup = nil
+}
+
+// hasWorkflowCommandGroupInBranch returns true when some ::group:: or ::endgroup:: command in the
+// script is in a conditional branch like if-statement, case-statement, && or ||, or in a body of
+// function. Blocks are detected roughly by keywords and braces.
+func hasWorkflowCommandGroupInBranch(script string) bool {
+	depth := 0
+	for _, line := range strings.Split(script, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		inBranch := depth > 0
+		code := reShellNonBlock.ReplaceAllString(line, " ")
+		for _, w := range strings.Fields(reShellBlockDelim.ReplaceAllString(code, " $0 ")) {
+			switch w {
+			case "if", "case", "{":
+				depth++
+				inBranch = true
+			case "fi", "esac", "}":
+				depth--
+			case "&&", "||":
+				inBranch = true
+			}
+		}
+		if !inBranch {
+			continue
+		}
+		for _, m := range reWorkflowCommand.FindAllStringSubmatch(line, -1) {
+			if m[1] == "group" || m[1] == "endgroup" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (rule *RuleWorkflowCommands) checkAnnotationParams(cmd, params string, line int, pos *Pos) {
+	if params == "" {
+		return
+	}
+	for _, p := range strings.Split(params, ",") {
+		k := strings.TrimSpace(p)
+		if i := strings.IndexByte(k, '='); i >= 0 {
+			k = k[:i]
+		}
+		if k == "" {
+			continue
+		}
+		known := false
+		for _, a := range annotationCommandParams {
+			if strings.EqualFold(k, a) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			rule.errorf(
+				pos,
+				"unknown parameter %q of workflow command \"::%s\" at line %d in this script. available parameters are %s",
+				k,
+				cmd,
+				line,
+				sortedQuotes(annotationCommandParams),
+			)
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package actionlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleWorkflowCommands(t *testing.T) {
+	testCases := []struct {
+		what   string
+		script string
+		want   []string
+	}{
+		{
+			what:   "group and endgroup",
+			script: "echo '::group::Install'\nnpm ci\necho '::endgroup::'",
+		},
+		{
+			what:   "endgroup without group",
+			script: "npm ci\necho '::endgroup::'",
+			want:   []string{`"::endgroup::" at line 2 in this script has no matching "::group::"`},
+		},
+		{
+			what:   "unclosed group",
+			script: "echo '::group::Install'\nnpm ci",
+			want:   []string{`"::group::" at line 1 in this script is not closed by "::endgroup::"`},
+		},
+		{
+			what:   "nested group",
+			script: "echo ::group::foo\necho ::group::bar\necho ::endgroup::",
+			want:   []string{`"::group::" at line 1 in this script is not closed by "::endgroup::" before starting another group at line 2`},
+		},
+		{
+			what:   "annotation parameters",
+			script: `echo "::error file=app.js,line=1,col=5,endLine=2,endColumn=3,title=Oops::Something went wrong"`,
+		},
+		{
+			what:   "annotation parameters are case insensitive",
+			script: `echo "::warning FILE=app.js,Line=1::message"`,
+		},
+		{
+			what:   "unknown annotation parameter",
+			script: `echo "::notice file=app.js,column=1::message"`,
+			want:   []string{`unknown parameter "column" of workflow command "::notice" at line 1 in this script`},
+		},
+		{
+			what:   "annotation without parameters",
+			script: `echo "::warning::message"`,
+		},
+		{
+			what:   "commands in comment",
+			script: "# echo '::endgroup::'\necho ok",
+		},
+		{
+			what:   "stop-commands",
+			script: "echo \"::stop-commands::$TOKEN\"\necho '::endgroup::'\necho \"::$TOKEN::\"",
+		},
+		{
+			what:   "not workflow command",
+			script: "ruby -e 'Foo::endgroup::bar'",
+		},
+		{
+			what:   "groups in if-else branches",
+			script: "if [[ -n \"$A\" ]]; then\n  echo '::group::A'\nelse\n  echo '::group::B'\nfi\nmake\necho '::endgroup::'",
+		},
+		{
+			what:   "groups in if-statement in one line",
+			script: "if [ -n \"$A\" ]; then echo '::group::A'; else echo '::group::B'; fi\nmake\necho '::endgroup::'",
+		},
+		{
+			what:   "groups in function",
+			script: "start() {\n  echo \"::group::$1\"\n}\nstart foo\nmake\nstart bar\nmake\necho '::endgroup::'",
+		},
+		{
+			what:   "groups in && and || chain",
+			script: "test -n \"$A\" && echo '::group::A' || echo '::group::B'\nmake\necho '::endgroup::'",
+		},
+		{
+			what:   "braces and keywords in strings and parameter expansions",
+			script: "echo \"::group::${TITLE} if {\"\nmake\necho '::endgroup::'\necho '::endgroup::'",
+			want:   []string{`"::endgroup::" at line 4 in this script has no matching "::group::"`},
+		},
+		{
+			what:   "if-statement not containing groups",
+			script: "if [ -n \"$A\" ]; then\n  make\nfi\necho '::endgroup::'",
+			want:   []string{`"::endgroup::" at line 4 in this script has no matching "::group::"`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			r := NewRuleWorkflowCommands()
+			s := &Step{
+				Exec: &ExecRun{
+					Run:    &String{Value: tc.script, Pos: &Pos{}},
+					RunPos: &Pos{},
+				},
+			}
+			j := &Job{Steps: []*Step{s}}
+			if err := r.VisitJobPre(j); err != nil {
+				t.Fatal(err)
+			}
+			if err := r.VisitStep(s); err != nil {
+				t.Fatal(err)
+			}
+			if err := r.VisitJobPost(j); err != nil {
+				t.Fatal(err)
+			}
+			errs := r.Errs()
+			if len(errs) != len(tc.want) {
+				t.Fatalf("wanted %d errors but got %v", len(tc.want), errs)
+			}
+			for i, err := range errs {
+				if !strings.Contains(err.Message, tc.want[i]) {
+					t.Errorf("error message %q does not contain %q", err.Message, tc.want[i])
+				}
+				// Unmatched groups may be false positives since groups are matched textually
+				want := SeverityError
+				if strings.Contains(err.Message, "group") {
+					want = SeverityWarning
+				}
+				if s := err.Severity(); s != want {
+					t.Errorf("wanted severity %q but got %q: %q", want, s, err.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestRuleWorkflowCommandsGroupAcrossSteps(t *testing.T) {
+	testCases := []struct {
+		what    string
+		scripts []string
+		want    []string
+	}{
+		{
+			what:    "group closed by next step",
+			scripts: []string{"echo '::group::Build'\nmake", "make test\necho '::endgroup::'"},
+		},
+		{
+			what:    "group closed by later step",
+			scripts: []string{"echo '::group::Build'", "make", "echo '::endgroup::'"},
+		},
+		{
+			what:    "group not closed until end of job",
+			scripts: []string{"echo '::group::Build'", "make"},
+			want:    []string{`"::group::" at line 1 in this script is not closed by "::endgroup::"`},
+		},
+		{
+			what:    "another group started by next step",
+			scripts: []string{"echo '::group::Build'", "echo '::group::Test'\necho '::endgroup::'"},
+			want:    []string{`"::group::" at line 1 in this script is not closed by "::endgroup::"`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			r := NewRuleWorkflowCommands()
+			j := &Job{}
+			for i, src := range tc.scripts {
+				j.Steps = append(j.Steps, &Step{
+					Exec: &ExecRun{
+						Run:    &String{Value: src, Pos: &Pos{Line: i + 1}},
+						RunPos: &Pos{Line: i + 1},
+					},
+				})
+			}
+			if err := r.VisitJobPre(j); err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range j.Steps {
+				if err := r.VisitStep(s); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := r.VisitJobPost(j); err != nil {
+				t.Fatal(err)
+			}
+			errs := r.Errs()
+			if len(errs) != len(tc.want) {
+				t.Fatalf("wanted %d errors but got %v", len(tc.want), errs)
+			}
+			for i, err := range errs {
+				if !strings.Contains(err.Message, tc.want[i]) {
+					t.Errorf("error message %q does not contain %q", err.Message, tc.want[i])
+				}
+				if err.Line != 1 {
+					t.Errorf("error should be reported at the step which started the group: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package actionlint
+
+import (
+	"sort"
+	"strings"
+)
+
+// Limits of workflows enforced by GitHub.
+// https://docs.github.com/en/actions/learn-github-actions/usage-limits-billing-and-administration#usage-limits
+// https://docs.github.com/en/actions/using-workflows/reusing-workflows#limitations
+const (
+	// maxWorkflowFileBytes is the max size of a workflow file.
+	maxWorkflowFileBytes = 512 * 1024
+	// maxWorkflowJobs is the max number of jobs in a workflow run.
+	maxWorkflowJobs = 256
+	// maxWorkflowNestingLevels is the max levels of workflows connected by calling reusable
+	// workflows. The top-level caller workflow is counted as one level.
+	maxWorkflowNestingLevels = 10
+	// workflowLimitsWarnPercent is a percentage of the limits. When a value reaches the percentage
+	// of its limit, the value is reported as close to the limit.
+	workflowLimitsWarnPercent = 90
+)
+
+// RuleWorkflowLimits is a rule to check a workflow does not exceed or approach the limits enforced
+// by GitHub: size of the workflow file, number of jobs, and levels of nested reusable workflow
+// calls. Nesting levels are computed by following local reusable workflows ("uses: ./..."). Values
+// approaching the limits are reported as warnings.
+type RuleWorkflowLimits struct {
+	RuleBase
+	size  int
+	cache *LocalReusableWorkflowCache
+	// levels is a memo of the longest call chains from local reusable workflows
+	levels map[string][]string
+}
+
+// NewRuleWorkflowLimits creates new RuleWorkflowLimits instance. The size parameter is the size of
+// the workflow file in bytes. The cache parameter can be nil. In the case, nesting levels of
+// reusable workflows are not checked.
+func NewRuleWorkflowLimits(size int, cache *LocalReusableWorkflowCache) *RuleWorkflowLimits {
+	return &RuleWorkflowLimits{
+		RuleBase: RuleBase{name: "workflow-limits"},
+		size:     size,
+		cache:    cache,
+		levels:   map[string][]string{},
+	}
+}
+
+// checkWorkflowLimit compares the value with the limit. It returns how the value relates to the
+// limit and severity of the error. Approaching the limit is a warning since the workflow still
+// works. The severity is empty when the value is far from the limit.
+func checkWorkflowLimit(v, limit int) (string, string) {
+	if v > limit {
+		return "exceeds", SeverityError
+	}
+	if v*100 >= limit*workflowLimitsWarnPercent {
+		return "is close to", SeverityWarning
+	}
+	return "", ""
+}
+
+func (rule *RuleWorkflowLimits) reportf(severity string, pos *Pos, format string, args ...interface{}) {
+	if severity == SeverityWarning {
+		rule.warnf(pos, format, args...)
+	} else {
+		rule.errorf(pos, format, args...)
+	}
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RuleWorkflowLimits) VisitWorkflowPre(n *Workflow) error {
+	if s, sev := checkWorkflowLimit(rule.size, maxWorkflowFileBytes); sev != "" {
+		rule.reportf(
+			sev,
+			&Pos{Line: 1, Col: 1},
+			"size of this workflow file is %d bytes. it %s the limit of %d bytes (%dKB). split the workflow into multiple workflows or reusable workflows",
+			rule.size,
+			s,
+			maxWorkflowFileBytes,
+			maxWorkflowFileBytes/1024,
+		)
+	}
+
+	if s, sev := checkWorkflowLimit(len(n.Jobs), maxWorkflowJobs); sev != "" {
+		jobs := make([]*Job, 0, len(n.Jobs))
+		for _, j := range n.Jobs {
+			jobs = append(jobs, j)
+		}
+		sort.Slice(jobs, func(i, j int) bool {
+			return jobs[i].Pos.Line < jobs[j].Pos.Line
+		})
+		// Report at the job which reached the threshold
+		i := maxWorkflowJobs * workflowLimitsWarnPercent / 100
+		if len(jobs) > maxWorkflowJobs {
+			i = maxWorkflowJobs
+		}
+		if i >= len(jobs) {
+			i = len(jobs) - 1
+		}
+		rule.reportf(
+			sev,
+			jobs[i].Pos,
+			"this workflow has %d jobs. it %s the limit of %d jobs in a workflow run",
+			len(jobs),
+			s,
+			maxWorkflowJobs,
+		)
+	}
+
+	return nil
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleWorkflowLimits) VisitJobPre(n *Job) error {
+	if rule.cache == nil || n.WorkflowCall == nil || n.WorkflowCall.Uses == nil {
+		return nil
+	}
+	u := n.WorkflowCall.Uses
+	if !strings.HasPrefix(u.Value, "./") || strings.Contains(u.Value, "${{") {
+		return nil
+	}
+
+	chain, _ := rule.callChain(u.Value, map[string]struct{}{})
+	// The current workflow is also counted as one level
+	levels := len(chain) + 1
+	if s, sev := checkWorkflowLimit(levels, maxWorkflowNestingLevels); sev != "" {
+		rule.reportf(
+			sev,
+			u.Pos,
+			"calling reusable workflow %q nests workflows in %d levels. it %s the limit of %d levels. the longest call chain is %s",
+			u.Value,
+			levels,
+			s,
+			maxWorkflowNestingLevels,
+			strings.Join(chain, " -> "),
+		)
+	}
+	return nil
+}
+
+// callChain returns the longest chain of local reusable workflows called from the workflow. The
+// first element of the chain is the given spec. Recursive calls are not followed. The second return
+// value is true when some recursive call was skipped. In the case, the chain depends on workflows
+// being visited so it is not memorized.
+func (rule *RuleWorkflowLimits) callChain(spec string, visiting map[string]struct{}) ([]string, bool) {
+	if c, ok := rule.levels[spec]; ok {
+		return c, false
+	}
+
+	w, err := rule.cache.FindWorkflow(spec)
+	if err != nil {
+		rule.debug("Could not read reusable workflow %q to compute nesting levels: %v", spec, err)
+	}
+	if w == nil {
+		return []string{spec}, false
+	}
+
+	visiting[spec] = struct{}{}
+	longest := []string{}
+	recursive := false
+	for _, j := range w.Jobs {
+		if j.WorkflowCall == nil || j.WorkflowCall.Uses == nil {
+			continue
+		}
+		s := j.WorkflowCall.Uses.Value
+		if !strings.HasPrefix(s, "./") || strings.Contains(s, "${{") {
+			continue
+		}
+		if _, ok := visiting[s]; ok {
+			recursive = true
+			continue
+		}
+		c, r := rule.callChain(s, visiting)
+		if r {
+			recursive = true
+		}
+		if len(c) > len(longest) || (len(c) == len(longest) && len(c) > 0 && c[0] < longest[0]) {
+			longest = c
+		}
+	}
+	delete(visiting, spec)
+
+	chain := append([]string{spec}, longest...)
+	if !recursive {
+		rule.levels[spec] = chain
+	}
+	return chain, recursive
+}
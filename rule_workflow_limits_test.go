@@ -0,0 +1,216 @@
+package actionlint
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// checkWorkflowLimitsSeverity checks that approaching the limit is a warning and exceeding the limit
+// is an error.
+func checkWorkflowLimitsSeverity(t *testing.T, err *Error) {
+	t.Helper()
+	want := SeverityError
+	if strings.Contains(err.Message, "is close to the limit") {
+		want = SeverityWarning
+	}
+	if s := err.Severity(); s != want {
+		t.Fatalf("wanted severity %q but got %q: %s", want, s, err)
+	}
+}
+
+func TestRuleWorkflowLimitsFileSize(t *testing.T) {
+	testCases := []struct {
+		what string
+		size int
+		want string
+	}{
+		{"small", 1024, ""},
+		{"close to limit", maxWorkflowFileBytes * 95 / 100, "is close to the limit of 524288 bytes"},
+		{"exceeds limit", maxWorkflowFileBytes + 1, "exceeds the limit of 524288 bytes"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			src := "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n"
+			errs, err := RunRule(NewRuleWorkflowLimits(tc.size, nil), []byte(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Message, tc.want) {
+				t.Fatalf("error message should contain %q but got %v", tc.want, errs)
+			}
+			checkWorkflowLimitsSeverity(t, errs[0])
+		})
+	}
+}
+
+func TestRuleWorkflowLimitsNumberOfJobs(t *testing.T) {
+	testCases := []struct {
+		jobs int
+		want string
+		line int
+	}{
+		{100, "", 0},
+		{240, "this workflow has 240 jobs. it is close to the limit of 256 jobs", 3 + 3*230},
+		{300, "this workflow has 300 jobs. it exceeds the limit of 256 jobs", 3 + 3*256},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprint(tc.jobs), func(t *testing.T) {
+			var b strings.Builder
+			b.WriteString("on: push\njobs:\n")
+			for i := 0; i < tc.jobs; i++ {
+				fmt.Fprintf(&b, "  job%d:\n    runs-on: ubuntu-latest\n    steps: [run: echo]\n", i)
+			}
+			src := b.String()
+			errs, err := RunRule(NewRuleWorkflowLimits(len(src), nil), []byte(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Message, tc.want) {
+				t.Fatalf("error message should contain %q but got %v", tc.want, errs)
+			}
+			checkWorkflowLimitsSeverity(t, errs[0])
+			if errs[0].Line != tc.line {
+				t.Fatalf("wanted error at line %d but got line %d", tc.line, errs[0].Line)
+			}
+		})
+	}
+}
+
+func TestRuleWorkflowLimitsNestingLevels(t *testing.T) {
+	dir := t.TempDir()
+	wfdir := filepath.Join(dir, ".github", "workflows")
+	if err := os.MkdirAll(wfdir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// level1.yaml calls level2.yaml, ..., level10.yaml calls nothing. recursive.yaml calls itself
+	for i := 1; i <= 10; i++ {
+		src := "on: workflow_call\njobs:\n  test:\n"
+		if i < 10 {
+			src += fmt.Sprintf("    uses: ./.github/workflows/level%d.yaml\n", i+1)
+		} else {
+			src += "    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n"
+		}
+		if err := ioutil.WriteFile(filepath.Join(wfdir, fmt.Sprintf("level%d.yaml", i)), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	src := "on: workflow_call\njobs:\n  test:\n    uses: ./.github/workflows/recursive.yaml\n  other:\n    uses: ./.github/workflows/level9.yaml\n"
+	if err := ioutil.WriteFile(filepath.Join(wfdir, "recursive.yaml"), []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		what string
+		uses string
+		want string
+	}{
+		{
+			what: "8 levels",
+			uses: "./.github/workflows/level4.yaml",
+		},
+		{
+			what: "9 levels",
+			uses: "./.github/workflows/level3.yaml",
+			want: `calling reusable workflow "./.github/workflows/level3.yaml" nests workflows in 9 levels. it is close to the limit of 10 levels. the longest call chain is ./.github/workflows/level3.yaml -> ./.github/workflows/level4.yaml -> `,
+		},
+		{
+			what: "10 levels",
+			uses: "./.github/workflows/level2.yaml",
+			want: `calling reusable workflow "./.github/workflows/level2.yaml" nests workflows in 10 levels. it is close to the limit of 10 levels`,
+		},
+		{
+			what: "11 levels",
+			uses: "./.github/workflows/level1.yaml",
+			want: `calling reusable workflow "./.github/workflows/level1.yaml" nests workflows in 11 levels. it exceeds the limit of 10 levels. the longest call chain is ./.github/workflows/level1.yaml -> ./.github/workflows/level2.yaml -> `,
+		},
+		{
+			what: "recursive call",
+			uses: "./.github/workflows/recursive.yaml",
+		},
+		{
+			what: "not found",
+			uses: "./.github/workflows/not-found.yaml",
+		},
+		{
+			what: "remote workflow",
+			uses: "owner/repo/.github/workflows/level1.yaml@v1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			c := NewLocalReusableWorkflowCache(&Project{root: dir}, nil)
+			src := "on: push\njobs:\n  call:\n    uses: " + tc.uses + "\n"
+			errs, err := RunRule(NewRuleWorkflowLimits(len(src), c), []byte(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 || !strings.Contains(errs[0].Message, tc.want) {
+				t.Fatalf("error message should contain %q but got %v", tc.want, errs)
+			}
+			checkWorkflowLimitsSeverity(t, errs[0])
+			if errs[0].Line != 4 || errs[0].Column != 11 {
+				t.Fatalf("error should be reported at \"uses\" but got line %d, col %d", errs[0].Line, errs[0].Column)
+			}
+		})
+	}
+}
+
+func TestRuleWorkflowLimitsNestingLevelsIndependentOfVisitOrder(t *testing.T) {
+	dir := t.TempDir()
+	wfdir := filepath.Join(dir, ".github", "workflows")
+	if err := os.MkdirAll(wfdir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	// a.yaml calls b.yaml and c1.yaml -> c2.yaml -> c3.yaml. b.yaml calls a.yaml
+	files := map[string]string{
+		"a.yaml":  "on: workflow_call\njobs:\n  b:\n    uses: ./.github/workflows/b.yaml\n  c:\n    uses: ./.github/workflows/c1.yaml\n",
+		"b.yaml":  "on: workflow_call\njobs:\n  a:\n    uses: ./.github/workflows/a.yaml\n",
+		"c1.yaml": "on: workflow_call\njobs:\n  c:\n    uses: ./.github/workflows/c2.yaml\n",
+		"c2.yaml": "on: workflow_call\njobs:\n  c:\n    uses: ./.github/workflows/c3.yaml\n",
+		"c3.yaml": "on: workflow_call\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n",
+	}
+	for n, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(wfdir, n), []byte(src), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, order := range [][]string{{"a", "b"}, {"b", "a"}} {
+		t.Run(strings.Join(order, ","), func(t *testing.T) {
+			c := NewLocalReusableWorkflowCache(&Project{root: dir}, nil)
+			r := NewRuleWorkflowLimits(0, c)
+			want := map[string]int{"a": 4, "b": 5}
+			for _, n := range order {
+				chain, _ := r.callChain("./.github/workflows/"+n+".yaml", map[string]struct{}{})
+				if len(chain) != want[n] {
+					t.Errorf("wanted chain of %d workflows from %s.yaml but got %v", want[n], n, chain)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package actionlint
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Matches absolute paths on Windows like `C:\path` or `D:/path`.
+var reWindowsAbsPath = regexp.MustCompile(`^[a-zA-Z]:[\\/]`)
+
+// RuleWorkingDirectory is a rule to check paths at "working-directory:". The paths are relative to
+// the workspace directory. Paths escaping the workspace via ".." refer directories outside of the
+// repository, so they are likely mistakes. Absolute paths depend on the runner environment, so they
+// are reported as warnings. Paths
+// containing ${{ }} are not checked since they are evaluated at runtime. Their types are checked by
+// RuleExpression.
+// https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#jobsjob_idstepsworking-directory
+type RuleWorkingDirectory struct {
+	RuleBase
+}
+
+// NewRuleWorkingDirectory creates new RuleWorkingDirectory instance.
+func NewRuleWorkingDirectory() *RuleWorkingDirectory {
+	return &RuleWorkingDirectory{
+		RuleBase: RuleBase{name: "working-directory"},
+	}
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RuleWorkingDirectory) VisitWorkflowPre(n *Workflow) error {
+	rule.checkDefaults(n.Defaults)
+	return nil
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleWorkingDirectory) VisitJobPre(n *Job) error {
+	rule.checkDefaults(n.Defaults)
+	return nil
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RuleWorkingDirectory) VisitStep(n *Step) error {
+	if e, ok := n.Exec.(*ExecRun); ok {
+		rule.check(e.WorkingDirectory)
+	}
+	return nil
+}
+
+func (rule *RuleWorkingDirectory) checkDefaults(d *Defaults) {
+	if d != nil && d.Run != nil {
+		rule.check(d.Run.WorkingDirectory)
+	}
+}
+
+func (rule *RuleWorkingDirectory) check(dir *String) {
+	if dir == nil || dir.Value == "" || strings.Contains(dir.Value, "${{") {
+		return
+	}
+	v := dir.Value
+
+	if strings.HasPrefix(v, "/") || strings.HasPrefix(v, "\\") || reWindowsAbsPath.MatchString(v) {
+		// Absolute paths are sometimes intended like /tmp/build on self-hosted runners. Report them as
+		// warnings
+		rule.warnf(
+			dir.Pos,
+			"working directory %q is an absolute path. \"working-directory\" should be a path relative to the workspace since absolute paths depend on the runner environment",
+			v,
+		)
+		return
+	}
+
+	if p := path.Clean(strings.ReplaceAll(v, "\\", "/")); p == ".." || strings.HasPrefix(p, "../") {
+		rule.errorf(
+			dir.Pos,
+			"working directory %q escapes the workspace via \"..\". \"working-directory\" should be a path inside the workspace",
+			v,
+		)
+	}
+}
+
+// RuleWorkingDirectoryExists is a rule to detect directories at "working-directory:" which don't
+// exist in the repository. It catches paths which are not updated after moving or renaming the
+// directories. Since directories may be created by previous steps at runtime, this rule is disabled
+// by default. Steps after checking out other repositories are not checked. This rule checks nothing
+// when the repository is unknown, for example on linting stdin.
+type RuleWorkingDirectoryExists struct {
+	RuleBase
+	proj *Project
+}
+
+// NewRuleWorkingDirectoryExists creates new RuleWorkingDirectoryExists instance.
+func NewRuleWorkingDirectoryExists() *RuleWorkingDirectoryExists {
+	return &RuleWorkingDirectoryExists{
+		RuleBase: RuleBase{name: "working-directory-exists"},
+	}
+}
+
+// SetProject sets the project where the working directories are searched. When it is nil, this
+// rule checks nothing.
+func (rule *RuleWorkingDirectoryExists) SetProject(p *Project) {
+	rule.proj = p
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RuleWorkingDirectoryExists) VisitWorkflowPre(n *Workflow) error {
+	if rule.proj == nil || n.Defaults == nil || n.Defaults.Run == nil {
+		return nil
+	}
+	for _, j := range n.Jobs {
+		if checksOutOtherRepositoryInSteps(j.Steps) {
+			return nil // The default working directory may be put by the checkout
+		}
+	}
+	rule.check(n.Defaults.Run.WorkingDirectory)
+	return nil
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleWorkingDirectoryExists) VisitJobPre(n *Job) error {
+	if rule.proj == nil {
+		return nil
+	}
+	if n.Defaults != nil && n.Defaults.Run != nil && !checksOutOtherRepositoryInSteps(n.Steps) {
+		rule.check(n.Defaults.Run.WorkingDirectory)
+	}
+	for _, s := range n.Steps {
+		switch e := s.Exec.(type) {
+		case *ExecAction:
+			if checksOutOtherRepository(e) {
+				return nil // Directories after this step may be put by the checkout
+			}
+		case *ExecRun:
+			rule.check(e.WorkingDirectory)
+		}
+	}
+	return nil
+}
+
+func (rule *RuleWorkingDirectoryExists) check(dir *String) {
+	if dir == nil || dir.Value == "" || strings.Contains(dir.Value, "${{") || filepath.IsAbs(dir.Value) {
+		return
+	}
+	p := filepath.Join(rule.proj.RootDir(), filepath.FromSlash(dir.Value))
+	if s, err := os.Stat(p); err != nil || !s.IsDir() {
+		rule.errorf(
+			dir.Pos,
+			"working directory %q does not exist in the repository. the directory may have been moved or renamed",
+			dir.Value,
+		)
+	}
+}
+
+func checksOutOtherRepositoryInSteps(steps []*Step) bool {
+	for _, s := range steps {
+		if e, ok := s.Exec.(*ExecAction); ok && checksOutOtherRepository(e) {
+			return true
+		}
+	}
+	return false
+}
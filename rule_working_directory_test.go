@@ -0,0 +1,180 @@
+package actionlint
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRuleWorkingDirectory(t *testing.T) {
+	testCases := []struct {
+		what string
+		dir  string
+		want string
+		warn bool
+	}{
+		{
+			what: "relative path",
+			dir:  "frontend",
+		},
+		{
+			what: "relative path with dot",
+			dir:  "./packages/../frontend",
+		},
+		{
+			what: "current directory",
+			dir:  ".",
+		},
+		{
+			what: "expression",
+			dir:  "${{ runner.temp }}/build",
+		},
+		{
+			what: "absolute path",
+			dir:  "/home/runner/work",
+			want: `working directory "/home/runner/work" is an absolute path`,
+			warn: true,
+		},
+		{
+			what: "absolute path on Windows",
+			dir:  `'C:\work'`,
+			want: `working directory "C:\\work" is an absolute path`,
+			warn: true,
+		},
+		{
+			what: "parent directory",
+			dir:  "..",
+			want: `working directory ".." escapes the workspace via ".."`,
+		},
+		{
+			what: "escaping via parent directory",
+			dir:  "frontend/../../other",
+			want: `working directory "frontend/../../other" escapes the workspace`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			src := "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n        working-directory: " + tc.dir + "\n"
+			errs, err := RunRule(NewRuleWorkingDirectory(), []byte(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tc.want == "" {
+				if len(errs) > 0 {
+					t.Fatal("unexpected errors:", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("wanted 1 error but got %v", errs)
+			}
+			if !strings.Contains(errs[0].Message, tc.want) {
+				t.Fatalf("error message %q does not contain %q", errs[0].Message, tc.want)
+			}
+			want := SeverityError
+			if tc.warn {
+				want = SeverityWarning
+			}
+			if s := errs[0].Severity(); s != want {
+				t.Fatalf("wanted severity %q but got %q", want, s)
+			}
+		})
+	}
+}
+
+func TestRuleWorkingDirectoryExists(t *testing.T) {
+	proj := &Project{root: filepath.Join("testdata", "examples")}
+
+	testCases := []struct {
+		what string
+		src  string
+		want []string
+	}{
+		{
+			what: "existing directory",
+			src: `
+    steps:
+      - run: echo
+        working-directory: .github/actions/my-action
+`,
+		},
+		{
+			what: "missing directory",
+			src: `
+    steps:
+      - run: echo
+        working-directory: frontend
+`,
+			want: []string{`working directory "frontend" does not exist in the repository`},
+		},
+		{
+			what: "missing default directory",
+			src: `
+    defaults:
+      run:
+        working-directory: backend
+    steps:
+      - run: echo
+`,
+			want: []string{`working directory "backend" does not exist in the repository`},
+		},
+		{
+			what: "expression",
+			src: `
+    steps:
+      - run: echo
+        working-directory: ${{ github.workspace }}/frontend
+`,
+		},
+		{
+			what: "after checking out other repository",
+			src: `
+    defaults:
+      run:
+        working-directory: backend
+    steps:
+      - run: echo
+        working-directory: frontend
+      - uses: actions/checkout@v4
+        with:
+          repository: owner/repo
+          path: other
+      - run: echo
+        working-directory: other
+`,
+			want: []string{`working directory "frontend" does not exist in the repository`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			src := "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest" + tc.src
+			r := NewRuleWorkingDirectoryExists()
+			r.SetProject(proj)
+			errs, err := RunRule(r, []byte(src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(errs) != len(tc.want) {
+				t.Fatalf("wanted %d errors but got %v", len(tc.want), errs)
+			}
+			for i, want := range tc.want {
+				if !strings.Contains(errs[i].Message, want) {
+					t.Fatalf("error message %q does not contain %q", errs[i].Message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRuleWorkingDirectoryExistsWithoutProject(t *testing.T) {
+	src := "on: push\njobs:\n  test:\n    runs-on: ubuntu-latest\n    steps:\n      - run: echo\n        working-directory: frontend\n"
+	errs, err := RunRule(NewRuleWorkingDirectoryExists(), []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(errs) > 0 {
+		t.Fatal("nothing should be checked when project is unknown:", errs)
+	}
+}
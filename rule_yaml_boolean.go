@@ -0,0 +1,106 @@
+package actionlint
+
+import "fmt"
+
+// YAML 1.1 boolean values which are not booleans in YAML 1.2. "true" and "false" are booleans in
+// both versions.
+// https://yaml.org/type/bool.html
+var yaml11OnlyBooleans = map[string]struct{}{
+	"y": {}, "Y": {}, "yes": {}, "Yes": {}, "YES": {},
+	"n": {}, "N": {}, "no": {}, "No": {}, "NO": {},
+	"on": {}, "On": {}, "ON": {},
+	"off": {}, "Off": {}, "OFF": {},
+}
+
+// RuleYAMLBoolean is a rule to detect unquoted values like 'yes' or 'on' at 'env:' and 'with:'
+// sections. They are booleans in YAML 1.1 but strings in YAML 1.2 which GitHub Actions uses. Users
+// often expect that they are booleans, but actions receive them as strings like "yes". For example
+// core.getBooleanInput() of @actions/core fails for the input value "yes". Errors can be fixed by
+// quoting the values. Since the values are valid strings, this rule is disabled by default.
+type RuleYAMLBoolean struct {
+	RuleBase
+	fixes []*Fix
+}
+
+// NewRuleYAMLBoolean creates new RuleYAMLBoolean instance.
+func NewRuleYAMLBoolean() *RuleYAMLBoolean {
+	return &RuleYAMLBoolean{
+		RuleBase: RuleBase{name: "yaml-boolean"},
+	}
+}
+
+// Fixes returns fixes of errors found by the rule.
+func (rule *RuleYAMLBoolean) Fixes() []*Fix {
+	return rule.fixes
+}
+
+// VisitWorkflowPre is callback when visiting Workflow node before visiting its children.
+func (rule *RuleYAMLBoolean) VisitWorkflowPre(n *Workflow) error {
+	rule.checkEnv(n.Env)
+	return nil
+}
+
+// VisitJobPre is callback when visiting Job node before visiting its children.
+func (rule *RuleYAMLBoolean) VisitJobPre(n *Job) error {
+	rule.checkEnv(n.Env)
+	if n.Container != nil {
+		rule.checkEnv(n.Container.Env)
+	}
+	for _, s := range n.Services {
+		if s.Container != nil {
+			rule.checkEnv(s.Container.Env)
+		}
+	}
+	if n.WorkflowCall != nil {
+		for _, i := range n.WorkflowCall.Inputs {
+			rule.check(i.Value, fmt.Sprintf("input %q of reusable workflow", i.Name.Value))
+		}
+	}
+	return nil
+}
+
+// VisitStep is callback when visiting Step node.
+func (rule *RuleYAMLBoolean) VisitStep(n *Step) error {
+	rule.checkEnv(n.Env)
+	if a, ok := n.Exec.(*ExecAction); ok {
+		for _, i := range a.Inputs {
+			rule.check(i.Value, fmt.Sprintf("input %q of action", i.Name.Value))
+		}
+	}
+	return nil
+}
+
+func (rule *RuleYAMLBoolean) checkEnv(env *Env) {
+	if env == nil {
+		return
+	}
+	for _, v := range env.Vars {
+		rule.check(v.Value, fmt.Sprintf("env var %q", v.Name.Value))
+	}
+}
+
+func (rule *RuleYAMLBoolean) check(s *String, what string) {
+	if s == nil || s.Quoted || s.Literal {
+		return
+	}
+	if _, ok := yaml11OnlyBooleans[s.Value]; !ok {
+		return
+	}
+
+	err := errorfAt(
+		s.Pos,
+		rule.name,
+		"%s is set to %s which is a boolean in YAML 1.1 but a string in YAML 1.2 used by GitHub Actions. use true or false for a boolean value, or quote it like \"%s\" for a string value",
+		what,
+		s.Value,
+		s.Value,
+	)
+	rule.errs = append(rule.errs, err)
+	rule.fixes = append(rule.fixes, &Fix{
+		Error:  err,
+		Line:   s.Pos.Line,
+		Column: s.Pos.Col,
+		Old:    s.Value,
+		New:    `"` + s.Value + `"`,
+	})
+}
@@ -0,0 +1,68 @@
+package actionlint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRuleYAMLBooleanFixes(t *testing.T) {
+	src := `on: push
+env:
+  A: yes
+  B: 'no'
+  C: true
+  D: On
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    container:
+      image: node:18
+      env:
+        E: off
+    steps:
+      - uses: actions/checkout@v4
+        with: { fetch-tags: Y, lfs: "n" }
+      - run: echo
+        env:
+          F: |-
+            yes
+          G: yes!
+  call:
+    uses: owner/repo/.github/workflows/reusable.yaml@main
+    with:
+      debug: NO
+`
+	errs, err := RunRule(NewRuleYAMLBoolean(), []byte(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		line int
+		col  int
+		what string
+		new  string
+	}{
+		{3, 6, `env var "A"`, `"yes"`},
+		{6, 6, `env var "D"`, `"On"`},
+		{13, 12, `env var "E"`, `"off"`},
+		{16, 29, `input "fetch-tags" of action`, `"Y"`},
+		{25, 14, `input "debug" of reusable workflow`, `"NO"`},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("wanted %d errors but got %v", len(want), errs)
+	}
+	for i, w := range want {
+		e := errs[i]
+		if e.Line != w.line || e.Column != w.col || !strings.HasPrefix(e.Message, w.what+" is set to ") {
+			t.Errorf("unexpected error at %d: %s", i, e)
+		}
+		if len(e.Fixes) != 1 {
+			t.Fatalf("wanted one fix at %d but got %v", i, e.Fixes)
+		}
+		f := e.Fixes[0]
+		if f.Line != w.line || f.Column != w.col || f.EndColumn != w.col+len(w.new)-2 || f.NewText != w.new {
+			t.Errorf("unexpected fix at %d: %+v", i, f)
+		}
+	}
+}
@@ -0,0 +1,290 @@
+package actionlint
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SARIF 2.1.0 objects written by actionlint. Only the properties used by actionlint are defined.
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+// https://docs.github.com/en/code-security/code-scanning/integrating-with-code-scanning/sarif-support-for-code-scanning
+
+type sarifLog struct {
+	Schema  string      `json:"$schema"`
+	Version string      `json:"version"`
+	Runs    []*sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       *sarifTool     `json:"tool"`
+	ColumnKind string         `json:"columnKind"`
+	Results    []*sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver *sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string       `json:"name"`
+	InformationURI string       `json:"informationUri"`
+	Rules          []*sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string        `json:"id"`
+	Name             string        `json:"name"`
+	ShortDescription *sarifMessage `json:"shortDescription"`
+	HelpURI          string        `json:"helpUri"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID           string           `json:"ruleId"`
+	RuleIndex        int              `json:"ruleIndex"`
+	Level            string           `json:"level"`
+	Message          *sarifMessage    `json:"message"`
+	Locations        []*sarifLocation `json:"locations,omitempty"`
+	RelatedLocations []*sarifLocation `json:"relatedLocations,omitempty"`
+	Fixes            []*sarifFix      `json:"fixes,omitempty"`
+}
+
+type sarifLocation struct {
+	ID               *int                    `json:"id,omitempty"`
+	PhysicalLocation *sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []*sarifLogicalLocation `json:"logicalLocations,omitempty"`
+	Message          *sarifMessage           `json:"message,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation *sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine,omitempty"`
+	EndColumn   int `json:"endColumn,omitempty"`
+}
+
+type sarifFix struct {
+	Description     *sarifMessage          `json:"description"`
+	ArtifactChanges []*sarifArtifactChange `json:"artifactChanges"`
+}
+
+type sarifArtifactChange struct {
+	ArtifactLocation *sarifArtifactLocation `json:"artifactLocation"`
+	Replacements     []*sarifReplacement    `json:"replacements"`
+}
+
+type sarifReplacement struct {
+	DeletedRegion   *sarifRegion          `json:"deletedRegion"`
+	InsertedContent *sarifArtifactContent `json:"insertedContent"`
+}
+
+type sarifArtifactContent struct {
+	Text string `json:"text"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+func newSARIFLocation(e *ErrorTemplateFields) *sarifLocation {
+	// Column 0 means the position in the line is unknown. SARIF columns must be positive
+	col := e.Column
+	if col < 1 {
+		col = 1
+	}
+	uri := filepath.ToSlash(e.Filepath)
+	return &sarifLocation{
+		PhysicalLocation: &sarifPhysicalLocation{
+			ArtifactLocation: &sarifArtifactLocation{URI: uri},
+			Region:           &sarifRegion{StartLine: e.Line, StartColumn: col},
+		},
+		// The workflow file is the logical location of the result. A workflow has no smaller logical
+		// unit which can be identified across runs like a function in source code.
+		LogicalLocations: []*sarifLogicalLocation{{FullyQualifiedName: uri, Kind: "module"}},
+	}
+}
+
+// newSARIFReplacements converts the text edits of the error into SARIF replacements. Columns of
+// the text edits are counted in bytes so they match to the column kind of the run when the lines
+// consist of ASCII characters.
+func newSARIFReplacements(e *ErrorTemplateFields) []*sarifReplacement {
+	rs := make([]*sarifReplacement, 0, len(e.Fixes))
+	for _, f := range e.Fixes {
+		rs = append(rs, &sarifReplacement{
+			DeletedRegion: &sarifRegion{
+				StartLine:   f.Line,
+				StartColumn: f.Column,
+				EndLine:     f.EndLine,
+				EndColumn:   f.EndColumn,
+			},
+			InsertedContent: &sarifArtifactContent{Text: f.NewText},
+		})
+	}
+	return rs
+}
+
+// addSARIFFix adds the text edits of the error to the fix of the result. All edits of errors grouped
+// into one result are put in one fix since they are applied together as -fix flag does.
+func addSARIFFix(r *sarifResult, e *ErrorTemplateFields) {
+	if len(e.Fixes) == 0 || e.Filepath == "" {
+		return
+	}
+	rs := newSARIFReplacements(e)
+	if len(r.Fixes) > 0 {
+		c := r.Fixes[0].ArtifactChanges[0]
+		c.Replacements = append(c.Replacements, rs...)
+		return
+	}
+	r.Fixes = []*sarifFix{
+		{
+			Description: &sarifMessage{Text: "Edits applied by actionlint -fix"},
+			ArtifactChanges: []*sarifArtifactChange{
+				{
+					ArtifactLocation: &sarifArtifactLocation{URI: filepath.ToSlash(e.Filepath)},
+					Replacements:     rs,
+				},
+			},
+		},
+	}
+}
+
+// sarifLevel returns "level" property of SARIF result for the severity of the error.
+func sarifLevel(severity string) string {
+	if severity == SeverityWarning {
+		return "warning"
+	}
+	return "error"
+}
+
+// newSARIFLog converts errors into SARIF log. When grouped is true, errors which have the same rule
+// ID and logical location are grouped into one result. Severities are also compared since one result
+// has only one level. The first occurrence is put in "locations" and distinct positions of other
+// occurrences are put in "relatedLocations" with their messages since GitHub code scanning uses only
+// the first element of "locations". Text edits to fix errors are put in "fixes". Errors read from stdin have no location since SARIF requires an
+// artifact location.
+func newSARIFLog(errs []*ErrorTemplateFields, grouped bool) *sarifLog {
+	kinds := []string{}
+	seen := map[string]struct{}{}
+	for _, e := range errs {
+		if _, ok := seen[e.Kind]; !ok {
+			seen[e.Kind] = struct{}{}
+			kinds = append(kinds, e.Kind)
+		}
+	}
+	sort.Strings(kinds)
+	indices := make(map[string]int, len(kinds))
+	rules := make([]*sarifRule, 0, len(kinds))
+	for i, k := range kinds {
+		indices[k] = i
+		rules = append(rules, &sarifRule{
+			ID:               k,
+			Name:             k,
+			ShortDescription: &sarifMessage{Text: fmt.Sprintf("Errors reported by %q rule of actionlint", k)},
+			HelpURI:          "https://github.com/rhysd/actionlint/blob/main/docs/checks.md",
+		})
+	}
+
+	type key struct {
+		kind     string
+		file     string
+		severity string
+	}
+	type pos struct {
+		line int
+		col  int
+		msg  string
+	}
+	groups := map[key]*sarifResult{}
+	poses := map[key]map[pos]struct{}{}
+	counts := map[*sarifResult]int{}
+
+	results := make([]*sarifResult, 0, len(errs))
+	for _, e := range errs {
+		k := key{e.Kind, e.Filepath, e.Severity}
+		p := pos{e.Line, e.Column, e.Message}
+		if r, ok := groups[k]; grouped && ok {
+			if _, ok := poses[k][p]; ok {
+				continue // Identical error at the same position
+			}
+			poses[k][p] = struct{}{}
+			counts[r]++
+			if e.Filepath == "" {
+				continue
+			}
+			l := newSARIFLocation(e)
+			id := len(r.RelatedLocations) + 1
+			l.ID = &id
+			l.Message = &sarifMessage{Text: e.Message}
+			r.RelatedLocations = append(r.RelatedLocations, l)
+			addSARIFFix(r, e)
+			continue
+		}
+
+		r := &sarifResult{
+			RuleID:    e.Kind,
+			RuleIndex: indices[e.Kind],
+			Level:     sarifLevel(e.Severity),
+			Message:   &sarifMessage{Text: e.Message},
+		}
+		if e.Filepath != "" {
+			r.Locations = []*sarifLocation{newSARIFLocation(e)}
+		}
+		addSARIFFix(r, e)
+		results = append(results, r)
+		if grouped {
+			groups[k] = r
+			poses[k] = map[pos]struct{}{p: {}}
+			counts[r] = 1
+		}
+	}
+
+	for r, n := range counts {
+		if n > 1 {
+			r.Message.Text = fmt.Sprintf("%s (%d errors of this rule in this file)", r.Message.Text, n)
+		}
+	}
+
+	return &sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []*sarifRun{
+			{
+				Tool: &sarifTool{
+					Driver: &sarifDriver{
+						Name:           "actionlint",
+						InformationURI: "https://github.com/rhysd/actionlint",
+						Rules:          rules,
+					},
+				},
+				ColumnKind: "unicodeCodePoints",
+				Results:    results,
+			},
+		},
+	}
+}
+
+func encodeSARIF(errs []*ErrorTemplateFields, grouped bool) (string, error) {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(newSARIFLog(errs, grouped)); err != nil {
+		return "", fmt.Errorf("could not encode errors into SARIF: %w", err)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,224 @@
+package actionlint
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var testSARIFErrors = []*ErrorTemplateFields{
+	{Message: "message 1", Filepath: "file1", Line: 1, Column: 2, Kind: "kind1", Severity: SeverityError, Fixes: []*TextEdit{{Line: 1, Column: 2, EndLine: 1, EndColumn: 5, NewText: "bar"}}},
+	{Message: "message 1", Filepath: "file1", Line: 3, Column: 4, Kind: "kind1", Severity: SeverityError},
+	{Message: "message 1", Filepath: "file1", Line: 3, Column: 4, Kind: "kind1", Severity: SeverityError},
+	{Message: "message 1", Filepath: "file2", Line: 1, Column: 2, Kind: "kind1", Severity: SeverityError},
+	{Message: "message 2", Filepath: "file1", Line: 5, Column: 0, Kind: "kind2", Severity: SeverityWarning},
+	{Message: "message 3", Line: 7, Column: 8, Kind: "kind1", Severity: SeverityError},
+	{Message: "message 4", Filepath: "file1", Line: 9, Column: 1, Kind: "kind1", Severity: SeverityError, Fixes: []*TextEdit{{Line: 9, Column: 1, EndLine: 9, EndColumn: 4, NewText: "foo"}}},
+	{Message: "message 5", Filepath: "file1", Line: 10, Column: 1, Kind: "kind1", Severity: SeverityWarning},
+}
+
+// checkSARIFLog checks required properties of SARIF 2.1.0 log used by actionlint are set.
+// https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+func checkSARIFLog(t *testing.T, out string) *sarifLog {
+	t.Helper()
+	var l sarifLog
+	if err := json.Unmarshal([]byte(out), &l); err != nil {
+		t.Fatalf("output is not valid JSON: %s: %q", err, out)
+	}
+	if l.Version != "2.1.0" || l.Schema == "" {
+		t.Fatalf("unexpected version or schema: %q, %q", l.Version, l.Schema)
+	}
+	if len(l.Runs) != 1 || l.Runs[0].Tool == nil || l.Runs[0].Tool.Driver == nil || l.Runs[0].Tool.Driver.Name != "actionlint" {
+		t.Fatalf("unexpected run: %q", out)
+	}
+	run := l.Runs[0]
+	if run.Results == nil || run.Tool.Driver.Rules == nil {
+		t.Fatalf("results and rules must be arrays: %q", out)
+	}
+	for _, r := range run.Results {
+		if r.Message == nil || r.Message.Text == "" {
+			t.Fatalf("message is missing in result %+v", r)
+		}
+		if r.RuleIndex < 0 || r.RuleIndex >= len(run.Tool.Driver.Rules) || run.Tool.Driver.Rules[r.RuleIndex].ID != r.RuleID {
+			t.Fatalf("rule index %d does not match to rule %q", r.RuleIndex, r.RuleID)
+		}
+		for _, f := range r.Fixes {
+			if f.Description == nil || len(f.ArtifactChanges) == 0 {
+				t.Fatalf("fix is incomplete in result %+v", r)
+			}
+			for _, c := range f.ArtifactChanges {
+				if c.ArtifactLocation == nil || c.ArtifactLocation.URI == "" || len(c.Replacements) == 0 {
+					t.Fatalf("artifact change is incomplete in result %+v", r)
+				}
+				for _, rep := range c.Replacements {
+					if rep.DeletedRegion == nil || rep.DeletedRegion.StartLine < 1 || rep.DeletedRegion.StartColumn < 1 || rep.InsertedContent == nil {
+						t.Fatalf("replacement is incomplete: %+v", rep)
+					}
+				}
+			}
+		}
+		for _, loc := range append(append([]*sarifLocation{}, r.Locations...), r.RelatedLocations...) {
+			p := loc.PhysicalLocation
+			if p == nil || p.ArtifactLocation == nil || p.ArtifactLocation.URI == "" || p.Region == nil {
+				t.Fatalf("physical location is incomplete in result %+v", r)
+			}
+			if p.Region.StartLine < 1 || p.Region.StartColumn < 1 {
+				t.Fatalf("line and column must be positive: %+v", p.Region)
+			}
+		}
+	}
+	return &l
+}
+
+func TestSARIFPerOccurrence(t *testing.T) {
+	f, err := NewErrorFormatter(ErrorFormatSARIF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := f.Print(&b, testSARIFErrors); err != nil {
+		t.Fatal(err)
+	}
+
+	l := checkSARIFLog(t, b.String())
+	run := l.Runs[0]
+	if len(run.Tool.Driver.Rules) != 2 || run.Tool.Driver.Rules[0].ID != "kind1" || run.Tool.Driver.Rules[1].ID != "kind2" {
+		t.Fatalf("unexpected rules: %q", b.String())
+	}
+	if len(run.Results) != len(testSARIFErrors) {
+		t.Fatalf("wanted %d results but got %d", len(testSARIFErrors), len(run.Results))
+	}
+	for i, r := range run.Results {
+		e := testSARIFErrors[i]
+		if r.Message.Text != e.Message || r.RelatedLocations != nil {
+			t.Errorf("unexpected result at %d: %+v", i, r)
+		}
+		if r.Level != e.Severity {
+			t.Errorf("wanted level %q at %d but got %q", e.Severity, i, r.Level)
+		}
+		if e.Filepath == "" {
+			if r.Locations != nil {
+				t.Errorf("error from stdin should have no location: %+v", r.Locations)
+			}
+			continue
+		}
+		if len(r.Locations) != 1 {
+			t.Fatalf("wanted one location but got %+v", r.Locations)
+		}
+		p := r.Locations[0].PhysicalLocation
+		if p.ArtifactLocation.URI != e.Filepath || p.Region.StartLine != e.Line {
+			t.Errorf("unexpected location at %d: %+v", i, p)
+		}
+		if len(e.Fixes) == 0 {
+			if r.Fixes != nil {
+				t.Errorf("unexpected fixes at %d: %+v", i, r.Fixes)
+			}
+			continue
+		}
+		if len(r.Fixes) != 1 || len(r.Fixes[0].ArtifactChanges) != 1 {
+			t.Fatalf("wanted one fix at %d but got %+v", i, r.Fixes)
+		}
+		c := r.Fixes[0].ArtifactChanges[0]
+		f := e.Fixes[0]
+		if c.ArtifactLocation.URI != e.Filepath || len(c.Replacements) != 1 {
+			t.Fatalf("unexpected artifact change at %d: %+v", i, c)
+		}
+		want := sarifRegion{StartLine: f.Line, StartColumn: f.Column, EndLine: f.EndLine, EndColumn: f.EndColumn}
+		if rep := c.Replacements[0]; *rep.DeletedRegion != want || rep.InsertedContent.Text != f.NewText {
+			t.Errorf("unexpected replacement at %d: %+v", i, rep)
+		}
+	}
+}
+
+func TestSARIFGrouped(t *testing.T) {
+	f, err := NewErrorFormatter(ErrorFormatGroupedSARIF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := f.Print(&b, testSARIFErrors); err != nil {
+		t.Fatal(err)
+	}
+
+	l := checkSARIFLog(t, b.String())
+	rs := l.Runs[0].Results
+	want := []struct {
+		msg   string
+		level string
+	}{
+		{"message 1 (3 errors of this rule in this file)", "error"},
+		{"message 1", "error"},
+		{"message 2", "warning"},
+		{"message 3", "error"},
+		{"message 5", "warning"},
+	}
+	if len(rs) != len(want) {
+		t.Fatalf("wanted %d results but got %d: %q", len(want), len(rs), b.String())
+	}
+	for i, w := range want {
+		if rs[i].Message.Text != w.msg || rs[i].Level != w.level {
+			t.Errorf("wanted message %q and level %q at %d but got %q and %q", w.msg, w.level, i, rs[i].Message.Text, rs[i].Level)
+		}
+	}
+
+	if len(rs[0].Locations) != 1 || rs[0].Locations[0].PhysicalLocation.Region.StartLine != 1 {
+		t.Fatalf("first occurrence should be in locations: %+v", rs[0].Locations)
+	}
+	// Errors of the same rule in the same file are grouped even if their messages are different
+	rel := rs[0].RelatedLocations
+	if len(rel) != 2 {
+		t.Fatalf("other occurrences should be in related locations: %+v", rel)
+	}
+	for i, w := range []struct {
+		line int
+		col  int
+		msg  string
+	}{
+		{3, 4, "message 1"},
+		{9, 1, "message 4"},
+	} {
+		l := rel[i]
+		if l.ID == nil || *l.ID != i+1 {
+			t.Fatalf("unexpected ID of related location at %d: %+v", i, l)
+		}
+		if r := l.PhysicalLocation.Region; r.StartLine != w.line || r.StartColumn != w.col {
+			t.Fatalf("unexpected related location at %d: %+v", i, r)
+		}
+		if l.Message == nil || l.Message.Text != w.msg {
+			t.Fatalf("related location at %d should have message %q: %+v", i, w.msg, l.Message)
+		}
+	}
+	// Edits of grouped errors are put in one fix
+	if len(rs[0].Fixes) != 1 || len(rs[0].Fixes[0].ArtifactChanges) != 1 {
+		t.Fatalf("wanted one fix but got %+v", rs[0].Fixes)
+	}
+	reps := rs[0].Fixes[0].ArtifactChanges[0].Replacements
+	if len(reps) != 2 || reps[0].InsertedContent.Text != "bar" || reps[1].InsertedContent.Text != "foo" {
+		t.Fatalf("unexpected replacements: %+v", reps)
+	}
+	if p := rs[1].Locations[0].PhysicalLocation; p.ArtifactLocation.URI != "file2" || rs[1].RelatedLocations != nil {
+		t.Fatalf("errors in different files should not be grouped: %+v", rs[1])
+	}
+	if rs[4].RelatedLocations != nil {
+		t.Fatalf("errors of different severities should not be grouped: %+v", rs[4])
+	}
+}
+
+func TestSARIFNoError(t *testing.T) {
+	for _, format := range []string{ErrorFormatSARIF, ErrorFormatGroupedSARIF} {
+		t.Run(format, func(t *testing.T) {
+			f, err := NewErrorFormatter(format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var b strings.Builder
+			if err := f.Print(&b, []*ErrorTemplateFields{}); err != nil {
+				t.Fatal(err)
+			}
+			l := checkSARIFLog(t, b.String())
+			if len(l.Runs[0].Results) != 0 {
+				t.Fatalf("unexpected results: %q", b.String())
+			}
+		})
+	}
+}
@@ -1,26 +1,74 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"sort"
 
 	"github.com/catthehacker/actionlint"
 	"github.com/kr/pretty"
 )
 
+type typedExpr struct {
+	pos *actionlint.Pos
+	src string
+	ty  actionlint.ExprType
+}
+
+func printExprTypes(out io.Writer, w *actionlint.Workflow) error {
+	ts := []typedExpr{}
+	rule := actionlint.NewRuleExpression(actionlint.NewLocalActionsCache(nil, nil))
+	rule.SetExprTypeHook(func(pos *actionlint.Pos, src string, ty actionlint.ExprType) {
+		ts = append(ts, typedExpr{pos, src, ty})
+	})
+
+	v := actionlint.NewVisitor()
+	v.AddPass(rule)
+	if err := v.Visit(w); err != nil {
+		return err
+	}
+
+	// Jobs are visited in random order. Sort the expressions by their positions to make the output
+	// deterministic
+	sort.SliceStable(ts, func(i, j int) bool {
+		l, r := ts[i].pos, ts[j].pos
+		if l.Line != r.Line {
+			return l.Line < r.Line
+		}
+		return l.Col < r.Col
+	})
+
+	for _, t := range ts {
+		fmt.Fprintf(out, "%d:%d: %s => %s\n", t.pos.Line, t.pos.Col, t.src, t.ty.String())
+	}
+	return nil
+}
+
 func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	types := flags.Bool("types", false, "Print inferred types of all expressions in the workflow instead of AST")
+	help := flags.Bool("help", false, "Show this help")
+	flags.BoolVar(help, "h", false, "Show this help")
+	if err := flags.Parse(args[1:]); err != nil {
+		return 1
+	}
+	if *help {
+		fmt.Fprintln(stdout, "Usage: go run ./scripts/actionlint-workflow-ast [-types] {workflow_file}")
+		flags.SetOutput(stdout)
+		flags.PrintDefaults()
+		return 0
+	}
+
 	var src []byte
 	var err error
-	if len(args) <= 1 {
+	if flags.NArg() == 0 {
 		src, err = ioutil.ReadAll(stdin)
 	} else {
-		if args[1] == "-h" || args[1] == "-help" || args[1] == "--help" {
-			fmt.Fprintln(stdout, "Usage: go run ./scripts/actionlint-workflow-ast {workflow_file}")
-			return 0
-		}
-		src, err = ioutil.ReadFile(args[1])
+		src, err = ioutil.ReadFile(flags.Arg(0))
 	}
 	if err != nil {
 		fmt.Fprintln(stderr, err)
@@ -33,6 +81,13 @@ func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
 		}
 		return 1
 	}
+	if *types {
+		if err := printExprTypes(stdout, w); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		return 0
+	}
 	pretty.Fprintf(stdout, "%# v\n", w)
 	return 0
 }
@@ -121,3 +121,32 @@ func TestViewASTErrorFailToReadStdin(t *testing.T) {
 		t.Fatal("unexpected stderr:", out)
 	}
 }
+
+func TestViewASTPrintExprTypes(t *testing.T) {
+	stdin := strings.NewReader(`on: push
+
+jobs:
+  test:
+    strategy:
+      matrix:
+        os: [ubuntu-latest, macos-latest]
+    runs-on: ${{ matrix.os }}
+    steps:
+      - run: echo ${{ toJSON(github.event) }}
+        if: github.event_name == 'push'
+      - run: echo '${{ matrix }}'`)
+	stdout := &bytes.Buffer{}
+	stderr := ioutil.Discard
+	s := run([]string{"actionlint-workflow-ast", "-types"}, stdin, stdout, stderr)
+	if s != 0 {
+		t.Fatal("exit status is non-zero:", s)
+	}
+	want := `8:14: matrix.os => string
+10:19: toJSON(github.event) => string
+11:13: github.event_name == 'push' => bool
+12:20: matrix => {os: string}
+`
+	if have := stdout.String(); have != want {
+		t.Fatalf("wanted %q but got %q", want, have)
+	}
+}
@@ -65,6 +65,7 @@ var popularActions = []*action{
 	{"actions-rs/toolchain", []string{"v1"}, "v2", yamlExtYML},
 	{"actions/cache", []string{"v1", "v2", "v3"}, "v4", yamlExtYML},
 	{"actions/checkout", []string{"v1", "v2", "v3"}, "v4", yamlExtYML},
+	{"actions/create-release", []string{"v1"}, "", yamlExtYML},
 	{"actions/delete-package-versions", []string{"v1", "v2", "v3"}, "v4", yamlExtYML},
 	{"actions/download-artifact", []string{"v1", "v2", "v3"}, "v4", yamlExtYML},
 	{"actions/first-interaction", []string{"v1"}, "v2", yamlExtYML},
@@ -77,6 +78,7 @@ var popularActions = []*action{
 	{"actions/setup-python", []string{"v1", "v2", "v3"}, "v4", yamlExtYML},
 	{"actions/stale", []string{"v1", "v2", "v3", "v4", "v5"}, "v6", yamlExtYML},
 	{"actions/upload-artifact", []string{"v1", "v2", "v3"}, "v4", yamlExtYML},
+	{"actions/upload-release-asset", []string{"v1"}, "", yamlExtYML},
 	{"aws-actions/configure-aws-credentials", []string{"v1"}, "v2", yamlExtYML},
 	{"azure/aks-set-context", []string{"v1"}, "v2", yamlExtYML},
 	{"azure/login", []string{"v1"}, "v2", yamlExtYML},
@@ -147,6 +149,35 @@ var doNotCheckOutputs = slugSet{
 	"getsentry/paths-filter": {},
 }
 
+type deprecation struct {
+	reason      string
+	replacement string
+}
+
+// Actions which are deprecated or archived. Keys are slugs or specs (owner/repo@ref). A slug matches
+// all versions of the action. Using these actions is reported with their recommended replacements.
+var deprecatedActions = map[string]*deprecation{
+	"actions/create-release":       {"this repository was archived and is no longer maintained", "softprops/action-gh-release@v1"},
+	"actions/setup-node@v1":        {"this version runs on Node.js 12 which is no longer supported by GitHub-hosted runners", "actions/setup-node@v3"},
+	"actions/upload-release-asset": {"this repository was archived and is no longer maintained", "softprops/action-gh-release@v1"},
+}
+
+// Permissions of GITHUB_TOKEN required by actions. Keys are slugs or specs (owner/repo@ref). A slug
+// matches all versions of the action. Values map permission scopes to their required levels. These
+// are not in action.yml so they are maintained manually.
+var requiredPermissions = map[string]map[string]string{
+	"JamesIves/github-pages-deploy-action":  {"contents": "write"},
+	"actions/create-release":                {"contents": "write"},
+	"actions/labeler":                       {"contents": "read", "pull-requests": "write"},
+	"actions/stale":                         {"issues": "write", "pull-requests": "write"},
+	"actions/upload-release-asset":          {"contents": "write"},
+	"github/codeql-action/analyze":          {"security-events": "write"},
+	"marvinpinto/action-automatic-releases": {"contents": "write"},
+	"ncipollo/release-action":               {"contents": "write"},
+	"peaceiris/actions-gh-pages":            {"contents": "write"},
+	"peter-evans/create-pull-request":       {"contents": "write", "pull-requests": "write"},
+}
+
 type app struct {
 	stdout      io.Writer
 	stderr      io.Writer
@@ -222,6 +253,21 @@ func (a *app) fetchRemote() (map[string]*actionlint.ActionMetadata, error) {
 					if _, ok := a.skipOutputs[req.slug]; ok {
 						meta.SkipOutputs = true
 					}
+					d, ok := deprecatedActions[spec]
+					if !ok {
+						d, ok = deprecatedActions[req.slug]
+					}
+					if ok {
+						meta.Deprecated = d.reason
+						meta.Replacement = d.replacement
+					}
+					p, ok := requiredPermissions[spec]
+					if !ok {
+						p, ok = requiredPermissions[req.slug]
+					}
+					if ok {
+						meta.Permissions = p
+					}
 					ret <- &fetched{spec: spec, meta: &meta}
 				case <-done:
 					return
@@ -335,6 +381,31 @@ var PopularActions = map[string]*ActionMetadata{
 			fmt.Fprintf(b, "},\n")
 		}
 
+		if meta.Runs != nil && meta.Runs.Using != "" {
+			fmt.Fprintf(b, "Runs: &ActionMetadataRuns{Using: %q},\n", meta.Runs.Using)
+		}
+
+		if len(meta.Permissions) > 0 {
+			scopes := make([]string, 0, len(meta.Permissions))
+			for s := range meta.Permissions {
+				scopes = append(scopes, s)
+			}
+			sort.Strings(scopes)
+
+			fmt.Fprintf(b, "Permissions: map[string]string{\n")
+			for _, s := range scopes {
+				fmt.Fprintf(b, "%q: %q,\n", s, meta.Permissions[s])
+			}
+			fmt.Fprintf(b, "},\n")
+		}
+
+		if meta.Deprecated != "" {
+			fmt.Fprintf(b, "Deprecated: %q,\n", meta.Deprecated)
+			if meta.Replacement != "" {
+				fmt.Fprintf(b, "Replacement: %q,\n", meta.Replacement)
+			}
+		}
+
 		fmt.Fprintf(b, "},\n")
 	}
 
@@ -83,6 +83,15 @@ func TestReadWriteJSONL(t *testing.T) {
 			file:        "skip_outputs.jsonl",
 			skipOutputs: slugSet{"rhysd/action-setup-vim": {}},
 		},
+		{
+			file: "deprecated.jsonl",
+		},
+		{
+			file: "permissions.jsonl",
+		},
+		{
+			file: "runs.jsonl",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -131,6 +140,18 @@ func TestWriteGoToStdout(t *testing.T) {
 			want:        "skip_outputs_want.go",
 			skipOutputs: slugSet{"rhysd/action-setup-vim": {}},
 		},
+		{
+			in:   "deprecated.jsonl",
+			want: "deprecated_want.go",
+		},
+		{
+			in:   "permissions.jsonl",
+			want: "permissions_want.go",
+		},
+		{
+			in:   "runs.jsonl",
+			want: "runs_want.go",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,86 @@
+package actionlint
+
+import (
+	"strings"
+)
+
+// shellResolver resolves the shell which runs scripts at 'run:' of steps. The shell is resolved in
+// order of 'shell:' of the step, 'defaults.run.shell' of the job, and 'defaults.run.shell' of the
+// workflow. When none of them is specified, the default shell of the runner is used.
+// https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#defaultsrunshell
+type shellResolver struct {
+	workflow *String
+	job      *String
+	windows  bool
+}
+
+// enterWorkflow sets the default shell of the workflow. This should be called at VisitWorkflowPre.
+func (r *shellResolver) enterWorkflow(n *Workflow) {
+	r.workflow = nil
+	if n.Defaults != nil && n.Defaults.Run != nil {
+		r.workflow = n.Defaults.Run.Shell
+	}
+}
+
+// leaveWorkflow resets the default shell of the workflow. This should be called at
+// VisitWorkflowPost.
+func (r *shellResolver) leaveWorkflow() {
+	r.workflow = nil
+}
+
+// enterJob sets the default shell and the runner OS of the job. This should be called at
+// VisitJobPre.
+func (r *shellResolver) enterJob(n *Job) {
+	r.job = nil
+	if n.Defaults != nil && n.Defaults.Run != nil {
+		r.job = n.Defaults.Run.Shell
+	}
+	r.windows = false
+	if n.RunsOn != nil {
+		for _, label := range n.RunsOn.Labels {
+			l := strings.ToLower(label.Value)
+			if l == "windows" || strings.HasPrefix(l, "windows-") {
+				r.windows = true
+				break
+			}
+		}
+	}
+}
+
+// leaveJob resets the default shell and the runner OS of the job. This should be called at
+// VisitJobPost.
+func (r *shellResolver) leaveJob() {
+	r.job = nil
+	r.windows = false
+}
+
+// resolve returns the value of 'shell:' which is applied to the step. It returns nil when no shell
+// is specified and the default shell of the runner is used.
+func (r *shellResolver) resolve(run *ExecRun) *String {
+	if run.Shell != nil {
+		return run.Shell
+	}
+	if r.job != nil {
+		return r.job
+	}
+	return r.workflow
+}
+
+// name returns the name of the shell which runs the script at 'run:' of the step. When the shell is
+// specified with arguments like "bash -e {0}", the command name is returned. When no shell is
+// specified, it returns "pwsh" on Windows runners and "bash" on other runners.
+// https://docs.github.com/en/actions/using-workflows/workflow-syntax-for-github-actions#using-a-specific-shell
+func (r *shellResolver) name(run *ExecRun) string {
+	s := r.resolve(run)
+	if s == nil {
+		if r.windows {
+			return "pwsh"
+		}
+		// TODO: When bash is not found, GitHub-hosted runner fallbacks to sh. What OSes require this behavior?
+		return "bash"
+	}
+	if ss := strings.Fields(s.Value); len(ss) > 0 {
+		return ss[0]
+	}
+	return ""
+}
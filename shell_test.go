@@ -0,0 +1,108 @@
+package actionlint
+
+import (
+	"testing"
+)
+
+func TestShellResolverName(t *testing.T) {
+	str := func(s string) *String {
+		if s == "" {
+			return nil
+		}
+		return &String{Value: s, Pos: &Pos{}}
+	}
+	defaults := func(s string) *Defaults {
+		if s == "" {
+			return nil
+		}
+		return &Defaults{Run: &DefaultsRun{Shell: str(s)}}
+	}
+
+	testCases := []struct {
+		what     string
+		workflow string
+		job      string
+		step     string
+		runsOn   string
+		want     string
+	}{
+		{
+			what:   "no shell on Linux",
+			runsOn: "ubuntu-latest",
+			want:   "bash",
+		},
+		{
+			what:   "no shell on Windows",
+			runsOn: "windows-latest",
+			want:   "pwsh",
+		},
+		{
+			what:   "step shell",
+			step:   "python",
+			runsOn: "ubuntu-latest",
+			want:   "python",
+		},
+		{
+			what:   "step shell with arguments",
+			step:   "bash -e {0}",
+			runsOn: "ubuntu-latest",
+			want:   "bash",
+		},
+		{
+			what:   "job default",
+			job:    "sh",
+			runsOn: "ubuntu-latest",
+			want:   "sh",
+		},
+		{
+			what:     "workflow default on Linux",
+			workflow: "pwsh",
+			runsOn:   "ubuntu-latest",
+			want:     "pwsh",
+		},
+		{
+			what:     "workflow default on Windows",
+			workflow: "bash",
+			runsOn:   "windows-latest",
+			want:     "bash",
+		},
+		{
+			what:     "job default overrides workflow default",
+			workflow: "pwsh",
+			job:      "python",
+			runsOn:   "ubuntu-latest",
+			want:     "python",
+		},
+		{
+			what:     "step shell overrides all defaults",
+			workflow: "pwsh",
+			job:      "python",
+			step:     "sh -e {0}",
+			runsOn:   "windows-latest",
+			want:     "sh",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.what, func(t *testing.T) {
+			w := &Workflow{Defaults: defaults(tc.workflow)}
+			j := &Job{
+				Defaults: defaults(tc.job),
+				RunsOn:   &Runner{Labels: []*String{str(tc.runsOn)}},
+			}
+			r := &ExecRun{Shell: str(tc.step)}
+
+			var s shellResolver
+			s.enterWorkflow(w)
+			s.enterJob(j)
+			if have := s.name(r); have != tc.want {
+				t.Fatalf("wanted %q but got %q", tc.want, have)
+			}
+			s.leaveJob()
+			s.leaveWorkflow()
+			if s.workflow != nil || s.job != nil || s.windows {
+				t.Fatalf("state was not reset: %#v", s)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package actionlint
+
+import "sort"
+
+// editDistance calculates Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	if a == b {
+		return 0
+	}
+	if len(a) == 0 {
+		return len(b)
+	}
+	if len(b) == 0 {
+		return len(a)
+	}
+
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d := prev[j] + 1 // Deletion
+			if c := cur[j-1] + 1; c < d {
+				d = c // Insertion
+			}
+			if c := prev[j-1] + cost; c < d {
+				d = c // Substitution
+			}
+			cur[j] = d
+		}
+		prev, cur = cur, prev
+	}
+
+	return prev[len(b)]
+}
+
+// findSimilarStrings finds candidates which are similar to the given string. Candidates are
+// considered similar when their edit distance is small enough compared to their lengths. Returned
+// slice is sorted by the distance and then alphabetically.
+func findSimilarStrings(s string, candidates []string) []string {
+	max := len(s) / 3
+	if max < 2 {
+		max = 2
+	}
+
+	type similar struct {
+		value string
+		dist  int
+	}
+	found := []similar{}
+	for _, c := range candidates {
+		if d := editDistance(s, c); d <= max && d < len(c) {
+			found = append(found, similar{c, d})
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		if found[i].dist != found[j].dist {
+			return found[i].dist < found[j].dist
+		}
+		return found[i].value < found[j].value
+	})
+
+	ret := make([]string, 0, len(found))
+	for _, f := range found {
+		ret = append(ret, f.value)
+	}
+	return ret
+}
@@ -0,0 +1,53 @@
+package actionlint
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSimilarEditDistance(t *testing.T) {
+	testCases := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{"", "", 0},
+		{"foo", "", 3},
+		{"", "foo", 3},
+		{"foo", "foo", 0},
+		{"opend", "opened", 1},
+		{"synchronized", "synchronize", 1},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+	}
+
+	for _, tc := range testCases {
+		if have := editDistance(tc.a, tc.b); have != tc.want {
+			t.Errorf("edit distance between %q and %q should be %d but got %d", tc.a, tc.b, tc.want, have)
+		}
+	}
+}
+
+func TestSimilarFindSimilarStrings(t *testing.T) {
+	cands := []string{"opened", "reopened", "closed", "edited", "labeled", "unlabeled"}
+	testCases := []struct {
+		input string
+		want  []string
+	}{
+		{"opend", []string{"opened"}},
+		{"labled", []string{"labeled"}},
+		{"close", []string{"closed"}},
+		{"created", []string{}},
+		{"x", []string{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			have := findSimilarStrings(tc.input, cands)
+			if diff := cmp.Diff(tc.want, have); diff != "" {
+				t.Fatal(diff)
+			}
+		})
+	}
+}
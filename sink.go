@@ -0,0 +1,177 @@
+package actionlint
+
+import (
+	"sort"
+	"sync"
+)
+
+// errorSink is an output sink of errors in linted files. Files are added in arbitrary order since
+// they are linted in parallel, but their errors are always printed in the order of their indices
+// to make output deterministic. Errors are printed considering the max number of findings.
+//
+// The sink works in streaming mode or buffered mode. In streaming mode, errors of a file are
+// printed as soon as the file and all files before it are added. It is used for the default format
+// and "ghactions" format whose outputs can be separated per file. In buffered mode, errors of all
+// files are printed at once on flush since custom templates like {{json .}} produce one document
+// from all errors.
+//
+// When a comparator to sort errors is set, nothing is printed until flush because errors of all
+// files need to be sorted before printing them.
+type errorSink struct {
+	l         *Linter
+	mu        sync.Mutex
+	streaming bool
+	files     []*lintedFile
+	next      int // Index of the next file to be printed
+	printed   int
+	omitted   int
+	temp      []*ErrorTemplateFields // Template fields of errors to be printed in buffered mode
+}
+
+func (l *Linter) newErrorSink(n int) *errorSink {
+	return &errorSink{
+		l:         l,
+		streaming: l.errFmt == nil || l.errFmt.streamable,
+		files:     make([]*lintedFile, n),
+	}
+}
+
+// add adds the linted file at the index. In streaming mode, errors of the files which are ready
+// to be printed are printed. Calling this method is thread-safe.
+func (s *errorSink) add(idx int, w *lintedFile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.files[idx] = w
+	if s.l.sortLess != nil {
+		return nil // Errors are sorted and printed on flush
+	}
+	for s.next < len(s.files) && s.files[s.next] != nil {
+		if err := s.print(s.files[s.next]); err != nil {
+			return err
+		}
+		s.next++
+	}
+	return nil
+}
+
+func (s *errorSink) print(w *lintedFile) error {
+	errs, omitted := s.l.limitErrors(w.errs, s.printed)
+	s.printed += len(errs)
+	s.omitted += omitted
+
+	if !s.streaming {
+		for _, err := range errs {
+			s.temp = append(s.temp, err.GetTemplateFields(w.src))
+		}
+		return nil
+	}
+
+	s.l.outMu.Lock() // Outputs from multiple sinks should not be mixed
+	defer s.l.outMu.Unlock()
+	if s.l.errFmt != nil {
+		return s.l.errFmt.PrintErrors(s.l.out, errs, w.src)
+	}
+	s.l.printErrors(errs, w.src)
+	if s.l.maxPerFile {
+		s.l.printOmitted(omitted, w.path)
+	}
+	return nil
+}
+
+// flush prints the remaining output and returns errors of all files in the order of their indices.
+// All files must be added before calling this method.
+func (s *errorSink) flush() ([]*Error, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.l.sortLess != nil {
+		return s.flushSorted()
+	}
+
+	total := 0
+	for _, w := range s.files {
+		total += len(w.errs)
+	}
+	all := make([]*Error, 0, total)
+	for _, w := range s.files {
+		all = append(all, w.errs...)
+	}
+
+	s.l.outMu.Lock()
+	defer s.l.outMu.Unlock()
+	if !s.streaming {
+		if s.temp == nil {
+			s.temp = []*ErrorTemplateFields{}
+		}
+		if err := s.l.errFmt.Print(s.l.out, s.temp); err != nil {
+			return nil, err
+		}
+	} else if !s.l.maxPerFile {
+		s.l.printOmitted(s.omitted, "")
+	}
+	if s.omitted > 0 {
+		s.l.log("Omitted", s.omitted, "errors from output due to max findings", s.l.maxFindings)
+	}
+
+	return all, nil
+}
+
+// flushSorted sorts errors of all files with the comparator and prints them considering the max
+// number of findings. It returns all errors in the sorted order.
+func (s *errorSink) flushSorted() ([]*Error, error) {
+	type sortedError struct {
+		err *Error
+		src []byte
+	}
+
+	all := []*Error{}
+	errs := []*sortedError{}
+	for _, w := range s.files {
+		all = append(all, w.errs...)
+		es, omitted := w.errs, 0
+		if s.l.maxPerFile {
+			es, omitted = s.l.limitErrors(w.errs, 0)
+		}
+		s.omitted += omitted
+		for _, err := range es {
+			errs = append(errs, &sortedError{err, w.src})
+		}
+	}
+	SortErrors(all, s.l.sortLess)
+	sort.SliceStable(errs, func(i, j int) bool {
+		return s.l.sortLess(errs[i].err, errs[j].err)
+	})
+	if !s.l.maxPerFile && s.l.maxFindings > 0 && len(errs) > s.l.maxFindings {
+		s.omitted += len(errs) - s.l.maxFindings
+		errs = errs[:s.l.maxFindings]
+	}
+
+	s.l.outMu.Lock()
+	defer s.l.outMu.Unlock()
+	if s.streaming {
+		for _, e := range errs {
+			if s.l.errFmt != nil {
+				if err := s.l.errFmt.PrintErrors(s.l.out, []*Error{e.err}, e.src); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			s.l.printErrors([]*Error{e.err}, e.src)
+		}
+		s.l.printOmitted(s.omitted, "")
+	} else {
+		temp := make([]*ErrorTemplateFields, 0, len(errs))
+		for _, e := range errs {
+			temp = append(temp, e.err.GetTemplateFields(e.src))
+		}
+		if err := s.l.errFmt.Print(s.l.out, temp); err != nil {
+			return nil, err
+		}
+	}
+	if s.omitted > 0 {
+		s.l.log("Omitted", s.omitted, "errors from output due to max findings", s.l.maxFindings)
+	}
+
+	return all, nil
+}
@@ -0,0 +1,135 @@
+package actionlint
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func testLintedFileForSink(path string, msgs ...string) *lintedFile {
+	errs := make([]*Error, 0, len(msgs))
+	for i, m := range msgs {
+		errs = append(errs, &Error{
+			Message:  m,
+			Filepath: path,
+			Line:     i + 1,
+			Column:   1,
+			Kind:     "test",
+		})
+	}
+	return &lintedFile{path: path, errs: errs}
+}
+
+func TestErrorSinkStreamingInPathOrder(t *testing.T) {
+	for _, format := range []string{"", ErrorFormatGitHubActions, ErrorFormatJSONL} {
+		t.Run(format, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			l, err := NewLinter(out, &LinterOptions{Oneline: true, Format: format})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s := l.newErrorSink(3)
+			if !s.streaming {
+				t.Fatal("sink is not in streaming mode")
+			}
+
+			if err := s.add(1, testLintedFileForSink("b.yaml", "error in b")); err != nil {
+				t.Fatal(err)
+			}
+			if out.Len() != 0 {
+				t.Fatalf("errors of the second file were printed before the first file: %q", out.String())
+			}
+
+			if err := s.add(0, testLintedFileForSink("a.yaml", "error in a")); err != nil {
+				t.Fatal(err)
+			}
+			o := out.String()
+			ia, ib := strings.Index(o, "error in a"), strings.Index(o, "error in b")
+			if ia < 0 || ib < 0 || ia > ib {
+				t.Fatalf("errors of completed files were not printed in order: %q", o)
+			}
+
+			if err := s.add(2, testLintedFileForSink("c.yaml", "error in c")); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out.String(), "error in c") {
+				t.Fatalf("errors of the last file were not printed: %q", out.String())
+			}
+
+			all, err := s.flush()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(all) != 3 {
+				t.Fatal("all errors were not returned:", all)
+			}
+			for i, want := range []string{"a.yaml", "b.yaml", "c.yaml"} {
+				if all[i].Filepath != want {
+					t.Errorf("file path of error at %d should be %q but got %q", i, want, all[i].Filepath)
+				}
+			}
+		})
+	}
+}
+
+func TestErrorSinkBufferedWithCustomFormat(t *testing.T) {
+	out := &bytes.Buffer{}
+	l, err := NewLinter(out, &LinterOptions{Format: "{{json .}}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := l.newErrorSink(2)
+	if s.streaming {
+		t.Fatal("sink should not be in streaming mode with custom format")
+	}
+	if err := s.add(1, testLintedFileForSink("b.yaml", "error in b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.add(0, testLintedFileForSink("a.yaml", "error in a")); err != nil {
+		t.Fatal(err)
+	}
+	if out.Len() != 0 {
+		t.Fatalf("errors were printed before flush: %q", out.String())
+	}
+
+	if _, err := s.flush(); err != nil {
+		t.Fatal(err)
+	}
+	o := out.String()
+	if !strings.HasPrefix(o, "[") || strings.Count(o, "[") != 1 {
+		t.Fatalf("errors were not printed as one JSON array: %q", o)
+	}
+	ia, ib := strings.Index(o, "error in a"), strings.Index(o, "error in b")
+	if ia < 0 || ib < 0 || ia > ib {
+		t.Fatalf("errors were not printed in order: %q", o)
+	}
+}
+
+func TestErrorSinkMaxFindings(t *testing.T) {
+	out := &bytes.Buffer{}
+	l, err := NewLinter(out, &LinterOptions{Oneline: true, MaxFindings: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := l.newErrorSink(2)
+	if err := s.add(1, testLintedFileForSink("b.yaml", "error 3 in b", "error 4 in b")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.add(0, testLintedFileForSink("a.yaml", "error 1 in a")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	o := out.String()
+	if !strings.Contains(o, "error 1 in a") || !strings.Contains(o, "error 3 in b") || strings.Contains(o, "error 4 in b") {
+		t.Fatalf("max findings was not applied in path order: %q", o)
+	}
+	if !strings.HasSuffix(o, "... and 1 more errors\n") {
+		t.Fatalf("number of omitted errors was not printed at the end: %q", o)
+	}
+}
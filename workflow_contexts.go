@@ -0,0 +1,43 @@
+package actionlint
+
+import (
+	"sort"
+	"strings"
+)
+
+// UsedContexts returns contexts referenced by expressions in ${{ }} placeholders and 'if:'
+// conditions in the workflow. Keys of the returned map are lower-cased context names like "github"
+// or "secrets", and values are positions of the references sorted by their lines and columns. It
+// is useful for auditing workflows, for example, to find workflows which touch secrets.
+// Expressions are walked in the same manner as the "expression" rule. Broken expressions are
+// ignored.
+func (w *Workflow) UsedContexts() map[string][]*Pos {
+	used := map[string][]*Pos{}
+
+	rule := NewRuleExpression(NewLocalActionsCache(nil, nil))
+	rule.exprHook = func(expr ExprNode, line, col int) {
+		VisitExprNode(expr, func(n, _ ExprNode, entering bool) {
+			// All variables in expressions are contexts
+			if v, ok := n.(*VariableNode); ok && entering {
+				t := v.Token()
+				name := strings.ToLower(v.Name)
+				used[name] = append(used[name], convertExprLineColToPos(t.Line, t.Column, line, col))
+			}
+		})
+	}
+
+	v := NewVisitor()
+	v.AddPass(rule)
+	v.Visit(w) // Errors from the rule are not returned from Visit
+
+	for _, ps := range used {
+		sort.Slice(ps, func(i, j int) bool {
+			if ps[i].Line != ps[j].Line {
+				return ps[i].Line < ps[j].Line
+			}
+			return ps[i].Col < ps[j].Col
+		})
+	}
+
+	return used
+}
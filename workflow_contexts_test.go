@@ -0,0 +1,70 @@
+package actionlint
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWorkflowUsedContexts(t *testing.T) {
+	src := `on:
+  workflow_dispatch:
+    inputs:
+      name:
+        type: string
+env:
+  TOKEN: ${{ secrets.TOKEN }}
+jobs:
+  build:
+    if: github.event_name == 'push'
+    runs-on: ${{ matrix.os }}
+    strategy:
+      matrix:
+        os: [ubuntu-latest]
+    steps:
+      - run: echo "${{ inputs.name }} ${{ env.TOKEN }}"
+        id: hello
+      - run: echo '${{ steps.hello.outcome }}'
+        if: ${{ SECRETS.PASSWORD != '' }}
+  test:
+    needs: [build]
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo '${{ needs.build.result }}'
+`
+	w, errs := Parse([]byte(src))
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	want := map[string][]*Pos{
+		"secrets": {{Line: 7, Col: 14}, {Line: 19, Col: 17}},
+		"github":  {{Line: 10, Col: 9}},
+		"matrix":  {{Line: 11, Col: 18}},
+		"inputs":  {{Line: 16, Col: 24}},
+		"env":     {{Line: 16, Col: 43}},
+		"steps":   {{Line: 18, Col: 24}},
+		"needs":   {{Line: 24, Col: 24}},
+	}
+	have := w.UsedContexts()
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestWorkflowUsedContextsNoExpression(t *testing.T) {
+	src := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+`
+	w, errs := Parse([]byte(src))
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if have := w.UsedContexts(); len(have) != 0 {
+		t.Fatalf("wanted no context but got %v", have)
+	}
+}
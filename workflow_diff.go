@@ -0,0 +1,361 @@
+package actionlint
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// WorkflowDiffKind is kind of structural difference between two workflows.
+type WorkflowDiffKind int
+
+const (
+	// WorkflowDiffAdded is kind of difference where the element only exists in the second workflow.
+	WorkflowDiffAdded WorkflowDiffKind = iota
+	// WorkflowDiffRemoved is kind of difference where the element only exists in the first workflow.
+	WorkflowDiffRemoved
+	// WorkflowDiffChanged is kind of difference where the element exists in both workflows but its
+	// value is different.
+	WorkflowDiffChanged
+)
+
+// WorkflowDiff is a structural difference between two workflows found by DiffWorkflows.
+type WorkflowDiff struct {
+	// Kind is kind of the difference.
+	Kind WorkflowDiffKind
+	// Path is a dot-separated path to the different element like "jobs.build.steps.test.run". Steps
+	// are identified by their IDs. Steps without ID are identified by their 1-based indices like
+	// "#2".
+	Path string
+	// Before is a value in the first workflow. It is empty unless Kind is WorkflowDiffChanged.
+	Before string
+	// After is a value in the second workflow. It is empty unless Kind is WorkflowDiffChanged.
+	After string
+}
+
+// String returns a one-line description of the difference. "+", "-" and "~" are prefixed for
+// added, removed and changed elements respectively.
+func (d *WorkflowDiff) String() string {
+	switch d.Kind {
+	case WorkflowDiffAdded:
+		return "+ " + d.Path
+	case WorkflowDiffRemoved:
+		return "- " + d.Path
+	default:
+		return fmt.Sprintf("~ %s: %s -> %s", d.Path, d.Before, d.After)
+	}
+}
+
+// DiffWorkflows compares two workflows structurally and returns their differences in triggers,
+// jobs and steps. Unlike line diff, formatting, comments and order of mapping keys don't matter.
+// Jobs are matched by their IDs and steps are matched by their IDs or indices. Empty slice is
+// returned when no difference is found.
+func DiffWorkflows(a, b *Workflow) []*WorkflowDiff {
+	d := &workflowDiffer{diffs: []*WorkflowDiff{}}
+	d.str("name", a.Name, b.Name)
+	d.events(a.On, b.On)
+	d.env("env", a.Env, b.Env)
+	d.jobs(a.Jobs, b.Jobs)
+	return d.diffs
+}
+
+type workflowDiffer struct {
+	diffs []*WorkflowDiff
+}
+
+func (d *workflowDiffer) add(kind WorkflowDiffKind, path string) {
+	d.diffs = append(d.diffs, &WorkflowDiff{Kind: kind, Path: path})
+}
+
+func (d *workflowDiffer) changed(path, before, after string) {
+	if before != after {
+		d.diffs = append(d.diffs, &WorkflowDiff{WorkflowDiffChanged, path, before, after})
+	}
+}
+
+// keys compares keys of two sets. Keys only in one of them are reported as added or removed. Keys
+// in both of them are returned in sorted order.
+func (d *workflowDiffer) keys(path string, a, b map[string]struct{}) []string {
+	common := []string{}
+	for k := range a {
+		if _, ok := b[k]; ok {
+			common = append(common, k)
+		}
+	}
+	sort.Strings(common)
+
+	for _, k := range sortedKeys(a) {
+		if _, ok := b[k]; !ok {
+			d.add(WorkflowDiffRemoved, path+"."+k)
+		}
+	}
+	for _, k := range sortedKeys(b) {
+		if _, ok := a[k]; !ok {
+			d.add(WorkflowDiffAdded, path+"."+k)
+		}
+	}
+
+	return common
+}
+
+func sortedKeys(m map[string]struct{}) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
+
+func (d *workflowDiffer) str(path string, a, b *String) {
+	d.changed(path, diffValueOfString(a), diffValueOfString(b))
+}
+
+func (d *workflowDiffer) strs(path string, a, b []*String) {
+	d.changed(path, diffValueOfStrings(a), diffValueOfStrings(b))
+}
+
+func diffValueOfString(s *String) string {
+	if s == nil {
+		return "(none)"
+	}
+	return strconv.Quote(s.Value)
+}
+
+func diffValueOfStrings(ss []*String) string {
+	if ss == nil {
+		return "(none)"
+	}
+	qs := make([]string, 0, len(ss))
+	for _, s := range ss {
+		qs = append(qs, strconv.Quote(s.Value))
+	}
+	return "[" + strings.Join(qs, ", ") + "]"
+}
+
+func (d *workflowDiffer) events(a, b []Event) {
+	ea := make(map[string]Event, len(a))
+	sa := make(map[string]struct{}, len(a))
+	for _, e := range a {
+		ea[e.EventName()] = e
+		sa[e.EventName()] = struct{}{}
+	}
+	eb := make(map[string]Event, len(b))
+	sb := make(map[string]struct{}, len(b))
+	for _, e := range b {
+		eb[e.EventName()] = e
+		sb[e.EventName()] = struct{}{}
+	}
+
+	for _, n := range d.keys("on", sa, sb) {
+		path := "on." + n
+		switch a := ea[n].(type) {
+		case *WebhookEvent:
+			b := eb[n].(*WebhookEvent)
+			d.strs(path+".types", a.Types, b.Types)
+			d.strs(path+".branches", a.Branches, b.Branches)
+			d.strs(path+".branches-ignore", a.BranchesIgnore, b.BranchesIgnore)
+			d.strs(path+".tags", a.Tags, b.Tags)
+			d.strs(path+".tags-ignore", a.TagsIgnore, b.TagsIgnore)
+			d.strs(path+".paths", a.Paths, b.Paths)
+			d.strs(path+".paths-ignore", a.PathsIgnore, b.PathsIgnore)
+			d.strs(path+".workflows", a.Workflows, b.Workflows)
+		case *ScheduledEvent:
+			d.strs(path+".cron", a.Cron, eb[n].(*ScheduledEvent).Cron)
+		case *RepositoryDispatchEvent:
+			d.strs(path+".types", a.Types, eb[n].(*RepositoryDispatchEvent).Types)
+		case *WorkflowDispatchEvent:
+			ia := make(map[string]struct{}, len(a.Inputs))
+			for k := range a.Inputs {
+				ia[k] = struct{}{}
+			}
+			b := eb[n].(*WorkflowDispatchEvent)
+			ib := make(map[string]struct{}, len(b.Inputs))
+			for k := range b.Inputs {
+				ib[k] = struct{}{}
+			}
+			d.keys(path+".inputs", ia, ib)
+		case *WorkflowCallEvent:
+			b := eb[n].(*WorkflowCallEvent)
+			ia, ib := map[string]struct{}{}, map[string]struct{}{}
+			for k := range a.Inputs {
+				ia[k.Value] = struct{}{}
+			}
+			for k := range b.Inputs {
+				ib[k.Value] = struct{}{}
+			}
+			d.keys(path+".inputs", ia, ib)
+			sa, sb := map[string]struct{}{}, map[string]struct{}{}
+			for k := range a.Secrets {
+				sa[k.Value] = struct{}{}
+			}
+			for k := range b.Secrets {
+				sb[k.Value] = struct{}{}
+			}
+			d.keys(path+".secrets", sa, sb)
+			oa, ob := map[string]struct{}{}, map[string]struct{}{}
+			for k := range a.Outputs {
+				oa[k.Value] = struct{}{}
+			}
+			for k := range b.Outputs {
+				ob[k.Value] = struct{}{}
+			}
+			d.keys(path+".outputs", oa, ob)
+		}
+	}
+}
+
+func (d *workflowDiffer) env(path string, a, b *Env) {
+	if a == nil || b == nil {
+		if (a == nil) != (b == nil) {
+			d.changed(path, diffValueOfEnv(a), diffValueOfEnv(b))
+		}
+		return
+	}
+	if a.Expression != nil || b.Expression != nil {
+		d.str(path, a.Expression, b.Expression)
+		return
+	}
+
+	ka := make(map[string]struct{}, len(a.Vars))
+	for k := range a.Vars {
+		ka[k] = struct{}{}
+	}
+	kb := make(map[string]struct{}, len(b.Vars))
+	for k := range b.Vars {
+		kb[k] = struct{}{}
+	}
+	for _, k := range d.keys(path, ka, kb) {
+		d.str(path+"."+k, a.Vars[k].Value, b.Vars[k].Value)
+	}
+}
+
+func diffValueOfEnv(e *Env) string {
+	if e == nil {
+		return "(none)"
+	}
+	if e.Expression != nil {
+		return strconv.Quote(e.Expression.Value)
+	}
+	return fmt.Sprintf("(%d variables)", len(e.Vars))
+}
+
+func (d *workflowDiffer) jobs(a, b map[string]*Job) {
+	ka := make(map[string]struct{}, len(a))
+	for k := range a {
+		ka[k] = struct{}{}
+	}
+	kb := make(map[string]struct{}, len(b))
+	for k := range b {
+		kb[k] = struct{}{}
+	}
+
+	for _, id := range d.keys("jobs", ka, kb) {
+		d.job("jobs."+id, a[id], b[id])
+	}
+}
+
+func (d *workflowDiffer) job(path string, a, b *Job) {
+	d.str(path+".name", a.Name, b.Name)
+	d.str(path+".if", a.If, b.If)
+	d.strs(path+".needs", a.Needs, b.Needs)
+	var ra, rb []*String
+	var ga, gb *String
+	if a.RunsOn != nil {
+		ra = a.RunsOn.Labels
+		ga = a.RunsOn.Group
+	}
+	if b.RunsOn != nil {
+		rb = b.RunsOn.Labels
+		gb = b.RunsOn.Group
+	}
+	d.strs(path+".runs-on", ra, rb)
+	d.str(path+".runs-on.group", ga, gb)
+	var ua, ub *String
+	if a.WorkflowCall != nil {
+		ua = a.WorkflowCall.Uses
+	}
+	if b.WorkflowCall != nil {
+		ub = b.WorkflowCall.Uses
+	}
+	d.str(path+".uses", ua, ub)
+	d.env(path+".env", a.Env, b.Env)
+	d.steps(path+".steps", a.Steps, b.Steps)
+}
+
+// stepDiffKey returns the key to match the step in two jobs. It is an ID of the step or its
+// 1-based index like "#2" when the step has no ID.
+func stepDiffKey(s *Step, idx int) string {
+	if s.ID != nil && s.ID.Value != "" {
+		return s.ID.Value
+	}
+	return fmt.Sprintf("#%d", idx+1)
+}
+
+func (d *workflowDiffer) steps(path string, a, b []*Step) {
+	ma := make(map[string]*Step, len(a))
+	for i, s := range a {
+		ma[stepDiffKey(s, i)] = s
+	}
+	mb := make(map[string]*Step, len(b))
+	for i, s := range b {
+		mb[stepDiffKey(s, i)] = s
+	}
+
+	// Report steps in the order of their appearances
+	for i, s := range a {
+		k := stepDiffKey(s, i)
+		if t, ok := mb[k]; ok {
+			d.step(path+"."+k, s, t)
+		} else {
+			d.add(WorkflowDiffRemoved, path+"."+k)
+		}
+	}
+	for i, s := range b {
+		k := stepDiffKey(s, i)
+		if _, ok := ma[k]; !ok {
+			d.add(WorkflowDiffAdded, path+"."+k)
+		}
+	}
+}
+
+func (d *workflowDiffer) step(path string, a, b *Step) {
+	d.str(path+".name", a.Name, b.Name)
+	d.str(path+".if", a.If, b.If)
+
+	var ua, ub, ra, rb, sa, sb, wa, wb *String
+	ia, ib := map[string]*Input{}, map[string]*Input{}
+	switch e := a.Exec.(type) {
+	case *ExecAction:
+		ua, wa = e.Uses, e.WorkingDirectory
+		ia = e.Inputs
+	case *ExecRun:
+		ra, sa, wa = e.Run, e.Shell, e.WorkingDirectory
+	}
+	switch e := b.Exec.(type) {
+	case *ExecAction:
+		ub, wb = e.Uses, e.WorkingDirectory
+		ib = e.Inputs
+	case *ExecRun:
+		rb, sb, wb = e.Run, e.Shell, e.WorkingDirectory
+	}
+	d.str(path+".uses", ua, ub)
+	d.str(path+".run", ra, rb)
+	d.str(path+".shell", sa, sb)
+	d.str(path+".working-directory", wa, wb)
+
+	ka := make(map[string]struct{}, len(ia))
+	for k := range ia {
+		ka[k] = struct{}{}
+	}
+	kb := make(map[string]struct{}, len(ib))
+	for k := range ib {
+		kb[k] = struct{}{}
+	}
+	for _, k := range d.keys(path+".with", ka, kb) {
+		d.str(path+".with."+k, ia[k].Value, ib[k].Value)
+	}
+
+	d.env(path+".env", a.Env, b.Env)
+}
@@ -0,0 +1,123 @@
+package actionlint
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiffWorkflows(t *testing.T) {
+	before := `name: Staging
+on:
+  push:
+    branches: [staging]
+  workflow_dispatch:
+env:
+  TARGET: staging
+jobs:
+  build:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+      - id: build
+        run: make build
+      - id: test
+        run: make test
+  notify:
+    runs-on: ubuntu-latest
+    steps:
+      - run: ./notify.sh
+`
+	after := `name: Production
+on:
+  push:
+    branches: [main]
+  schedule:
+    - cron: '0 0 * * *'
+env:
+  TARGET: production
+jobs:
+  build:
+    runs-on: [self-hosted, linux]
+    steps:
+      - uses: actions/checkout@v4
+        with:
+          fetch-depth: 0
+      - id: build
+        run: make build-release
+        shell: bash
+      - id: lint
+        run: make lint
+  deploy:
+    needs: [build]
+    runs-on: ubuntu-latest
+    steps:
+      - run: ./deploy.sh
+`
+	a, errs := Parse([]byte(before))
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	b, errs := Parse([]byte(after))
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	want := []string{
+		`~ name: "Staging" -> "Production"`,
+		`- on.workflow_dispatch`,
+		`+ on.schedule`,
+		`~ on.push.branches: ["staging"] -> ["main"]`,
+		`~ env.target: "staging" -> "production"`,
+		`- jobs.notify`,
+		`+ jobs.deploy`,
+		`~ jobs.build.runs-on: ["ubuntu-latest"] -> ["self-hosted", "linux"]`,
+		`+ jobs.build.steps.#1.with.fetch-depth`,
+		`~ jobs.build.steps.build.run: "make build" -> "make build-release"`,
+		`~ jobs.build.steps.build.shell: (none) -> "bash"`,
+		`- jobs.build.steps.test`,
+		`+ jobs.build.steps.lint`,
+	}
+	have := []string{}
+	for _, d := range DiffWorkflows(a, b) {
+		have = append(have, d.String())
+	}
+	if diff := cmp.Diff(want, have); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if ds := DiffWorkflows(a, a); len(ds) != 0 {
+		t.Fatalf("no difference was expected for the same workflow but got %v", ds)
+	}
+}
+
+func TestDiffWorkflowsIgnoreFormatting(t *testing.T) {
+	before := `on: push
+jobs:
+  test:
+    runs-on: ubuntu-latest
+    steps:
+      - run: echo hello
+        name: Hello
+`
+	after := `# Comments and order of keys don't matter
+"on": [push]
+jobs:
+  test:
+    steps:
+      - name: 'Hello'
+        run: "echo hello"
+    runs-on: ubuntu-latest
+`
+	a, errs := Parse([]byte(before))
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	b, errs := Parse([]byte(after))
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if ds := DiffWorkflows(a, b); len(ds) != 0 {
+		t.Fatalf("no difference was expected but got %v", ds)
+	}
+}